@@ -14,6 +14,7 @@ import ru.fluentlyapp.fluently.network.model.internal.CardApiModel
 import ru.fluentlyapp.fluently.network.model.internal.ChatResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.LessonResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.TopicApiModel
+import ru.fluentlyapp.fluently.network.model.internal.TopicsResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.UserPreferencesResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.WordOfTheDayResponseBody
 import ru.fluentlyapp.fluently.network.services.FluentlyApiService
@@ -118,10 +119,16 @@ class FluentlyApiDefaultDataSource @Inject constructor(
     override suspend fun getTopics(): List<String> {
         return withContext(Dispatchers.IO) {
             Timber.d("Performing getTopics")
-            val response = fluentlyApiService.getTopics()
-            val body: List<TopicApiModel> = getSuccessfulResponseBody(response)
-            Timber.d("Performing getTopics: body=$body")
-            body.map { it.title }
+            val topics = mutableListOf<TopicApiModel>()
+            var cursor: String? = null
+            do {
+                val response = fluentlyApiService.getTopics(root = true, limit = 200, cursor = cursor)
+                val body: TopicsResponseBody = getSuccessfulResponseBody(response)
+                Timber.d("Performing getTopics: body=$body")
+                topics += body.topics
+                cursor = body.nextCursor?.takeIf { it.isNotEmpty() }
+            } while (cursor != null)
+            topics.map { it.title }
         }
     }
 }
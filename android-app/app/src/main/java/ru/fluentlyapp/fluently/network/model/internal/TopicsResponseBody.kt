@@ -1,7 +1,14 @@
 package ru.fluentlyapp.fluently.network.model.internal
 
+import kotlinx.serialization.SerialName
 import kotlinx.serialization.Serializable
 
+@Serializable
+data class TopicsResponseBody(
+    val topics: List<TopicApiModel>,
+    @SerialName("next_cursor") val nextCursor: String? = null
+)
+
 @Serializable
 data class TopicApiModel(
     val title: String
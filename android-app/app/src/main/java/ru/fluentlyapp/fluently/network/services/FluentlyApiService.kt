@@ -5,12 +5,13 @@ import retrofit2.http.Body
 import retrofit2.http.GET
 import retrofit2.http.POST
 import retrofit2.http.PUT
+import retrofit2.http.Query
 import ru.fluentlyapp.fluently.common.model.UserPreferences
 import ru.fluentlyapp.fluently.network.model.internal.CardApiModel
 import ru.fluentlyapp.fluently.network.model.internal.ChatRequestBody
 import ru.fluentlyapp.fluently.network.model.internal.ChatResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.LessonResponseBody
-import ru.fluentlyapp.fluently.network.model.internal.TopicApiModel
+import ru.fluentlyapp.fluently.network.model.internal.TopicsResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.UserPreferencesRequestBody
 import ru.fluentlyapp.fluently.network.model.internal.UserPreferencesResponseBody
 import ru.fluentlyapp.fluently.network.model.internal.WordOfTheDayResponseBody
@@ -39,5 +40,9 @@ interface FluentlyApiService {
     suspend fun putUserPreferences(@Body preferences: UserPreferencesRequestBody)
 
     @GET("/api/v1/topics")
-    suspend fun getTopics(): Response<List<TopicApiModel>>
+    suspend fun getTopics(
+        @Query("root") root: Boolean,
+        @Query("limit") limit: Int,
+        @Query("cursor") cursor: String?
+    ): Response<TopicsResponseBody>
 }
\ No newline at end of file
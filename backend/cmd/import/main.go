@@ -3,17 +3,18 @@ package main
 import (
 	"bufio"
 	"context"
-	"encoding/csv"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
-	"strconv"
+	osuser "os/user"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"fluently/go-backend/internal/config"
 	"fluently/go-backend/internal/repository/models"
+	pg "fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/utils"
 	"fluently/go-backend/pkg/logger"
 
@@ -25,22 +26,24 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 var (
-	csvFilePath string
-	batchSize   = 100
+	csvFilePath    string
+	importFormat   string
+	importMapping  string
+	importDryRun   bool
+	importRejects  string
+	importRunID    string
+	importRunLimit int
+	rollbackYes    bool
+	exportOut      string
+	exportFormat   string
+	exportTopicID  string
+	exportCEFR     []string
 )
 
-type ImportStats struct {
-	WordsImported     int
-	TopicsCreated     int
-	SentencesAdded    int
-	ErrorsEncountered int
-	StartTime         time.Time
-	TotalRows         int
-}
-
 type ClearStats struct {
 	LearnedWordsDeleted int
 	SentencesDeleted    int
@@ -50,20 +53,6 @@ type ClearStats struct {
 	StartTime           time.Time
 }
 
-type CSVRecord struct {
-	Index       int
-	Total       float64
-	Word        string
-	Topic       string
-	Subtopic    string
-	Subsubtopic string
-	CEFRLevel   string
-	Translation string
-	Sentences   string
-}
-
-type SentencePair []string
-
 var rootCmd = &cobra.Command{
 	Use:   "import",
 	Short: "A CLI tool for importing data",
@@ -72,11 +61,45 @@ var rootCmd = &cobra.Command{
 
 var csvCmd = &cobra.Command{
 	Use:   "csv",
-	Short: "Import data from CSV file",
-	Long:  `Import words, topics, and sentences from a CSV file into the database.`,
+	Short: "Import words from a CSV, TSV, JSON or NDJSON file",
+	Long:  `Import words, topics, and sentences into the database. Columns are mapped to word fields by a mapping file, the dictionary CSV columns by default. Words are created, updated or skipped by word, part of speech and topic, and every run is recorded for rollback.`,
 	RunE:  runCSVImport,
 }
 
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List import runs",
+	Long:  `List the latest import runs with their changes, newest first.`,
+	RunE:  runListImportRuns,
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back an import run",
+	Long:  `Delete the words, sentences and topics an import run created and restore the words it updated.`,
+	RunE:  runRollbackImport,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export words or topics",
+	Long:  `Export global words with their topics, sentences and pick options in the format the csv command imports, or the topic tree.`,
+}
+
+var exportWordsCmd = &cobra.Command{
+	Use:   "words",
+	Short: "Export words to a CSV, TSV or NDJSON file",
+	Long:  `Export global words with their topic path, shared sentences and pick options, sorted by word. The file imports back with the default mapping.`,
+	RunE:  runExportWords,
+}
+
+var exportTopicsCmd = &cobra.Command{
+	Use:   "topics",
+	Short: "Export topics to a CSV, TSV or NDJSON file",
+	Long:  `Export topics with their path and the number of their global words, sorted by path.`,
+	RunE:  runExportTopics,
+}
+
 var clearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all words, sentences, topics and learned words from database",
@@ -105,6 +128,41 @@ var resetEnrichmentCmd = &cobra.Command{
 	RunE:  runResetEnrichment,
 }
 
+var wordsCmd = &cobra.Command{
+	Use:   "words",
+	Short: "List global words for curation",
+	Long:  `List global words filtered by CEFR level, topic, or missing audio, sentences or distractors.`,
+	RunE:  runListWords,
+}
+
+var bulkEditCmd = &cobra.Command{
+	Use:   "bulk-edit",
+	Short: "Change CEFR level, part of speech or topic of several words",
+	Long:  `Change CEFR level, part of speech or topic of several global words at once. Every changed word is recorded in the content audit log.`,
+	RunE:  runBulkEdit,
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge duplicate words into one",
+	Long:  `Move sentences, distractors, progress, deck and assignment entries of duplicate words to the target word and delete the duplicates. The merge is recorded in the content audit log.`,
+	RunE:  runMergeWords,
+}
+
+var reparentCmd = &cobra.Command{
+	Use:   "reparent",
+	Short: "Move a topic under another topic",
+	Long:  `Move a topic under another topic, or make it a main topic when no parent is given. The move is recorded in the content audit log.`,
+	RunE:  runReparentTopic,
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the content audit log",
+	Long:  `Show who changed which words and topics, newest first.`,
+	RunE:  runShowAudit,
+}
+
 var (
 	enrichLimit   int
 	enrichDelay   int
@@ -112,21 +170,93 @@ var (
 	sentenceDelay int
 )
 
+var (
+	curateFilter       pg.WordFilter
+	curateTopicID      string
+	curatePage         int
+	curateIDs          []string
+	curateCEFR         string
+	curatePartOfSpeech string
+	curateClearTopic   bool
+	mergeInto          string
+	reparentTopicID    string
+	reparentParentID   string
+	auditEntityID      string
+	auditAction        string
+)
+
 func init() {
-	csvCmd.Flags().StringVarP(&csvFilePath, "file", "f", "", "Path to CSV file (required)")
+	csvCmd.Flags().StringVarP(&csvFilePath, "file", "f", "", "Path to the file (required)")
+	csvCmd.Flags().StringVar(&importFormat, "format", "", "File format: csv, tsv, json or ndjson, detected from the file name by default")
+	csvCmd.Flags().StringVarP(&importMapping, "mapping", "m", "", "Path to a JSON file mapping word fields to columns")
+	csvCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would change without changing anything")
+	csvCmd.Flags().StringVar(&importRejects, "rejects", "", "Write rejected rows with the reason to this file")
 	csvCmd.MarkFlagRequired("file")
 
+	runsCmd.Flags().IntVarP(&importRunLimit, "limit", "l", 20, "Number of runs to show")
+
+	rollbackCmd.Flags().StringVar(&importRunID, "run", "", "ID of the import run (required)")
+	rollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Don't ask for confirmation")
+	rollbackCmd.MarkFlagRequired("run")
+
+	for _, cmd := range []*cobra.Command{exportWordsCmd, exportTopicsCmd} {
+		cmd.Flags().StringVarP(&exportOut, "out", "o", "", "Path to the output file, standard output by default")
+		cmd.Flags().StringVar(&exportFormat, "format", "", "File format: csv, tsv or ndjson, detected from the file name, csv by default")
+		cmd.Flags().StringVar(&exportTopicID, "topic", "", "Only the topic ID and its subtopics")
+	}
+	exportWordsCmd.Flags().StringSliceVar(&exportCEFR, "cefr", nil, "Only words of the comma separated CEFR levels")
+	exportCmd.AddCommand(exportWordsCmd)
+	exportCmd.AddCommand(exportTopicsCmd)
+
 	enrichCmd.Flags().IntVarP(&enrichLimit, "limit", "l", 100000, "Maximum number of words to enrich in one run")
 	enrichCmd.Flags().IntVarP(&enrichDelay, "delay", "d", 500, "Delay between API calls in milliseconds")
 
 	enrichSentencesCmd.Flags().IntVarP(&sentenceLimit, "limit", "l", 1000000, "Maximum number of sentences to enrich in one run")
 	enrichSentencesCmd.Flags().IntVarP(&sentenceDelay, "delay", "d", 10, "Delay between API calls in milliseconds")
 
+	wordsCmd.Flags().StringVar(&curateFilter.CEFRLevel, "cefr", "", "Only words of the CEFR level")
+	wordsCmd.Flags().StringVar(&curateTopicID, "topic", "", "Only words of the topic ID")
+	wordsCmd.Flags().StringVarP(&curateFilter.Search, "search", "s", "", "Only words starting with the text")
+	wordsCmd.Flags().BoolVar(&curateFilter.MissingAudio, "missing-audio", false, "Only words without audio")
+	wordsCmd.Flags().BoolVar(&curateFilter.MissingSentences, "missing-sentences", false, "Only words without sentences")
+	wordsCmd.Flags().BoolVar(&curateFilter.MissingDistractors, "missing-distractors", false, "Only words without distractors")
+	wordsCmd.Flags().IntVarP(&curatePage, "page", "p", 1, "Page number, starting from 1")
+	wordsCmd.Flags().IntVarP(&curateFilter.Limit, "limit", "l", 50, "Words per page")
+
+	bulkEditCmd.Flags().StringSliceVar(&curateIDs, "ids", nil, "Comma separated word IDs (required)")
+	bulkEditCmd.Flags().StringVar(&curateCEFR, "cefr", "", "New CEFR level")
+	bulkEditCmd.Flags().StringVar(&curatePartOfSpeech, "part-of-speech", "", "New part of speech")
+	bulkEditCmd.Flags().StringVar(&curateTopicID, "topic", "", "New topic ID")
+	bulkEditCmd.Flags().BoolVar(&curateClearTopic, "clear-topic", false, "Remove the words from their topic")
+	bulkEditCmd.MarkFlagRequired("ids")
+
+	mergeCmd.Flags().StringVar(&mergeInto, "into", "", "ID of the word to keep (required)")
+	mergeCmd.Flags().StringSliceVar(&curateIDs, "words", nil, "Comma separated IDs of the duplicates (required)")
+	mergeCmd.MarkFlagRequired("into")
+	mergeCmd.MarkFlagRequired("words")
+
+	reparentCmd.Flags().StringVar(&reparentTopicID, "topic", "", "ID of the topic to move (required)")
+	reparentCmd.Flags().StringVar(&reparentParentID, "parent", "", "ID of the new parent, empty for a main topic")
+	reparentCmd.MarkFlagRequired("topic")
+
+	auditCmd.Flags().StringVar(&auditEntityID, "entity", "", "Only changes of the word or topic ID")
+	auditCmd.Flags().StringVar(&auditAction, "action", "", "Only changes of the action: word.update, word.merge or topic.reparent")
+	auditCmd.Flags().IntVarP(&curatePage, "page", "p", 1, "Page number, starting from 1")
+	auditCmd.Flags().IntVarP(&curateFilter.Limit, "limit", "l", 50, "Entries per page")
+
 	rootCmd.AddCommand(csvCmd)
+	rootCmd.AddCommand(runsCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(clearCmd)
 	rootCmd.AddCommand(enrichCmd)
 	rootCmd.AddCommand(enrichSentencesCmd)
 	rootCmd.AddCommand(resetEnrichmentCmd)
+	rootCmd.AddCommand(wordsCmd)
+	rootCmd.AddCommand(bulkEditCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(reparentCmd)
+	rootCmd.AddCommand(auditCmd)
 }
 
 func main() {
@@ -142,50 +272,109 @@ func runCSVImport(cmd *cobra.Command, args []string) error {
 	logger.Init(true) // Enable debug logging
 	defer logger.Log.Sync()
 
-	// Validate file exists
-	if _, err := os.Stat(csvFilePath); os.IsNotExist(err) {
-		return fmt.Errorf("CSV file does not exist: %s", csvFilePath)
+	format := importFormat
+	if format == "" {
+		format = utils.WordImportFormat(csvFilePath)
 	}
-
-	// Connect to database
-	db, err := connectToDatabase()
-	if err != nil {
-		return fmt.Errorf("failed to connect to database: %v", err)
+	if format == "" {
+		return fmt.Errorf("can't detect the format of %s, set --format", csvFilePath)
 	}
 
-	logger.Log.Info("Starting CSV import", zap.String("file", csvFilePath))
+	mapping := utils.DefaultWordImportMapping()
+	if importMapping != "" {
+		file, err := os.Open(importMapping)
+		if err != nil {
+			return fmt.Errorf("failed to open mapping: %v", err)
+		}
+		mapping, err = utils.LoadWordImportMapping(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
 
-	// Count total rows for progress bar
-	totalRows, err := countCSVRows(csvFilePath)
+	// Read the whole file first, so a broken file changes nothing
+	file, err := os.Open(csvFilePath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("file does not exist: %s", csvFilePath)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to count CSV rows: %v", err)
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	header, records, err := utils.ReadWordImportRecords(file, format)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	if header != nil {
+		if err := mapping.Check(header); err != nil {
+			return err
+		}
 	}
 
-	stats := &ImportStats{
-		StartTime: time.Now(),
-		TotalRows: totalRows,
+	logger.Log.Info("Starting import",
+		zap.String("file", csvFilePath),
+		zap.String("format", format),
+		zap.Int("rows", len(records)),
+		zap.Bool("dry_run", importDryRun))
+
+	byLine := make(map[int]utils.WordImportRecord, len(records))
+	rows := make([]pg.WordImportRow, 0, len(records))
+	var invalid []pg.ImportResult
+	for _, record := range records {
+		byLine[record.Line] = record
+		row, err := mapping.Row(record)
+		if err != nil {
+			invalid = append(invalid, pg.ImportResult{
+				Line:   record.Line,
+				Word:   row.Word,
+				Action: pg.ImportResultReject,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	db, err := connectForImport()
+	if err != nil {
+		return err
 	}
 
-	// Create progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionSetDescription("Importing CSV data..."),
+	start := time.Now()
+	bar := progressbar.NewOptions(len(rows),
+		progressbar.OptionSetDescription("Importing rows..."),
 		progressbar.OptionSetWidth(50),
 		progressbar.OptionShowCount(),
 		progressbar.OptionShowIts(),
 		progressbar.OptionSetItsString("rows"),
 		progressbar.OptionOnCompletion(func() {
-			fmt.Println("\nImport completed!")
+			fmt.Println()
 		}),
 	)
 
-	// Process CSV file
-	err = processCSVFile(db, csvFilePath, bar, stats)
+	report, err := pg.NewImportRepository(db).Import(context.Background(), rows, pg.ImportOptions{
+		Source:   filepath.Base(csvFilePath),
+		Actor:    cliActor().Name,
+		DryRun:   importDryRun,
+		Rejected: len(invalid),
+		Progress: func() { bar.Add(1) },
+	})
 	if err != nil {
-		return fmt.Errorf("failed to process CSV: %v", err)
+		return fmt.Errorf("failed to import: %v", err)
 	}
+	report.Results = append(report.Results, invalid...)
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return report.Results[i].Line < report.Results[j].Line
+	})
 
-	// Print final statistics
-	printStats(stats)
+	if importRejects != "" && report.Rejected > 0 {
+		if err := writeRejects(importRejects, format, header, report.Results, byLine); err != nil {
+			return fmt.Errorf("failed to write rejects: %v", err)
+		}
+	}
+
+	printImportReport(report, time.Since(start))
 	return nil
 }
 
@@ -205,518 +394,302 @@ func connectToDatabase() (*gorm.DB, error) {
 	return db, nil
 }
 
-func countCSVRows(filepath string) (int, error) {
-	file, err := os.Open(filepath)
+// connectForImport connects to the database and makes sure the import tables exist
+func connectForImport() (*gorm.DB, error) {
+	db, err := connectToDatabase()
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	count := 0
-	for {
-		_, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return 0, err
-		}
-		count++
+	if err := db.AutoMigrate(&models.Topic{}, &models.Sentence{}, &models.ImportRun{}, &models.ImportRunItem{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate import tables: %v", err)
 	}
 
-	// Subtract 1 for header row
-	if count > 0 {
-		count--
-	}
-	return count, nil
+	return db, nil
 }
 
-func processCSVFile(db *gorm.DB, filepath string, bar *progressbar.ProgressBar, stats *ImportStats) error {
-	file, err := os.Open(filepath)
+// writeRejects writes the records of rejected rows to the file
+func writeRejects(path, format string, header []string, results []pg.ImportResult, records map[int]utils.WordImportRecord) error {
+	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-
-	// Read header
-	headers, err := reader.Read()
+	rejects, err := utils.NewWordImportRejects(file, format, header)
 	if err != nil {
-		return fmt.Errorf("failed to read CSV header: %v", err)
-	}
-
-	// Validate headers
-	expectedHeaders := []string{"", "Total", "word", "topic", "subtopic", "subsubtopic", "CEFR_level", "translation", "sentences"}
-	if len(headers) != len(expectedHeaders) {
-		return fmt.Errorf("invalid CSV format: expected %d columns, got %d", len(expectedHeaders), len(headers))
+		return err
 	}
-
-	var batch []CSVRecord
-	rowNum := 0
-
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			logger.Log.Error("Failed to read CSV row", zap.Error(err), zap.Int("row", rowNum+2))
-			stats.ErrorsEncountered++
+	for _, result := range results {
+		if result.Action != pg.ImportResultReject {
 			continue
 		}
-
-		rowNum++
-
-		if len(record) != len(expectedHeaders) {
-			logger.Log.Error("Invalid CSV row format", zap.Int("row", rowNum+1), zap.Int("expected_cols", len(expectedHeaders)), zap.Int("actual_cols", len(record)))
-			stats.ErrorsEncountered++
-			bar.Add(1)
-			continue
+		if err := rejects.Write(records[result.Line], result.Error); err != nil {
+			return err
 		}
+	}
+	return rejects.Flush()
+}
 
-		// Parse total field
-		total := 0.0
-		if record[1] != "" {
-			if t, err := strconv.ParseFloat(record[1], 64); err == nil {
-				total = t
+// printImportReport prints the changes of every row for dry runs and rejects, then the totals
+func printImportReport(report *pg.ImportReport, duration time.Duration) {
+	dryRun := report.RunID == nil
+
+	for _, result := range report.Results {
+		switch {
+		case result.Action == pg.ImportResultReject:
+			fmt.Printf("! line %-6d %-30s %s\n", result.Line, result.Word, result.Error)
+		case !dryRun || result.Action == pg.ImportResultSkip:
+		case result.Action == models.ImportActionCreate:
+			fmt.Printf("+ line %-6d %-30s sentences: %d\n", result.Line, result.Word, result.SentencesAdded)
+		default:
+			fields := make([]string, 0, len(result.Changes))
+			for field, change := range result.Changes {
+				fields = append(fields, fmt.Sprintf("%s: %q → %q", field, change[0], change[1]))
+			}
+			sort.Strings(fields)
+			if result.SentencesAdded > 0 {
+				fields = append(fields, fmt.Sprintf("sentences: +%d", result.SentencesAdded))
 			}
+			fmt.Printf("~ line %-6d %-30s %s\n", result.Line, result.Word, strings.Join(fields, ", "))
 		}
+	}
 
-		csvRecord := CSVRecord{
-			Index:       rowNum,
-			Total:       total,
-			Word:        strings.TrimSpace(record[2]),
-			Topic:       strings.TrimSpace(record[3]),
-			Subtopic:    strings.TrimSpace(record[4]),
-			Subsubtopic: strings.TrimSpace(record[5]),
-			CEFRLevel:   strings.TrimSpace(record[6]),
-			Translation: strings.TrimSpace(record[7]),
-			Sentences:   normalizeJSONQuotes(strings.TrimSpace(record[8])),
-		}
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	if dryRun {
+		fmt.Println("📊 DRY RUN, NOTHING WAS CHANGED")
+	} else {
+		fmt.Println("📊 IMPORT STATISTICS")
+	}
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("⏱️  Duration: %v\n", duration.Round(time.Second))
+	fmt.Printf("📝 Words created: %d\n", report.Created)
+	fmt.Printf("✏️  Words updated: %d\n", report.Updated)
+	fmt.Printf("⏭️  Rows skipped: %d\n", report.Skipped)
+	fmt.Printf("🏷️  Topics created: %d\n", report.TopicsCreated)
+	fmt.Printf("💬 Sentences added: %d\n", report.SentencesAdded)
+	fmt.Printf("🔤 Pick options added: %d\n", report.OptionsAdded)
+	fmt.Printf("❌ Rows rejected: %d\n", report.Rejected)
+	if report.RunID != nil {
+		fmt.Printf("🔖 Run ID: %s\n", report.RunID)
+	}
+	fmt.Println(strings.Repeat("=", 50))
+}
 
-		// Skip empty words
-		if csvRecord.Word == "" {
-			logger.Log.Warn("Skipping row with empty word", zap.Int("row", rowNum+1))
-			bar.Add(1)
-			continue
-		}
+func runListImportRuns(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
 
-		batch = append(batch, csvRecord)
+	db, err := connectForImport()
+	if err != nil {
+		return err
+	}
 
-		// Process batch when it reaches batchSize
-		if len(batch) >= batchSize {
-			err := processBatch(db, batch, stats)
-			if err != nil {
-				logger.Log.Error("Failed to process batch", zap.Error(err))
-				stats.ErrorsEncountered += len(batch)
-			}
-			bar.Add(len(batch))
-			batch = nil
-		}
+	runs, err := pg.NewImportRepository(db).ListRuns(context.Background(), importRunLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list import runs: %v", err)
 	}
 
-	// Process remaining records
-	if len(batch) > 0 {
-		err := processBatch(db, batch, stats)
-		if err != nil {
-			logger.Log.Error("Failed to process final batch", zap.Error(err))
-			stats.ErrorsEncountered += len(batch)
-		}
-		bar.Add(len(batch))
+	for _, run := range runs {
+		fmt.Printf("%s  %s  %-11s %-30s %s  created: %d  updated: %d  skipped: %d  rejected: %d\n",
+			run.ID, run.CreatedAt.Format(time.RFC3339), run.Status, run.Source, run.Actor,
+			run.Created, run.Updated, run.Skipped, run.Rejected)
 	}
 
 	return nil
 }
 
-func processBatch(db *gorm.DB, batch []CSVRecord, stats *ImportStats) error {
-	ctx := context.Background()
+func runRollbackImport(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
 
-	// Process each record individually instead of grouping by word
-	for _, record := range batch {
-		err := db.Transaction(func(tx *gorm.DB) error {
-			return processWordRecord(tx, ctx, record, stats)
-		})
+	runID, err := uuid.Parse(importRunID)
+	if err != nil {
+		return fmt.Errorf("invalid run ID: %v", err)
+	}
 
+	if !rollbackYes {
+		fmt.Printf("⚠️  Roll back import run %s? Changes made to its words since the import are lost. [y/N] ", runID)
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
 		if err != nil {
-			logger.Log.Error("Failed to process word record",
-				zap.Error(err),
-				zap.String("word", record.Word),
-				zap.String("translation", record.Translation),
-				zap.String("topic", record.Topic),
-				zap.String("subtopic", record.Subtopic),
-				zap.String("subsubtopic", record.Subsubtopic))
-			stats.ErrorsEncountered++
-			continue
+			return fmt.Errorf("failed to read answer: %v", err)
+		}
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			return fmt.Errorf("operation cancelled")
 		}
 	}
 
-	return nil
-}
-
-func processWordRecord(tx *gorm.DB, ctx context.Context, record CSVRecord, stats *ImportStats) error {
-	// Log detailed information about what we're processing
-	logger.Log.Debug("Processing word record",
-		zap.String("word", record.Word),
-		zap.String("translation", record.Translation),
-		zap.String("topic", record.Topic),
-		zap.String("subtopic", record.Subtopic),
-		zap.String("subsubtopic", record.Subsubtopic),
-		zap.String("cefr_level", record.CEFRLevel))
-
-	// Create topic hierarchy
-	topicID, err := createTopicHierarchy(tx, ctx, record.Topic, record.Subtopic, record.Subsubtopic, stats)
+	db, err := connectForImport()
 	if err != nil {
-		logger.Log.Error("Failed to create topic hierarchy",
-			zap.Error(err),
-			zap.String("word", record.Word),
-			zap.String("translation", record.Translation),
-			zap.String("topic", record.Topic),
-			zap.String("subtopic", record.Subtopic),
-			zap.String("subsubtopic", record.Subsubtopic))
-		return fmt.Errorf("failed to create topic hierarchy: %v", err)
-	}
-
-	if topicID != nil {
-		logger.Log.Debug("Topic hierarchy created",
-			zap.String("topicID", topicID.String()),
-			zap.String("word", record.Word),
-			zap.String("translation", record.Translation))
-	} else {
-		logger.Log.Debug("No topic hierarchy created (all empty)",
-			zap.String("word", record.Word),
-			zap.String("translation", record.Translation))
-	}
-
-	// Use the translation from this specific record
-	translation := record.Translation
-
-	// Truncate translation if too long (varchar(255) limit)
-	if len(translation) > 255 {
-		translation = translation[:255]
-		logger.Log.Warn("Translation truncated due to length limit",
-			zap.String("word", record.Word),
-			zap.String("original_translation", record.Translation),
-			zap.String("truncated_translation", translation))
-	}
-
-	// Check word length (varchar(255) limit)
-	wordText := record.Word
-	if len(wordText) > 255 {
-		wordText = wordText[:255]
-		logger.Log.Warn("Word truncated due to length limit",
-			zap.String("original_word", record.Word),
-			zap.String("truncated_word", wordText))
-	}
-
-	// Check if word with this translation already exists
-	// Uniqueness is now based on (word, translation, topic_id) combination
-	var existingWord models.Word
-	query := tx.WithContext(ctx).Where("word = ? AND translation = ?", wordText, translation)
-	if topicID != nil {
-		query = query.Where("topic_id = ?", *topicID)
-	} else {
-		query = query.Where("topic_id IS NULL")
-	}
-	result := query.First(&existingWord)
-
-	var wordID uuid.UUID
-	if result.Error == gorm.ErrRecordNotFound {
-		// Create new word - each (word, translation) pair gets its own entity
-		newWord := models.Word{
-			Word:         wordText,
-			Translation:  translation,
-			PartOfSpeech: "unknown",
-			Context:      "",
-			CEFRLevel:    record.CEFRLevel,
-			TopicID:      topicID,
-		}
-
-		logger.Log.Debug("Creating new word",
-			zap.String("word", wordText),
-			zap.String("translation", translation),
-			zap.String("cefr_level", record.CEFRLevel))
-
-		if err := tx.WithContext(ctx).Create(&newWord).Error; err != nil {
-			topicIDStr := "NULL"
-			if topicID != nil {
-				topicIDStr = topicID.String()
-			}
-			logger.Log.Error("Failed to create word",
-				zap.Error(err),
-				zap.String("word", wordText),
-				zap.String("translation", translation),
-				zap.String("topic_id", topicIDStr))
-			return fmt.Errorf("failed to create word: %v", err)
-		}
-		wordID = newWord.ID
-		stats.WordsImported++
-
-		logger.Log.Debug("Successfully created word",
-			zap.String("id", newWord.ID.String()),
-			zap.String("word", wordText),
-			zap.String("translation", translation))
-	} else if result.Error != nil {
-		return fmt.Errorf("failed to check existing word: %v", result.Error)
-	} else {
-		// Update existing word with CEFR level if provided
-		if record.CEFRLevel != "" {
-			existingWord.CEFRLevel = record.CEFRLevel
-		}
+		return err
+	}
 
-		if err := tx.WithContext(ctx).Save(&existingWord).Error; err != nil {
-			return fmt.Errorf("failed to update word: %v", err)
-		}
-		wordID = existingWord.ID
-		logger.Log.Info("Updated existing word",
-			zap.String("word", record.Word),
-			zap.String("translation", record.Translation))
+	report, err := pg.NewImportRepository(db).Rollback(context.Background(), runID)
+	if err != nil {
+		return fmt.Errorf("failed to roll back import run: %v", err)
 	}
 
-	// Process sentences for this record
-	if record.Sentences != "" {
-		sentencesAdded, err := processSentences(tx, ctx, wordID, record.Sentences)
-		if err != nil {
-			logger.Log.Error("Failed to process sentences",
-				zap.Error(err),
-				zap.String("word", record.Word),
-				zap.String("translation", record.Translation))
-			return err
-		}
-		stats.SentencesAdded += sentencesAdded
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("🗑️  Words deleted: %d\n", report.WordsDeleted)
+	fmt.Printf("↩️  Words restored: %d\n", report.WordsRestored)
+	fmt.Printf("💬 Sentences deleted: %d\n", report.SentencesDeleted)
+	fmt.Printf("🔤 Pick options deleted: %d\n", report.OptionsDeleted)
+	fmt.Printf("🏷️  Topics deleted: %d, kept: %d\n", report.TopicsDeleted, report.TopicsKept)
+	if report.WordsKept > 0 {
+		fmt.Printf("🔒 Words kept, learners use them: %d\n", report.WordsKept)
 	}
+	fmt.Println(strings.Repeat("=", 50))
 
 	return nil
 }
 
-func createTopicHierarchy(tx *gorm.DB, ctx context.Context, topicName, subtopicName, subsubtopicName string, stats *ImportStats) (*uuid.UUID, error) {
-	var parentID *uuid.UUID
-
-	// Create main topic
-	if topicName != "" {
-		topicID, created, err := getOrCreateTopic(tx, ctx, topicName, nil)
-		if err != nil {
-			return nil, err
-		}
-		if created {
-			stats.TopicsCreated++
-		}
-		parentID = &topicID
+// exportTarget opens the export file, or standard output, and resolves the format
+func exportTarget() (*os.File, string, error) {
+	format := exportFormat
+	if format == "" && exportOut != "" {
+		format = utils.WordImportFormat(exportOut)
 	}
-
-	// Create subtopic
-	if subtopicName != "" {
-		subtopicID, created, err := getOrCreateTopic(tx, ctx, subtopicName, parentID)
-		if err != nil {
-			return nil, err
-		}
-		if created {
-			stats.TopicsCreated++
-		}
-		parentID = &subtopicID
+	if format == "" {
+		format = utils.WordImportCSV
 	}
 
-	// Create subsubtopic
-	if subsubtopicName != "" {
-		subsubtopicID, created, err := getOrCreateTopic(tx, ctx, subsubtopicName, parentID)
-		if err != nil {
-			return nil, err
-		}
-		if created {
-			stats.TopicsCreated++
-		}
-		parentID = &subsubtopicID
+	if exportOut == "" {
+		return os.Stdout, format, nil
 	}
-
-	return parentID, nil
+	file, err := os.Create(exportOut)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create file: %v", err)
+	}
+	return file, format, nil
 }
 
-func getOrCreateTopic(tx *gorm.DB, ctx context.Context, title string, parentID *uuid.UUID) (uuid.UUID, bool, error) {
-	var topic models.Topic
-
-	// Log what we're trying to find/create
-	parentIDStr := "NULL"
-	if parentID != nil {
-		parentIDStr = parentID.String()
+// exportTopic parses the --topic flag
+func exportTopic() (*uuid.UUID, error) {
+	if exportTopicID == "" {
+		return nil, nil
 	}
-	logger.Log.Debug("Getting or creating topic",
-		zap.String("title", title),
-		zap.String("parent_id", parentIDStr))
-
-	query := tx.WithContext(ctx).Where("title = ?", title)
-	if parentID != nil {
-		query = query.Where("parent_id = ?", *parentID)
-	} else {
-		query = query.Where("parent_id IS NULL")
+	topicID, err := uuid.Parse(exportTopicID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic ID: %v", err)
 	}
+	return &topicID, nil
+}
 
-	result := query.First(&topic)
-
-	if result.Error == gorm.ErrRecordNotFound {
-		// Create new topic
-		newTopic := models.Topic{
-			Title:    title,
-			ParentID: parentID,
-		}
-
-		logger.Log.Debug("Creating new topic",
-			zap.String("title", title),
-			zap.String("parent_id", parentIDStr))
+func runExportWords(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
 
-		if err := tx.WithContext(ctx).Create(&newTopic).Error; err != nil {
-			logger.Log.Error("Failed to create topic",
-				zap.Error(err),
-				zap.String("title", title),
-				zap.String("parent_id", parentIDStr))
-			return uuid.Nil, false, fmt.Errorf("failed to create topic: %v", err)
+	filter := pg.ExportFilter{}
+	topicID, err := exportTopic()
+	if err != nil {
+		return err
+	}
+	filter.TopicID = topicID
+	for _, level := range exportCEFR {
+		level = strings.ToUpper(strings.TrimSpace(level))
+		switch level {
+		case "A1", "A2", "B1", "B2", "C1", "C2":
+			filter.CEFRLevels = append(filter.CEFRLevels, level)
+		default:
+			return fmt.Errorf("invalid CEFR level %q", level)
 		}
-
-		logger.Log.Debug("Successfully created topic",
-			zap.String("id", newTopic.ID.String()),
-			zap.String("title", title))
-
-		return newTopic.ID, true, nil
-	} else if result.Error != nil {
-		logger.Log.Error("Database error when checking topic",
-			zap.Error(result.Error),
-			zap.String("title", title),
-			zap.String("parent_id", parentIDStr))
-		return uuid.Nil, false, fmt.Errorf("failed to check existing topic: %v", result.Error)
 	}
 
-	logger.Log.Debug("Found existing topic",
-		zap.String("id", topic.ID.String()),
-		zap.String("title", title))
-
-	return topic.ID, false, nil
-}
+	db, err := connectToDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if exportOut == "" {
+		// GORM logs slow queries to standard output, which would end up in the export
+		db.Logger = db.Logger.LogMode(gormlogger.Silent)
+	}
 
-func processSentences(tx *gorm.DB, ctx context.Context, wordID uuid.UUID, sentencesJSON string) (int, error) {
-	if sentencesJSON == "" {
-		return 0, nil
-	}
-
-	// Log the JSON we're trying to parse for debugging
-	logger.Log.Debug("Processing sentences JSON",
-		zap.String("word_id", wordID.String()),
-		zap.String("sentences_json", sentencesJSON))
-
-	// Parse JSON sentences
-	var sentencePairs []SentencePair
-	if err := json.Unmarshal([]byte(sentencesJSON), &sentencePairs); err != nil {
-		logger.Log.Error("Failed to parse sentences JSON",
-			zap.Error(err),
-			zap.String("word_id", wordID.String()),
-			zap.String("sentences_json", sentencesJSON))
-		return 0, fmt.Errorf("failed to parse sentences JSON: %v (JSON: %s)", err, sentencesJSON)
-	}
-
-	sentencesAdded := 0
-	for _, pair := range sentencePairs {
-		if len(pair) >= 2 {
-			sentence := models.Sentence{
-				WordID:      wordID,
-				Sentence:    pair[0],
-				Translation: pair[1],
-			}
+	out, format, err := exportTarget()
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+	writer, err := utils.NewWordExportWriter(out, format)
+	if err != nil {
+		return err
+	}
 
-			// Check if sentence already exists
-			var existingSentence models.Sentence
-			result := tx.WithContext(ctx).Where("word_id = ? AND sentence = ?", wordID, pair[0]).First(&existingSentence)
-
-			if result.Error == gorm.ErrRecordNotFound {
-				if err := tx.WithContext(ctx).Create(&sentence).Error; err != nil {
-					logger.Log.Error("Failed to create sentence", zap.Error(err))
-					continue
-				}
-				sentencesAdded++
-			} else if result.Error != nil {
-				logger.Log.Error("Failed to check existing sentence", zap.Error(result.Error))
+	exported, skipped := 0, 0
+	err = pg.NewExportRepository(db).ExportWords(context.Background(), filter, 500, func(words []pg.ExportedWord) error {
+		for _, word := range words {
+			err := writer.Write(word)
+			if errors.Is(err, utils.ErrTopicTooDeep) {
+				logger.Log.Warn("Skipping word with a deep topic",
+					zap.String("word", word.Word.Word),
+					zap.Strings("topics", word.Topics))
+				skipped++
 				continue
 			}
-			// If sentence exists, skip it (no update needed)
+			if err != nil {
+				return err
+			}
+			exported++
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export words: %v", err)
 	}
-
-	return sentencesAdded, nil
-}
-
-// normalizeJSONQuotes converts mixed quote JSON to valid JSON format
-// Handles cases like: [["text", 'текст'], ['text2', 'текст2']]
-// Converts to: [["text", "текст"], ["text2", "текст2"]]
-func normalizeJSONQuotes(jsonStr string) string {
-	if jsonStr == "" {
-		return ""
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write words: %v", err)
 	}
 
-	// Log original for debugging
-	logger.Log.Debug("Normalizing JSON quotes", zap.String("original", jsonStr))
-
-	result := make([]rune, 0, len(jsonStr))
-	runes := []rune(jsonStr)
-	inString := false
-	currentQuote := rune(0)
-
-	for i := 0; i < len(runes); i++ {
-		r := runes[i]
+	// The summary goes to stderr, so exports to standard output stay importable
+	fmt.Fprintf(os.Stderr, "📦 Words exported: %d, skipped: %d\n", exported, skipped)
+	return nil
+}
 
-		// Handle escaped characters first
-		if r == '\\' && inString && i+1 < len(runes) {
-			result = append(result, r)
-			i++
-			nextChar := runes[i]
-			result = append(result, nextChar)
-			continue
-		}
+func runExportTopics(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
 
-		switch r {
-		case '"', '\'':
-			if !inString {
-				// Starting a string - normalize to double quote
-				result = append(result, '"')
-				inString = true
-				currentQuote = r
-			} else if r == currentQuote {
-				// Ending the current string - use double quote
-				result = append(result, '"')
-				inString = false
-				currentQuote = 0
-			} else {
-				// Different quote inside string - escape if it's a double quote
-				if r == '"' {
-					result = append(result, '\\', '"')
-				} else {
-					// Single quote inside double-quoted string - keep as is
-					result = append(result, r)
-				}
-			}
-		default:
-			result = append(result, r)
-		}
+	topicID, err := exportTopic()
+	if err != nil {
+		return err
 	}
 
-	normalized := string(result)
-	logger.Log.Debug("Normalized JSON quotes", zap.String("normalized", normalized))
+	db, err := connectToDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if exportOut == "" {
+		// GORM logs slow queries to standard output, which would end up in the export
+		db.Logger = db.Logger.LogMode(gormlogger.Silent)
+	}
 
-	return normalized
-}
+	topics, err := pg.NewExportRepository(db).ExportTopics(context.Background(), topicID)
+	if err != nil {
+		return fmt.Errorf("failed to export topics: %v", err)
+	}
 
-func printStats(stats *ImportStats) {
-	duration := time.Since(stats.StartTime)
-	speed := float64(stats.TotalRows) / duration.Seconds()
+	out, format, err := exportTarget()
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+	if err := utils.WriteTopicExport(out, format, topics); err != nil {
+		return fmt.Errorf("failed to write topics: %v", err)
+	}
 
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("📊 IMPORT STATISTICS")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("⏱️  Duration: %v\n", duration.Round(time.Second))
-	fmt.Printf("🚀 Speed: %.1f rows/second\n", speed)
-	fmt.Printf("📄 Total rows processed: %d\n", stats.TotalRows)
-	fmt.Printf("📝 Words imported: %d\n", stats.WordsImported)
-	fmt.Printf("🏷️  Topics created: %d\n", stats.TopicsCreated)
-	fmt.Printf("💬 Sentences added: %d\n", stats.SentencesAdded)
-	fmt.Printf("❌ Errors encountered: %d\n", stats.ErrorsEncountered)
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Fprintf(os.Stderr, "🏷️  Topics exported: %d\n", len(topics))
+	return nil
 }
 
 func runClearData(cmd *cobra.Command, args []string) error {
@@ -1030,3 +1003,245 @@ func runResetEnrichment(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// connectForCuration connects to the database and makes sure the audit log table exists
+func connectForCuration() (*pg.CurationRepository, error) {
+	db, err := connectToDatabase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.ContentAudit{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate content audit log: %v", err)
+	}
+
+	return pg.NewCurationRepository(db), nil
+}
+
+// cliActor records changes made with the CLI under the OS user running it
+func cliActor() pg.Actor {
+	name := os.Getenv("USER")
+	if u, err := osuser.Current(); err == nil {
+		name = u.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	return pg.Actor{Name: "cli:" + name}
+}
+
+// parseIDs parses comma separated UUIDs from a flag
+func parseIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %q: %v", v, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func runListWords(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
+
+	if curatePage < 1 || curateFilter.Limit < 1 {
+		return fmt.Errorf("page and limit must be positive")
+	}
+	filter := curateFilter
+	filter.Offset = (curatePage - 1) * filter.Limit
+	if curateTopicID != "" {
+		topicID, err := uuid.Parse(curateTopicID)
+		if err != nil {
+			return fmt.Errorf("invalid topic ID: %v", err)
+		}
+		filter.TopicID = &topicID
+	}
+
+	repo, err := connectForCuration()
+	if err != nil {
+		return err
+	}
+
+	words, total, err := repo.ListWords(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list words: %v", err)
+	}
+
+	for _, w := range words {
+		audio := "-"
+		if w.AudioURL != "" {
+			audio = "audio"
+		}
+		fmt.Printf("%s  %-30s %-2s  %-12s %s  sentences: %d  distractors: %d\n",
+			w.ID, w.Word.Word, w.CEFRLevel, w.PartOfSpeech, audio, w.SentenceCount, w.DistractorCount)
+	}
+
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📄 Page %d, %d of %d words\n", curatePage, len(words), total)
+
+	return nil
+}
+
+func runBulkEdit(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
+
+	ids, err := parseIDs(curateIDs)
+	if err != nil {
+		return err
+	}
+
+	var changes pg.WordChanges
+	if cmd.Flags().Changed("cefr") {
+		changes.CEFRLevel = &curateCEFR
+	}
+	if cmd.Flags().Changed("part-of-speech") {
+		changes.PartOfSpeech = &curatePartOfSpeech
+	}
+	if curateTopicID != "" {
+		topicID, err := uuid.Parse(curateTopicID)
+		if err != nil {
+			return fmt.Errorf("invalid topic ID: %v", err)
+		}
+		changes.TopicID = &topicID
+	}
+	changes.ClearTopic = curateClearTopic
+	if changes.CEFRLevel == nil && changes.PartOfSpeech == nil && changes.TopicID == nil && !changes.ClearTopic {
+		return fmt.Errorf("nothing to change, set --cefr, --part-of-speech, --topic or --clear-topic")
+	}
+
+	repo, err := connectForCuration()
+	if err != nil {
+		return err
+	}
+
+	updated, err := repo.BulkUpdateWords(context.Background(), ids, changes, cliActor())
+	if err != nil {
+		return fmt.Errorf("failed to update words: %v", err)
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	fmt.Println("✏️  BULK EDIT STATISTICS")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📄 Words requested: %d\n", len(ids))
+	fmt.Printf("📝 Words changed: %d\n", updated)
+	fmt.Println(strings.Repeat("=", 50))
+
+	return nil
+}
+
+func runMergeWords(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
+
+	targetID, err := uuid.Parse(mergeInto)
+	if err != nil {
+		return fmt.Errorf("invalid target ID: %v", err)
+	}
+	sourceIDs, err := parseIDs(curateIDs)
+	if err != nil {
+		return err
+	}
+
+	repo, err := connectForCuration()
+	if err != nil {
+		return err
+	}
+
+	word, err := repo.MergeWords(context.Background(), targetID, sourceIDs, cliActor())
+	if err != nil {
+		return fmt.Errorf("failed to merge words: %v", err)
+	}
+
+	fmt.Printf("✅ Merged %d words into %q (%s)\n", len(sourceIDs), word.Word, word.ID)
+	return nil
+}
+
+func runReparentTopic(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
+
+	topicID, err := uuid.Parse(reparentTopicID)
+	if err != nil {
+		return fmt.Errorf("invalid topic ID: %v", err)
+	}
+	var parentID *uuid.UUID
+	if reparentParentID != "" {
+		id, err := uuid.Parse(reparentParentID)
+		if err != nil {
+			return fmt.Errorf("invalid parent ID: %v", err)
+		}
+		parentID = &id
+	}
+
+	repo, err := connectForCuration()
+	if err != nil {
+		return err
+	}
+
+	topic, err := repo.ReparentTopic(context.Background(), topicID, parentID, cliActor())
+	if err != nil {
+		return fmt.Errorf("failed to move topic: %v", err)
+	}
+
+	if topic.ParentID == nil {
+		fmt.Printf("✅ %q is now a main topic\n", topic.Title)
+	} else {
+		fmt.Printf("✅ %q moved under %s\n", topic.Title, topic.ParentID)
+	}
+	return nil
+}
+
+func runShowAudit(cmd *cobra.Command, args []string) error {
+	// Initialize config and logger
+	config.Init()
+	logger.Init(true) // Enable debug logging
+	defer logger.Log.Sync()
+
+	if curatePage < 1 || curateFilter.Limit < 1 {
+		return fmt.Errorf("page and limit must be positive")
+	}
+	filter := pg.AuditFilter{
+		Action: auditAction,
+		Offset: (curatePage - 1) * curateFilter.Limit,
+		Limit:  curateFilter.Limit,
+	}
+	if auditEntityID != "" {
+		entityID, err := uuid.Parse(auditEntityID)
+		if err != nil {
+			return fmt.Errorf("invalid entity ID: %v", err)
+		}
+		filter.EntityID = &entityID
+	}
+
+	repo, err := connectForCuration()
+	if err != nil {
+		return err
+	}
+
+	audits, total, err := repo.ListAudits(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list audit log: %v", err)
+	}
+
+	for _, a := range audits {
+		fmt.Printf("%s  %-30s %-15s %s %s  %s\n",
+			a.CreatedAt.Format(time.RFC3339), a.Actor, a.Action, a.EntityType, a.EntityID, string(a.Changes))
+	}
+
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📄 Page %d, %d of %d entries\n", curatePage, len(audits), total)
+
+	return nil
+}
@@ -7,8 +7,11 @@ import (
 	"fluently/go-backend/docs"
 
 	appConfig "fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/jobs"
 	"fluently/go-backend/internal/repository/models"
+	pg "fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/router"
+	"fluently/go-backend/internal/utils"
 	"fluently/go-backend/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
@@ -36,6 +39,11 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apikey ServiceSignature
+// @in header
+// @name X-Service-Signature
+// @description HMAC-SHA256 request signature of an internal service, sent with X-Service-Key, X-Service-Timestamp and X-Service-Nonce.
 func main() {
 	// Config init
 	appConfig.Init()
@@ -68,12 +76,46 @@ func main() {
 		&models.LinkToken{},
 		&models.ChatHistory{},
 		&models.NotLearnedWords{},
+		&models.Deck{},
+		&models.DeckWord{},
+		&models.Capture{},
+		&models.SyncEvent{},
+		&models.UserStats{},
+		&models.XPEvent{},
+		&models.UserAchievement{},
+		&models.Friendship{},
+		&models.FriendInvite{},
+		&models.Classroom{},
+		&models.ClassroomMember{},
+		&models.Assignment{},
+		&models.AssignmentWord{},
+		&models.ServiceTokenAudit{},
+		&models.UserIdentity{},
+		&models.ContentAudit{},
+		&models.ImportRun{},
+		&models.ImportRunItem{},
 	)
 	if err != nil {
 		logger.Log.Fatal("Failed to auto-migrate", zap.Error(err))
 	}
 	logger.Log.Info("Database migration completed successfully")
 
+	// Search still works without the indexes, only slower
+	if err := pg.EnsureSearchIndexes(db); err != nil {
+		logger.Log.Error("Failed to create search indexes", zap.Error(err))
+	}
+
+	// Background jobs worker, disable with JOBS_WORKER_ENABLED=false to run cmd/worker separately
+	if appConfig.GetConfig().Jobs.WorkerEnabled {
+		worker := jobs.NewWorker(jobs.RedisOpt(), utils.NewWordEnrichmentService(db), utils.NewWordAudioService(db), appConfig.GetConfig().Jobs.Concurrency)
+		if err := worker.Start(); err != nil {
+			// Jobs stay in Redis and are processed once a worker is up
+			logger.Log.Error("Failed to start job worker", zap.Error(err))
+		} else {
+			defer worker.Shutdown()
+		}
+	}
+
 	//Init Router with routes
 	r := chi.NewRouter()
 	router.InitRoutes(db, r)
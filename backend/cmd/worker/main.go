@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	appConfig "fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Standalone background jobs worker, run it with JOBS_WORKER_ENABLED=false on the API
+// to process enrichment jobs outside the API process
+func main() {
+	appConfig.Init()
+	logger.Init(true)
+	defer logger.Log.Sync()
+
+	db, err := gorm.Open(postgres.Open(appConfig.GetPostgresDSN()), &gorm.Config{})
+	if err != nil {
+		logger.Log.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	worker := jobs.NewWorker(jobs.RedisOpt(), utils.NewWordEnrichmentService(db), utils.NewWordAudioService(db), appConfig.GetConfig().Jobs.Concurrency)
+	if err := worker.Start(); err != nil {
+		logger.Log.Fatal("Failed to start job worker", zap.Error(err))
+	}
+	logger.Log.Info("Job worker started", zap.Int("concurrency", appConfig.GetConfig().Jobs.Concurrency))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Log.Info("Shutting down job worker")
+	worker.Shutdown()
+}
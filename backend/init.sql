@@ -1,2 +1,3 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp"; -- UUID extension
-CREATE DATABASE sonarqube; -- Create the database for SonarQube
\ No newline at end of file
+CREATE EXTENSION IF NOT EXISTS pg_trgm; -- Trigram similarity for search
+CREATE DATABASE sonarqube; -- Create the database for SonarQube
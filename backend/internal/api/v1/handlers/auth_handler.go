@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/identity"
 	"fluently/go-backend/internal/repository/models"
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/pkg/logger"
@@ -17,6 +19,7 @@ import (
 	"fluently/go-backend/internal/utils"
 
 	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/idtoken"
@@ -28,6 +31,12 @@ type Handlers struct {
 	UserRepo         *postgres.UserRepository
 	UserPrefRepo     *postgres.PreferenceRepository
 	RefreshTokenRepo *postgres.RefreshTokenRepository
+	IdentityRepo     *postgres.IdentityRepository
+	Providers        *identity.Registry
+	Redis            *goredis.Client // state of authorization code flows
+
+	// ValidateGoogleToken validates ID tokens of /auth/google, idtoken.Validate when nil
+	ValidateGoogleToken func(ctx context.Context, idToken, audience string) (*idtoken.Payload, error)
 }
 
 // generateRandomState generates a random state string
@@ -102,7 +111,12 @@ func (h *Handlers) GoogleAuthHandler(w http.ResponseWriter, r *http.Request) {
 
 	audience := googleClientIDs[platform]
 
-	payload, err := idtoken.Validate(r.Context(), googleToken, audience)
+	validate := h.ValidateGoogleToken
+	if validate == nil {
+		validate = idtoken.Validate
+	}
+
+	payload, err := validate(r.Context(), googleToken, audience)
 	if err != nil {
 		logger.Log.Error("Invalid token", zap.Error(err))
 		http.Error(w, "invalid token", http.StatusUnauthorized)
@@ -159,6 +173,9 @@ func (h *Handlers) GoogleAuthHandler(w http.ResponseWriter, r *http.Request) {
 			logger.Log.Error("Failed to update last login time", zap.Error(err))
 		}
 	}
+	if user != nil {
+		h.ensureGoogleIdentity(r, user, sub, email)
+	}
 
 	resp, err := h.generateTokens(user, w, r)
 	if err != nil {
@@ -714,6 +731,7 @@ func processGoogleIDToken(h *Handlers, w http.ResponseWriter, r *http.Request, g
 			logger.Log.Error("Failed to update last login time", zap.Error(err))
 		}
 	}
+	h.ensureGoogleIdentity(r, user, sub, email)
 
 	resp, err := h.generateTokens(user, w, r)
 	if err != nil {
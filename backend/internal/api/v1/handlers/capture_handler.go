@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCapturePageSize = 20
+	maxCapturePageSize     = 100
+	maxCaptureSentence     = 1000
+)
+
+// CaptureHandler handles words captured while reading, e.g. with the browser extension
+type CaptureHandler struct {
+	Repo         *postgres.CaptureRepository
+	WordRepo     *postgres.WordRepository
+	SentenceRepo *postgres.SentenceRepository
+	Jobs         *jobs.Client
+}
+
+// buildCaptureResponse builds a CaptureResponse from a Capture with preloaded word and sentence
+func buildCaptureResponse(c *models.Capture) schemas.CaptureResponse {
+	resp := schemas.CaptureResponse{
+		ID:          c.ID.String(),
+		WordID:      c.WordID.String(),
+		Word:        c.Word.Word,
+		Translation: c.Word.Translation,
+		Personal:    c.Word.OwnerID != nil,
+		SourceURL:   c.SourceURL,
+		SourceTitle: c.SourceTitle,
+		Queued:      c.Queued,
+		CreatedAt:   c.CreatedAt,
+	}
+
+	if c.Sentence != nil {
+		resp.Sentence = c.Sentence.Sentence
+	}
+
+	return resp
+}
+
+// normalizeSelection trims whitespace and surrounding punctuation from a selection
+// and collapses inner whitespace, so "  Serendipity," and "serendipity" match
+func normalizeSelection(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '-' && r != '\''
+	})
+
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// CreateCapture godoc
+// @Summary      Capture a word
+// @Description  Saves a word met while reading together with its sentence and source page.
+// @Description  The word is matched against the dictionary or created as a personal word and queued into the next lesson.
+// @Description  Capturing the same word in the same sentence again returns the existing capture.
+// @Tags         capture
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        capture  body      schemas.CaptureRequest  true  "Captured word"
+// @Success      200      {object}  schemas.CaptureResponse  "Already captured"
+// @Success      201      {object}  schemas.CaptureResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/capture [post]
+func (h *CaptureHandler) CreateCapture(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/capture"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.CaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	selection := normalizeSelection(req.Word)
+	if selection == "" {
+		statusCode = 400
+		http.Error(w, "word is required", http.StatusBadRequest)
+		return
+	}
+
+	if utf8.RuneCountInString(selection) > maxWordLength {
+		statusCode = 400
+		http.Error(w, "word is too long", http.StatusBadRequest)
+		return
+	}
+
+	sentenceText := strings.Join(strings.Fields(req.Sentence), " ")
+	if utf8.RuneCountInString(sentenceText) > maxCaptureSentence {
+		statusCode = 400
+		http.Error(w, "sentence is too long", http.StatusBadRequest)
+		return
+	}
+
+	if utf8.RuneCountInString(req.SourceTitle) > 255 {
+		req.SourceTitle = string([]rune(req.SourceTitle)[:255])
+	}
+
+	// Look the word up in the shared dictionary first, then in the user's own words
+	created := false
+	word, err := h.WordRepo.GetByValue(r.Context(), selection)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		word, err = h.WordRepo.GetPersonalByValue(r.Context(), user.ID, selection)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		word = &models.Word{
+			ID:      uuid.New(),
+			Word:    selection,
+			OwnerID: &user.ID,
+		}
+		if req.Translation != nil {
+			word.Translation = strings.TrimSpace(*req.Translation)
+		}
+
+		err = h.WordRepo.Create(r.Context(), word)
+		created = err == nil
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to resolve captured word", zap.Error(err), zap.String("word", selection))
+		http.Error(w, "failed to save word", http.StatusInternalServerError)
+		return
+	}
+
+	// Store the real-world sentence as the user's own sentence for the word
+	var sentenceID *uuid.UUID
+	if sentenceText != "" {
+		sentence, err := h.SentenceRepo.GetUserSentence(r.Context(), user.ID, word.ID, sentenceText)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			sentence = &models.Sentence{
+				ID:        uuid.New(),
+				WordID:    word.ID,
+				Sentence:  sentenceText,
+				UserID:    &user.ID,
+				SourceURL: req.SourceURL,
+			}
+			err = h.SentenceRepo.Create(r.Context(), sentence)
+		}
+		if err != nil {
+			statusCode = 500
+			logger.Log.Error("Failed to save captured sentence", zap.Error(err), zap.String("word_id", word.ID.String()))
+			http.Error(w, "failed to save sentence", http.StatusInternalServerError)
+			return
+		}
+		sentenceID = &sentence.ID
+	}
+
+	// Enrich new words once the captured sentence is saved so it gets distractors too
+	if created {
+		enqueueWordEnrichment(r.Context(), h.Jobs, word.ID)
+	}
+
+	if existing, err := h.Repo.FindDuplicate(r.Context(), user.ID, word.ID, sentenceID); err == nil {
+		statusCode = 200
+		resp := buildCaptureResponse(existing)
+		resp.Duplicate = true
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 500
+		logger.Log.Error("Failed to check capture duplicate", zap.Error(err))
+		http.Error(w, "failed to save capture", http.StatusInternalServerError)
+		return
+	}
+
+	capture := models.Capture{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		WordID:      word.ID,
+		SentenceID:  sentenceID,
+		Selection:   selection,
+		SourceURL:   req.SourceURL,
+		SourceTitle: req.SourceTitle,
+		Queued:      true,
+	}
+	if err := h.Repo.Create(r.Context(), &capture); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to create capture", zap.Error(err))
+		http.Error(w, "failed to save capture", http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := h.Repo.GetByID(r.Context(), capture.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch capture", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildCaptureResponse(saved))
+}
+
+// ListCaptures godoc
+// @Summary      List captured words
+// @Description  Returns a page of the capture inbox of the authenticated user, newest first unless sorted by created_at
+// @Tags         capture
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sort    query     string  false  "Sort, -created_at (default) is newest first"  Enums(-created_at, created_at)
+// @Param        limit   query     int     false  "Page size, up to 100"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200    {object}  schemas.CaptureListResponse
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/capture [get]
+func (h *CaptureHandler) ListCaptures(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/capture"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := utils.ParseListParams(r, defaultCapturePageSize, maxCapturePageSize, "-created_at")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.Repo.CountByUserID(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to count captures", http.StatusInternalServerError)
+		return
+	}
+
+	captures, next, err := h.Repo.ListByUserID(r.Context(), user.ID, postgres.Page{
+		Sort:  params.Sort,
+		Desc:  params.Desc,
+		After: params.After,
+		Limit: params.Limit,
+	})
+	if errors.Is(err, postgres.ErrInvalidCursor) {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch captures", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.CaptureListResponse{
+		Items:      make([]schemas.CaptureResponse, 0, len(captures)),
+		Total:      total,
+		NextCursor: params.NextCursor(next),
+	}
+	for _, c := range captures {
+		resp.Items = append(resp.Items, buildCaptureResponse(&c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,731 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	joinCodeLength     = 8
+	maxAssignmentWords = 500
+)
+
+// joinCodeAlphabet has no look-alike characters, codes are dictated in class
+const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// ClassroomHandler handles classrooms, assignments and the teacher's dashboard
+type ClassroomHandler struct {
+	Repo      *postgres.ClassroomRepository
+	TopicRepo *postgres.TopicRepository
+}
+
+// generateJoinCode generates a random classroom join code
+func generateJoinCode() (string, error) {
+	code := make([]byte, joinCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(joinCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = joinCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// buildClassroomResponse builds a ClassroomResponse, the join code is only shown to the teacher
+func buildClassroomResponse(classroom *models.Classroom, userID uuid.UUID) schemas.ClassroomResponse {
+	resp := schemas.ClassroomResponse{
+		ID:          classroom.ID,
+		Name:        classroom.Name,
+		TeacherID:   classroom.TeacherID,
+		TeacherName: classroom.Teacher.Name,
+		IsTeacher:   classroom.TeacherID == userID,
+		CreatedAt:   classroom.CreatedAt,
+	}
+	if resp.IsTeacher {
+		resp.JoinCode = classroom.JoinCode
+	}
+	return resp
+}
+
+// buildAssignmentResponse builds an AssignmentResponse from an Assignment with its words
+func buildAssignmentResponse(assignment *models.Assignment) schemas.AssignmentResponse {
+	resp := schemas.AssignmentResponse{
+		ID:        assignment.ID,
+		Title:     assignment.Title,
+		TopicID:   assignment.TopicID,
+		WordIDs:   make([]uuid.UUID, 0, len(assignment.Words)),
+		DueAt:     assignment.DueAt,
+		CreatedAt: assignment.CreatedAt,
+	}
+	for _, w := range assignment.Words {
+		resp.WordIDs = append(resp.WordIDs, w.WordID)
+	}
+	return resp
+}
+
+// CreateClassroom godoc
+// @Summary      Create classroom
+// @Description  Creates a classroom taught by the user with a join code for students. Requires the teacher or admin role.
+// @Tags         classrooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.CreateClassroomRequest  true  "Classroom data"
+// @Success      201      {object}  schemas.ClassroomResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms [post]
+func (h *ClassroomHandler) CreateClassroom(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.CreateClassroomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > 100 {
+		statusCode = 400
+		http.Error(w, "name must be between 1 and 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	code, err := generateJoinCode()
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to create classroom", http.StatusInternalServerError)
+		return
+	}
+
+	classroom := models.Classroom{
+		ID:        uuid.New(),
+		TeacherID: user.ID,
+		Name:      req.Name,
+		JoinCode:  code,
+	}
+	if err := h.Repo.Create(r.Context(), &classroom); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to create classroom", zap.Error(err))
+		http.Error(w, "failed to create classroom", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildClassroomResponse(&classroom, user.ID))
+}
+
+// ListClassrooms godoc
+// @Summary      List classrooms
+// @Description  Returns classrooms the user teaches followed by classrooms they joined as a student
+// @Tags         classrooms
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   schemas.ClassroomResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms [get]
+func (h *ClassroomHandler) ListClassrooms(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	taught, err := h.Repo.ListByTeacher(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list taught classrooms", zap.Error(err))
+		http.Error(w, "failed to list classrooms", http.StatusInternalServerError)
+		return
+	}
+
+	joined, err := h.Repo.ListByMember(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list joined classrooms", zap.Error(err))
+		http.Error(w, "failed to list classrooms", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.ClassroomResponse, 0, len(taught)+len(joined))
+	for _, classroom := range taught {
+		item := buildClassroomResponse(&classroom, user.ID)
+		item.Students, err = h.Repo.CountMembers(r.Context(), classroom.ID)
+		if err != nil {
+			statusCode = 500
+			logger.Log.Error("Failed to count classroom students", zap.Error(err))
+			http.Error(w, "failed to list classrooms", http.StatusInternalServerError)
+			return
+		}
+		resp = append(resp, item)
+	}
+	for _, classroom := range joined {
+		resp = append(resp, buildClassroomResponse(&classroom, user.ID))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// JoinClassroom godoc
+// @Summary      Join classroom
+// @Description  Joins a classroom as a student by its join code. Joining a classroom again is a no-op.
+// @Tags         classrooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.JoinClassroomRequest  true  "Join code"
+// @Success      200      {object}  schemas.ClassroomResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/join [post]
+func (h *ClassroomHandler) JoinClassroom(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/join"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.JoinClassroomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Code) == "" {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	classroom, err := h.Repo.GetByJoinCode(r.Context(), strings.ToUpper(strings.TrimSpace(req.Code)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 404
+		http.Error(w, "classroom not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get classroom by code", zap.Error(err))
+		http.Error(w, "failed to join classroom", http.StatusInternalServerError)
+		return
+	}
+
+	if classroom.TeacherID == user.ID {
+		statusCode = 400
+		http.Error(w, "cannot join your own classroom", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.AddMember(r.Context(), classroom.ID, user.ID); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to add classroom member", zap.Error(err))
+		http.Error(w, "failed to join classroom", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildClassroomResponse(classroom, user.ID))
+}
+
+// RemoveStudent godoc
+// @Summary      Remove student
+// @Description  Removes a student from a classroom. Teachers remove their students, students leave with their own ID.
+// @Tags         classrooms
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Classroom ID"
+// @Param        user_id  path  string  true  "Student's user ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/{id}/students/{user_id} [delete]
+func (h *ClassroomHandler) RemoveStudent(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/{id}/students/{user_id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	studentID, err := utils.ParseUUIDParam(r, "user_id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return
+	}
+
+	if studentID != user.ID {
+		if _, ok := h.getManagedClassroom(r.Context(), user, id); !ok {
+			statusCode = 404
+			http.Error(w, "classroom not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if err := h.Repo.RemoveMember(r.Context(), id, studentID); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to remove classroom member", zap.Error(err))
+		http.Error(w, "failed to remove student", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAssignments godoc
+// @Summary      List assignments
+// @Description  Returns assignments of a classroom, soonest due first. Available to the teacher and the students.
+// @Tags         classrooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Classroom ID"
+// @Success      200  {array}   schemas.AssignmentResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/{id}/assignments [get]
+func (h *ClassroomHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/{id}/assignments"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getManagedClassroom(r.Context(), user, id); !ok {
+		member, err := h.Repo.IsMember(r.Context(), id, user.ID)
+		if err != nil || !member {
+			statusCode = 404
+			http.Error(w, "classroom not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	assignments, err := h.Repo.ListAssignments(r.Context(), id)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list assignments", zap.Error(err))
+		http.Error(w, "failed to list assignments", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.AssignmentResponse, 0, len(assignments))
+	for _, assignment := range assignments {
+		resp = append(resp, buildAssignmentResponse(&assignment))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateAssignment godoc
+// @Summary      Create assignment
+// @Description  Assigns a topic or a list of words to the classroom with a due date. A topic is assigned as its current global words.
+// @Description  Assigned words go first in the students' lessons until they learn them, soonest due first.
+// @Tags         classrooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                           true  "Classroom ID"
+// @Param        request  body      schemas.CreateAssignmentRequest  true  "Assignment data"
+// @Success      201      {object}  schemas.AssignmentResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/{id}/assignments [post]
+func (h *ClassroomHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/{id}/assignments"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	classroom, ok := h.getManagedClassroom(r.Context(), user, id)
+	if !ok {
+		statusCode = 404
+		http.Error(w, "classroom not found", http.StatusNotFound)
+		return
+	}
+
+	var req schemas.CreateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if (req.TopicID == nil) == (len(req.WordIDs) == 0) {
+		statusCode = 400
+		http.Error(w, "either topic_id or word_ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.WordIDs) > maxAssignmentWords {
+		statusCode = 400
+		http.Error(w, "at most 500 words can be assigned at once", http.StatusBadRequest)
+		return
+	}
+	if !req.DueAt.After(time.Now()) {
+		statusCode = 400
+		http.Error(w, "due_at must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	if utf8.RuneCountInString(req.Title) > 100 {
+		statusCode = 400
+		http.Error(w, "title must be at most 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	var wordIDs []uuid.UUID
+	if req.TopicID != nil {
+		topic, err := h.TopicRepo.GetByID(r.Context(), *req.TopicID)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "topic not found", http.StatusBadRequest)
+			return
+		}
+		if req.Title == "" {
+			req.Title = topic.Title
+		}
+
+		wordIDs, err = h.Repo.TopicWordIDs(r.Context(), topic.ID)
+	} else {
+		// Words the teacher can't see are dropped, students only get global words and the teacher's own
+		wordIDs, err = h.Repo.AssignableWordIDs(r.Context(), classroom.TeacherID, req.WordIDs)
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get assignment words", zap.Error(err))
+		http.Error(w, "failed to create assignment", http.StatusInternalServerError)
+		return
+	}
+	if len(wordIDs) == 0 {
+		statusCode = 400
+		http.Error(w, "no words to assign", http.StatusBadRequest)
+		return
+	}
+	if req.Title == "" {
+		statusCode = 400
+		http.Error(w, "title is required for word lists", http.StatusBadRequest)
+		return
+	}
+
+	assignment := models.Assignment{
+		ID:          uuid.New(),
+		ClassroomID: classroom.ID,
+		Title:       req.Title,
+		TopicID:     req.TopicID,
+		DueAt:       req.DueAt.UTC(),
+	}
+	if err := h.Repo.CreateAssignment(r.Context(), &assignment, wordIDs); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to create assignment", zap.Error(err))
+		http.Error(w, "failed to create assignment", http.StatusInternalServerError)
+		return
+	}
+
+	for _, wordID := range wordIDs {
+		assignment.Words = append(assignment.Words, models.AssignmentWord{AssignmentID: assignment.ID, WordID: wordID})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildAssignmentResponse(&assignment))
+}
+
+// DeleteAssignment godoc
+// @Summary      Delete assignment
+// @Description  Deletes an assignment of the classroom, its words stop being prioritized in lessons
+// @Tags         classrooms
+// @Security     BearerAuth
+// @Param        id             path  string  true  "Classroom ID"
+// @Param        assignment_id  path  string  true  "Assignment ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/{id}/assignments/{assignment_id} [delete]
+func (h *ClassroomHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/{id}/assignments/{assignment_id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	assignmentID, err := utils.ParseUUIDParam(r, "assignment_id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid assignment_id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getManagedClassroom(r.Context(), user, id); !ok {
+		statusCode = 404
+		http.Error(w, "classroom not found", http.StatusNotFound)
+		return
+	}
+
+	assignment, err := h.Repo.GetAssignment(r.Context(), assignmentID)
+	if err != nil || assignment.ClassroomID != id {
+		statusCode = 404
+		http.Error(w, "assignment not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeleteAssignment(r.Context(), assignment.ID); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to delete assignment", zap.Error(err))
+		http.Error(w, "failed to delete assignment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProgress godoc
+// @Summary      Get classroom progress
+// @Description  Returns the teacher's dashboard: every student's progress on every assignment, counted from learned and not learned words.
+// @Description  Words a student got wrong and hasn't learned since are counted as not learned.
+// @Tags         classrooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Classroom ID"
+// @Success      200  {object}  schemas.ClassroomProgressResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/classrooms/{id}/progress [get]
+func (h *ClassroomHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/classrooms/{id}/progress"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getManagedClassroom(r.Context(), user, id); !ok {
+		statusCode = 404
+		http.Error(w, "classroom not found", http.StatusNotFound)
+		return
+	}
+
+	members, err := h.Repo.ListMembers(r.Context(), id)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list classroom members", zap.Error(err))
+		http.Error(w, "failed to get progress", http.StatusInternalServerError)
+		return
+	}
+
+	assignments, err := h.Repo.ListAssignments(r.Context(), id)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list assignments", zap.Error(err))
+		http.Error(w, "failed to get progress", http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := h.Repo.ListProgress(r.Context(), id)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get classroom progress", zap.Error(err))
+		http.Error(w, "failed to get progress", http.StatusInternalServerError)
+		return
+	}
+
+	type progressKey struct{ userID, assignmentID uuid.UUID }
+	byKey := make(map[progressKey]postgres.AssignmentProgress, len(progress))
+	for _, p := range progress {
+		byKey[progressKey{p.UserID, p.AssignmentID}] = p
+	}
+
+	now := time.Now()
+	resp := schemas.ClassroomProgressResponse{
+		ClassroomID: id,
+		Students:    make([]schemas.StudentProgressResponse, 0, len(members)),
+	}
+	for _, m := range members {
+		student := schemas.StudentProgressResponse{
+			UserID:      m.UserID,
+			Name:        m.User.Name,
+			JoinedAt:    m.JoinedAt,
+			Assignments: make([]schemas.AssignmentProgressResponse, 0, len(assignments)),
+		}
+		for _, a := range assignments {
+			p := byKey[progressKey{m.UserID, a.ID}]
+			item := schemas.AssignmentProgressResponse{
+				AssignmentID: a.ID,
+				Title:        a.Title,
+				DueAt:        a.DueAt,
+				Total:        len(a.Words),
+				Learned:      p.Learned,
+				NotLearned:   p.NotLearned,
+				Completed:    p.Learned >= len(a.Words),
+			}
+			item.Overdue = !item.Completed && now.After(a.DueAt)
+
+			student.Total += item.Total
+			student.Learned += item.Learned
+			student.NotLearned += item.NotLearned
+			student.Assignments = append(student.Assignments, item)
+		}
+		resp.Students = append(resp.Students, student)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// getManagedClassroom returns a classroom the user teaches, admins manage every classroom
+func (h *ClassroomHandler) getManagedClassroom(ctx context.Context, user *models.User, id uuid.UUID) (*models.Classroom, bool) {
+	classroom, err := h.Repo.GetByID(ctx, id)
+	if err != nil || (classroom.TeacherID != user.ID && user.Role != "admin") {
+		return nil, false
+	}
+
+	return classroom, true
+}
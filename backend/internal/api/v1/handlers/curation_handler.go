@@ -0,0 +1,536 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCurationPageSize = 50
+	maxCurationPageSize     = 200
+	maxBulkEditWords        = 1000
+)
+
+// cefrLevels are the levels words can be tagged with
+var cefrLevels = map[string]bool{"A1": true, "A2": true, "B1": true, "B2": true, "C1": true, "C2": true}
+
+// CurationHandler handles the admin API for curating global words and topics
+type CurationHandler struct {
+	Repo *postgres.CurationRepository
+}
+
+// parseCurationPage parses the page and limit query parameters
+func parseCurationPage(r *http.Request) (page, limit int, err error) {
+	page = 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return 0, 0, errors.New("invalid page")
+		}
+	}
+
+	limit = defaultCurationPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("invalid limit")
+		}
+		if limit > maxCurationPageSize {
+			limit = maxCurationPageSize
+		}
+	}
+
+	return page, limit, nil
+}
+
+// parseUUIDs parses a list of ids from a request body
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// curationActor returns the admin making the request for the audit log
+func curationActor(r *http.Request) (postgres.Actor, error) {
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		return postgres.Actor{}, err
+	}
+	return postgres.Actor{UserID: &user.ID, Name: user.Email}, nil
+}
+
+// buildAdminWordResponse builds an AdminWordResponse from a curated word
+func buildAdminWordResponse(w *postgres.CuratedWord) schemas.AdminWordResponse {
+	resp := schemas.AdminWordResponse{
+		WordResponse:    buildWordResponse(&w.Word),
+		SentenceCount:   w.SentenceCount,
+		DistractorCount: w.DistractorCount,
+	}
+	if w.TopicID != nil {
+		topicID := w.TopicID.String()
+		resp.TopicID = &topicID
+	}
+	return resp
+}
+
+// ListCurationWords godoc
+// @Summary      List words for curation
+// @Description  Returns a page of global words, optionally only the ones missing audio, sentences or distractors. Admin only.
+// @Tags         admin-content
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cefr_level           query     string  false  "CEFR level"  Enums(A1, A2, B1, B2, C1, C2)
+// @Param        topic_id             query     string  false  "Topic ID"
+// @Param        q                    query     string  false  "Prefix of the word"
+// @Param        missing_audio        query     bool    false  "Only words without audio"
+// @Param        missing_sentences    query     bool    false  "Only words without sentences"
+// @Param        missing_distractors  query     bool    false  "Only words without distractors"
+// @Param        page                 query     int     false  "Page number, starting from 1"
+// @Param        limit                query     int     false  "Page size, up to 200"
+// @Success      200                  {object}  schemas.AdminWordListResponse
+// @Failure      400                  {object}  schemas.ErrorResponse
+// @Failure      401                  {object}  schemas.ErrorResponse
+// @Failure      403                  {object}  schemas.ErrorResponse
+// @Failure      500                  {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/content/words [get]
+func (h *CurationHandler) ListWords(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/content/words"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	page, limit, err := parseCurationPage(r)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := postgres.WordFilter{
+		CEFRLevel: query.Get("cefr_level"),
+		Search:    query.Get("q"),
+		Offset:    (page - 1) * limit,
+		Limit:     limit,
+	}
+	if filter.CEFRLevel != "" && !cefrLevels[filter.CEFRLevel] {
+		statusCode = 400
+		http.Error(w, "invalid cefr_level", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("topic_id"); v != "" {
+		topicID, err := uuid.Parse(v)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid topic_id", http.StatusBadRequest)
+			return
+		}
+		filter.TopicID = &topicID
+	}
+	for name, flag := range map[string]*bool{
+		"missing_audio":       &filter.MissingAudio,
+		"missing_sentences":   &filter.MissingSentences,
+		"missing_distractors": &filter.MissingDistractors,
+	} {
+		if v := query.Get(name); v != "" {
+			*flag, err = strconv.ParseBool(v)
+			if err != nil {
+				statusCode = 400
+				http.Error(w, "invalid "+name, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	words, total, err := h.Repo.ListWords(r.Context(), filter)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list words for curation", zap.Error(err))
+		http.Error(w, "failed to list words", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.AdminWordListResponse{
+		Words: make([]schemas.AdminWordResponse, 0, len(words)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for i := range words {
+		resp.Words = append(resp.Words, buildAdminWordResponse(&words[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// BulkUpdateWords godoc
+// @Summary      Bulk edit words
+// @Description  Sets the CEFR level, part of speech or topic of several global words at once. Every changed word is recorded in the audit log. Admin only.
+// @Tags         admin-content
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.BulkWordUpdateRequest  true  "Words and changes"
+// @Success      200      {object}  schemas.BulkWordUpdateResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/content/words [patch]
+func (h *CurationHandler) BulkUpdateWords(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/content/words"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	actor, err := curationActor(r)
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.BulkWordUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > maxBulkEditWords {
+		statusCode = 400
+		http.Error(w, "ids must hold 1 to "+strconv.Itoa(maxBulkEditWords)+" words", http.StatusBadRequest)
+		return
+	}
+	ids, err := parseUUIDs(req.IDs)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid word id", http.StatusBadRequest)
+		return
+	}
+
+	changes := postgres.WordChanges{
+		CEFRLevel:    req.CEFRLevel,
+		PartOfSpeech: req.PartOfSpeech,
+		ClearTopic:   req.ClearTopic,
+	}
+	if changes.CEFRLevel != nil && !cefrLevels[*changes.CEFRLevel] {
+		statusCode = 400
+		http.Error(w, "invalid cefr_level", http.StatusBadRequest)
+		return
+	}
+	if changes.PartOfSpeech != nil && (*changes.PartOfSpeech == "" || len(*changes.PartOfSpeech) > 30) {
+		statusCode = 400
+		http.Error(w, "invalid part_of_speech", http.StatusBadRequest)
+		return
+	}
+	if req.TopicID != nil && !req.ClearTopic {
+		topicID, err := uuid.Parse(*req.TopicID)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid topic_id", http.StatusBadRequest)
+			return
+		}
+		changes.TopicID = &topicID
+	}
+	if changes.CEFRLevel == nil && changes.PartOfSpeech == nil && changes.TopicID == nil && !changes.ClearTopic {
+		statusCode = 400
+		http.Error(w, "nothing to change", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.Repo.BulkUpdateWords(r.Context(), ids, changes, actor)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 404
+		http.Error(w, "topic not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to bulk edit words", zap.Error(err))
+		http.Error(w, "failed to update words", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Log.Info("Words bulk edited",
+		zap.String("actor", actor.Name),
+		zap.Int("requested", len(ids)),
+		zap.Int("updated", updated))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas.BulkWordUpdateResponse{Updated: updated})
+}
+
+// MergeWords godoc
+// @Summary      Merge duplicate words
+// @Description  Moves sentences, distractors, progress, captures, deck and assignment entries of the duplicates to the target word, fills its empty fields from them and deletes the duplicates. Admin only.
+// @Tags         admin-content
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.MergeWordsRequest  true  "Target word and duplicates"
+// @Success      200      {object}  schemas.AdminWordResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/content/words/merge [post]
+func (h *CurationHandler) MergeWords(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/content/words/merge"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	actor, err := curationActor(r)
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.MergeWordsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid target_id", http.StatusBadRequest)
+		return
+	}
+	sourceIDs, err := parseUUIDs(req.SourceIDs)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid source id", http.StatusBadRequest)
+		return
+	}
+
+	word, err := h.Repo.MergeWords(r.Context(), targetID, sourceIDs, actor)
+	if errors.Is(err, postgres.ErrMergeInvalid) {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to merge words", zap.Error(err), zap.String("target_id", targetID.String()))
+		http.Error(w, "failed to merge words", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Log.Info("Words merged",
+		zap.String("actor", actor.Name),
+		zap.String("target_id", targetID.String()),
+		zap.Int("merged", len(sourceIDs)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildAdminWordResponse(&postgres.CuratedWord{Word: *word}))
+}
+
+// ReparentTopic godoc
+// @Summary      Move topic
+// @Description  Moves a topic under another one, an empty parent makes it a main topic. A topic can't be moved under its own subtopics. Admin only.
+// @Tags         admin-content
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "Topic ID"
+// @Param        request  body      schemas.ReparentTopicRequest  true  "New parent"
+// @Success      200      {object}  schemas.TopicResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      409      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/content/topics/{id}/parent [put]
+func (h *CurationHandler) ReparentTopic(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/content/topics/{id}/parent"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	actor, err := curationActor(r)
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	topicID, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req schemas.ReparentTopicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var parentID *uuid.UUID
+	if req.ParentID != nil && *req.ParentID != "" {
+		id, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid parent_id", http.StatusBadRequest)
+			return
+		}
+		parentID = &id
+	}
+
+	topic, err := h.Repo.ReparentTopic(r.Context(), topicID, parentID, actor)
+	if errors.Is(err, postgres.ErrTopicCycle) {
+		statusCode = 409
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 404
+		http.Error(w, "topic not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to move topic", zap.Error(err), zap.String("topic_id", topicID.String()))
+		http.Error(w, "failed to move topic", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildTopicResponse(topic))
+}
+
+// ListContentAudit godoc
+// @Summary      List content audit log
+// @Description  Returns who changed which words and topics, newest first. Admin only.
+// @Tags         admin-content
+// @Produce      json
+// @Security     BearerAuth
+// @Param        entity_id  query     string  false  "Word or topic ID"
+// @Param        action     query     string  false  "Action"  Enums(word.update, word.merge, topic.reparent)
+// @Param        page       query     int     false  "Page number, starting from 1"
+// @Param        limit      query     int     false  "Page size, up to 200"
+// @Success      200        {object}  schemas.ContentAuditListResponse
+// @Failure      400        {object}  schemas.ErrorResponse
+// @Failure      401        {object}  schemas.ErrorResponse
+// @Failure      403        {object}  schemas.ErrorResponse
+// @Failure      500        {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/content/audit [get]
+func (h *CurationHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/content/audit"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	page, limit, err := parseCurationPage(r)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := postgres.AuditFilter{
+		Action: r.URL.Query().Get("action"),
+		Offset: (page - 1) * limit,
+		Limit:  limit,
+	}
+	if v := r.URL.Query().Get("entity_id"); v != "" {
+		entityID, err := uuid.Parse(v)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid entity_id", http.StatusBadRequest)
+			return
+		}
+		filter.EntityID = &entityID
+	}
+
+	audits, total, err := h.Repo.ListAudits(r.Context(), filter)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list content audit", zap.Error(err))
+		http.Error(w, "failed to list audit log", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.ContentAuditListResponse{
+		Entries: make([]schemas.ContentAuditResponse, 0, len(audits)),
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}
+	for _, a := range audits {
+		resp.Entries = append(resp.Entries, buildContentAuditResponse(&a))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildContentAuditResponse builds a ContentAuditResponse from an audit entry
+func buildContentAuditResponse(a *models.ContentAudit) schemas.ContentAuditResponse {
+	resp := schemas.ContentAuditResponse{
+		ID:         a.ID.String(),
+		Actor:      a.Actor,
+		Action:     a.Action,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID.String(),
+		Changes:    json.RawMessage(a.Changes),
+		CreatedAt:  a.CreatedAt,
+	}
+	if a.ActorID != nil {
+		actorID := a.ActorID.String()
+		resp.ActorID = &actorID
+	}
+	return resp
+}
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// DeckHandler handles user decks
+type DeckHandler struct {
+	Repo     *postgres.DeckRepository
+	WordRepo *postgres.WordRepository
+}
+
+// buildDeckResponse builds a DeckResponse from a Deck
+func buildDeckResponse(deck *models.Deck, wordCount int64) schemas.DeckResponse {
+	return schemas.DeckResponse{
+		ID:          deck.ID.String(),
+		Name:        deck.Name,
+		Description: deck.Description,
+		WordCount:   wordCount,
+		CreatedAt:   deck.CreatedAt,
+	}
+}
+
+// ListDecks godoc
+// @Summary      List decks
+// @Description  Returns decks of the authenticated user
+// @Tags         decks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   schemas.DeckResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks [get]
+func (h *DeckHandler) ListDecks(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	decks, err := h.Repo.ListByUserID(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch decks", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.DeckResponse, 0, len(decks))
+	for _, deck := range decks {
+		count, err := h.Repo.CountWords(r.Context(), deck.ID)
+		if err != nil {
+			statusCode = 500
+			http.Error(w, "failed to count deck words", http.StatusInternalServerError)
+			return
+		}
+		resp = append(resp, buildDeckResponse(&deck, count))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetDeck godoc
+// @Summary      Get deck
+// @Description  Returns a deck of the authenticated user with its words
+// @Tags         decks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Deck ID"
+// @Success      200  {object}  schemas.DeckResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks/{id} [get]
+func (h *DeckHandler) GetDeck(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks/{id}"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	deck, ok := h.getOwnedDeck(r.Context(), user.ID, id)
+	if !ok {
+		statusCode = 404
+		http.Error(w, "deck not found", http.StatusNotFound)
+		return
+	}
+
+	words, err := h.Repo.ListWords(r.Context(), deck.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch deck words", http.StatusInternalServerError)
+		return
+	}
+
+	resp := buildDeckResponse(deck, int64(len(words)))
+	for _, word := range words {
+		resp.Words = append(resp.Words, buildWordResponse(&word))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateDeck godoc
+// @Summary      Create deck
+// @Description  Creates a named deck for the authenticated user
+// @Tags         decks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.DeckRequest  true  "Deck data"
+// @Success      201  {object}  schemas.DeckResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks [post]
+func (h *DeckHandler) CreateDeck(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.DeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		statusCode = 400
+		http.Error(w, "name must be between 1 and 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	deck := models.Deck{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Name:   req.Name,
+	}
+
+	if req.Description != nil {
+		deck.Description = *req.Description
+	}
+
+	if err := h.Repo.Create(r.Context(), &deck); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to create deck", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildDeckResponse(&deck, 0))
+}
+
+// UpdateDeck godoc
+// @Summary      Update deck
+// @Description  Renames a deck or changes its description
+// @Tags         decks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string               true  "Deck ID"
+// @Param        request  body      schemas.DeckRequest  true  "Deck data"
+// @Success      200  {object}  schemas.DeckResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks/{id} [put]
+func (h *DeckHandler) UpdateDeck(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks/{id}"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req schemas.DeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		statusCode = 400
+		http.Error(w, "name must be between 1 and 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	deck, ok := h.getOwnedDeck(r.Context(), user.ID, id)
+	if !ok {
+		statusCode = 404
+		http.Error(w, "deck not found", http.StatusNotFound)
+		return
+	}
+
+	deck.Name = req.Name
+	if req.Description != nil {
+		deck.Description = *req.Description
+	}
+
+	if err := h.Repo.Update(r.Context(), deck); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to update deck", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := h.Repo.CountWords(r.Context(), deck.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to count deck words", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildDeckResponse(deck, count))
+}
+
+// DeleteDeck godoc
+// @Summary      Delete deck
+// @Description  Deletes a deck. Words in the deck are kept.
+// @Tags         decks
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Deck ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks/{id} [delete]
+func (h *DeckHandler) DeleteDeck(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks/{id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getOwnedDeck(r.Context(), user.ID, id); !ok {
+		statusCode = 404
+		http.Error(w, "deck not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.Delete(r.Context(), id); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to delete deck", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddDeckWord godoc
+// @Summary      Add word to deck
+// @Description  Adds a global word or a personal word of the user to a deck
+// @Tags         decks
+// @Accept       json
+// @Security     BearerAuth
+// @Param        id       path  string                      true  "Deck ID"
+// @Param        request  body  schemas.AddDeckWordRequest  true  "Word to add"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks/{id}/words [post]
+func (h *DeckHandler) AddDeckWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks/{id}/words"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req schemas.AddDeckWordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WordID == uuid.Nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getOwnedDeck(r.Context(), user.ID, id); !ok {
+		statusCode = 404
+		http.Error(w, "deck not found", http.StatusNotFound)
+		return
+	}
+
+	word, err := h.WordRepo.GetByID(r.Context(), req.WordID)
+	if err != nil || (word.OwnerID != nil && *word.OwnerID != user.ID) {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.AddWord(r.Context(), id, word.ID); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to add word to deck", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveDeckWord godoc
+// @Summary      Remove word from deck
+// @Description  Removes a word from a deck without deleting the word
+// @Tags         decks
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Deck ID"
+// @Param        word_id  path  string  true  "Word ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/decks/{id}/words/{word_id} [delete]
+func (h *DeckHandler) RemoveDeckWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/decks/{id}/words/{word_id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	wordID, err := utils.ParseUUIDParam(r, "word_id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid word id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getOwnedDeck(r.Context(), user.ID, id); !ok {
+		statusCode = 404
+		http.Error(w, "deck not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.RemoveWord(r.Context(), id, wordID); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to remove word from deck", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwnedDeck returns the deck if it exists and belongs to the user
+func (h *DeckHandler) getOwnedDeck(ctx context.Context, userID, deckID uuid.UUID) (*models.Deck, bool) {
+	deck, err := h.Repo.GetByID(ctx, deckID)
+	if err != nil || deck.UserID != userID {
+		return nil, false
+	}
+
+	return deck, true
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// friendInviteTTL is how long an invite link can be accepted
+const friendInviteTTL = 7 * 24 * time.Hour
+
+// FriendHandler handles friends and friend invites
+type FriendHandler struct {
+	Repo     *postgres.FriendRepository
+	UserRepo *postgres.UserRepository
+}
+
+// generateInviteToken generates a random invite token, safe for Telegram start links
+func generateInviteToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ListFriends godoc
+// @Summary      List friends
+// @Description  Returns the user's friends, oldest friendships first
+// @Tags         friends
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   schemas.FriendResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/friends [get]
+func (h *FriendHandler) ListFriends(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/friends"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	friendships, err := h.Repo.List(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list friends", zap.Error(err))
+		http.Error(w, "failed to list friends", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.FriendResponse, 0, len(friendships))
+	for _, f := range friendships {
+		resp = append(resp, schemas.FriendResponse{
+			UserID: f.FriendID,
+			Name:   f.Friend.Name,
+			Since:  f.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateInvite godoc
+// @Summary      Create friend invite
+// @Description  Creates an invite token valid for 7 days. Clients share it as a link, e.g. https://t.me/<bot>?start=friend_<token>;
+// @Description  everyone who accepts it becomes the user's friend.
+// @Tags         friends
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201  {object}  schemas.FriendInviteResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/friends/invites [post]
+func (h *FriendHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/friends/invites"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to create invite", http.StatusInternalServerError)
+		return
+	}
+
+	invite := &models.FriendInvite{
+		Token:     token,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(friendInviteTTL),
+	}
+	if err := h.Repo.CreateInvite(r.Context(), invite); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to create friend invite", zap.Error(err))
+		http.Error(w, "failed to create invite", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schemas.FriendInviteResponse{
+		Token:     invite.Token,
+		ExpiresAt: invite.ExpiresAt,
+	})
+}
+
+// AcceptInvite godoc
+// @Summary      Accept friend invite
+// @Description  Makes the user and the author of the invite friends. Accepting an invite of a friend again is a no-op.
+// @Tags         friends
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.AcceptFriendInviteRequest  true  "Invite token"
+// @Success      200      {object}  schemas.FriendResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      404      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/friends/accept [post]
+func (h *FriendHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/friends/accept"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.AcceptFriendInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.Repo.GetInvite(r.Context(), req.Token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		statusCode = 404
+		http.Error(w, "invite not found or expired", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get friend invite", zap.Error(err))
+		http.Error(w, "failed to accept invite", http.StatusInternalServerError)
+		return
+	}
+
+	if invite.UserID == user.ID {
+		statusCode = 400
+		http.Error(w, "cannot accept your own invite", http.StatusBadRequest)
+		return
+	}
+
+	friend, err := h.UserRepo.GetByID(r.Context(), invite.UserID)
+	if err != nil {
+		statusCode = 404
+		http.Error(w, "invite not found or expired", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.Add(r.Context(), user.ID, friend.ID); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to add friend", zap.Error(err))
+		http.Error(w, "failed to accept invite", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas.FriendResponse{
+		UserID: friend.ID,
+		Name:   friend.Name,
+		Since:  time.Now(),
+	})
+}
+
+// RemoveFriend godoc
+// @Summary      Remove friend
+// @Description  Ends a friendship for both users
+// @Tags         friends
+// @Security     BearerAuth
+// @Param        friend_id  path  string  true  "Friend's user ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/friends/{friend_id} [delete]
+func (h *FriendHandler) RemoveFriend(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/friends/{friend_id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	friendID, err := utils.ParseUUIDParam(r, "friend_id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid friend_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.Remove(r.Context(), user.ID, friendID); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to remove friend", zap.Error(err))
+		http.Error(w, "failed to remove friend", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/gamification"
+	"fluently/go-backend/internal/leaderboard"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GamificationHandler handles XP, streaks and achievements
+type GamificationHandler struct {
+	Repo           *postgres.GamificationRepository
+	PreferenceRepo *postgres.PreferenceRepository
+	Leaderboard    *leaderboard.Board // weekly boards aren't updated when nil
+}
+
+// GetAchievements godoc
+// @Summary      Get achievements
+// @Description  Returns the user's XP, level and streak with all achievements and the progress towards them
+// @Tags         gamification
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  schemas.AchievementsResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/achievements [get]
+func (h *GamificationHandler) GetAchievements(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/achievements"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := h.Repo.GetStats(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get user stats", zap.Error(err))
+		http.Error(w, "failed to get achievements", http.StatusInternalServerError)
+		return
+	}
+
+	metrics, err := h.Repo.GetMetrics(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get achievement metrics", zap.Error(err))
+		http.Error(w, "failed to get achievements", http.StatusInternalServerError)
+		return
+	}
+
+	unlocked, err := h.Repo.ListAchievements(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list unlocked achievements", zap.Error(err))
+		http.Error(w, "failed to get achievements", http.StatusInternalServerError)
+		return
+	}
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, u := range unlocked {
+		unlockedAt[u.AchievementID] = u.UnlockedAt
+	}
+
+	statsResp, err := h.buildStatsResponse(r.Context(), user.ID, stats)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get user time zone", zap.Error(err))
+		http.Error(w, "failed to get achievements", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.AchievementsResponse{
+		Stats:        statsResp,
+		Achievements: []schemas.AchievementResponse{},
+	}
+	for _, a := range gamification.Achievements() {
+		item := buildAchievementResponse(a)
+		item.Progress = a.Progress(metrics)
+		if at, ok := unlockedAt[a.ID]; ok {
+			item.Unlocked = true
+			item.UnlockedAt = &at
+			item.Progress = a.Threshold
+		}
+		resp.Achievements = append(resp.Achievements, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RecordActivity godoc
+// @Summary      Record activity
+// @Description  Awards XP for answered exercises or a finished lesson and counts the day towards the streak.
+// @Description  XP of exercises and lessons is capped per day, client_time older than the backdate window counts on its first day.
+// @Description  Returns events for celebratory messages: level ups, streak changes and unlocked achievements.
+// @Tags         gamification
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        activity  body      schemas.ActivityRequest  true  "Activity"
+// @Success      200       {object}  schemas.ActivityResponse
+// @Failure      400       {object}  schemas.ErrorResponse
+// @Failure      401       {object}  schemas.ErrorResponse
+// @Failure      500       {object}  schemas.ErrorResponse
+// @Router       /api/v1/activity [post]
+func (h *GamificationHandler) RecordActivity(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/activity"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.ActivityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if utf8.RuneCountInString(req.IdempotencyKey) > maxSyncKeyLength {
+		statusCode = 400
+		http.Error(w, "idempotency_key must be at most 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	activity := gamification.Activity{
+		Type:    req.Type,
+		Correct: req.Correct,
+		Total:   req.Total,
+		Time:    time.Now(),
+	}
+	if req.IdempotencyKey != "" {
+		activity.Key = "activity:" + req.IdempotencyKey
+	}
+	if req.ClientTime != nil {
+		if req.ClientTime.After(time.Now().Add(maxClockSkew)) {
+			statusCode = 400
+			http.Error(w, "client_time is in the future", http.StatusBadRequest)
+			return
+		}
+		activity.Time = *req.ClientTime
+	}
+
+	if err := activity.Validate(); err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, events, err := h.Repo.Record(r.Context(), user.ID, []gamification.Activity{activity})
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to record activity", zap.Error(err))
+		http.Error(w, "failed to record activity", http.StatusInternalServerError)
+		return
+	}
+
+	addLeaderboardXP(r.Context(), h.Leaderboard, h.PreferenceRepo, user.ID, events)
+
+	statsResp, err := h.buildStatsResponse(r.Context(), user.ID, stats)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get user time zone", zap.Error(err))
+		http.Error(w, "failed to record activity", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.ActivityResponse{
+		Stats:  statsResp,
+		Events: buildGamificationEvents(events),
+	}
+	for _, e := range events {
+		if e.Type == gamification.EventXPGained {
+			resp.XPGained = e.XP
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildStatsResponse builds a stats response, the streak as seen today in the user's time zone
+func (h *GamificationHandler) buildStatsResponse(ctx context.Context, userID uuid.UUID, stats *models.UserStats) (schemas.GamificationStatsResponse, error) {
+	loc, err := h.Repo.Location(ctx, userID)
+	if err != nil {
+		return schemas.GamificationStatsResponse{}, err
+	}
+
+	level := gamification.Level(stats.XP)
+	resp := schemas.GamificationStatsResponse{
+		XP:            stats.XP,
+		Level:         level,
+		LevelXP:       gamification.LevelXP(level),
+		NextLevelXP:   gamification.LevelXP(level + 1),
+		LongestStreak: stats.LongestStreak,
+		StreakFreezes: stats.StreakFreezes,
+		TimeZone:      loc.String(),
+	}
+
+	if stats.LastActiveDate != nil {
+		streak := gamification.Streak{
+			Current: stats.CurrentStreak,
+			Freezes: stats.StreakFreezes,
+			LastDay: gamification.Day(*stats.LastActiveDate, time.UTC),
+		}
+		resp.CurrentStreak = streak.CurrentOn(gamification.Day(time.Now(), loc))
+		resp.LastActiveDate = stats.LastActiveDate.Format(gamification.DateLayout)
+	}
+
+	return resp, nil
+}
+
+// buildAchievementResponse builds a response from an achievement definition
+func buildAchievementResponse(a gamification.Achievement) schemas.AchievementResponse {
+	return schemas.AchievementResponse{
+		ID:          a.ID,
+		Title:       a.Title,
+		Description: a.Description,
+		Icon:        a.Icon,
+		Metric:      a.Metric,
+		Threshold:   a.Threshold,
+	}
+}
+
+// buildGamificationEvents builds event responses, never nil so clients get a list
+func buildGamificationEvents(events []gamification.Event) []schemas.GamificationEvent {
+	result := make([]schemas.GamificationEvent, 0, len(events))
+	for _, e := range events {
+		item := schemas.GamificationEvent{
+			Type:    e.Type,
+			XP:      e.XP,
+			Level:   e.Level,
+			Streak:  e.Streak,
+			Freezes: e.Freezes,
+		}
+		if e.Achievement != nil {
+			achievement := buildAchievementResponse(*e.Achievement)
+			achievement.Progress = achievement.Threshold
+			achievement.Unlocked = true
+			item.Achievement = &achievement
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,642 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fluently/go-backend/internal/identity"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// oauthStateTTL is how long a user has to finish the consent screen of a provider
+const oauthStateTTL = 10 * time.Minute
+
+// oauthState is what we remember about a started authorization code flow, keyed by its state parameter.
+// It's kept in Redis rather than a cookie, Sign in with Apple posts the callback cross-site where cookies aren't sent.
+type oauthState struct {
+	Provider    string     `json:"provider"`
+	RedirectURI string     `json:"redirect_uri"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"` // set when a signed in user links the account
+}
+
+// saveOAuthState stores the flow and returns its state parameter
+func (h *Handlers) saveOAuthState(ctx context.Context, st oauthState) (string, error) {
+	state, err := generateRandomState()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+	if err := h.Redis.Set(ctx, "oauth:state:"+state, data, oauthStateTTL).Err(); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// takeOAuthState returns the flow of the state parameter, a state can be used once
+func (h *Handlers) takeOAuthState(ctx context.Context, state string) (*oauthState, error) {
+	data, err := h.Redis.GetDel(ctx, "oauth:state:"+state).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var st oauthState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// defaultIdentityCallbackURL returns our callback URL for the provider
+func defaultIdentityCallbackURL(r *http.Request, provider string) string {
+	scheme := r.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		if r.TLS != nil {
+			scheme = "https"
+		} else {
+			scheme = "http"
+		}
+	}
+	return fmt.Sprintf("%s://%s/auth/oidc/%s/callback", scheme, r.Host, provider)
+}
+
+// authenticateIdentity verifies the ID token or exchanges the code of the request
+func authenticateIdentity(r *http.Request, provider identity.Provider, req schemas.IdentityAuthRequest) (*identity.Identity, error) {
+	switch {
+	case req.IDToken != "":
+		return provider.VerifyIDToken(r.Context(), req.IDToken)
+	case req.Code != "":
+		redirectURI := req.RedirectURI
+		if redirectURI == "" {
+			redirectURI = defaultIdentityCallbackURL(r, provider.Name())
+		}
+		return provider.Exchange(r.Context(), req.Code, redirectURI)
+	default:
+		return nil, errors.New("id_token or code is required")
+	}
+}
+
+// writeIdentityError maps provider errors to responses
+func writeIdentityError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, identity.ErrUnknownProvider):
+		http.Error(w, "unknown provider", http.StatusNotFound)
+	case errors.Is(err, identity.ErrIDTokenNotSupported):
+		http.Error(w, "provider doesn't issue id tokens, use code", http.StatusBadRequest)
+	case errors.Is(err, identity.ErrInvalidToken), errors.Is(err, identity.ErrExchange):
+		logger.Log.Warn("External account verification failed", zap.Error(err))
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	default:
+		logger.Log.Error("Failed to authenticate with provider", zap.Error(err))
+		http.Error(w, "provider unavailable", http.StatusBadGateway)
+	}
+}
+
+// ListIdentityProviders godoc
+// @Summary      List sign in providers
+// @Description  Returns external providers users can sign in with and link, besides /auth/google and /auth/telegram
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  schemas.IdentityProvidersResponse
+// @Router       /auth/providers [get]
+func (h *Handlers) ListIdentityProviders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas.IdentityProvidersResponse{Providers: h.Providers.Names()})
+}
+
+// IdentityRedirectHandler godoc
+// @Summary      Redirects to the consent screen of a provider
+// @Description  Starts the authorization code flow, the provider redirects back to /auth/oidc/{provider}/callback or redirect_uri
+// @Tags         auth
+// @Param        provider      path      string  true   "Provider, see /auth/providers"
+// @Param        redirect_uri  query     string  false  "Custom redirect URI for mobile apps"
+// @Success      307           {string}  string  "Redirect"
+// @Failure      404           {object}  schemas.ErrorResponse
+// @Failure      500           {object}  schemas.ErrorResponse
+// @Failure      502           {object}  schemas.ErrorResponse
+// @Router       /auth/oidc/{provider} [get]
+func (h *Handlers) IdentityRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.Providers.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	redirectURI := r.URL.Query().Get("redirect_uri")
+	if redirectURI == "" {
+		redirectURI = defaultIdentityCallbackURL(r, provider.Name())
+	}
+
+	state, err := h.saveOAuthState(r.Context(), oauthState{Provider: provider.Name(), RedirectURI: redirectURI})
+	if err != nil {
+		logger.Log.Error("Failed to save OAuth state", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	url, err := provider.AuthCodeURL(r.Context(), state, redirectURI)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
+
+// IdentityCallbackHandler godoc
+// @Summary      Handles the callback of a provider
+// @Description  Exchanges the code for the user's identity and signs them in, or links the account when the flow
+// @Description  was started with /api/v1/identities/{provider}/authorize. Accepts form posts for Sign in with Apple.
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider"
+// @Param        state     query     string  true  "State of the flow"
+// @Param        code      query     string  true  "Authorization code"
+// @Success      200       {object}  schemas.JwtResponse
+// @Failure      400       {object}  schemas.ErrorResponse
+// @Failure      401       {object}  schemas.ErrorResponse
+// @Failure      409       {object}  schemas.ErrorResponse
+// @Failure      500       {object}  schemas.ErrorResponse
+// @Failure      502       {object}  schemas.ErrorResponse
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *Handlers) IdentityCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	st, err := h.takeOAuthState(r.Context(), r.FormValue("state"))
+	if err != nil || st.Provider != chi.URLParam(r, "provider") {
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			logger.Log.Error("Failed to load OAuth state", zap.Error(err))
+		}
+		http.Error(w, "invalid state", http.StatusBadRequest)
+		return
+	}
+
+	if errMsg := r.FormValue("error"); errMsg != "" {
+		logger.Log.Warn("User denied authorization", zap.String("provider", st.Provider), zap.String("error", errMsg))
+		http.Error(w, "authorization cancelled or denied", http.StatusUnauthorized)
+		return
+	}
+
+	code := r.FormValue("code")
+	if code == "" {
+		http.Error(w, "code not found", http.StatusBadRequest)
+		return
+	}
+
+	provider, err := h.Providers.Get(st.Provider)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	ident, err := provider.Exchange(r.Context(), code, st.RedirectURI)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	if st.UserID != nil {
+		h.linkIdentity(w, r, *st.UserID, ident)
+		return
+	}
+	h.signInWithIdentity(w, r, ident)
+}
+
+// IdentityAuthHandler godoc
+// @Summary      Authenticates with a provider
+// @Description  Signs in with an ID token from the provider's SDK or an authorization code the client got itself.
+// @Description  A new account is created for unknown users, but an existing account with the same verified email
+// @Description  is never taken over: the owner has to sign in and link the provider first.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        provider  path      string                       true  "Provider"
+// @Param        request   body      schemas.IdentityAuthRequest  true  "ID token or code"
+// @Success      200       {object}  schemas.JwtResponse
+// @Failure      400       {object}  schemas.ErrorResponse
+// @Failure      401       {object}  schemas.ErrorResponse
+// @Failure      404       {object}  schemas.ErrorResponse
+// @Failure      409       {object}  schemas.ErrorResponse
+// @Failure      500       {object}  schemas.ErrorResponse
+// @Failure      502       {object}  schemas.ErrorResponse
+// @Router       /auth/oidc/{provider} [post]
+func (h *Handlers) IdentityAuthHandler(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.Providers.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	var req schemas.IdentityAuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.IDToken == "" && req.Code == "") {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	ident, err := authenticateIdentity(r, provider, req)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	h.signInWithIdentity(w, r, ident)
+}
+
+// signInWithIdentity signs in the user the account is linked to, or creates a new user for it
+func (h *Handlers) signInWithIdentity(w http.ResponseWriter, r *http.Request, ident *identity.Identity) {
+	var user *models.User
+
+	linked, err := h.IdentityRepo.GetBySubject(r.Context(), ident.Provider, ident.Subject)
+	switch {
+	case err == nil:
+		user = &linked.User
+		if err := h.IdentityRepo.Touch(r.Context(), linked.ID); err != nil {
+			logger.Log.Error("Failed to update identity last use", zap.Error(err))
+		}
+		if err := h.UserRepo.UpdateLastLogin(r.Context(), user.ID); err != nil {
+			logger.Log.Error("Failed to update last login time", zap.Error(err))
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Linking by email would hand the account to whoever controls that email at the provider
+		if ident.Email != "" && ident.EmailVerified {
+			_, err := h.UserRepo.GetByEmail(r.Context(), ident.Email)
+			if err == nil {
+				http.Error(w, "an account with this email already exists, sign in and link "+ident.Provider+" in settings", http.StatusConflict)
+				return
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Log.Error("Failed to get user", zap.Error(err))
+				http.Error(w, "failed to get user", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		user = h.createUserViaIdentity(r, ident)
+		if user == nil {
+			http.Error(w, "failed to create user", http.StatusInternalServerError)
+			return
+		}
+	default:
+		logger.Log.Error("Failed to get identity", zap.Error(err))
+		http.Error(w, "failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := h.generateTokens(user, w, r)
+	if err != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// createUserViaIdentity creates a new user with the external account linked.
+// Unverified emails aren't stored on the user, so they can't block their real owner from registering.
+func (h *Handlers) createUserViaIdentity(r *http.Request, ident *identity.Identity) *models.User {
+	logger.Log.Info("Creating new user with external account",
+		zap.String("provider", ident.Provider),
+		zap.String("subject", ident.Subject))
+
+	userID := uuid.New()
+	email := userID.String() + "@identity.invalid"
+	if ident.Email != "" && ident.EmailVerified {
+		email = ident.Email
+	}
+
+	name := ident.Name
+	if name == "" && ident.Email != "" {
+		name, _, _ = strings.Cut(ident.Email, "@")
+	}
+	if name == "" {
+		name = "User"
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+
+	newUser := &models.User{
+		ID:           userID,
+		Name:         name,
+		Email:        email,
+		PasswordHash: "",
+		Provider:     ident.Provider,
+		Role:         "user",
+		IsActive:     true,
+		LastLoginAt:  time.Now(),
+	}
+	if ident.Provider == "google" {
+		newUser.GoogleID = ident.Subject
+	}
+
+	if err := h.UserRepo.Create(r.Context(), newUser); err != nil {
+		logger.Log.Error("Failed to create user", zap.Error(err))
+		return nil
+	}
+
+	userPreferences := models.Preference{
+		UserID:          userID,
+		Subscribed:      false,
+		CEFRLevel:       "A1",
+		FactEveryday:    false,
+		Notifications:   false,
+		NotificationsAt: nil,
+		WordsPerDay:     10,
+		Goal:            "Learn new words",
+		AvatarImageURL:  ident.AvatarURL,
+	}
+
+	if err := h.UserPrefRepo.Create(r.Context(), &userPreferences); err != nil {
+		logger.Log.Error("Failed to create user preferences", zap.Error(err))
+		return nil
+	}
+
+	if err := h.IdentityRepo.Create(r.Context(), &models.UserIdentity{
+		UserID:     userID,
+		Provider:   ident.Provider,
+		Subject:    ident.Subject,
+		Email:      ident.Email,
+		LastUsedAt: time.Now(),
+	}); err != nil {
+		logger.Log.Error("Failed to create identity", zap.Error(err))
+		return nil
+	}
+
+	return newUser
+}
+
+// ensureGoogleIdentity records the Google account of a user signed in with /auth/google,
+// so it shows up among linked accounts like the ones linked through /api/v1/identities.
+// Users whose Google ID was cleared by unlinking the account aren't linked again.
+func (h *Handlers) ensureGoogleIdentity(r *http.Request, user *models.User, sub, email string) {
+	if h.IdentityRepo == nil || user.GoogleID != sub {
+		return
+	}
+
+	if _, err := h.IdentityRepo.GetBySubject(r.Context(), "google", sub); err == nil {
+		return
+	}
+	if _, err := h.IdentityRepo.GetByUserProvider(r.Context(), user.ID, "google"); err == nil {
+		return
+	}
+
+	if err := h.IdentityRepo.Create(r.Context(), &models.UserIdentity{
+		UserID:     user.ID,
+		Provider:   "google",
+		Subject:    sub,
+		Email:      email,
+		LastUsedAt: time.Now(),
+	}); err != nil {
+		logger.Log.Error("Failed to record Google identity", zap.Error(err))
+	}
+}
+
+// ListIdentities godoc
+// @Summary      List linked accounts
+// @Description  Returns the external accounts linked to the current user and the providers that can be linked
+// @Tags         identities
+// @Produce      json
+// @Success      200  {object}  schemas.IdentityListResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/identities [get]
+func (h *Handlers) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	identities, err := h.IdentityRepo.ListByUserID(r.Context(), user.ID)
+	if err != nil {
+		logger.Log.Error("Failed to list identities", zap.Error(err))
+		http.Error(w, "failed to list identities", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.IdentityListResponse{
+		Identities: make([]schemas.IdentityResponse, 0, len(identities)),
+		Providers:  h.Providers.Names(),
+	}
+	for _, i := range identities {
+		resp.Identities = append(resp.Identities, schemas.IdentityResponse{
+			Provider:   i.Provider,
+			Email:      i.Email,
+			CreatedAt:  i.CreatedAt,
+			LastUsedAt: i.LastUsedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// IdentityLinkURL godoc
+// @Summary      Start linking an account
+// @Description  Returns the consent screen URL of the provider, its callback links the account to the current user
+// @Tags         identities
+// @Produce      json
+// @Param        provider      path      string  true   "Provider"
+// @Param        redirect_uri  query     string  false  "Custom redirect URI for mobile apps"
+// @Success      200           {object}  schemas.AuthorizeURLResponse
+// @Failure      401           {object}  schemas.ErrorResponse
+// @Failure      404           {object}  schemas.ErrorResponse
+// @Failure      500           {object}  schemas.ErrorResponse
+// @Failure      502           {object}  schemas.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/identities/{provider}/authorize [get]
+func (h *Handlers) IdentityLinkURL(w http.ResponseWriter, r *http.Request) {
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := h.Providers.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	redirectURI := r.URL.Query().Get("redirect_uri")
+	if redirectURI == "" {
+		redirectURI = defaultIdentityCallbackURL(r, provider.Name())
+	}
+
+	state, err := h.saveOAuthState(r.Context(), oauthState{
+		Provider:    provider.Name(),
+		RedirectURI: redirectURI,
+		UserID:      &user.ID,
+	})
+	if err != nil {
+		logger.Log.Error("Failed to save OAuth state", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	url, err := provider.AuthCodeURL(r.Context(), state, redirectURI)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas.AuthorizeURLResponse{URL: url})
+}
+
+// LinkIdentity godoc
+// @Summary      Link an account
+// @Description  Links the external account of the ID token or code to the current user
+// @Tags         identities
+// @Accept       json
+// @Produce      json
+// @Param        provider  path      string                       true  "Provider"
+// @Param        request   body      schemas.IdentityAuthRequest  true  "ID token or code"
+// @Success      200       {object}  map[string]string
+// @Failure      400       {object}  schemas.ErrorResponse
+// @Failure      401       {object}  schemas.ErrorResponse
+// @Failure      404       {object}  schemas.ErrorResponse
+// @Failure      409       {object}  schemas.ErrorResponse
+// @Failure      500       {object}  schemas.ErrorResponse
+// @Failure      502       {object}  schemas.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/identities/{provider} [post]
+func (h *Handlers) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := h.Providers.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	var req schemas.IdentityAuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.IDToken == "" && req.Code == "") {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ident, err := authenticateIdentity(r, provider, req)
+	if err != nil {
+		writeIdentityError(w, err)
+		return
+	}
+
+	h.linkIdentity(w, r, user.ID, ident)
+}
+
+// linkIdentity links the external account to the user unless it belongs to someone else
+func (h *Handlers) linkIdentity(w http.ResponseWriter, r *http.Request, userID uuid.UUID, ident *identity.Identity) {
+	linked, err := h.IdentityRepo.GetBySubject(r.Context(), ident.Provider, ident.Subject)
+	switch {
+	case err == nil && linked.UserID != userID:
+		http.Error(w, ident.Provider+" account is linked to another user", http.StatusConflict)
+		return
+	case err == nil:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "account already linked"})
+		return
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Log.Error("Failed to get identity", zap.Error(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.IdentityRepo.GetByUserProvider(r.Context(), userID, ident.Provider); err == nil {
+		http.Error(w, "another "+ident.Provider+" account is linked, unlink it first", http.StatusConflict)
+		return
+	}
+
+	if err := h.IdentityRepo.Create(r.Context(), &models.UserIdentity{
+		UserID:     userID,
+		Provider:   ident.Provider,
+		Subject:    ident.Subject,
+		Email:      ident.Email,
+		LastUsedAt: time.Now(),
+	}); err != nil {
+		logger.Log.Error("Failed to link identity", zap.Error(err))
+		http.Error(w, "failed to link account", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "account successfully linked"})
+}
+
+// UnlinkIdentity godoc
+// @Summary      Unlink an account
+// @Description  Unlinks the external account of the provider, unless it's the only way left to sign in
+// @Tags         identities
+// @Produce      json
+// @Param        provider  path      string  true  "Provider"
+// @Success      204       "No Content"
+// @Failure      401       {object}  schemas.ErrorResponse
+// @Failure      404       {object}  schemas.ErrorResponse
+// @Failure      409       {object}  schemas.ErrorResponse
+// @Failure      500       {object}  schemas.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/identities/{provider} [delete]
+func (h *Handlers) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	current, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+	if _, err := h.IdentityRepo.GetByUserProvider(r.Context(), current.ID, provider); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "account not linked", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to get identity", zap.Error(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := h.UserRepo.GetByID(r.Context(), current.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get user", zap.Error(err))
+		http.Error(w, "failed to get user", http.StatusInternalServerError)
+		return
+	}
+	count, err := h.IdentityRepo.CountByUserID(r.Context(), user.ID)
+	if err != nil {
+		logger.Log.Error("Failed to count identities", zap.Error(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if user.PasswordHash == "" && user.TelegramID == nil && count <= 1 {
+		http.Error(w, "can't unlink the only way to sign in, link another account first", http.StatusConflict)
+		return
+	}
+
+	if err := h.IdentityRepo.Delete(r.Context(), user.ID, provider); err != nil {
+		logger.Log.Error("Failed to unlink identity", zap.Error(err))
+		http.Error(w, "failed to unlink account", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultJobPageSize = 20
+	maxJobPageSize     = 100
+	defaultBackfill    = 100
+	maxBackfill        = 5000
+)
+
+// JobHandler handles the admin API for background jobs
+type JobHandler struct {
+	Jobs       *jobs.Client
+	Inspector  *jobs.Inspector
+	Enrichment *utils.WordEnrichmentService
+	Audio      *utils.WordAudioService // nil when audio generation is disabled
+}
+
+// timePtr returns nil for zero time so unset timestamps are omitted
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// buildJobTaskResponse builds a JobTaskResponse from asynq TaskInfo
+func buildJobTaskResponse(t *asynq.TaskInfo) schemas.JobTaskResponse {
+	payload := json.RawMessage(t.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(t.Payload))
+	}
+
+	return schemas.JobTaskResponse{
+		ID:            t.ID,
+		Queue:         t.Queue,
+		Type:          t.Type,
+		Payload:       payload,
+		State:         t.State.String(),
+		MaxRetry:      t.MaxRetry,
+		Retried:       t.Retried,
+		LastError:     t.LastErr,
+		LastFailedAt:  timePtr(t.LastFailedAt),
+		NextProcessAt: timePtr(t.NextProcessAt),
+		CompletedAt:   timePtr(t.CompletedAt),
+	}
+}
+
+// ListQueues godoc
+// @Summary      List job queues
+// @Description  Returns the state of background job queues. Admin only.
+// @Tags         admin-jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   schemas.JobQueueResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      403  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/queues [get]
+func (h *JobHandler) ListQueues(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/queues"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	resp := make([]schemas.JobQueueResponse, 0, len(jobs.Queues))
+	for queue := range jobs.Queues {
+		info, err := h.Inspector.GetQueueInfo(queue)
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			// Queue has not received any task yet
+			resp = append(resp, schemas.JobQueueResponse{Queue: queue})
+			continue
+		}
+		if err != nil {
+			statusCode = 500
+			logger.Log.Error("Failed to get queue info", zap.Error(err), zap.String("queue", queue))
+			http.Error(w, "failed to get queue info", http.StatusInternalServerError)
+			return
+		}
+
+		resp = append(resp, schemas.JobQueueResponse{
+			Queue:     info.Queue,
+			Size:      info.Size,
+			Pending:   info.Pending,
+			Active:    info.Active,
+			Scheduled: info.Scheduled,
+			Retry:     info.Retry,
+			Archived:  info.Archived,
+			Completed: info.Completed,
+			Processed: info.Processed,
+			Failed:    info.Failed,
+			Paused:    info.Paused,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListTasks godoc
+// @Summary      List job tasks
+// @Description  Returns tasks of a queue in a state. The archived state is the dead-letter queue. Admin only.
+// @Tags         admin-jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        queue  path      string  true   "Queue name"
+// @Param        state  path      string  true   "Task state"  Enums(pending, active, scheduled, retry, archived, completed)
+// @Param        page   query     int     false  "Page number, starting from 1"
+// @Param        limit  query     int     false  "Page size, up to 100"
+// @Success      200    {array}   schemas.JobTaskResponse
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      403    {object}  schemas.ErrorResponse
+// @Failure      404    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/queues/{queue}/{state} [get]
+func (h *JobHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/queues/{queue}/{state}"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	var err error
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			statusCode = 400
+			http.Error(w, "invalid page", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := defaultJobPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			statusCode = 400
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxJobPageSize {
+			limit = maxJobPageSize
+		}
+	}
+
+	tasks, err := h.Inspector.ListTasks(chi.URLParam(r, "queue"), chi.URLParam(r, "state"), page, limit)
+	if errors.Is(err, jobs.ErrUnknownState) {
+		statusCode = 400
+		http.Error(w, "invalid state", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, asynq.ErrQueueNotFound) {
+		statusCode = 404
+		http.Error(w, "queue not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list tasks", zap.Error(err))
+		http.Error(w, "failed to list tasks", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.JobTaskResponse, 0, len(tasks))
+	for _, t := range tasks {
+		resp = append(resp, buildJobTaskResponse(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RunTask godoc
+// @Summary      Re-run job task
+// @Description  Moves a scheduled, retry or archived task to pending so it runs right away. Admin only.
+// @Tags         admin-jobs
+// @Security     BearerAuth
+// @Param        queue  path  string  true  "Queue name"
+// @Param        id     path  string  true  "Task ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      403    {object}  schemas.ErrorResponse
+// @Failure      404    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/queues/{queue}/tasks/{id}/run [post]
+func (h *JobHandler) RunTask(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/queues/{queue}/tasks/{id}/run"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	err := h.Inspector.RunTask(chi.URLParam(r, "queue"), chi.URLParam(r, "id"))
+	if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+		statusCode = 404
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		// Pending and active tasks can't be re-run
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteTask godoc
+// @Summary      Delete job task
+// @Description  Deletes a task that is not being processed. Admin only.
+// @Tags         admin-jobs
+// @Security     BearerAuth
+// @Param        queue  path  string  true  "Queue name"
+// @Param        id     path  string  true  "Task ID"
+// @Success      204    "No Content"
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      403    {object}  schemas.ErrorResponse
+// @Failure      404    {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/queues/{queue}/tasks/{id} [delete]
+func (h *JobHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/queues/{queue}/tasks/{id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	err := h.Inspector.DeleteTask(chi.URLParam(r, "queue"), chi.URLParam(r, "id"))
+	if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+		statusCode = 404
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunArchivedTasks godoc
+// @Summary      Re-run dead-letter tasks
+// @Description  Moves all archived tasks of a queue back to pending. Admin only.
+// @Tags         admin-jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        queue  path      string  true  "Queue name"
+// @Success      200    {object}  schemas.JobCountResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      403    {object}  schemas.ErrorResponse
+// @Failure      404    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/queues/{queue}/archived/run [post]
+func (h *JobHandler) RunArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/queues/{queue}/archived/run"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	count, err := h.Inspector.RunAllArchivedTasks(chi.URLParam(r, "queue"))
+	if errors.Is(err, asynq.ErrQueueNotFound) {
+		statusCode = 404
+		http.Error(w, "queue not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to run archived tasks", zap.Error(err))
+		http.Error(w, "failed to run archived tasks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas.JobCountResponse{Count: count})
+}
+
+// EnqueueEnrichment godoc
+// @Summary      Enqueue enrichment backfill
+// @Description  Enqueues enrichment of words missing dictionary data and sentences without distractors. Admin only.
+// @Tags         admin-jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.JobBackfillRequest  false  "Backfill limits"
+// @Success      202      {object}  schemas.JobCountResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/enrich [post]
+func (h *JobHandler) EnqueueEnrichment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/enrich"
+	method := r.Method
+	statusCode := 202
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	req := schemas.JobBackfillRequest{Limit: defaultBackfill}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			statusCode = 400
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Limit <= 0 || req.Limit > maxBackfill {
+		statusCode = 400
+		http.Error(w, "limit must be between 1 and 5000", http.StatusBadRequest)
+		return
+	}
+
+	wordIDs, err := h.Enrichment.ListWordsNeedingEnrichment(r.Context(), req.Limit)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to find words", http.StatusInternalServerError)
+		return
+	}
+
+	sentenceIDs, err := h.Enrichment.ListSentencesNeedingDistractors(r.Context(), req.Limit)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to find sentences", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := h.Jobs.EnqueueBackfill(r.Context(), wordIDs, sentenceIDs)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to enqueue enrichment backfill", zap.Error(err), zap.Int("enqueued", count))
+		http.Error(w, "failed to enqueue jobs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(schemas.JobCountResponse{Count: count})
+}
+
+// EnqueueAudio godoc
+// @Summary      Enqueue audio backfill
+// @Description  Enqueues moving word and sentence audio hosted by third parties into storage and speech generation for those without audio. Admin only.
+// @Tags         admin-jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.JobBackfillRequest  false  "Backfill limits"
+// @Success      202      {object}  schemas.JobCountResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      403      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Failure      503      {object}  schemas.ErrorResponse
+// @Router       /api/v1/admin/jobs/audio [post]
+func (h *JobHandler) EnqueueAudio(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/admin/jobs/audio"
+	method := r.Method
+	statusCode := 202
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	if h.Audio == nil {
+		statusCode = 503
+		http.Error(w, "media storage is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	req := schemas.JobBackfillRequest{Limit: defaultBackfill}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			statusCode = 400
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Limit <= 0 || req.Limit > maxBackfill {
+		statusCode = 400
+		http.Error(w, "limit must be between 1 and 5000", http.StatusBadRequest)
+		return
+	}
+
+	wordIDs, err := h.Audio.ListWordsMissingAudio(r.Context(), req.Limit)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to find words", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := h.Jobs.EnqueueAudioBackfill(r.Context(), wordIDs)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to enqueue audio backfill", zap.Error(err), zap.Int("enqueued", count))
+		http.Error(w, "failed to enqueue jobs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(schemas.JobCountResponse{Count: count})
+}
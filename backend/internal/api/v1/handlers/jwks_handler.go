@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"fluently/go-backend/internal/utils"
+)
+
+// JWKSHandler godoc
+// @Summary      Public keys of access tokens
+// @Description  Returns the JSON Web Key Set access tokens are signed with, pick the key by the "kid" header of a token.
+// @Description  Verifiers should fetch the set again when a token names an unknown key, keys are rotated regularly.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Router       /.well-known/jwks.json [get]
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(utils.JWKS())
+}
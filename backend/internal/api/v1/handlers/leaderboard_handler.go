@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fluently/go-backend/internal/gamification"
+	"fluently/go-backend/internal/leaderboard"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// LeaderboardHandler handles weekly XP leaderboards
+type LeaderboardHandler struct {
+	Board          *leaderboard.Board
+	FriendRepo     *postgres.FriendRepository
+	UserRepo       *postgres.UserRepository
+	PreferenceRepo *postgres.PreferenceRepository
+}
+
+// GetLeaderboard godoc
+// @Summary      Get leaderboard
+// @Description  Returns this week's XP leaderboard, global or among the user and their friends.
+// @Description  Weeks run Monday to Sunday UTC. Users who opted out in preferences are left out of the global board.
+// @Tags         friends
+// @Produce      json
+// @Security     BearerAuth
+// @Param        scope  query     string  false  "global or friends"  Enums(global, friends)  default(global)
+// @Param        limit  query     int     false  "Max entries, up to 100"  default(10)
+// @Success      200    {object}  schemas.LeaderboardResponse
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/leaderboard"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		scope = "global"
+	}
+	if scope != "global" && scope != "friends" {
+		statusCode = 400
+		http.Error(w, "scope must be global or friends", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultLeaderboardLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			statusCode = 400
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxLeaderboardLimit)
+	}
+
+	now := time.Now()
+	resp := schemas.LeaderboardResponse{
+		Scope:   scope,
+		Week:    leaderboard.Week(now),
+		EndsAt:  leaderboard.WeekEnd(now),
+		Entries: []schemas.LeaderboardEntry{},
+	}
+
+	var entries []leaderboard.Entry
+	var me *leaderboard.Entry
+	if scope == "friends" {
+		entries, me, err = h.friendsBoard(r.Context(), resp.Week, user.ID)
+	} else {
+		entries, me, resp.Hidden, err = h.globalBoard(r.Context(), resp.Week, user.ID, limit)
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get leaderboard", zap.Error(err), zap.String("scope", scope))
+		http.Error(w, "failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries)+1)
+	for _, e := range entries {
+		ids = append(ids, e.UserID)
+	}
+	ids = append(ids, user.ID)
+
+	users, err := h.UserRepo.ListByIDs(r.Context(), ids)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get leaderboard users", zap.Error(err))
+		http.Error(w, "failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+	names := make(map[uuid.UUID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, buildLeaderboardEntry(e, user.ID, names))
+	}
+	if me != nil {
+		entry := buildLeaderboardEntry(*me, user.ID, names)
+		resp.Me = &entry
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// friendsBoard ranks the user among their friends
+func (h *LeaderboardHandler) friendsBoard(ctx context.Context, week string, userID uuid.UUID) ([]leaderboard.Entry, *leaderboard.Entry, error) {
+	friendIDs, err := h.FriendRepo.ListIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := h.Board.Among(ctx, week, append(friendIDs, userID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range entries {
+		if entries[i].UserID == userID {
+			me := entries[i]
+			return entries, &me, nil
+		}
+	}
+	return entries, nil, nil
+}
+
+// globalBoard returns the top of the global board and the user's place unless they opted out
+func (h *LeaderboardHandler) globalBoard(ctx context.Context, week string, userID uuid.UUID, limit int) ([]leaderboard.Entry, *leaderboard.Entry, bool, error) {
+	entries, err := h.Board.Top(ctx, week, limit)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	hidden, err := leaderboardOptOut(ctx, h.PreferenceRepo, userID)
+	if err != nil || hidden {
+		return entries, nil, hidden, err
+	}
+
+	me, err := h.Board.Rank(ctx, week, userID)
+	return entries, me, false, err
+}
+
+// buildLeaderboardEntry builds a response from a board entry
+func buildLeaderboardEntry(e leaderboard.Entry, userID uuid.UUID, names map[uuid.UUID]string) schemas.LeaderboardEntry {
+	return schemas.LeaderboardEntry{
+		Rank:   e.Rank,
+		UserID: e.UserID,
+		Name:   names[e.UserID],
+		XP:     e.XP,
+		IsMe:   e.UserID == userID,
+	}
+}
+
+// leaderboardOptOut reports whether the user hid themselves from the global board,
+// users without preferences are shown
+func leaderboardOptOut(ctx context.Context, prefs *postgres.PreferenceRepository, userID uuid.UUID) (bool, error) {
+	pref, err := prefs.GetByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return pref.LeaderboardOptOut, nil
+}
+
+// addLeaderboardXP adds XP gained by recorded activity to the weekly boards.
+// The XP is already stored, so failures are only logged.
+func addLeaderboardXP(ctx context.Context, board *leaderboard.Board, prefs *postgres.PreferenceRepository, userID uuid.UUID, events []gamification.Event) {
+	if board == nil {
+		return
+	}
+
+	xp := 0
+	for _, e := range events {
+		if e.Type == gamification.EventXPGained {
+			xp += e.XP
+		}
+	}
+	if xp == 0 {
+		return
+	}
+
+	hidden, err := leaderboardOptOut(ctx, prefs, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get leaderboard visibility", zap.Error(err), zap.String("user_id", userID.String()))
+		return
+	}
+
+	if err := board.AddXP(ctx, userID, xp, !hidden, time.Now()); err != nil {
+		logger.Log.Error("Failed to add leaderboard XP", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+}
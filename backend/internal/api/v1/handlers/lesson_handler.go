@@ -30,6 +30,9 @@ type LessonHandler struct {
 	Repo               *postgres.LessonRepository
 	LearnedWordRepo    *postgres.LearnedWordRepository
 	NotLearnedWordRepo *postgres.NotLearnedWordRepository
+	DeckRepo           *postgres.DeckRepository
+	CaptureRepo        *postgres.CaptureRepository
+	ClassroomRepo      *postgres.ClassroomRepository
 	ThesaurusClient    *utils.ThesaurusClient
 }
 
@@ -52,9 +55,11 @@ func replaceWordWithUnderscores(text, word string) string {
 // @Tags lessons
 // @Produce json
 // @Security BearerAuth
+// @Param deck_id query string false "Build the lesson only from words of this deck"
 // @Success 200 {object} schemas.LessonResponse "Successfully generated lesson"
 // @Failure 400 {string} string "Bad request - invalid user or preferences"
 // @Failure 401 {string} string "Unauthorized - invalid or missing token"
+// @Failure 404 {string} string "Deck not found"
 // @Failure 500 {string} string "Internal server error"
 // @Router /api/v1/lesson [get]
 func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
@@ -91,6 +96,26 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 	lessonInfo.CEFRLevel = userPref.CEFRLevel
 	lessonInfo.StartedAt = time.Now().UTC().Format(time.RFC3339)
 
+	// Optional deck restricts the lesson to the deck words only
+	var deck *models.Deck
+	if deckIDParam := r.URL.Query().Get("deck_id"); deckIDParam != "" {
+		deckID, err := uuid.Parse(deckIDParam)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid deck_id", http.StatusBadRequest)
+			return
+		}
+
+		deck, err = h.DeckRepo.GetByID(r.Context(), deckID)
+		if err != nil || deck.UserID != userID {
+			statusCode = 404
+			http.Error(w, "deck not found", http.StatusNotFound)
+			return
+		}
+
+		lessonInfo.DeckID = &deck.ID
+	}
+
 	// Lesson card block
 	var cards []schemas.Card
 
@@ -116,7 +141,50 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 	var words []models.Word
 	seen := make(map[uuid.UUID]struct{})
 
-	if len(known) > 0 { // Call Thesaurus only when we have something to send
+	if deck != nil {
+		deckWords, err := h.DeckRepo.GetWordsForLesson(r.Context(), deck.ID, userID, lessonInfo.TotalWords)
+		if err != nil {
+			statusCode = 500
+			logger.Log.Error("Failed to get deck words", zap.Error(err))
+			http.Error(w, "failed to get words for lesson", http.StatusInternalServerError)
+			return
+		}
+		for _, dw := range deckWords {
+			words = append(words, dw)
+			seen[dw.ID] = struct{}{}
+		}
+	}
+
+	// Words assigned by teachers go first until they are learned, soonest due first
+	if deck == nil && h.ClassroomRepo != nil {
+		assigned, err := h.ClassroomRepo.ListAssignedWords(r.Context(), userID, lessonInfo.TotalWords)
+		if err != nil {
+			logger.Log.Error("Failed to get assigned words", zap.Error(err))
+		}
+		for _, aw := range assigned {
+			words = append(words, aw)
+			seen[aw.ID] = struct{}{}
+		}
+	}
+
+	// Words captured while reading go next so they show up in the next lesson
+	var capturedIDs []uuid.UUID
+	if deck == nil && h.CaptureRepo != nil && len(words) < lessonInfo.TotalWords {
+		captured, err := h.CaptureRepo.ListQueuedWords(r.Context(), userID, lessonInfo.TotalWords-len(words))
+		if err != nil {
+			logger.Log.Error("Failed to get captured words", zap.Error(err))
+		}
+		for _, cw := range captured {
+			capturedIDs = append(capturedIDs, cw.ID)
+			if _, exists := seen[cw.ID]; exists {
+				continue
+			}
+			words = append(words, cw)
+			seen[cw.ID] = struct{}{}
+		}
+	}
+
+	if deck == nil && len(known) > 0 { // Call Thesaurus only when we have something to send
 		attempts := 0
 		for len(words) < lessonInfo.TotalWords && attempts < 5 {
 			recs, err := h.ThesaurusClient.Recommend(r.Context(), known)
@@ -130,9 +198,10 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 					break
 				}
 
-				wm, err := h.WordRepo.GetByValue(r.Context(), rec.Word)
+				// Thesaurus spelling and forms may differ from ours, "colour" or "ran"
+				wm, err := h.WordRepo.FindClosest(r.Context(), rec.Word)
 				if err != nil {
-					// Recommendation not present in local DB – skip
+					// Nothing close to the recommendation in local DB – skip
 					continue
 				}
 				if _, exists := seen[wm.ID]; exists {
@@ -146,7 +215,7 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 3. Fallback: get words from not learned table first
-	if len(words) < lessonInfo.TotalWords {
+	if deck == nil && len(words) < lessonInfo.TotalWords {
 		remaining := lessonInfo.TotalWords - len(words)
 		notLearnedWords, err := h.NotLearnedWordRepo.GetRecentlyNotLearnedWords(r.Context(), userID, remaining)
 		if err != nil {
@@ -167,7 +236,7 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 4. Fallback: fill remaining slots with random DB words
-	if len(words) < lessonInfo.TotalWords {
+	if deck == nil && len(words) < lessonInfo.TotalWords {
 		remaining := lessonInfo.TotalWords - len(words)
 		additional, err := h.Repo.GetWordsForLesson(
 			r.Context(),
@@ -205,6 +274,21 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 		words[i], words[j] = words[j], words[i]
 	})
 
+	// Resolve topics of all words with their main topics at once
+	topicIDs := make([]uuid.UUID, 0, len(words))
+	for _, word := range words {
+		if word.TopicID != nil {
+			topicIDs = append(topicIDs, *word.TopicID)
+		}
+	}
+	topics, err := h.TopicRepo.GetWithRoots(r.Context(), topicIDs)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to get topics", zap.Error(err))
+		http.Error(w, "failed to get topic", http.StatusInternalServerError)
+		return
+	}
+
 	// Process words
 	for _, word := range words {
 		var card schemas.Card
@@ -213,29 +297,22 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 		card.Word = word.Word
 		card.Translation = word.Translation
 
-		topic, err := h.TopicRepo.GetByID(r.Context(), *word.TopicID)
-		if err != nil {
-			statusCode = 400
-			http.Error(w, "failed to get topic", http.StatusBadRequest)
-			return
-		}
-
-		// Topic and subtopic process
-		card.Subtopic = topic.Title
-
-		for topic.ParentID != nil {
-			topic, err = h.TopicRepo.GetByID(r.Context(), *topic.ParentID)
-			if err != nil {
+		// Personal words have no topic
+		if word.TopicID != nil {
+			topic, ok := topics[*word.TopicID]
+			if !ok {
 				statusCode = 400
 				http.Error(w, "failed to get topic", http.StatusBadRequest)
 				return
 			}
-		}
 
-		card.Topic = topic.Title
+			// Topic and subtopic process
+			card.Subtopic = topic.Title
+			card.Topic = topic.RootTitle
+		}
 
 		// Sentence process
-		sentences, err := h.SentenceRepo.GetByWordID(r.Context(), word.ID)
+		sentences, err := h.SentenceRepo.GetByWordIDForUser(r.Context(), word.ID, userID)
 		if err != nil {
 			statusCode = 400
 			http.Error(w, "failed to get sentence", http.StatusBadRequest)
@@ -309,25 +386,27 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 			Data: writeWordFromTranslation,
 		})
 
-		// pick_option_sentence
-		var pickOptionSentence schemas.ExercisePickOptionSentence
+		// pick_option_sentence (only for words with an example sentence)
+		if len(sentences) > 0 {
+			var pickOptionSentence schemas.ExercisePickOptionSentence
 
-		// Shuffle options
-		rand.Shuffle(len(pickOptionTranslate.Option), func(i, j int) {
-			pickOptionTranslate.Option[i], pickOptionTranslate.Option[j] = pickOptionTranslate.Option[j], pickOptionTranslate.Option[i]
-		})
+			// Shuffle options
+			rand.Shuffle(len(pickOptionTranslate.Option), func(i, j int) {
+				pickOptionTranslate.Option[i], pickOptionTranslate.Option[j] = pickOptionTranslate.Option[j], pickOptionTranslate.Option[i]
+			})
 
-		pickOptionSentence.Template = replaceWordWithUnderscores(
-			sentences[0].Sentence,
-			word.Word,
-		)
-		pickOptionSentence.CorrectAnswer = word.Word
-		pickOptionSentence.PickOptions = pickOptionTranslate.Option
+			pickOptionSentence.Template = replaceWordWithUnderscores(
+				sentences[0].Sentence,
+				word.Word,
+			)
+			pickOptionSentence.CorrectAnswer = word.Word
+			pickOptionSentence.PickOptions = pickOptionTranslate.Option
 
-		exercises = append(exercises, schemas.Exercise{
-			Type: "pick_option_sentence",
-			Data: pickOptionSentence,
-		})
+			exercises = append(exercises, schemas.Exercise{
+				Type: "pick_option_sentence",
+				Data: pickOptionSentence,
+			})
+		}
 
 		// Pick random exercise
 		randomExercise := exercises[rand.Intn(len(exercises))]
@@ -337,6 +416,12 @@ func (h *LessonHandler) GenerateLesson(w http.ResponseWriter, r *http.Request) {
 		logger.Log.Info("Card generated", zap.Any("card", card))
 	}
 
+	if len(capturedIDs) > 0 {
+		if err := h.CaptureRepo.MarkDequeued(r.Context(), userID, capturedIDs); err != nil {
+			logger.Log.Error("Failed to dequeue captured words", zap.Error(err))
+		}
+	}
+
 	// Generate lesson
 	var lesson schemas.LessonResponse
 	lesson.Lesson = lessonInfo
@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PersonalWordHandler handles words owned by the current user
+type PersonalWordHandler struct {
+	Repo         *postgres.WordRepository
+	SentenceRepo *postgres.SentenceRepository
+	Jobs         *jobs.Client
+}
+
+// buildPersonalWordResponse builds a PersonalWordResponse from a Word with preloaded sentences
+func buildPersonalWordResponse(w *models.Word) schemas.PersonalWordResponse {
+	resp := schemas.PersonalWordResponse{
+		WordResponse: buildWordResponse(w),
+		Sentences:    []schemas.SentenceResponse{},
+	}
+
+	for _, sentence := range w.Sentences {
+		resp.Sentences = append(resp.Sentences, buildSentenceResponse(&sentence))
+	}
+
+	return resp
+}
+
+// validatePersonalWordRequest returns a validation message or an empty string
+func validatePersonalWordRequest(req *schemas.PersonalWordRequest) string {
+	req.Word = strings.TrimSpace(req.Word)
+	req.Translation = strings.TrimSpace(req.Translation)
+
+	if req.Word == "" || req.Translation == "" {
+		return "word and translation are required"
+	}
+
+	if utf8.RuneCountInString(req.Word) > maxWordLength {
+		return "word is too long"
+	}
+
+	if req.CEFRLevel != nil && len(*req.CEFRLevel) > 2 {
+		return "invalid cefr_level"
+	}
+
+	if req.Context != nil && utf8.RuneCountInString(*req.Context) > 100 {
+		return "context is too long"
+	}
+
+	return ""
+}
+
+// ListPersonalWords godoc
+// @Summary      List personal words
+// @Description  Returns words created by the authenticated user
+// @Tags         personal-words
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   schemas.PersonalWordResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/words [get]
+func (h *PersonalWordHandler) ListPersonalWords(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/words"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	words, err := h.Repo.ListByOwner(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch words", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.PersonalWordResponse, 0, len(words))
+	for _, word := range words {
+		resp = append(resp, buildPersonalWordResponse(&word))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetPersonalWord godoc
+// @Summary      Get personal word
+// @Description  Returns a word created by the authenticated user
+// @Tags         personal-words
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Word ID"
+// @Success      200  {object}  schemas.PersonalWordResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/words/{id} [get]
+func (h *PersonalWordHandler) GetPersonalWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/words/{id}"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	word, ok := h.getOwnedWord(r.Context(), user.ID, id)
+	if !ok {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildPersonalWordResponse(word))
+}
+
+// CreatePersonalWord godoc
+// @Summary      Create personal word
+// @Description  Creates a word owned by the authenticated user. Phonetics, audio and distractors are filled in the background.
+// @Tags         personal-words
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      schemas.PersonalWordRequest  true  "Word data"
+// @Success      201  {object}  schemas.PersonalWordResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      409  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/words [post]
+func (h *PersonalWordHandler) CreatePersonalWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/words"
+	method := r.Method
+	statusCode := 201
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.PersonalWordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validatePersonalWordRequest(&req); msg != "" {
+		statusCode = 400
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Repo.GetPersonalByWordTranslationPair(r.Context(), user.ID, req.Word, req.Translation); err == nil {
+		statusCode = 409
+		http.Error(w, "word already exists", http.StatusConflict)
+		return
+	}
+
+	word := models.Word{
+		ID:          uuid.New(),
+		Word:        req.Word,
+		Translation: req.Translation,
+		OwnerID:     &user.ID,
+	}
+
+	if req.PartOfSpeech != nil {
+		word.PartOfSpeech = *req.PartOfSpeech
+	}
+
+	if req.CEFRLevel != nil {
+		word.CEFRLevel = strings.ToLower(*req.CEFRLevel)
+	}
+
+	if req.Context != nil {
+		word.Context = *req.Context
+	}
+
+	if err := h.Repo.Create(r.Context(), &word); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to create word", http.StatusInternalServerError)
+		return
+	}
+
+	for _, s := range req.Sentences {
+		if strings.TrimSpace(s.Sentence) == "" {
+			continue
+		}
+
+		sentence := models.Sentence{
+			ID:          uuid.New(),
+			WordID:      word.ID,
+			Sentence:    s.Sentence,
+			Translation: s.Translation,
+		}
+		if err := h.SentenceRepo.Create(r.Context(), &sentence); err != nil {
+			logger.Log.Warn("failed to create personal word sentence", zap.Error(err), zap.String("word_id", word.ID.String()))
+			continue
+		}
+		word.Sentences = append(word.Sentences, sentence)
+	}
+
+	enqueueWordEnrichment(r.Context(), h.Jobs, word.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildPersonalWordResponse(&word))
+}
+
+// UpdatePersonalWord godoc
+// @Summary      Update personal word
+// @Description  Updates a word owned by the authenticated user. Sentences are replaced when provided.
+// @Tags         personal-words
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                       true  "Word ID"
+// @Param        request  body      schemas.PersonalWordRequest  true  "Word data"
+// @Success      200  {object}  schemas.PersonalWordResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/words/{id} [put]
+func (h *PersonalWordHandler) UpdatePersonalWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/words/{id}"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req schemas.PersonalWordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validatePersonalWordRequest(&req); msg != "" {
+		statusCode = 400
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	word, ok := h.getOwnedWord(r.Context(), user.ID, id)
+	if !ok {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+
+	// A changed spelling makes the old dictionary data wrong, so let enrichment fill it again
+	reEnrich := word.Word != req.Word
+	if reEnrich {
+		word.AudioURL = ""
+		word.Phonetic = ""
+	}
+
+	word.Word = req.Word
+	word.Translation = req.Translation
+
+	if req.PartOfSpeech != nil {
+		word.PartOfSpeech = *req.PartOfSpeech
+	}
+
+	if req.CEFRLevel != nil {
+		word.CEFRLevel = strings.ToLower(*req.CEFRLevel)
+	}
+
+	if req.Context != nil {
+		word.Context = *req.Context
+	}
+
+	sentences := word.Sentences
+	word.Sentences = nil
+	if err := h.Repo.Update(r.Context(), word); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to update word", http.StatusInternalServerError)
+		return
+	}
+
+	if req.Sentences != nil {
+		for _, s := range sentences {
+			if err := h.SentenceRepo.Delete(r.Context(), s.ID); err != nil {
+				statusCode = 500
+				http.Error(w, "failed to update sentences", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		sentences = nil
+		for _, s := range req.Sentences {
+			if strings.TrimSpace(s.Sentence) == "" {
+				continue
+			}
+
+			sentence := models.Sentence{
+				ID:          uuid.New(),
+				WordID:      word.ID,
+				Sentence:    s.Sentence,
+				Translation: s.Translation,
+			}
+			if err := h.SentenceRepo.Create(r.Context(), &sentence); err != nil {
+				statusCode = 500
+				http.Error(w, "failed to update sentences", http.StatusInternalServerError)
+				return
+			}
+			sentences = append(sentences, sentence)
+		}
+		reEnrich = true
+	}
+	word.Sentences = sentences
+
+	if reEnrich {
+		enqueueWordEnrichment(r.Context(), h.Jobs, word.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildPersonalWordResponse(word))
+}
+
+// DeletePersonalWord godoc
+// @Summary      Delete personal word
+// @Description  Deletes a word owned by the authenticated user
+// @Tags         personal-words
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Word ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/words/{id} [delete]
+func (h *PersonalWordHandler) DeletePersonalWord(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/words/{id}"
+	method := r.Method
+	statusCode := 204
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := utils.ParseUUIDParam(r, "id")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getOwnedWord(r.Context(), user.ID, id); !ok {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.Delete(r.Context(), id); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to delete word", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwnedWord returns the word if it exists and belongs to the user
+func (h *PersonalWordHandler) getOwnedWord(ctx context.Context, userID, wordID uuid.UUID) (*models.Word, bool) {
+	word, err := h.Repo.GetByIDWithSentences(ctx, wordID)
+	if err != nil || word.OwnerID == nil || *word.OwnerID != userID {
+		return nil, false
+	}
+
+	return word, true
+}
@@ -2,34 +2,51 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/leaderboard"
 	"fluently/go-backend/internal/repository/models"
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/storage"
 	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // PreferenceHandler is a handler for preferences
 type PreferenceHandler struct {
-	Repo *postgres.PreferenceRepository
+	Repo        *postgres.PreferenceRepository
+	Media       storage.Storage    // avatar uploads are disabled when nil
+	Leaderboard *leaderboard.Board // visibility changes apply from next week when nil
 }
 
+// errAvatarKey is returned for avatar URLs pointing into media storage, those are set by uploads only
+const errAvatarKey = "avatar_image_url must be an absolute URL, upload avatars to /api/v1/preferences/avatar"
+
 // buildPreferencesResponse builds a response from a preference
 func buildPreferencesResponse(pref *models.Preference) schemas.PreferenceResponse {
 	return schemas.PreferenceResponse{
-		ID:              pref.ID,
-		UserID:          pref.UserID,
-		CEFRLevel:       pref.CEFRLevel,
-		FactEveryday:    pref.FactEveryday,
-		Notifications:   pref.Notifications,
-		NotificationsAt: pref.NotificationsAt,
-		WordsPerDay:     pref.WordsPerDay,
-		Goal:            pref.Goal,
-		Subscribed:      pref.Subscribed,
-		AvatarImageURL:  pref.AvatarImageURL,
+		ID:                pref.ID,
+		UserID:            pref.UserID,
+		CEFRLevel:         pref.CEFRLevel,
+		FactEveryday:      pref.FactEveryday,
+		Notifications:     pref.Notifications,
+		NotificationsAt:   pref.NotificationsAt,
+		WordsPerDay:       pref.WordsPerDay,
+		Goal:              pref.Goal,
+		Subscribed:        pref.Subscribed,
+		AvatarImageURL:    utils.MediaURL(pref.AvatarImageURL),
+		TimeZone:          pref.TimeZone,
+		LeaderboardOptOut: pref.LeaderboardOptOut,
 	}
 }
 
@@ -57,18 +74,30 @@ func (h *PreferenceHandler) CreateUserPreferences(w http.ResponseWriter, r *http
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
+	if storage.IsKey(req.AvatarImageURL) {
+		statusCode = 400
+		http.Error(w, errAvatarKey, http.StatusBadRequest)
+		return
+	}
+	if !validTimeZone(req.TimeZone) {
+		statusCode = 400
+		http.Error(w, "invalid time_zone", http.StatusBadRequest)
+		return
+	}
 
 	pref := &models.Preference{
-		ID:              userId,
-		UserID:          userId,
-		CEFRLevel:       req.CEFRLevel,
-		FactEveryday:    req.FactEveryday,
-		Notifications:   req.Notifications,
-		NotificationsAt: req.NotificationAt,
-		WordsPerDay:     req.WordsPerDay,
-		Goal:            req.Goal,
-		Subscribed:      req.Subscribed,
-		AvatarImageURL:  req.AvatarImageURL,
+		ID:                userId,
+		UserID:            userId,
+		CEFRLevel:         req.CEFRLevel,
+		FactEveryday:      req.FactEveryday,
+		Notifications:     req.Notifications,
+		NotificationsAt:   req.NotificationAt,
+		WordsPerDay:       req.WordsPerDay,
+		Goal:              req.Goal,
+		Subscribed:        req.Subscribed,
+		AvatarImageURL:    req.AvatarImageURL,
+		TimeZone:          req.TimeZone,
+		LeaderboardOptOut: req.LeaderboardOptOut,
 	}
 
 	if err := h.Repo.Create(r.Context(), pref); err != nil {
@@ -161,6 +190,16 @@ func (h *PreferenceHandler) UpdateUserPreferences(w http.ResponseWriter, r *http
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.AvatarImageURL != nil && storage.IsKey(*req.AvatarImageURL) {
+		statusCode = 400
+		http.Error(w, errAvatarKey, http.StatusBadRequest)
+		return
+	}
+	if req.TimeZone != nil && !validTimeZone(*req.TimeZone) {
+		statusCode = 400
+		http.Error(w, "invalid time_zone", http.StatusBadRequest)
+		return
+	}
 
 	pref, err := h.Repo.GetByUserID(r.Context(), user.ID)
 	if err != nil {
@@ -191,9 +230,17 @@ func (h *PreferenceHandler) UpdateUserPreferences(w http.ResponseWriter, r *http
 	if req.Subscribed != nil {
 		pref.Subscribed = *req.Subscribed
 	}
+	oldAvatar := pref.AvatarImageURL
 	if req.AvatarImageURL != nil {
 		pref.AvatarImageURL = *req.AvatarImageURL
 	}
+	if req.TimeZone != nil {
+		pref.TimeZone = *req.TimeZone
+	}
+	oldOptOut := pref.LeaderboardOptOut
+	if req.LeaderboardOptOut != nil {
+		pref.LeaderboardOptOut = *req.LeaderboardOptOut
+	}
 	// ============================================================
 
 	if err := h.Repo.Update(r.Context(), pref.ID, &req); err != nil {
@@ -202,6 +249,16 @@ func (h *PreferenceHandler) UpdateUserPreferences(w http.ResponseWriter, r *http
 		return
 	}
 
+	if pref.AvatarImageURL != oldAvatar {
+		h.deleteAvatar(r, oldAvatar)
+	}
+
+	if pref.LeaderboardOptOut != oldOptOut && h.Leaderboard != nil {
+		if err := h.Leaderboard.SetPublic(r.Context(), user.ID, !pref.LeaderboardOptOut, time.Now()); err != nil {
+			logger.Log.Error("Failed to update leaderboard visibility", zap.Error(err), zap.String("user_id", user.ID.String()))
+		}
+	}
+
 	// Return the updated preferences
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(buildPreferencesResponse(pref))
@@ -243,3 +300,199 @@ func (h *PreferenceHandler) DeletePreference(w http.ResponseWriter, r *http.Requ
 	// Return no content
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// UploadAvatar godoc
+// @Summary      Upload avatar
+// @Description  Replaces the user's avatar with an uploaded JPEG, PNG or GIF image. The image is cropped to a square, scaled down and stored as JPEG; the response holds its signed URL.
+// @Tags         preferences
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        avatar  formData  file  true  "Image up to AVATAR_MAX_SIZE bytes (5 MB by default)"
+// @Success      200     {object}  schemas.PreferenceResponse
+// @Failure      400     {object}  schemas.ErrorResponse
+// @Failure      401     {object}  schemas.ErrorResponse
+// @Failure      404     {object}  schemas.ErrorResponse
+// @Failure      413     {object}  schemas.ErrorResponse
+// @Failure      415     {object}  schemas.ErrorResponse
+// @Failure      500     {object}  schemas.ErrorResponse
+// @Failure      503     {object}  schemas.ErrorResponse
+// @Router       /api/v1/preferences/avatar [post]
+func (h *PreferenceHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/preferences/avatar"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if h.Media == nil {
+		statusCode = 503
+		http.Error(w, "media storage is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	cfg := config.GetConfig().Media
+
+	// Leave room for the multipart envelope around the file
+	r.Body = http.MaxBytesReader(w, r.Body, cfg.AvatarMaxSize+64<<10)
+	if err := r.ParseMultipartForm(cfg.AvatarMaxSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode = 413
+			http.Error(w, fmt.Sprintf("avatar must be at most %d bytes", cfg.AvatarMaxSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		statusCode = 400
+		http.Error(w, "invalid multipart form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("avatar")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "avatar is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > cfg.AvatarMaxSize {
+		statusCode = 413
+		http.Error(w, fmt.Sprintf("avatar must be at most %d bytes", cfg.AvatarMaxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "failed to read avatar", http.StatusBadRequest)
+		return
+	}
+
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		statusCode = 415
+		http.Error(w, utils.ErrUnsupportedImage.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	avatar, err := utils.ProcessAvatar(data, cfg.AvatarSize)
+	if errors.Is(err, utils.ErrUnsupportedImage) {
+		statusCode = 415
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pref, err := h.Repo.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 404
+		http.Error(w, "preference not found", http.StatusNotFound)
+		return
+	}
+
+	// Every upload gets a new key, so clients and caches never show a stale image
+	key := fmt.Sprintf("avatars/%s/%s.jpg", user.ID, uuid.New())
+	if err := h.Media.Put(r.Context(), key, avatar, "image/jpeg"); err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to store avatar", zap.Error(err), zap.String("user_id", user.ID.String()))
+		http.Error(w, "failed to store avatar", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.Repo.Update(r.Context(), pref.ID, &schemas.UpdatePreferenceRequest{AvatarImageURL: &key}); err != nil {
+		statusCode = 500
+		h.deleteAvatar(r, key)
+		http.Error(w, "failed to update preference", http.StatusInternalServerError)
+		return
+	}
+
+	h.deleteAvatar(r, pref.AvatarImageURL)
+	pref.AvatarImageURL = key
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildPreferencesResponse(pref))
+}
+
+// DeleteAvatar godoc
+// @Summary      Delete avatar
+// @Description  Removes the user's avatar
+// @Tags         preferences
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  schemas.PreferenceResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      404  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/preferences/avatar [delete]
+func (h *PreferenceHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/preferences/avatar"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pref, err := h.Repo.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 404
+		http.Error(w, "preference not found", http.StatusNotFound)
+		return
+	}
+
+	empty := ""
+	if err := h.Repo.Update(r.Context(), pref.ID, &schemas.UpdatePreferenceRequest{AvatarImageURL: &empty}); err != nil {
+		statusCode = 500
+		http.Error(w, "failed to update preference", http.StatusInternalServerError)
+		return
+	}
+
+	h.deleteAvatar(r, pref.AvatarImageURL)
+	pref.AvatarImageURL = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildPreferencesResponse(pref))
+}
+
+// validTimeZone checks an IANA time zone name, empty means the server default
+func validTimeZone(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil && name != "Local"
+}
+
+// deleteAvatar removes a replaced avatar from storage. External URLs are left alone and
+// failures only leave an orphaned object behind, so they're logged.
+func (h *PreferenceHandler) deleteAvatar(r *http.Request, ref string) {
+	if h.Media == nil || !storage.IsKey(ref) {
+		return
+	}
+	if err := h.Media.Delete(r.Context(), ref); err != nil {
+		logger.Log.Warn("Failed to delete old avatar", zap.String("key", ref), zap.Error(err))
+	}
+}
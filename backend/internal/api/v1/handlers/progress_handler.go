@@ -210,19 +210,25 @@ func (h *ProgressHandler) generateAndStoreConversationTopic(ctx context.Context,
 		}
 	}
 
+	return generateConversationTopic(ctx, h.LLMClient, h.Redis, userID, learnedWords)
+}
+
+// generateConversationTopic generates a conversation topic for learned words and stores it in Redis,
+// where the chat handler picks it up. Progress and sync both call it.
+func generateConversationTopic(ctx context.Context, llm *utils.LLMClient, rdb *goredis.Client, userID uuid.UUID, learnedWords []models.Word) error {
 	if len(learnedWords) == 0 {
 		logger.Log.Info("no learned words found for topic generation")
 		return nil
 	}
 
 	// Generate topic using LLM
-	topic, err := h.generateTopicFromWords(ctx, learnedWords)
+	topic, err := generateTopicFromWords(ctx, llm, learnedWords)
 	if err != nil {
 		return fmt.Errorf("failed to generate topic: %w", err)
 	}
 
 	// Store topic and words in Redis
-	if err := h.storeConversationTopic(ctx, userID, topic, learnedWords); err != nil {
+	if err := storeConversationTopic(ctx, rdb, userID, topic, learnedWords); err != nil {
 		return fmt.Errorf("failed to store conversation topic: %w", err)
 	}
 
@@ -235,7 +241,7 @@ func (h *ProgressHandler) generateAndStoreConversationTopic(ctx context.Context,
 }
 
 // generateTopicFromWords generates a conversation topic based on the learned words
-func (h *ProgressHandler) generateTopicFromWords(ctx context.Context, words []models.Word) (string, error) {
+func generateTopicFromWords(ctx context.Context, llm *utils.LLMClient, words []models.Word) (string, error) {
 	// Build words list for the prompt
 	var wordsList strings.Builder
 	for i, word := range words {
@@ -260,7 +266,7 @@ Return only the topic, nothing else.`, wordsList.String())
 		{Role: "user", Content: prompt},
 	}
 
-	response, err := h.LLMClient.Chat(ctx, llmMsgs, "balanced", nil, nil)
+	response, err := llm.Chat(ctx, llmMsgs, "balanced", nil, nil)
 
 	if err != nil {
 		return "", fmt.Errorf("LLM error: %w", err)
@@ -279,7 +285,7 @@ Return only the topic, nothing else.`, wordsList.String())
 }
 
 // storeConversationTopic stores the generated topic and words in Redis
-func (h *ProgressHandler) storeConversationTopic(ctx context.Context, userID uuid.UUID, topic string, words []models.Word) error {
+func storeConversationTopic(ctx context.Context, rdb *goredis.Client, userID uuid.UUID, topic string, words []models.Word) error {
 	// Convert words to ChatWord format for consistency with chat handler
 	var chatWords []ChatWord
 	for _, word := range words {
@@ -306,7 +312,7 @@ func (h *ProgressHandler) storeConversationTopic(ctx context.Context, userID uui
 
 	// Store in Redis with key "chat_topic:{userID}"
 	key := "chat_topic:" + userID.String()
-	err = h.Redis.Set(ctx, key, data, 24*time.Hour).Err() // expire after a day
+	err = rdb.Set(ctx, key, data, 24*time.Hour).Err() // expire after a day
 	if err != nil {
 		return fmt.Errorf("failed to store topic in Redis: %w", err)
 	}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// SearchHandler handles dictionary search
+type SearchHandler struct {
+	Repo *postgres.WordRepository
+}
+
+// Search godoc
+// @Summary      Search the dictionary
+// @Description  Full-text and fuzzy search over words, translations and example sentences, best matches first.
+// @Description  The last term matches as a prefix for autocomplete, misspelled words match by trigram similarity.
+// @Tags         words
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query     string  true   "Word, translation or a part of them, in English or Russian"
+// @Param        limit  query     int     false  "Max results, up to 50"
+// @Success      200    {object}  schemas.SearchResponse
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/search [get]
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/search"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := normalizeSelection(r.URL.Query().Get("q"))
+	if query == "" || utf8.RuneCountInString(query) > maxWordLength {
+		statusCode = 400
+		http.Error(w, "invalid query", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			statusCode = 400
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+	}
+
+	results, err := h.Repo.Search(r.Context(), user.ID, query, limit)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to search words", zap.Error(err), zap.String("query", query))
+		http.Error(w, "failed to search words", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.SearchResponse{Query: query, Results: make([]schemas.SearchResultResponse, 0, len(results))}
+	for _, result := range results {
+		resp.Results = append(resp.Results, schemas.SearchResultResponse{
+			WordResponse: buildWordResponse(&result.Word),
+			Score:        result.Score,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -6,15 +6,20 @@ import (
 	"strconv"
 	"time"
 
+	"fluently/go-backend/internal/jobs"
 	"fluently/go-backend/internal/repository/models"
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/repository/schemas"
 	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"go.uber.org/zap"
 )
 
 // SentenceHandler handles the sentence endpoint
 type SentenceHandler struct {
 	Repo *postgres.SentenceRepository
+	Jobs *jobs.Client
 }
 
 // buildSentenceResponse builds a SentenceResponse from a Sentence
@@ -24,7 +29,7 @@ func buildSentenceResponse(sentence *models.Sentence) schemas.SentenceResponse {
 		WordID:      sentence.WordID.String(),
 		Sentence:    sentence.Sentence,
 		Translation: sentence.Translation,
-		AudioURL:    sentence.AudioURL,
+		AudioURL:    utils.MediaURL(sentence.AudioURL),
 	}
 }
 
@@ -94,6 +99,12 @@ func (h *SentenceHandler) CreateSentence(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if h.Jobs != nil {
+		if err := h.Jobs.EnqueueEnrichSentence(r.Context(), s.ID); err != nil {
+			logger.Log.Warn("Failed to enqueue sentence enrichment", zap.Error(err), zap.String("sentence_id", s.ID.String()))
+		}
+	}
+
 	// Return the created sentence
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/gamification"
+	"fluently/go-backend/internal/leaderboard"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxSyncEvents       = 500
+	maxSyncKeyLength    = 100
+	defaultSyncPageSize = 200
+	maxSyncPageSize     = 1000
+	maxClockSkew        = 5 * time.Minute // events from the future beyond this are rejected
+)
+
+// SyncHandler handles offline progress sync for mobile clients and the bot
+type SyncHandler struct {
+	Repo               *postgres.SyncRepository
+	WordRepo           *postgres.WordRepository
+	LearnedWordRepo    *postgres.LearnedWordRepository
+	NotLearnedWordRepo *postgres.NotLearnedWordRepository
+	Gamification       *postgres.GamificationRepository // no XP is awarded when nil
+	PreferenceRepo     *postgres.PreferenceRepository
+	Leaderboard        *leaderboard.Board // weekly boards aren't updated when nil
+	LLMClient          *utils.LLMClient   // conversation topics aren't generated when nil
+	Redis              *goredis.Client
+}
+
+// Sync godoc
+// @Summary      Sync progress
+// @Description  Pushes a batch of client-timestamped progress events and returns server changes after the cursor.
+// @Description  Each event is applied once per idempotency key. Conflicts on a word are resolved by the latest
+// @Description  client_time, equal times by the greater idempotency key. Events are applied in that order
+// @Description  whatever their order in the batch.
+// @Description  A reviewed event updates the confidence and revisions of a learned word and is skipped for other words.
+// @Tags         sync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sync  body      schemas.SyncRequest  true  "Events and cursor"
+// @Success      200   {object}  schemas.SyncResponse
+// @Failure      400   {object}  schemas.ErrorResponse
+// @Failure      401   {object}  schemas.ErrorResponse
+// @Failure      500   {object}  schemas.ErrorResponse
+// @Router       /api/v1/sync [post]
+func (h *SyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/sync"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Events) > maxSyncEvents {
+		statusCode = 400
+		http.Error(w, "too many events, at most 500 per request", http.StatusBadRequest)
+		return
+	}
+
+	if req.Cursor < 0 || utf8.RuneCountInString(req.DeviceID) > maxSyncKeyLength {
+		statusCode = 400
+		http.Error(w, "invalid cursor or device_id", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]schemas.SyncEventResult, len(req.Events))
+	var activities []gamification.Activity
+	var learnedWordIDs []uuid.UUID
+
+	// Apply events in conflict order so the outcome doesn't depend on the batch order
+	order := make([]int, len(req.Events))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := req.Events[order[a]], req.Events[order[b]]
+		if !ea.ClientTime.Equal(eb.ClientTime) {
+			return ea.ClientTime.Before(eb.ClientTime)
+		}
+		return ea.IdempotencyKey < eb.IdempotencyKey
+	})
+
+	for _, i := range order {
+		ev := req.Events[i]
+		results[i].IdempotencyKey = ev.IdempotencyKey
+
+		if msg := h.validateSyncEvent(r.Context(), user.ID, &ev); msg != "" {
+			results[i].Status = models.SyncStatusRejected
+			results[i].Error = msg
+			continue
+		}
+
+		event := &models.SyncEvent{
+			UserID:          user.ID,
+			IdempotencyKey:  ev.IdempotencyKey,
+			DeviceID:        req.DeviceID,
+			Type:            ev.Type,
+			WordID:          ev.WordID,
+			ClientTime:      ev.ClientTime.UTC(),
+			ConfidenceScore: ev.ConfidenceScore,
+			CntReviewed:     ev.CntReviewed,
+		}
+
+		status, moved, err := h.Repo.Apply(r.Context(), event)
+		if err != nil {
+			// Already applied events are kept, resending the batch is safe
+			statusCode = 500
+			logger.Log.Error("Failed to apply sync event", zap.Error(err), zap.String("idempotency_key", ev.IdempotencyKey))
+			http.Error(w, "failed to apply events", http.StatusInternalServerError)
+			return
+		}
+		results[i].Status = status
+
+		if moved {
+			activities = append(activities, syncActivity(event))
+		}
+		if status == models.SyncStatusApplied && event.Type == models.SyncEventLearned {
+			learnedWordIDs = append(learnedWordIDs, event.WordID)
+		}
+	}
+
+	var events []gamification.Event
+	if h.Gamification != nil && len(activities) > 0 {
+		// Progress is already stored, a failure here must not make clients resend it
+		_, events, err = h.Gamification.Record(r.Context(), user.ID, activities)
+		if err != nil {
+			logger.Log.Error("Failed to record sync activity", zap.Error(err), zap.String("user_id", user.ID.String()))
+		}
+		addLeaderboardXP(r.Context(), h.Leaderboard, h.PreferenceRepo, user.ID, events)
+	}
+
+	// The chat picks the conversation topic from the last learned words, like after /progress
+	if h.LLMClient != nil && h.Redis != nil && len(learnedWordIDs) > 0 {
+		words, err := h.WordRepo.GetByIDs(r.Context(), learnedWordIDs)
+		if err == nil {
+			err = generateConversationTopic(r.Context(), h.LLMClient, h.Redis, user.ID, words)
+		}
+		if err != nil {
+			logger.Log.Warn("failed to generate conversation topic", zap.Error(err))
+		}
+	}
+
+	resp, err := h.buildChanges(r.Context(), user.ID, req.Cursor, defaultSyncPageSize)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to build sync changes", zap.Error(err))
+		http.Error(w, "failed to fetch changes", http.StatusInternalServerError)
+		return
+	}
+	resp.Results = results
+	if len(events) > 0 {
+		resp.Events = buildGamificationEvents(events)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetChanges godoc
+// @Summary      Get sync changes
+// @Description  Returns the current state of words whose progress changed after the cursor, through sync or any other API
+// @Tags         sync
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor  query     int  false  "Cursor from the previous sync, 0 for all changes"
+// @Param        limit   query     int  false  "Max changes, up to 1000"
+// @Success      200     {object}  schemas.SyncResponse
+// @Failure      400     {object}  schemas.ErrorResponse
+// @Failure      401     {object}  schemas.ErrorResponse
+// @Failure      500     {object}  schemas.ErrorResponse
+// @Router       /api/v1/sync/changes [get]
+func (h *SyncHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/sync/changes"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var cursor int64
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		cursor, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || cursor < 0 {
+			statusCode = 400
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := defaultSyncPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			statusCode = 400
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxSyncPageSize {
+			limit = maxSyncPageSize
+		}
+	}
+
+	resp, err := h.buildChanges(r.Context(), user.ID, cursor, limit)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to build sync changes", zap.Error(err))
+		http.Error(w, "failed to fetch changes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// validateSyncEvent returns a rejection message or an empty string
+func (h *SyncHandler) validateSyncEvent(ctx context.Context, userID uuid.UUID, ev *schemas.SyncEventRequest) string {
+	if ev.IdempotencyKey == "" || utf8.RuneCountInString(ev.IdempotencyKey) > maxSyncKeyLength {
+		return "idempotency_key is required and must be at most 100 characters"
+	}
+
+	if ev.Type != models.SyncEventLearned && ev.Type != models.SyncEventNotLearned && ev.Type != models.SyncEventReviewed {
+		return "unknown event type"
+	}
+
+	if ev.ClientTime.IsZero() {
+		return "client_time is required"
+	}
+
+	if ev.ClientTime.After(time.Now().Add(maxClockSkew)) {
+		return "client_time is in the future"
+	}
+
+	word, err := h.WordRepo.GetByID(ctx, ev.WordID)
+	if err != nil || (word.OwnerID != nil && *word.OwnerID != userID) {
+		return "word not found"
+	}
+
+	return ""
+}
+
+// syncActivity returns the activity of a sync event that moved a word between lists.
+// The key is the word, so toggling a word between lists earns its XP once.
+func syncActivity(event *models.SyncEvent) gamification.Activity {
+	activity := gamification.Activity{
+		Type: gamification.ActivityWordLearned,
+		Key:  "sync:" + event.Type + ":" + event.WordID.String(),
+		Time: event.ClientTime,
+	}
+	if event.Type == models.SyncEventNotLearned {
+		activity.Type = gamification.ActivityWordMissed
+	}
+	return activity
+}
+
+// buildChanges returns the current state of words changed after the cursor
+func (h *SyncHandler) buildChanges(ctx context.Context, userID uuid.UUID, cursor int64, limit int) (schemas.SyncResponse, error) {
+	resp := schemas.SyncResponse{
+		Changes: []schemas.SyncChange{},
+		Cursor:  cursor,
+	}
+
+	changes, err := h.Repo.ListChanges(ctx, userID, cursor, limit+1)
+	if err != nil {
+		return resp, err
+	}
+
+	if len(changes) > limit {
+		changes = changes[:limit]
+		resp.HasMore = true
+	}
+
+	for _, c := range changes {
+		change := schemas.SyncChange{
+			Seq:    c.Seq,
+			WordID: c.WordID,
+			State:  "none",
+		}
+
+		lw, err := h.LearnedWordRepo.GetByUserWordID(ctx, userID, c.WordID)
+		switch {
+		case err == nil:
+			change.State = models.SyncEventLearned
+			change.LearnedAt = &lw.LearnedAt
+			change.LastReviewed = &lw.LastReviewed
+			change.ConfidenceScore = lw.ConfidenceScore
+			change.CntReviewed = lw.CountOfRevisions
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			notLearned, err := h.NotLearnedWordRepo.Exists(ctx, userID, c.WordID)
+			if err != nil {
+				return resp, err
+			}
+			if notLearned {
+				change.State = models.SyncEventNotLearned
+			}
+		default:
+			return resp, err
+		}
+
+		resp.Changes = append(resp.Changes, change)
+		resp.Cursor = c.Seq
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/telegramauth"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// verifyTelegramAuth checks the Login Widget data or Mini App initData of the request
+func verifyTelegramAuth(req schemas.TelegramAuthRequest) (*telegramauth.User, error) {
+	cfg := config.GetConfig().Telegram
+
+	switch {
+	case req.Widget != nil && req.InitData != "":
+		return nil, telegramauth.ErrInvalidData
+	case req.Widget != nil:
+		return telegramauth.VerifyWidget(cfg.BotToken, telegramauth.WidgetData{
+			ID:        req.Widget.ID,
+			FirstName: req.Widget.FirstName,
+			LastName:  req.Widget.LastName,
+			Username:  req.Widget.Username,
+			PhotoURL:  req.Widget.PhotoURL,
+			AuthDate:  req.Widget.AuthDate,
+			Hash:      req.Widget.Hash,
+		}, time.Now(), cfg.AuthMaxAge)
+	case req.InitData != "":
+		return telegramauth.VerifyInitData(cfg.BotToken, req.InitData, time.Now(), cfg.AuthMaxAge)
+	default:
+		return nil, telegramauth.ErrInvalidData
+	}
+}
+
+// writeTelegramAuthError maps verification errors to responses
+func writeTelegramAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, telegramauth.ErrNoBotToken):
+		logger.Log.Error("Telegram sign in is not configured", zap.Error(err))
+		http.Error(w, "telegram sign in is not available", http.StatusServiceUnavailable)
+	case errors.Is(err, telegramauth.ErrInvalidData):
+		http.Error(w, "invalid telegram auth data", http.StatusBadRequest)
+	default:
+		logger.Log.Warn("Telegram auth verification failed", zap.Error(err))
+		http.Error(w, "invalid telegram auth data", http.StatusUnauthorized)
+	}
+}
+
+// TelegramAuthHandler godoc
+// @Summary      Authenticates with Telegram
+// @Description  Signs in with data of the Telegram Login Widget or initData of a Telegram Mini App,
+// @Description  both are verified with the bot token. A new account is created for unknown Telegram users.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      schemas.TelegramAuthRequest  true  "Widget data or Mini App initData"
+// @Success      200      {object}  schemas.JwtResponse
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Failure      503      {object}  schemas.ErrorResponse
+// @Router       /auth/telegram [post]
+func (h *Handlers) TelegramAuthHandler(w http.ResponseWriter, r *http.Request) {
+	var req schemas.TelegramAuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Invalid request", zap.Error(err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	tgUser, err := verifyTelegramAuth(req)
+	if err != nil {
+		writeTelegramAuthError(w, err)
+		return
+	}
+
+	// Check if the Telegram account is already linked to a user
+	user, err := h.UserRepo.GetByTelegramID(r.Context(), tgUser.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			user = h.createUserViaTelegram(r, tgUser)
+		} else {
+			logger.Log.Error("Failed to get user", zap.Error(err))
+			http.Error(w, "failed to get user", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		// Update last login time for existing user
+		if err := h.UserRepo.UpdateLastLogin(r.Context(), user.ID); err != nil {
+			logger.Log.Error("Failed to update last login time", zap.Error(err))
+		}
+	}
+
+	if user == nil {
+		http.Error(w, "failed to create user", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := h.generateTokens(user, w, r)
+	if err != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// createUserViaTelegram creates a new user via Telegram.
+// Telegram shares no email, so the account gets a placeholder address nobody can receive mail at.
+func (h *Handlers) createUserViaTelegram(r *http.Request, tgUser *telegramauth.User) *models.User {
+	logger.Log.Info("Creating new user with Telegram ID", zap.Int64("telegram_id", tgUser.ID))
+
+	name := []rune(tgUser.Name())
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	telegramID := tgUser.ID
+	userID := uuid.New()
+	newUser := &models.User{
+		ID:           userID,
+		Name:         string(name),
+		Email:        fmt.Sprintf("tg%d@telegram.invalid", tgUser.ID),
+		PasswordHash: "",
+		Provider:     "telegram",
+		TelegramID:   &telegramID,
+		Role:         "user",
+		IsActive:     true,
+		LastLoginAt:  time.Now(),
+	}
+
+	if err := h.UserRepo.Create(r.Context(), newUser); err != nil {
+		logger.Log.Error("Failed to create user", zap.Error(err))
+		return nil
+	}
+
+	userPreferences := models.Preference{
+		UserID:          userID,
+		Subscribed:      false,
+		CEFRLevel:       "A1",
+		FactEveryday:    false,
+		Notifications:   false,
+		NotificationsAt: nil,
+		WordsPerDay:     10,
+		Goal:            "Learn new words",
+		AvatarImageURL:  tgUser.PhotoURL,
+	}
+
+	if err := h.UserPrefRepo.Create(r.Context(), &userPreferences); err != nil {
+		logger.Log.Error("Failed to create user preferences", zap.Error(err))
+		return nil
+	}
+
+	return newUser
+}
+
+// LinkTelegram godoc
+// @Summary      Link Telegram account
+// @Description  Links the Telegram account that signed the Login Widget data or Mini App initData to the current user
+// @Tags         telegram
+// @Accept       json
+// @Produce      json
+// @Param        request  body      schemas.TelegramAuthRequest  true  "Widget data or Mini App initData"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  schemas.ErrorResponse
+// @Failure      401      {object}  schemas.ErrorResponse
+// @Failure      409      {object}  schemas.ErrorResponse
+// @Failure      500      {object}  schemas.ErrorResponse
+// @Failure      503      {object}  schemas.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/telegram/link [post]
+func (h *TelegramHandler) LinkTelegram(w http.ResponseWriter, r *http.Request) {
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req schemas.TelegramAuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tgUser, err := verifyTelegramAuth(req)
+	if err != nil {
+		writeTelegramAuthError(w, err)
+		return
+	}
+
+	linked, err := h.UserRepo.GetByTelegramID(r.Context(), tgUser.ID)
+	switch {
+	case err == nil && linked.ID != user.ID:
+		http.Error(w, "telegram account is linked to another user", http.StatusConflict)
+		return
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Log.Error("Failed to find user", zap.Error(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.UserRepo.LinkTelegramID(r.Context(), user.ID, tgUser.ID); err != nil {
+		logger.Log.Error("Failed to link telegram", zap.Error(err))
+		http.Error(w, "failed to link account", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Telegram account successfully linked",
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/middleware"
 	"fluently/go-backend/internal/repository/models"
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/repository/schemas"
@@ -27,6 +28,7 @@ type TelegramHandler struct {
 	UserRepo         *postgres.UserRepository
 	LinkTokenRepo    *postgres.LinkTokenRepository
 	RefreshTokenRepo *postgres.RefreshTokenRepository
+	AuditRepo        *postgres.ServiceAuditRepository
 }
 
 // generateLinkToken generates a random link token
@@ -44,9 +46,12 @@ func generateLinkToken() (string, error) {
 // @Tags         telegram
 // @Accept       json
 // @Produce      json
+// @Security     ServiceSignature
 // @Param        request  body      schemas.TelegramLinkRequest  true  "Telegram ID"
 // @Success      200  {object}  schemas.TelegramLinkResponse
 // @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      403  {object}  schemas.ErrorResponse
 // @Failure      409  {object}  schemas.ErrorResponse
 // @Failure      500  {object}  schemas.ErrorResponse
 // @Router       /telegram/create-link [post]
@@ -115,9 +120,12 @@ func (h *TelegramHandler) CreateLinkToken(w http.ResponseWriter, r *http.Request
 // @Tags         telegram
 // @Accept       json
 // @Produce      json
+// @Security     ServiceSignature
 // @Param        request  body      schemas.TelegramLinkStatusRequest  true  "Telegram ID"
 // @Success      200  {object}  schemas.TelegramLinkStatusResponse
 // @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      403  {object}  schemas.ErrorResponse
 // @Failure      500  {object}  schemas.ErrorResponse
 // @Router       /telegram/check-status [post]
 func (h *TelegramHandler) CheckLinkStatus(w http.ResponseWriter, r *http.Request) {
@@ -396,9 +404,12 @@ func (h *TelegramHandler) LinkGoogleCallback(w http.ResponseWriter, r *http.Requ
 // @Tags         telegram
 // @Accept       json
 // @Produce      json
+// @Security     ServiceSignature
 // @Param        request  body      schemas.TelegramTokenRequest  true  "Telegram ID"
 // @Success      200  {object}  schemas.JwtResponse
 // @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      403  {object}  schemas.ErrorResponse
 // @Failure      404  {object}  schemas.ErrorResponse
 // @Failure      500  {object}  schemas.ErrorResponse
 // @Router       /telegram/get-tokens [post]
@@ -423,6 +434,23 @@ func (h *TelegramHandler) GetJWTTokens(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Tokens are only minted once recorded, the audit log must not miss any
+	audit := &models.ServiceTokenAudit{
+		Action:     "telegram.get_tokens",
+		UserID:     user.ID,
+		TelegramID: req.TelegramID,
+		RemoteAddr: r.RemoteAddr,
+		RequestID:  r.Header.Get("X-Request-ID"),
+	}
+	if service := middleware.GetServiceFromContext(r.Context()); service != nil {
+		audit.ServiceID = service.ID
+	}
+	if err := h.AuditRepo.Create(r.Context(), audit); err != nil {
+		logger.Log.Error("Failed to record minted tokens", zap.Error(err))
+		http.Error(w, "failed to generate tokens", http.StatusInternalServerError)
+		return
+	}
+
 	// Generate JWT tokens
 	resp, err := h.generateTokens(user, r)
 	if err != nil {
@@ -437,7 +465,10 @@ func (h *TelegramHandler) GetJWTTokens(w http.ResponseWriter, r *http.Request) {
 		// Don't fail the request for this
 	}
 
-	logger.Log.Info("JWT tokens generated for telegram user", zap.Int64("telegram_id", req.TelegramID))
+	logger.Log.Info("JWT tokens generated for telegram user",
+		zap.Int64("telegram_id", req.TelegramID),
+		zap.String("user_id", user.ID.String()),
+		zap.String("service", audit.ServiceID))
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,8 +11,11 @@ import (
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/repository/schemas"
 	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // TopicHandler handles the topic endpoint
@@ -21,10 +25,14 @@ type TopicHandler struct {
 
 // buildTopicResponse builds a TopicResponse from a Topic
 func buildTopicResponse(topic *models.Topic) schemas.TopicResponse {
-	return schemas.TopicResponse{
+	resp := schemas.TopicResponse{
 		ID:    topic.ID.String(),
 		Title: topic.Title,
 	}
+	if topic.ParentID != nil {
+		resp.ParentID = topic.ParentID.String()
+	}
+	return resp
 }
 
 // GetTopic gets a topic
@@ -76,26 +84,22 @@ func (h *TopicHandler) GetMainTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	topic, err := h.Repo.GetByID(r.Context(), id)
-	if err != nil {
+	// The main topic (the topic that has no parent) comes first
+	hierarchy, err := h.Repo.GetTopicHierarchy(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		statusCode = 404
 		http.Error(w, "topic not found", http.StatusNotFound)
 		return
 	}
-
-	// Find the main topic (the topic that has no parent)
-	for topic.ParentID != nil {
-		topic, err = h.Repo.GetByID(r.Context(), *topic.ParentID)
-		if err != nil {
-			statusCode = 500
-			http.Error(w, "failed to fetch parent topic", http.StatusInternalServerError)
-			return
-		}
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch parent topic", http.StatusInternalServerError)
+		return
 	}
 
 	// Return the topic
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(buildTopicResponse(topic))
+	json.NewEncoder(w).Encode(buildTopicResponse(&hierarchy[0]))
 }
 
 // GetPathToMainTopic gets the path to the main topic
@@ -117,31 +121,22 @@ func (h *TopicHandler) GetPathToMainTopic(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var path []uuid.UUID
-
-	topic, err := h.Repo.GetByID(r.Context(), id)
-	if err != nil {
+	hierarchy, err := h.Repo.GetTopicHierarchy(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		statusCode = 404
 		http.Error(w, "topic not found", http.StatusNotFound)
 		return
 	}
-	path = append(path, topic.ID)
-
-	// Find the main topic (the topic that has no parent)
-	for topic.ParentID != nil {
-		topic, err = h.Repo.GetByID(r.Context(), *topic.ParentID)
-		if err != nil {
-			statusCode = 500
-			http.Error(w, "failed to fetch parent topic", http.StatusInternalServerError)
-			return
-		}
-		path = append(path, topic.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to fetch parent topic", http.StatusInternalServerError)
+		return
 	}
 
-	// Convert the path to a slice of strings
-	strPath := make([]string, len(path))
-	for i, id := range path {
-		strPath[i] = id.String()
+	// The path goes from the topic up to the main topic
+	strPath := make([]string, 0, len(hierarchy))
+	for i := len(hierarchy) - 1; i >= 0; i-- {
+		strPath = append(strPath, hierarchy[i].ID.String())
 	}
 
 	// Return the path
@@ -236,13 +231,19 @@ func (h *TopicHandler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// GetTopics returns all topics that start with a capital letter
-// GetTopics возвращает список тем, которые начинаются с заглавной буквы
+// GetTopics returns a page of topics
+// GetTopics возвращает страницу тем
 // @Summary Получить список тем
-// @Description Возвращает все темы, которые начинаются с заглавной буквы (только названия)
+// @Description Возвращает страницу тем, отсортированных по названию, и next_cursor следующей страницы.
 // @Tags topics
 // @Produce json
-// @Success 200 {array} schemas.TopicTitleResponse
+// @Param parent_id query string false "Только подтемы темы"
+// @Param root      query bool   false "Только главные темы"
+// @Param sort      query string false "Сортировка, -title по убыванию" Enums(title, -title)
+// @Param limit     query int    false "Размер страницы, до 200"
+// @Param cursor    query string false "next_cursor предыдущей страницы"
+// @Success 200 {object} schemas.TopicListResponse
+// @Failure 400 {object} schemas.ErrorResponse
 // @Failure 500 {object} schemas.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/topics [get]
@@ -256,23 +257,124 @@ func (h *TopicHandler) GetTopics(w http.ResponseWriter, r *http.Request) {
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
 
-	// Fetch all topics that start with a capital letter from the repository
-	topics, err := h.Repo.GetAllStartingWithCapital(r.Context())
+	params, err := utils.ParseListParams(r, defaultListPageSize, maxListPageSize, "title")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := postgres.TopicListFilter{}
+	query := r.URL.Query()
+	if v := query.Get("root"); v != "" {
+		filter.Root, err = strconv.ParseBool(v)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid root", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("parent_id"); v != "" {
+		parentID, err := uuid.Parse(v)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid parent_id", http.StatusBadRequest)
+			return
+		}
+		filter.ParentID = &parentID
+	}
+
+	topics, next, err := h.Repo.List(r.Context(), filter, postgres.Page{
+		Sort:  params.Sort,
+		Desc:  params.Desc,
+		After: params.After,
+		Limit: params.Limit,
+	})
+	if errors.Is(err, postgres.ErrInvalidCursor) {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to list topics", zap.Error(err))
+		http.Error(w, "failed to fetch topics", http.StatusInternalServerError)
+		return
+	}
+
+	resp := schemas.TopicListResponse{
+		Topics:     make([]schemas.TopicResponse, 0, len(topics)),
+		NextCursor: params.NextCursor(next),
+	}
+	for i := range topics {
+		resp.Topics = append(resp.Topics, buildTopicResponse(&topics[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildTopicTreeNodeResponse builds a TopicTreeNodeResponse from a TopicNode and its subtopics
+func buildTopicTreeNodeResponse(node *postgres.TopicNode) schemas.TopicTreeNodeResponse {
+	resp := schemas.TopicTreeNodeResponse{
+		ID:           node.ID.String(),
+		Title:        node.Title,
+		Words:        node.Words,
+		WordsByLevel: node.WordsByLevel,
+		Learned:      node.Learned,
+		NotLearned:   node.NotLearned,
+		Children:     make([]schemas.TopicTreeNodeResponse, 0, len(node.Children)),
+	}
+	if node.ParentID != nil {
+		resp.ParentID = node.ParentID.String()
+	}
+	for _, child := range node.Children {
+		resp.Children = append(resp.Children, buildTopicTreeNodeResponse(child))
+	}
+	return resp
+}
+
+// GetTopicTree возвращает всё дерево тем с количеством слов и прогрессом пользователя
+// @Summary Получить дерево тем
+// @Description Возвращает главные темы с подтемами. Для каждой темы — количество слов по уровням CEFR,
+// @Description выученные и невыученные слова текущего пользователя; счётчики включают подтемы.
+// @Tags topics
+// @Produce json
+// @Success 200 {object} schemas.TopicTreeResponse
+// @Failure 401 {object} schemas.ErrorResponse
+// @Failure 500 {object} schemas.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/topics/tree [get]
+func (h *TopicHandler) GetTopicTree(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/topics/tree"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	roots, err := h.Repo.GetTree(r.Context(), user.ID)
 	if err != nil {
 		statusCode = 500
+		logger.Log.Error("Failed to get topic tree", zap.Error(err))
 		http.Error(w, "failed to fetch topics", http.StatusInternalServerError)
 		return
 	}
 
-	// Build response with only titles
-	var resp []schemas.TopicTitleResponse
-	for _, topic := range topics {
-		resp = append(resp, schemas.TopicTitleResponse{
-			Title: topic.Title,
-		})
+	resp := schemas.TopicTreeResponse{Topics: make([]schemas.TopicTreeNodeResponse, 0, len(roots))}
+	for _, root := range roots {
+		resp.Topics = append(resp.Topics, buildTopicTreeNodeResponse(root))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 }
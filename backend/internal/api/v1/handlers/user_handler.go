@@ -114,6 +114,13 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
 
+	caller, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	id, err := utils.ParseUUIDParam(r, "id")
 	if err != nil {
 		statusCode = 400
@@ -128,6 +135,13 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	isAdmin := caller.Role == "admin"
+	if !isAdmin && caller.ID != id {
+		statusCode = 403
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
 	user, err := h.Repo.GetByID(r.Context(), id)
 	if err != nil {
 		statusCode = 404
@@ -135,13 +149,22 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Users edit their name and email only, the role gates admin and teacher endpoints
+	if !isAdmin && req.Role != "" && req.Role != user.Role {
+		statusCode = 403
+		http.Error(w, "only admins can change the role", http.StatusForbidden)
+		return
+	}
+
 	user.Name = req.Name
 	user.Email = req.Email
-	user.Role = req.Role
-	user.IsActive = req.IsActive
-	user.Provider = req.Provider
-	user.GoogleID = req.GoogleID
-	user.PasswordHash = req.PasswordHash
+	if isAdmin {
+		user.Role = req.Role
+		user.IsActive = req.IsActive
+		user.Provider = req.Provider
+		user.GoogleID = req.GoogleID
+		user.PasswordHash = req.PasswordHash
+	}
 
 	if err := h.Repo.Update(r.Context(), user); err != nil {
 		statusCode = 500
@@ -166,6 +189,13 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
 
+	caller, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	id, err := utils.ParseUUIDParam(r, "id")
 	if err != nil {
 		statusCode = 400
@@ -173,6 +203,12 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if caller.Role != "admin" && caller.ID != id {
+		statusCode = 403
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
 	if err := h.Repo.Delete(r.Context(), id); err != nil {
 		statusCode = 500
 		http.Error(w, "failed to delete user", http.StatusInternalServerError)
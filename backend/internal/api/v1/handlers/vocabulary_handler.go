@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/repository/schemas"
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxVocabularyFileSize limits the size of uploaded vocabulary files
+const maxVocabularyFileSize = 32 << 20
+
+// maxWordLength matches the size of the words.word column
+const maxWordLength = 30
+
+// VocabularyHandler handles vocabulary import and export
+type VocabularyHandler struct {
+	WordRepo           *postgres.WordRepository
+	SentenceRepo       *postgres.SentenceRepository
+	LearnedWordRepo    *postgres.LearnedWordRepository
+	NotLearnedWordRepo *postgres.NotLearnedWordRepository
+	Jobs               *jobs.Client
+}
+
+// ImportVocabulary godoc
+// @Summary      Import vocabulary
+// @Description  Imports a CSV, TSV or Anki .apkg file into the user's vocabulary. Rows are matched to existing words by word and translation, unmatched rows become personal words. Each word is marked as learned or not learned.
+// @Tags         vocabulary
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file    formData  file    true   "CSV, TSV or .apkg file"
+// @Param        format  formData  string  false  "File format (csv, tsv, apkg), detected from the file name by default"
+// @Param        status  formData  string  false  "Status for all rows (learned, not_learned), overrides the status column"
+// @Success      200  {object}  schemas.VocabularyImportResponse
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/vocabulary/import [post]
+func (h *VocabularyHandler) ImportVocabulary(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/vocabulary/import"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxVocabularyFileSize)
+	if err := r.ParseMultipartForm(maxVocabularyFileSize); err != nil {
+		statusCode = 400
+		http.Error(w, "invalid multipart form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	var entries []utils.VocabularyEntry
+	switch format {
+	case "csv", "txt":
+		entries, err = utils.ParseVocabularyCSV(bytes.NewReader(data), ',')
+	case "tsv":
+		entries, err = utils.ParseVocabularyCSV(bytes.NewReader(data), '\t')
+	case "apkg":
+		entries, err = utils.ReadAnkiPackage(r.Context(), data, config.GetConfig().Media.ApkgMaxSize)
+	default:
+		statusCode = 400
+		http.Error(w, "unsupported format, use csv, tsv or apkg", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		statusCode = 400
+		http.Error(w, "failed to parse file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := ""
+	if value := r.FormValue("status"); value != "" {
+		status = utils.NormalizeVocabularyStatus(value)
+		if status == "" {
+			statusCode = 400
+			http.Error(w, "invalid status, use learned or not_learned", http.StatusBadRequest)
+			return
+		}
+	}
+
+	resp := schemas.VocabularyImportResponse{Total: len(entries)}
+	for i, entry := range entries {
+		if status != "" {
+			entry.Status = status
+		}
+
+		if err := h.importEntry(r.Context(), user.ID, entry, &resp); err != nil {
+			resp.Skipped++
+			resp.Errors = append(resp.Errors, fmt.Sprintf("row %d (%s): %s", i+1, entry.Word, err.Error()))
+		}
+	}
+
+	logger.Log.Info("vocabulary imported",
+		zap.String("user_id", user.ID.String()),
+		zap.String("format", format),
+		zap.Int("total", resp.Total),
+		zap.Int("created", resp.Created),
+		zap.Int("skipped", resp.Skipped),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ExportVocabulary godoc
+// @Summary      Export vocabulary
+// @Description  Exports the user's learned and not learned words with sentences and audio URLs as CSV, TSV or an Anki .apkg deck
+// @Tags         vocabulary
+// @Produce      text/csv
+// @Produce      application/octet-stream
+// @Security     BearerAuth
+// @Param        format  query  string  false  "Export format (csv, tsv, apkg)"  default(csv)
+// @Success      200  {file}  file
+// @Failure      400  {object}  schemas.ErrorResponse
+// @Failure      401  {object}  schemas.ErrorResponse
+// @Failure      500  {object}  schemas.ErrorResponse
+// @Router       /api/v1/me/vocabulary/export [get]
+func (h *VocabularyHandler) ExportVocabulary(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/me/vocabulary/export"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "tsv" && format != "apkg" {
+		statusCode = 400
+		http.Error(w, "unsupported format, use csv, tsv or apkg", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.collectEntries(r.Context(), user.ID)
+	if err != nil {
+		statusCode = 500
+		http.Error(w, "failed to load vocabulary", http.StatusInternalServerError)
+		return
+	}
+
+	// Build the file in memory so a failure can still be reported as an error response
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case "csv":
+		err = utils.WriteVocabularyCSV(&buf, entries, ',')
+	case "tsv":
+		contentType = "text/tab-separated-values; charset=utf-8"
+		err = utils.WriteVocabularyCSV(&buf, entries, '\t')
+	case "apkg":
+		contentType = "application/octet-stream"
+		err = utils.WriteAnkiPackage(r.Context(), &buf, "Fluently", entries)
+	}
+	if err != nil {
+		logger.Log.Error("failed to export vocabulary", zap.Error(err), zap.String("user_id", user.ID.String()))
+		statusCode = 500
+		http.Error(w, "failed to export vocabulary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="fluently-vocabulary.%s"`, format))
+	w.Write(buf.Bytes())
+}
+
+// importEntry matches or creates the word of an entry and marks it for the user
+func (h *VocabularyHandler) importEntry(ctx context.Context, userID uuid.UUID, entry utils.VocabularyEntry, resp *schemas.VocabularyImportResponse) error {
+	if entry.Word == "" {
+		return errors.New("word is empty")
+	}
+	if utf8.RuneCountInString(entry.Word) > maxWordLength {
+		return fmt.Errorf("word is longer than %d characters", maxWordLength)
+	}
+
+	word, err := h.WordRepo.GetByWordTranslationPair(ctx, entry.Word, entry.Translation)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		word, err = h.WordRepo.GetPersonalByWordTranslationPair(ctx, userID, entry.Word, entry.Translation)
+	}
+
+	switch {
+	case err == nil:
+		resp.Matched++
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		word, err = h.createPersonalWord(ctx, userID, entry)
+		if err != nil {
+			return err
+		}
+		resp.Created++
+	default:
+		return errors.New("failed to find word")
+	}
+
+	learned, err := h.LearnedWordRepo.IsLearned(ctx, userID, word.ID)
+	if err != nil {
+		return errors.New("failed to check learned word")
+	}
+
+	if entry.Status == utils.VocabularyStatusLearned {
+		if !learned {
+			now := time.Now().UTC()
+			lw := &models.LearnedWords{
+				ID:           uuid.New(),
+				UserID:       userID,
+				WordID:       word.ID,
+				LearnedAt:    now,
+				LastReviewed: now,
+			}
+			if err := h.LearnedWordRepo.Create(ctx, lw); err != nil {
+				return errors.New("failed to create learned word")
+			}
+		}
+
+		if err := h.NotLearnedWordRepo.DeleteIfExists(ctx, userID, word.ID); err != nil {
+			return errors.New("failed to delete not learned word")
+		}
+
+		resp.Learned++
+		return nil
+	}
+
+	// Words the user already learned are not moved back to the queue
+	if learned {
+		return nil
+	}
+
+	exists, err := h.NotLearnedWordRepo.Exists(ctx, userID, word.ID)
+	if err != nil {
+		return errors.New("failed to check not learned word")
+	}
+
+	if !exists {
+		nlw := &models.NotLearnedWords{
+			ID:     uuid.New(),
+			UserID: userID,
+			WordID: word.ID,
+		}
+		if err := h.NotLearnedWordRepo.Create(ctx, nlw); err != nil {
+			return errors.New("failed to create not learned word")
+		}
+	}
+
+	resp.NotLearned++
+	return nil
+}
+
+// createPersonalWord creates a word owned by the user together with its sentences
+func (h *VocabularyHandler) createPersonalWord(ctx context.Context, userID uuid.UUID, entry utils.VocabularyEntry) (*models.Word, error) {
+	word := &models.Word{
+		ID:           uuid.New(),
+		Word:         entry.Word,
+		Translation:  entry.Translation,
+		PartOfSpeech: entry.PartOfSpeech,
+		CEFRLevel:    entry.CEFRLevel,
+		AudioURL:     entry.AudioURL,
+		OwnerID:      &userID,
+	}
+
+	if len(word.CEFRLevel) > 2 {
+		word.CEFRLevel = ""
+	}
+	if utf8.RuneCountInString(word.PartOfSpeech) > maxWordLength {
+		word.PartOfSpeech = ""
+	}
+
+	if err := h.WordRepo.Create(ctx, word); err != nil {
+		return nil, errors.New("failed to create word")
+	}
+
+	for _, s := range entry.Sentences {
+		sentence := &models.Sentence{
+			ID:          uuid.New(),
+			WordID:      word.ID,
+			Sentence:    s.Sentence,
+			Translation: s.Translation,
+			AudioURL:    s.AudioURL,
+		}
+		if err := h.SentenceRepo.Create(ctx, sentence); err != nil {
+			logger.Log.Warn("failed to create imported sentence", zap.Error(err), zap.String("word_id", word.ID.String()))
+		}
+	}
+
+	enqueueWordEnrichment(ctx, h.Jobs, word.ID)
+
+	return word, nil
+}
+
+// collectEntries loads the user's learned and not learned words as vocabulary entries
+func (h *VocabularyHandler) collectEntries(ctx context.Context, userID uuid.UUID) ([]utils.VocabularyEntry, error) {
+	learnedWords, err := h.LearnedWordRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notLearnedWords, err := h.NotLearnedWordRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uuid.UUID]string)
+	var ids []uuid.UUID
+	for _, nlw := range notLearnedWords {
+		statuses[nlw.WordID] = utils.VocabularyStatusNotLearned
+		ids = append(ids, nlw.WordID)
+	}
+	for _, lw := range learnedWords {
+		if _, ok := statuses[lw.WordID]; !ok {
+			ids = append(ids, lw.WordID)
+		}
+		statuses[lw.WordID] = utils.VocabularyStatusLearned
+	}
+
+	words, err := h.WordRepo.ListByIDsWithSentences(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]utils.VocabularyEntry, 0, len(words))
+	for _, word := range words {
+		entry := utils.VocabularyEntry{
+			Word:         word.Word,
+			Translation:  word.Translation,
+			PartOfSpeech: word.PartOfSpeech,
+			CEFRLevel:    word.CEFRLevel,
+			Status:       statuses[word.ID],
+			AudioURL:     utils.MediaURL(word.AudioURL),
+		}
+		for _, s := range word.Sentences {
+			entry.Sentences = append(entry.Sentences, utils.VocabularySentence{
+				Sentence:    s.Sentence,
+				Translation: s.Translation,
+				AudioURL:    utils.MediaURL(s.AudioURL),
+			})
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -1,25 +1,51 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
+	"fluently/go-backend/internal/jobs"
 	"fluently/go-backend/internal/repository/models"
 	"fluently/go-backend/internal/repository/postgres"
 	"fluently/go-backend/internal/repository/schemas"
 	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // swagger:ignore
 var _ schemas.ErrorResponse
 
+const (
+	defaultLookupLimit  = 5
+	maxLookupLimit      = 20
+	defaultListPageSize = 50
+	maxListPageSize     = 200
+)
+
 // WordHandler handles the word endpoint
 type WordHandler struct {
 	Repo *postgres.WordRepository
+	Jobs *jobs.Client
+}
+
+// enqueueWordEnrichment enqueues enrichment of a word, failures are only logged
+// since the word is already saved and can be enriched later from the admin API
+func enqueueWordEnrichment(ctx context.Context, c *jobs.Client, wordID uuid.UUID) {
+	if c == nil {
+		return
+	}
+
+	if err := c.EnqueueEnrichWord(ctx, wordID); err != nil {
+		logger.Log.Warn("Failed to enqueue word enrichment", zap.Error(err), zap.String("word_id", wordID.String()))
+	}
 }
 
 // buildWordResponse builds a WordResponse from a Word
@@ -39,14 +65,39 @@ func buildWordResponse(w *models.Word) schemas.WordResponse {
 		resp.Context = &w.Context
 	}
 
-	if w.AudioURL != "" {
-		resp.AudioURL = &w.AudioURL
+	if audioURL := utils.MediaURL(w.AudioURL); audioURL != "" {
+		resp.AudioURL = &audioURL
+	}
+
+	if w.Phonetic != "" {
+		resp.Phonetic = &w.Phonetic
+	}
+
+	if w.OwnerID != nil {
+		ownerID := w.OwnerID.String()
+		resp.OwnerID = &ownerID
 	}
 
 	return resp
 }
 
-// ListWords lists all words
+// ListWords godoc
+// @Summary      List words
+// @Description  Returns a page of global words. Pass next_cursor of a page as cursor to get the next one.
+// @Tags         words
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cefr_level      query     string  false  "CEFR level"  Enums(A1, A2, B1, B2, C1, C2)
+// @Param        part_of_speech  query     string  false  "Part of speech"
+// @Param        topic_id        query     string  false  "Topic ID, words of its subtopics are included"
+// @Param        sort            query     string  false  "Sort key, prefix with - for descending"  Enums(word, -word, cefr_level, -cefr_level, part_of_speech, -part_of_speech)
+// @Param        limit           query     int     false  "Page size, up to 200"
+// @Param        cursor          query     string  false  "next_cursor of the previous page"
+// @Success      200             {object}  schemas.WordListResponse
+// @Failure      400             {object}  schemas.ErrorResponse
+// @Failure      401             {object}  schemas.ErrorResponse
+// @Failure      500             {object}  schemas.ErrorResponse
+// @Router       /api/v1/words [get]
 func (h *WordHandler) ListWords(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	endpoint := "/api/v1/words"
@@ -56,19 +107,135 @@ func (h *WordHandler) ListWords(w http.ResponseWriter, r *http.Request) {
 		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
-	words, err := h.Repo.ListWords(r.Context())
+
+	params, err := utils.ParseListParams(r, defaultListPageSize, maxListPageSize, "word", "cefr_level", "part_of_speech")
+	if err != nil {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := postgres.WordListFilter{
+		CEFRLevel:    query.Get("cefr_level"),
+		PartOfSpeech: query.Get("part_of_speech"),
+	}
+	if filter.CEFRLevel != "" && !cefrLevels[filter.CEFRLevel] {
+		statusCode = 400
+		http.Error(w, "invalid cefr_level", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("topic_id"); v != "" {
+		topicID, err := uuid.Parse(v)
+		if err != nil {
+			statusCode = 400
+			http.Error(w, "invalid topic_id", http.StatusBadRequest)
+			return
+		}
+		filter.TopicID = &topicID
+	}
+
+	words, next, err := h.Repo.ListWords(r.Context(), filter, postgres.Page{
+		Sort:  params.Sort,
+		Desc:  params.Desc,
+		After: params.After,
+		Limit: params.Limit,
+	})
+	if errors.Is(err, postgres.ErrInvalidCursor) {
+		statusCode = 400
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		statusCode = 500
+		logger.Log.Error("Failed to list words", zap.Error(err))
 		http.Error(w, "failed to fetch words", http.StatusInternalServerError)
 		return
 	}
 
-	var resp []schemas.WordResponse
+	resp := schemas.WordListResponse{
+		Words:      make([]schemas.WordResponse, 0, len(words)),
+		NextCursor: params.NextCursor(next),
+	}
+	for i := range words {
+		resp.Words = append(resp.Words, buildWordResponse(&words[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// LookupWords godoc
+// @Summary      Look up words
+// @Description  Finds global words and the user's personal words starting with the query, exact matches first.
+// @Description  Used by the bot for /word and inline queries.
+// @Tags         words
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query     string  true   "Word or its beginning"
+// @Param        limit  query     int     false  "Max results, up to 20"
+// @Success      200    {array}   schemas.WordLookupResponse
+// @Failure      400    {object}  schemas.ErrorResponse
+// @Failure      401    {object}  schemas.ErrorResponse
+// @Failure      500    {object}  schemas.ErrorResponse
+// @Router       /api/v1/words/lookup [get]
+func (h *WordHandler) LookupWords(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	endpoint := "/api/v1/words/lookup"
+	method := r.Method
+	statusCode := 200
+	defer func() {
+		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := normalizeSelection(r.URL.Query().Get("q"))
+	if query == "" || utf8.RuneCountInString(query) > maxWordLength {
+		statusCode = 400
+		http.Error(w, "invalid query", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultLookupLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			statusCode = 400
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxLookupLimit {
+			limit = maxLookupLimit
+		}
+	}
+
+	words, err := h.Repo.Lookup(r.Context(), user.ID, query, limit)
+	if err != nil {
+		statusCode = 500
+		logger.Log.Error("Failed to look up words", zap.Error(err), zap.String("query", query))
+		http.Error(w, "failed to look up words", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]schemas.WordLookupResponse, 0, len(words))
 	for _, word := range words {
-		resp = append(resp, buildWordResponse(&word))
+		item := schemas.WordLookupResponse{
+			WordResponse: buildWordResponse(&word),
+			Sentences:    []schemas.SentenceResponse{},
+		}
+		for _, sentence := range word.Sentences {
+			item.Sentences = append(item.Sentences, buildSentenceResponse(&sentence))
+		}
+		resp = append(resp, item)
 	}
 
-	// Return the list of words
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -83,6 +250,13 @@ func (h *WordHandler) GetWord(w http.ResponseWriter, r *http.Request) {
 		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	id, err := utils.ParseUUIDParam(r, "id")
 	if err != nil {
 		statusCode = 400
@@ -90,8 +264,8 @@ func (h *WordHandler) GetWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	word, err := h.Repo.GetByID(r.Context(), id)
-	if err != nil {
+	word, ok := h.getVisibleWord(r.Context(), user.ID, id)
+	if !ok {
 		statusCode = 404
 		http.Error(w, "word not found", http.StatusNotFound)
 		return
@@ -144,6 +318,8 @@ func (h *WordHandler) CreateWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	enqueueWordEnrichment(r.Context(), h.Jobs, word.ID)
+
 	resp := schemas.WordResponse{
 		ID:           word.ID.String(),
 		Word:         word.Word,
@@ -170,6 +346,13 @@ func (h *WordHandler) UpdateWord(w http.ResponseWriter, r *http.Request) {
 		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	id, err := utils.ParseUUIDParam(r, "id")
 	if err != nil {
 		statusCode = 400
@@ -184,8 +367,8 @@ func (h *WordHandler) UpdateWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	word, err := h.Repo.GetByID(r.Context(), id)
-	if err != nil {
+	word, ok := h.getVisibleWord(r.Context(), user.ID, id)
+	if !ok {
 		statusCode = 404
 		http.Error(w, "word not found", http.StatusNotFound)
 		return
@@ -244,6 +427,13 @@ func (h *WordHandler) DeleteWord(w http.ResponseWriter, r *http.Request) {
 		httpRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 		httpRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
 	}()
+	user, err := utils.GetCurrentUser(r.Context())
+	if err != nil {
+		statusCode = 401
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	id, err := utils.ParseUUIDParam(r, "id")
 	if err != nil {
 		statusCode = 400
@@ -251,6 +441,12 @@ func (h *WordHandler) DeleteWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.getVisibleWord(r.Context(), user.ID, id); !ok {
+		statusCode = 404
+		http.Error(w, "word not found", http.StatusNotFound)
+		return
+	}
+
 	if err := h.Repo.Delete(r.Context(), id); err != nil {
 		statusCode = 500
 		http.Error(w, "failed to delete word", http.StatusInternalServerError)
@@ -260,3 +456,12 @@ func (h *WordHandler) DeleteWord(w http.ResponseWriter, r *http.Request) {
 	// Return no content
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// getVisibleWord returns a global word or a personal word of the user, other users' personal words are not found
+func (h *WordHandler) getVisibleWord(ctx context.Context, userID, wordID uuid.UUID) (*models.Word, bool) {
+	word, err := h.Repo.GetByID(ctx, wordID)
+	if err != nil || (word.OwnerID != nil && *word.OwnerID != userID) {
+		return nil, false
+	}
+	return word, true
+}
@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/repository/models"
+	pg "fluently/go-backend/internal/repository/postgres"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/idtoken"
+)
+
+// TestUnlinkGoogleIdentity tests that an unlinked Google account isn't linked again by the next Google sign in
+func TestUnlinkGoogleIdentity(t *testing.T) {
+	setupTest(t)
+
+	db.Exec("DROP TABLE IF EXISTS user_identities CASCADE")
+	db.Exec("DROP TABLE IF EXISTS refresh_tokens CASCADE")
+	require.NoError(t, db.AutoMigrate(&models.UserIdentity{}, &models.RefreshToken{}))
+
+	ctx := context.Background()
+	identityRepo := pg.NewIdentityRepository(db)
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Name:         "Google User",
+		Email:        "google-user@example.com",
+		PasswordHash: "hash",
+		Provider:     "google",
+		GoogleID:     "google-sub",
+		Role:         "user",
+		IsActive:     true,
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+	require.NoError(t, identityRepo.Create(ctx, &models.UserIdentity{UserID: user.ID, Provider: "google", Subject: "google-sub"}))
+	setTestUser(user)
+	defer setTestUser(nil)
+
+	h := &handlers.Handlers{
+		UserRepo:         userRepo,
+		UserPrefRepo:     prefRepo,
+		RefreshTokenRepo: pg.NewRefreshTokenRepository(db),
+		IdentityRepo:     identityRepo,
+		ValidateGoogleToken: func(ctx context.Context, idToken, audience string) (*idtoken.Payload, error) {
+			return &idtoken.Payload{Subject: "google-sub", Claims: map[string]interface{}{
+				"sub":            "google-sub",
+				"email":          user.Email,
+				"email_verified": true,
+			}}, nil
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Post("/auth/google", h.GoogleAuthHandler)
+	r.With(testAuthMiddleware).Delete("/api/v1/identities/{provider}", h.UnlinkIdentity)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	e := httpexpect.Default(t, server.URL)
+
+	e.DELETE("/api/v1/identities/google").
+		Expect().
+		Status(http.StatusNoContent)
+
+	unlinked, err := userRepo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, unlinked.GoogleID)
+	assert.Equal(t, "password", unlinked.Provider)
+
+	e.POST("/auth/google").
+		WithJSON(map[string]string{"id_token": "token", "platform": "web"}).
+		Expect().
+		Status(http.StatusOK)
+
+	_, err = identityRepo.GetByUserProvider(ctx, user.ID, "google")
+	assert.Error(t, err)
+	_, err = identityRepo.GetBySubject(ctx, "google", "google-sub")
+	assert.Error(t, err)
+}
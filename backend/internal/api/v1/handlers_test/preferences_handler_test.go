@@ -1,8 +1,12 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/png"
 	"net/http"
+	"strings"
 	"testing"
 
 	"fluently/go-backend/internal/repository/models"
@@ -97,3 +101,65 @@ func TestDeletePreference(t *testing.T) {
 	_, err = prefRepo.GetByUserID(context.Background(), userID)
 	assert.Error(t, err, "Expected error when trying to get deleted preference")
 }
+
+// TestUploadAvatar tests replacing the avatar with an uploaded image served by a signed URL
+func TestUploadAvatar(t *testing.T) {
+	setupTest(t)
+
+	e := httpexpect.Default(t, testServer.URL)
+
+	user := models.User{
+		ID:           uuid.New(),
+		Email:        "avatar-" + uuid.New().String()[:8] + "@test.com",
+		Provider:     "local",
+		PasswordHash: "hashed",
+		Role:         "user",
+		IsActive:     true,
+	}
+	assert.NoError(t, userRepo.Create(context.Background(), &user))
+	assert.NoError(t, prefRepo.Create(context.Background(), &models.Preference{ID: uuid.New(), UserID: user.ID}))
+	setTestUser(&user)
+
+	var img bytes.Buffer
+	assert.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 512, 300))))
+
+	e.POST("/api/v1/preferences/avatar").
+		WithMultipart().
+		WithFileBytes("avatar", "me.png", img.Bytes()).
+		Expect().
+		Status(http.StatusOK)
+
+	// The key is stored, clients get it as a signed URL
+	pref, err := prefRepo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(pref.AvatarImageURL, "avatars/"+user.ID.String()+"/"), pref.AvatarImageURL)
+	avatar, err := testMedia.Get(context.Background(), pref.AvatarImageURL)
+	assert.NoError(t, err)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(avatar))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 256, cfg.Width)
+	assert.Equal(t, 256, cfg.Height)
+
+	// Storage keys can't be set directly
+	e.PUT("/api/v1/preferences").
+		WithJSON(map[string]string{"avatar_image_url": "avatars/other/a.jpg"}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.POST("/api/v1/preferences/avatar").
+		WithMultipart().
+		WithFileBytes("avatar", "me.svg", []byte("<svg></svg>")).
+		Expect().
+		Status(http.StatusUnsupportedMediaType)
+
+	e.DELETE("/api/v1/preferences/avatar").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("avatar_image_url").String().IsEmpty()
+
+	exists, err := testMedia.Exists(context.Background(), pref.AvatarImageURL)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
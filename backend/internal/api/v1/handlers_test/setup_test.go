@@ -13,6 +13,7 @@ import (
 	"fluently/go-backend/internal/middleware"
 	"fluently/go-backend/internal/repository/models"
 	pg "fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/storage"
 	"fluently/go-backend/internal/utils"
 
 	"github.com/go-chi/chi/v5"
@@ -33,6 +34,8 @@ var (
 	prefRepo        *pg.PreferenceRepository
 	pickOptionRepo  *pg.PickOptionRepository
 	learnedWordRepo *pg.LearnedWordRepository
+	notLearnedRepo  *pg.NotLearnedWordRepository
+	testMedia       *storage.FSStorage
 
 	// Global test user for authentication
 	testUser      *models.User
@@ -52,6 +55,8 @@ func setupTest(t *testing.T) {
 	}
 
 	// Drop all tables first to ensure clean state
+	db.Exec("DROP TABLE IF EXISTS sync_events CASCADE")
+	db.Exec("DROP TABLE IF EXISTS not_learned_words CASCADE")
 	db.Exec("DROP TABLE IF EXISTS learned_words CASCADE")
 	db.Exec("DROP TABLE IF EXISTS pick_options CASCADE")
 	db.Exec("DROP TABLE IF EXISTS user_preferences CASCADE")
@@ -72,6 +77,8 @@ func setupTest(t *testing.T) {
 		&models.Preference{},
 		&models.PickOption{},
 		&models.LearnedWords{},
+		&models.NotLearnedWords{},
+		&models.SyncEvent{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate DB: %v", err)
@@ -85,22 +92,33 @@ func setupTest(t *testing.T) {
 	prefRepo = pg.NewPreferenceRepository(db)
 	pickOptionRepo = pg.NewPickOptionRepository(db)
 	learnedWordRepo = pg.NewLearnedWordRepository(db)
+	notLearnedRepo = pg.NewNotLearnedWordRepository(db)
 
 	// Create handlers
 	wordHandler := &handlers.WordHandler{Repo: pg.NewWordRepository(db)}
 	userHandler := &handlers.UserHandler{Repo: pg.NewUserRepository(db)}
 	topicHandler := &handlers.TopicHandler{Repo: pg.NewTopicRepository(db)}
 	sentenceHandler := &handlers.SentenceHandler{Repo: pg.NewSentenceRepository(db)}
-	prefHandler := &handlers.PreferenceHandler{Repo: pg.NewPreferenceRepository(db)}
+	testMedia, err = storage.NewFSStorage(t.TempDir(), "", "test-secret")
+	if err != nil {
+		t.Fatalf("failed to create media storage: %v", err)
+	}
+	prefHandler := &handlers.PreferenceHandler{Repo: pg.NewPreferenceRepository(db), Media: testMedia}
 	pickOptionHandler := &handlers.PickOptionHandler{Repo: pg.NewPickOptionRepository(db)}
 	learnedWordHandler := &handlers.LearnedWordHandler{Repo: learnedWordRepo}
 	progressHandler := &handlers.ProgressHandler{
 		WordRepo:           wordRepo,
 		LearnedWordRepo:    learnedWordRepo,
-		NotLearnedWordRepo: pg.NewNotLearnedWordRepository(db),
+		NotLearnedWordRepo: notLearnedRepo,
 		LLMClient:          utils.NewLLMClient(utils.LLMClientConfig{}),
 		Redis:              utils.Redis(),
 	}
+	vocabularyHandler := &handlers.VocabularyHandler{
+		WordRepo:           wordRepo,
+		SentenceRepo:       sentenceRepo,
+		LearnedWordRepo:    learnedWordRepo,
+		NotLearnedWordRepo: notLearnedRepo,
+	}
 
 	// Create router
 	r := chi.NewRouter()
@@ -122,6 +140,7 @@ func setupTest(t *testing.T) {
 		routes.RegisterPickOptionRoutes(r, pickOptionHandler)
 		routes.RegisterLearnedWordRoutes(r, learnedWordHandler)
 		routes.RegisterProgressRoutes(r, progressHandler)
+		routes.RegisterVocabularyRoutes(r, vocabularyHandler)
 	})
 
 	// Create test server
@@ -55,6 +55,54 @@ func TestGetTopic(t *testing.T) {
 	assert.Equal(t, "Test Topic", resp.Value("title").String().Raw())
 }
 
+// TestListTopics tests that topics are listed in pages with a next_cursor, with or without query parameters
+func TestListTopics(t *testing.T) {
+	setupTest(t)
+
+	e := httpexpect.Default(t, testServer.URL)
+
+	root := models.Topic{ID: uuid.New(), Title: "Animals"}
+	assert.NoError(t, topicRepo.Create(context.Background(), &root))
+	child := models.Topic{ID: uuid.New(), Title: "birds", ParentID: &root.ID}
+	assert.NoError(t, topicRepo.Create(context.Background(), &child))
+
+	resp := e.GET("/api/v1/topics").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	assert.Equal(t, 2, int(resp.Value("topics").Array().Length().Raw()))
+	resp.NotContainsKey("next_cursor")
+
+	page := e.GET("/api/v1/topics").
+		WithQuery("limit", 1).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	assert.Equal(t, "Animals", page.Value("topics").Array().Value(0).Object().Value("title").String().Raw())
+
+	next := e.GET("/api/v1/topics").
+		WithQuery("limit", 1).
+		WithQuery("cursor", page.Value("next_cursor").String().Raw()).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	assert.Equal(t, "birds", next.Value("topics").Array().Value(0).Object().Value("title").String().Raw())
+	next.NotContainsKey("next_cursor")
+
+	roots := e.GET("/api/v1/topics").
+		WithQuery("root", true).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("topics").Array()
+	assert.Equal(t, 1, int(roots.Length().Raw()))
+	assert.Equal(t, root.ID.String(), roots.Value(0).Object().Value("id").String().Raw())
+
+	e.GET("/api/v1/topics").
+		WithQuery("cursor", "invalid").
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
 // TestUpdateTopic tests the update of a topic
 func TestUpdateTopic(t *testing.T) {
 	setupTest(t)
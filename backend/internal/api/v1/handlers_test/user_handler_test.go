@@ -16,6 +16,9 @@ import (
 func TestCreateUser(t *testing.T) {
 	setupTest(t)
 
+	setTestUser(&models.User{ID: uuid.New(), Email: "admin@example.com", Role: "admin"})
+	defer setTestUser(nil)
+
 	e := httpexpect.Default(t, testServer.URL)
 
 	req := map[string]interface{}{
@@ -74,6 +77,9 @@ func TestGetUser(t *testing.T) {
 func TestUpdateUser(t *testing.T) {
 	setupTest(t)
 
+	setTestUser(&models.User{ID: uuid.New(), Email: "admin@example.com", Role: "admin"})
+	defer setTestUser(nil)
+
 	e := httpexpect.Default(t, testServer.URL)
 
 	user := models.User{
@@ -111,6 +117,9 @@ func TestUpdateUser(t *testing.T) {
 func TestDeleteUser(t *testing.T) {
 	setupTest(t)
 
+	setTestUser(&models.User{ID: uuid.New(), Email: "admin@example.com", Role: "admin"})
+	defer setTestUser(nil)
+
 	e := httpexpect.Default(t, testServer.URL)
 
 	user := models.User{
@@ -134,3 +143,45 @@ func TestDeleteUser(t *testing.T) {
 		Expect().
 		Status(http.StatusNotFound)
 }
+
+// TestUpdateUserRole tests that users edit only their own record and can't change their role
+func TestUpdateUserRole(t *testing.T) {
+	setupTest(t)
+
+	e := httpexpect.Default(t, testServer.URL)
+
+	user := models.User{ID: uuid.New(), Name: "Self", Email: "self-" + uuid.New().String()[:8] + "@example.com", Role: "user", IsActive: true, Provider: "local", PasswordHash: "hashed"}
+	other := models.User{ID: uuid.New(), Name: "Other", Email: "other-" + uuid.New().String()[:8] + "@example.com", Role: "user", IsActive: true, Provider: "local", PasswordHash: "hashed"}
+	assert.NoError(t, userRepo.Create(context.Background(), &user))
+	assert.NoError(t, userRepo.Create(context.Background(), &other))
+	setTestUser(&user)
+	defer setTestUser(nil)
+
+	e.PUT("/api/v1/users/" + user.ID.String()).
+		WithJSON(map[string]interface{}{"name": "Self", "email": user.Email, "role": "admin"}).
+		Expect().
+		Status(http.StatusForbidden)
+	e.PUT("/api/v1/users/" + other.ID.String()).
+		WithJSON(map[string]interface{}{"name": "Renamed", "email": other.Email}).
+		Expect().
+		Status(http.StatusForbidden)
+	e.DELETE("/api/v1/users/" + other.ID.String()).
+		Expect().
+		Status(http.StatusForbidden)
+	e.POST("/api/v1/users").
+		WithJSON(map[string]interface{}{"name": "Admin", "email": "new-admin@example.com", "role": "admin"}).
+		Expect().
+		Status(http.StatusForbidden)
+
+	resp := e.PUT("/api/v1/users/" + user.ID.String()).
+		WithJSON(map[string]interface{}{"name": "Renamed", "email": user.Email, "password_hash": "forged"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	assert.Equal(t, "Renamed", resp.Value("name").String().Raw())
+	assert.Equal(t, "user", resp.Value("role").String().Raw())
+
+	stored, err := userRepo.GetByID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hashed", stored.PasswordHash)
+}
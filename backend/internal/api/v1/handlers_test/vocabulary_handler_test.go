@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// createVocabularyUser creates a user and sets it as the authenticated test user
+func createVocabularyUser(t *testing.T) models.User {
+	user := models.User{
+		ID:           uuid.New(),
+		Email:        "vocab-" + uuid.New().String()[:8] + "@test.com",
+		Provider:     "local",
+		PasswordHash: "hashed",
+		Role:         "user",
+		IsActive:     true,
+	}
+	assert.NoError(t, userRepo.Create(context.Background(), &user))
+	setTestUser(&user)
+
+	return user
+}
+
+// TestImportVocabularyCSV tests matching, creating and marking words from a CSV file
+func TestImportVocabularyCSV(t *testing.T) {
+	setupTest(t)
+	e := httpexpect.Default(t, testServer.URL)
+
+	user := createVocabularyUser(t)
+
+	existing := models.Word{ID: uuid.New(), Word: "apple", Translation: "яблоко", PartOfSpeech: "noun"}
+	assert.NoError(t, wordRepo.Create(context.Background(), &existing))
+
+	csv := "word,translation,status,sentences\n" +
+		"apple,яблоко,learned,\n" +
+		"serendipity,интуитивная прозорливость,not_learned,\"[[\"\"What a serendipity\"\", \"\"Какая удача\"\"]]\"\n"
+
+	resp := e.POST("/api/v1/me/vocabulary/import").
+		WithMultipart().
+		WithFileBytes("file", "deck.csv", []byte(csv)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	resp.Value("total").Number().IsEqual(2)
+	resp.Value("matched").Number().IsEqual(1)
+	resp.Value("created").Number().IsEqual(1)
+	resp.Value("learned").Number().IsEqual(1)
+	resp.Value("not_learned").Number().IsEqual(1)
+
+	learned, err := learnedWordRepo.IsLearned(context.Background(), user.ID, existing.ID)
+	assert.NoError(t, err)
+	assert.True(t, learned)
+
+	personal, err := wordRepo.GetPersonalByWordTranslationPair(context.Background(), user.ID, "serendipity", "интуитивная прозорливость")
+	assert.NoError(t, err)
+
+	exists, err := notLearnedRepo.Exists(context.Background(), user.ID, personal.ID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	sentences, err := sentenceRepo.ListByWord(context.Background(), personal.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sentences, 1)
+}
+
+// TestImportVocabularyUnsupportedFormat tests that unknown file types are rejected
+func TestImportVocabularyUnsupportedFormat(t *testing.T) {
+	setupTest(t)
+	e := httpexpect.Default(t, testServer.URL)
+
+	createVocabularyUser(t)
+
+	e.POST("/api/v1/me/vocabulary/import").
+		WithMultipart().
+		WithFileBytes("file", "deck.xlsx", []byte("data")).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+// TestExportVocabularyCSV tests exporting learned and not learned words
+func TestExportVocabularyCSV(t *testing.T) {
+	setupTest(t)
+	e := httpexpect.Default(t, testServer.URL)
+
+	user := createVocabularyUser(t)
+
+	word := models.Word{ID: uuid.New(), Word: "book", Translation: "книга", PartOfSpeech: "noun", AudioURL: "http://audio/book.ogg"}
+	assert.NoError(t, wordRepo.Create(context.Background(), &word))
+	assert.NoError(t, sentenceRepo.Create(context.Background(), &models.Sentence{ID: uuid.New(), WordID: word.ID, Sentence: "I read a book", Translation: "Я читаю книгу"}))
+	assert.NoError(t, notLearnedRepo.Create(context.Background(), &models.NotLearnedWords{ID: uuid.New(), UserID: user.ID, WordID: word.ID}))
+
+	body := e.GET("/api/v1/me/vocabulary/export").
+		WithQuery("format", "csv").
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "book,книга,noun,,not_learned,http://audio/book.ogg")
+	assert.Contains(t, lines[1], "I read a book")
+}
@@ -62,9 +62,11 @@ func TestListWords(t *testing.T) {
 	assert.NoError(t, err)
 
 	resp := e.GET("/api/v1/words").
+		WithQuery("cefr_level", "A1").
+		WithQuery("limit", 200).
 		Expect().
 		Status(http.StatusOK).
-		JSON().Array()
+		JSON().Object().Value("words").Array()
 
 	length := int(resp.Length().Raw())
 	found := false
@@ -172,3 +174,40 @@ func TestDeleteWord(t *testing.T) {
 		Expect().
 		Status(http.StatusNotFound)
 }
+
+// TestPersonalWordOwnership tests that personal words of other users can't be read, updated or deleted
+func TestPersonalWordOwnership(t *testing.T) {
+	setupTest(t)
+	e := httpexpect.Default(t, testServer.URL)
+
+	owner := models.User{ID: uuid.New(), Email: "owner-" + uuid.New().String()[:8] + "@test.com", Provider: "local", PasswordHash: "x", Role: "user", IsActive: true}
+	other := models.User{ID: uuid.New(), Email: "other-" + uuid.New().String()[:8] + "@test.com", Provider: "local", PasswordHash: "x", Role: "user", IsActive: true}
+	assert.NoError(t, userRepo.Create(context.Background(), &owner))
+	assert.NoError(t, userRepo.Create(context.Background(), &other))
+
+	word := models.Word{ID: uuid.New(), Word: "secret", PartOfSpeech: "noun", OwnerID: &owner.ID}
+	assert.NoError(t, wordRepo.Create(context.Background(), &word))
+	defer setTestUser(nil)
+
+	setTestUser(&other)
+	e.GET("/api/v1/words/" + word.ID.String()).
+		Expect().
+		Status(http.StatusNotFound)
+	e.PUT("/api/v1/words/" + word.ID.String()).
+		WithJSON(map[string]interface{}{"word": "stolen", "cefr_level": "A1", "part_of_speech": "noun"}).
+		Expect().
+		Status(http.StatusNotFound)
+	e.DELETE("/api/v1/words/" + word.ID.String()).
+		Expect().
+		Status(http.StatusNotFound)
+
+	setTestUser(&owner)
+	resp := e.GET("/api/v1/words/" + word.ID.String()).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	assert.Equal(t, "secret", resp.Value("word").String().Raw())
+	e.DELETE("/api/v1/words/" + word.ID.String()).
+		Expect().
+		Status(http.StatusNoContent)
+}
@@ -18,6 +18,14 @@ func RegisterAuthRoutes(r chi.Router, h *handlers.Handlers) {
 		r.Post("/google", h.GoogleAuthHandler)
 		r.Get("/google", h.GoogleAuthRedirectHandler)
 		r.Get("/google/callback", h.GoogleCallbackHandler)
+		r.Post("/telegram", h.TelegramAuthHandler)
+
+		// Other external accounts, see internal/identity
+		r.Get("/providers", h.ListIdentityProviders)
+		r.Get("/oidc/{provider}", h.IdentityRedirectHandler)
+		r.Post("/oidc/{provider}", h.IdentityAuthHandler)
+		r.Get("/oidc/{provider}/callback", h.IdentityCallbackHandler)
+		r.Post("/oidc/{provider}/callback", h.IdentityCallbackHandler) // Sign in with Apple posts the callback
 
 		// Add alias for backward compatibility (used by some OAuth flows)
 		r.Get("/swagger/callback", h.GoogleCallbackHandler)
@@ -28,3 +36,13 @@ func RegisterAuthRoutes(r chi.Router, h *handlers.Handlers) {
 		// r.Post("/reset-password", h.ResetPasswordHandler)
 	})
 }
+
+// RegisterIdentityRoutes registers routes for linking external accounts to the current user
+func RegisterIdentityRoutes(r chi.Router, h *handlers.Handlers) {
+	r.Route("/identities", func(r chi.Router) {
+		r.Get("/", h.ListIdentities)
+		r.Get("/{provider}/authorize", h.IdentityLinkURL)
+		r.Post("/{provider}", h.LinkIdentity)
+		r.Delete("/{provider}", h.UnlinkIdentity)
+	})
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterCaptureRoutes registers routes for words captured while reading
+func RegisterCaptureRoutes(r chi.Router, h *handler.CaptureHandler) {
+	r.Route("/capture", func(r chi.Router) {
+		r.Get("/", h.ListCaptures)
+		r.Post("/", h.CreateCapture)
+	})
+}
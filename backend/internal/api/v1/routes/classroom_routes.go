@@ -0,0 +1,22 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterClassroomRoutes registers classroom routes, only teachers and admins create classrooms
+func RegisterClassroomRoutes(r chi.Router, h *handler.ClassroomHandler) {
+	r.Route("/classrooms", func(r chi.Router) {
+		r.With(middleware.RequireRole("teacher", "admin")).Post("/", h.CreateClassroom)
+		r.Get("/", h.ListClassrooms)
+		r.Post("/join", h.JoinClassroom)
+		r.Delete("/{id}/students/{user_id}", h.RemoveStudent)
+		r.Get("/{id}/assignments", h.ListAssignments)
+		r.Post("/{id}/assignments", h.CreateAssignment)
+		r.Delete("/{id}/assignments/{assignment_id}", h.DeleteAssignment)
+		r.Get("/{id}/progress", h.GetProgress)
+	})
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterCurationRoutes registers admin routes for curating words and topics
+func RegisterCurationRoutes(r chi.Router, h *handler.CurationHandler) {
+	r.Route("/admin/content", func(r chi.Router) {
+		r.Use(middleware.RequireRole("admin"))
+
+		r.Get("/words", h.ListWords)
+		r.Patch("/words", h.BulkUpdateWords)
+		r.Post("/words/merge", h.MergeWords)
+		r.Put("/topics/{id}/parent", h.ReparentTopic)
+		r.Get("/audit", h.ListAudit)
+	})
+}
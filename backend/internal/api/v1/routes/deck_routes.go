@@ -0,0 +1,20 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterDeckRoutes registers deck routes
+func RegisterDeckRoutes(r chi.Router, h *handler.DeckHandler) {
+	r.Route("/decks", func(r chi.Router) {
+		r.Get("/", h.ListDecks)
+		r.Post("/", h.CreateDeck)
+		r.Get("/{id}", h.GetDeck)
+		r.Put("/{id}", h.UpdateDeck)
+		r.Delete("/{id}", h.DeleteDeck)
+		r.Post("/{id}/words", h.AddDeckWord)
+		r.Delete("/{id}/words/{word_id}", h.RemoveDeckWord)
+	})
+}
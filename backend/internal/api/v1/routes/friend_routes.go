@@ -0,0 +1,22 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterFriendRoutes registers friend routes
+func RegisterFriendRoutes(r chi.Router, h *handler.FriendHandler) {
+	r.Route("/friends", func(r chi.Router) {
+		r.Get("/", h.ListFriends)
+		r.Post("/invites", h.CreateInvite)
+		r.Post("/accept", h.AcceptInvite)
+		r.Delete("/{friend_id}", h.RemoveFriend)
+	})
+}
+
+// RegisterLeaderboardRoutes registers leaderboard routes
+func RegisterLeaderboardRoutes(r chi.Router, h *handler.LeaderboardHandler) {
+	r.Get("/leaderboard", h.GetLeaderboard)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterGamificationRoutes registers XP, streak and achievement routes
+func RegisterGamificationRoutes(r chi.Router, h *handler.GamificationHandler) {
+	r.Get("/achievements", h.GetAchievements)
+	r.Post("/activity", h.RecordActivity)
+}
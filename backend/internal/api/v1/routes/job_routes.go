@@ -0,0 +1,23 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterJobRoutes registers admin routes for background jobs
+func RegisterJobRoutes(r chi.Router, h *handler.JobHandler) {
+	r.Route("/admin/jobs", func(r chi.Router) {
+		r.Use(middleware.RequireRole("admin"))
+
+		r.Get("/queues", h.ListQueues)
+		r.Get("/queues/{queue}/{state}", h.ListTasks)
+		r.Post("/queues/{queue}/tasks/{id}/run", h.RunTask)
+		r.Delete("/queues/{queue}/tasks/{id}", h.DeleteTask)
+		r.Post("/queues/{queue}/archived/run", h.RunArchivedTasks)
+		r.Post("/enrich", h.EnqueueEnrichment)
+		r.Post("/audio", h.EnqueueAudio)
+	})
+}
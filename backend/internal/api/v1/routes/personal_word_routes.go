@@ -0,0 +1,18 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterPersonalWordRoutes registers routes for words owned by the current user
+func RegisterPersonalWordRoutes(r chi.Router, h *handler.PersonalWordHandler) {
+	r.Route("/me/words", func(r chi.Router) {
+		r.Get("/", h.ListPersonalWords)
+		r.Post("/", h.CreatePersonalWord)
+		r.Get("/{id}", h.GetPersonalWord)
+		r.Put("/{id}", h.UpdatePersonalWord)
+		r.Delete("/{id}", h.DeletePersonalWord)
+	})
+}
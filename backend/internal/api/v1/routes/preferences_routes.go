@@ -12,6 +12,8 @@ func RegisterPreferencesRoutes(r chi.Router, h *handler.PreferenceHandler) {
 		r.Get("/", h.GetUserPreferences)                   // /preferences (gets user from context)
 		r.Put("/", h.UpdateUserPreferences)                // /preferences (information from token)
 		r.Delete("/", h.DeletePreference)                  // /preferences (gets user from context)
+		r.Post("/avatar", h.UploadAvatar)                  // /preferences/avatar (multipart upload)
+		r.Delete("/avatar", h.DeleteAvatar)                // /preferences/avatar
 		r.Post("/user/{user_id}", h.CreateUserPreferences) // /preferences/user/{user_id}
 	})
 }
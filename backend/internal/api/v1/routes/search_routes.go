@@ -0,0 +1,12 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterSearchRoutes registers routes for dictionary search
+func RegisterSearchRoutes(r chi.Router, h *handler.SearchHandler) {
+	r.Get("/search", h.Search)
+}
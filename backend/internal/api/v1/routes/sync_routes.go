@@ -0,0 +1,15 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterSyncRoutes registers offline sync routes
+func RegisterSyncRoutes(r chi.Router, h *handler.SyncHandler) {
+	r.Route("/sync", func(r chi.Router) {
+		r.Post("/", h.Sync)
+		r.Get("/changes", h.GetChanges)
+	})
+}
@@ -1,18 +1,24 @@
 package routes
 
 import (
+	"net/http"
+
 	handler "fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/middleware"
+	"fluently/go-backend/internal/serviceauth"
 
 	"github.com/go-chi/chi/v5"
 )
 
-// RegisterTelegramRoutes registers telegram routes
-func RegisterTelegramRoutes(r chi.Router, h *handler.TelegramHandler) {
-	// Public routes (no authentication required)
+// RegisterTelegramRoutes registers telegram routes, serviceAuth authenticates the bot on the machine endpoints
+func RegisterTelegramRoutes(r chi.Router, h *handler.TelegramHandler, serviceAuth func(http.Handler) http.Handler) {
+	// Machine routes, only for the Telegram bot
 	r.Route("/telegram", func(r chi.Router) {
-		r.Post("/create-link", h.CreateLinkToken)
-		r.Post("/check-status", h.CheckLinkStatus)
-		r.Post("/get-tokens", h.GetJWTTokens)
+		r.Use(serviceAuth)
+
+		r.With(middleware.RequireServiceScope(serviceauth.ScopeTelegramLink)).Post("/create-link", h.CreateLinkToken)
+		r.With(middleware.RequireServiceScope(serviceauth.ScopeTelegramLink)).Post("/check-status", h.CheckLinkStatus)
+		r.With(middleware.RequireServiceScope(serviceauth.ScopeTelegramTokens)).Post("/get-tokens", h.GetJWTTokens)
 	})
 
 	// Protected routes
@@ -24,3 +30,8 @@ func RegisterTelegramRoutes(r chi.Router, h *handler.TelegramHandler) {
 	r.Get("/link-google", h.LinkWithGoogle)
 	r.Get("/link-google/callback", h.LinkGoogleCallback)
 }
+
+// RegisterTelegramAccountRoutes registers routes for the current user's Telegram account, they must be mounted behind JWT authentication
+func RegisterTelegramAccountRoutes(r chi.Router, h *handler.TelegramHandler) {
+	r.Post("/telegram/link", h.LinkTelegram)
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	handler "fluently/go-backend/internal/api/v1/handlers"
+	"fluently/go-backend/internal/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -9,7 +10,8 @@ import (
 // RegisterUserRoutes registers user routes
 func RegisterUserRoutes(r chi.Router, h *handler.UserHandler) {
 	r.Route("/users", func(r chi.Router) {
-		r.Post("/", h.CreateUser)
+		// Created users get any role and password hash, so only admins create them
+		r.With(middleware.RequireRole("admin")).Post("/", h.CreateUser)
 		r.Get("/{id}", h.GetUser)
 		r.Put("/{id}", h.UpdateUser)
 		r.Delete("/{id}", h.DeleteUser)
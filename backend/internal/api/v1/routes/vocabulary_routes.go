@@ -0,0 +1,15 @@
+package routes
+
+import (
+	handler "fluently/go-backend/internal/api/v1/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterVocabularyRoutes registers vocabulary import and export routes
+func RegisterVocabularyRoutes(r chi.Router, h *handler.VocabularyHandler) {
+	r.Route("/me/vocabulary", func(r chi.Router) {
+		r.Post("/import", h.ImportVocabulary) // POST /api/v1/me/vocabulary/import
+		r.Get("/export", h.ExportVocabulary)  // GET /api/v1/me/vocabulary/export
+	})
+}
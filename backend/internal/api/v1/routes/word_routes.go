@@ -10,6 +10,7 @@ import (
 func RegisterWordRoutes(r chi.Router, h *handler.WordHandler) {
 	r.Route("/words", func(r chi.Router) {
 		r.Get("/", h.ListWords)
+		r.Get("/lookup", h.LookupWords)
 		r.Get("/{id}", h.GetWord)
 		r.Post("/", h.CreateWord)
 		r.Put("/{id}", h.UpdateWord)
@@ -16,19 +16,31 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Auth     AuthConfig
-	API      ApiConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
-	Google   GoogleConfig
-	Swagger  SwaggerConfig
-	Redis    RedisConfig
+	Auth         AuthConfig
+	API          ApiConfig
+	Database     DatabaseConfig
+	Logger       LoggerConfig
+	Google       GoogleConfig
+	Swagger      SwaggerConfig
+	Redis        RedisConfig
+	Jobs         JobsConfig
+	TTS          TTSConfig
+	Storage      StorageConfig
+	Media        MediaConfig
+	Gamification GamificationConfig
+	Service      ServiceConfig
+	Telegram     TelegramConfig
+	Identity     IdentityConfig
 }
 
 // AuthConfig represents the authentication configuration
 type AuthConfig struct {
-	JWTSecret         string
+	JWTSecret         string // HS256 secret, with JWTKeysDir set it only verifies tokens issued before the switch
 	JWTExpiration     time.Duration
+	JWTKeysDir        string        // directory of the asymmetric signing keys, empty keeps signing with JWTSecret
+	JWTSigningAlg     string        // algorithm of generated keys, RS256 or EdDSA
+	JWTKeyRotation    time.Duration // how often a new signing key is generated, 0 disables rotation
+	JWTKeyPrePublish  time.Duration // how long a new key is in the JWKS before it signs
 	RefreshExpiration time.Duration
 	PasswordMinLength int
 	RateLimitRequests int
@@ -69,6 +81,18 @@ type GoogleConfig struct {
 	WebClientID     string
 }
 
+// IdentityConfig represents sign in with other external accounts, a provider is enabled by setting its client ID
+type IdentityConfig struct {
+	AppleClientID      string // Services ID of the website
+	AppleClientSecret  string // client secret JWT made with the Sign in with Apple key, Apple accepts it for up to 6 months
+	AppleBundleID      string // audience of ID tokens from the iOS app
+	YandexClientID     string
+	YandexClientSecret string
+	GitHubClientID     string
+	GitHubClientSecret string
+	OIDCProviders      string // any OpenID Connect issuers, "name|issuer|client_id|client_secret" separated by ";"
+}
+
 // SwaggerConfig represents the Swagger configuration
 type SwaggerConfig struct {
 	AllowedEmails map[string]bool
@@ -80,6 +104,71 @@ type RedisConfig struct {
 	ChatLockTTL time.Duration
 }
 
+// JobsConfig represents the background job queue configuration
+type JobsConfig struct {
+	WorkerEnabled bool // run the job worker inside the API process
+	Concurrency   int  // number of jobs processed at the same time
+	MaxRetry      int  // attempts before a job is moved to the archive (dead-letter) queue
+}
+
+// GamificationConfig represents XP, streak and achievement settings
+type GamificationConfig struct {
+	TimeZone    string        // streak days of users without a time zone in preferences
+	MaxFreezes  int           // streak freezes a user can hold, 0 disables them
+	FreezeEvery int           // days of streak that earn a freeze
+	MaxBackdate time.Duration // how far back client times count, older activity counts on the first day of the window
+	DailyXPCap  int           // XP a day for exercises and lessons reported by clients, 0 disables the cap
+}
+
+// ServiceConfig represents credentials of our own services calling the machine endpoints
+type ServiceConfig struct {
+	Keys    string        // "id:secret:scope1,scope2" entries separated by semicolons, see serviceauth.ParseKeys
+	MaxSkew time.Duration // how old a signed request may be
+}
+
+// TelegramConfig represents sign in with Telegram, the Login Widget and Mini Apps are verified with the bot token
+type TelegramConfig struct {
+	BotToken   string
+	AuthMaxAge time.Duration // how long signed login data is accepted after Telegram issued it
+}
+
+// TTSConfig represents the text-to-speech configuration used to voice words and sentences.
+// The provider is an OpenAI-compatible speech server, the same one the Telegram bot uses.
+type TTSConfig struct {
+	URL      string // audio generation is disabled when empty
+	APIKey   string
+	Model    string
+	Voice    string
+	Language string
+	Timeout  time.Duration
+}
+
+// StorageConfig represents the S3-compatible object storage configuration
+type StorageConfig struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // base URL objects are served from, defaults to the bucket URL
+	PathStyle bool   // bucket in the path instead of the host name, required by MinIO
+
+	// Filesystem fallback used when no S3 endpoint is configured
+	Dir     string // directory objects are stored in
+	BaseURL string // public URL of the API, objects are served from {BaseURL}/media
+
+	SigningKey string        // key of filesystem URL signatures, defaults to the JWT secret
+	URLTTL     time.Duration // lifetime of signed URLs
+}
+
+// MediaConfig represents limits of uploaded and mirrored media
+type MediaConfig struct {
+	AvatarMaxSize int64 // largest accepted avatar upload in bytes
+	AvatarSize    int   // avatars are cropped to a square of this many pixels
+	AudioMaxSize  int64 // largest remote audio mirrored into storage
+	ApkgMaxSize   int64 // largest collection unpacked from an imported Anki package
+}
+
 // Init loads the configuration from environment variables
 var cfg *Config
 
@@ -98,16 +187,44 @@ func Init() {
 	viper.SetDefault("APP_PORT", "8070")
 	viper.SetDefault("APP_HOST", "0.0.0.0")
 	viper.SetDefault("JWT_EXPIRATION", "24h")
+	viper.SetDefault("JWT_SIGNING_ALG", "RS256")
+	viper.SetDefault("JWT_KEY_ROTATION", "720h")   // 30 days
+	viper.SetDefault("JWT_KEY_PREPUBLISH", "1h")   // longer than verifiers cache the JWKS
 	viper.SetDefault("REFRESH_EXPIRATION", "720h") // 30 days
 	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
 	viper.SetDefault("RATE_LIMIT_REQUESTS", 100)
 	viper.SetDefault("RATE_LIMIT_DURATION", "1h")
+	viper.SetDefault("JOBS_WORKER_ENABLED", true)
+	viper.SetDefault("JOBS_CONCURRENCY", 2) // external dictionary APIs are rate limited
+	viper.SetDefault("JOBS_MAX_RETRY", 5)
+	viper.SetDefault("TTS_MODEL", "tts-1")
+	viper.SetDefault("TTS_VOICE", "alloy")
+	viper.SetDefault("TTS_LANGUAGE", "en")
+	viper.SetDefault("TTS_TIMEOUT", "30s")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("STORAGE_DIR", "./media")
+	viper.SetDefault("STORAGE_URL_TTL", "24h")
+	viper.SetDefault("AVATAR_MAX_SIZE", 5<<20)
+	viper.SetDefault("AVATAR_SIZE", 256)
+	viper.SetDefault("AUDIO_MAX_SIZE", 5<<20)
+	viper.SetDefault("APKG_MAX_UNPACKED_SIZE", 256<<20)
+	viper.SetDefault("STREAK_TIME_ZONE", "UTC")
+	viper.SetDefault("STREAK_MAX_FREEZES", 2)
+	viper.SetDefault("STREAK_FREEZE_EVERY", 7)
+	viper.SetDefault("STREAK_MAX_BACKDATE", "72h")
+	viper.SetDefault("XP_DAILY_CAP", 1000)
+	viper.SetDefault("SERVICE_SIGNATURE_MAX_SKEW", "5m")
+	viper.SetDefault("TELEGRAM_AUTH_MAX_AGE", "24h")
 
 	// Read configuration
 	cfg = &Config{
 		Auth: AuthConfig{
 			JWTSecret:         viper.GetString("JWT_SECRET"),
 			JWTExpiration:     viper.GetDuration("JWT_EXPIRATION"),
+			JWTKeysDir:        viper.GetString("JWT_KEYS_DIR"),
+			JWTSigningAlg:     viper.GetString("JWT_SIGNING_ALG"),
+			JWTKeyRotation:    viper.GetDuration("JWT_KEY_ROTATION"),
+			JWTKeyPrePublish:  viper.GetDuration("JWT_KEY_PREPUBLISH"),
 			RefreshExpiration: viper.GetDuration("REFRESH_EXPIRATION"),
 			PasswordMinLength: viper.GetInt("PASSWORD_MIN_LENGTH"),
 			RateLimitRequests: viper.GetInt("RATE_LIMIT_REQUESTS"),
@@ -139,6 +256,16 @@ func Init() {
 			AndroidClientID: viper.GetString("ANDROID_GOOGLE_CLIENT_ID"),
 			WebClientID:     viper.GetString("WEB_GOOGLE_CLIENT_ID"),
 		},
+		Identity: IdentityConfig{
+			AppleClientID:      viper.GetString("APPLE_CLIENT_ID"),
+			AppleClientSecret:  viper.GetString("APPLE_CLIENT_SECRET"),
+			AppleBundleID:      viper.GetString("APPLE_BUNDLE_ID"),
+			YandexClientID:     viper.GetString("YANDEX_CLIENT_ID"),
+			YandexClientSecret: viper.GetString("YANDEX_CLIENT_SECRET"),
+			GitHubClientID:     viper.GetString("GITHUB_CLIENT_ID"),
+			GitHubClientSecret: viper.GetString("GITHUB_CLIENT_SECRET"),
+			OIDCProviders:      viper.GetString("OIDC_PROVIDERS"),
+		},
 		Swagger: SwaggerConfig{
 			AllowedEmails: parseEmailWhitelist(viper.GetString("SWAGGER_ALLOWED_EMAILS")),
 			Host:          viper.GetString("SWAGGER_HOST"),
@@ -146,6 +273,58 @@ func Init() {
 		Redis: RedisConfig{
 			ChatLockTTL: viper.GetDuration("REDIS_CHAT_LOCK_TTL"),
 		},
+		Jobs: JobsConfig{
+			WorkerEnabled: viper.GetBool("JOBS_WORKER_ENABLED"),
+			Concurrency:   viper.GetInt("JOBS_CONCURRENCY"),
+			MaxRetry:      viper.GetInt("JOBS_MAX_RETRY"),
+		},
+		TTS: TTSConfig{
+			URL:      viper.GetString("TTS_URL"),
+			APIKey:   viper.GetString("TTS_API_KEY"),
+			Model:    viper.GetString("TTS_MODEL"),
+			Voice:    viper.GetString("TTS_VOICE"),
+			Language: viper.GetString("TTS_LANGUAGE"),
+			Timeout:  viper.GetDuration("TTS_TIMEOUT"),
+		},
+		Storage: StorageConfig{
+			Endpoint:  viper.GetString("S3_ENDPOINT"),
+			Region:    viper.GetString("S3_REGION"),
+			Bucket:    viper.GetString("S3_BUCKET"),
+			AccessKey: viper.GetString("S3_ACCESS_KEY"),
+			SecretKey: viper.GetString("S3_SECRET_KEY"),
+			PublicURL: viper.GetString("S3_PUBLIC_URL"),
+			PathStyle: viper.GetBool("S3_PATH_STYLE"),
+
+			Dir:     viper.GetString("STORAGE_DIR"),
+			BaseURL: viper.GetString("STORAGE_BASE_URL"),
+
+			SigningKey: viper.GetString("STORAGE_SIGNING_KEY"),
+			URLTTL:     viper.GetDuration("STORAGE_URL_TTL"),
+		},
+		Media: MediaConfig{
+			AvatarMaxSize: viper.GetInt64("AVATAR_MAX_SIZE"),
+			AvatarSize:    viper.GetInt("AVATAR_SIZE"),
+			AudioMaxSize:  viper.GetInt64("AUDIO_MAX_SIZE"),
+			ApkgMaxSize:   viper.GetInt64("APKG_MAX_UNPACKED_SIZE"),
+		},
+		Gamification: GamificationConfig{
+			TimeZone:    viper.GetString("STREAK_TIME_ZONE"),
+			MaxFreezes:  viper.GetInt("STREAK_MAX_FREEZES"),
+			FreezeEvery: viper.GetInt("STREAK_FREEZE_EVERY"),
+			MaxBackdate: viper.GetDuration("STREAK_MAX_BACKDATE"),
+			DailyXPCap:  viper.GetInt("XP_DAILY_CAP"),
+		},
+		Service: ServiceConfig{
+			Keys:    viper.GetString("SERVICE_KEYS"),
+			MaxSkew: viper.GetDuration("SERVICE_SIGNATURE_MAX_SKEW"),
+		},
+		Telegram: TelegramConfig{
+			BotToken:   viper.GetString("BOT_TOKEN"),
+			AuthMaxAge: viper.GetDuration("TELEGRAM_AUTH_MAX_AGE"),
+		},
+	}
+	if cfg.Storage.SigningKey == "" {
+		cfg.Storage.SigningKey = cfg.Auth.JWTSecret
 	}
 }
 
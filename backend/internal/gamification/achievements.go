@@ -0,0 +1,90 @@
+// Package gamification holds the rules of XP, levels, daily streaks and achievements.
+// It has no storage of its own, postgres.GamificationRepository applies the rules.
+package gamification
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// Metrics achievements are unlocked by
+const (
+	MetricLearnedWords    = "learned_words"    // words in the learned list
+	MetricLessons         = "lessons"          // finished lessons
+	MetricStreak          = "streak"           // longest daily streak
+	MetricXP              = "xp"               // total XP
+	MetricTopicsCompleted = "topics_completed" // topics with every word learned
+)
+
+// Achievement is unlocked once a metric reaches the threshold
+type Achievement struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+}
+
+// Metrics are the current values of a user's metrics
+type Metrics map[string]int
+
+//go:embed achievements.json
+var achievementsJSON []byte
+
+// achievements are the definitions from achievements.json, in display order
+var achievements = mustParseAchievements(achievementsJSON)
+
+// Achievements returns all achievement definitions
+func Achievements() []Achievement {
+	return achievements
+}
+
+// Progress returns how far the user is towards the achievement, capped at its threshold
+func (a Achievement) Progress(metrics Metrics) int {
+	return min(metrics[a.Metric], a.Threshold)
+}
+
+// NewlyUnlocked returns achievements the metrics reach that aren't unlocked yet
+func NewlyUnlocked(metrics Metrics, unlocked map[string]bool) []Achievement {
+	var result []Achievement
+	for _, a := range achievements {
+		if !unlocked[a.ID] && metrics[a.Metric] >= a.Threshold {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// UsesMetric reports whether any achievement still to unlock depends on the metric,
+// so expensive metrics are only computed when they matter
+func UsesMetric(metric string, unlocked map[string]bool) bool {
+	for _, a := range achievements {
+		if a.Metric == metric && !unlocked[a.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// mustParseAchievements parses and validates achievement definitions
+func mustParseAchievements(data []byte) []Achievement {
+	var list []Achievement
+	if err := json.Unmarshal(data, &list); err != nil {
+		panic(fmt.Sprintf("invalid achievements.json: %v", err))
+	}
+
+	known := map[string]bool{
+		MetricLearnedWords: true, MetricLessons: true, MetricStreak: true, MetricXP: true, MetricTopicsCompleted: true,
+	}
+	ids := make(map[string]bool, len(list))
+	for _, a := range list {
+		if a.ID == "" || ids[a.ID] || !known[a.Metric] || a.Threshold <= 0 {
+			panic(fmt.Sprintf("invalid achievement %q in achievements.json", a.ID))
+		}
+		ids[a.ID] = true
+	}
+
+	return list
+}
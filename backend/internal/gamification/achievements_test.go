@@ -0,0 +1,46 @@
+package gamification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAchievementsDefinitions tests that the embedded definitions parse
+func TestAchievementsDefinitions(t *testing.T) {
+	ids := map[string]bool{}
+	for _, a := range Achievements() {
+		ids[a.ID] = true
+	}
+	assert.True(t, ids["words_100"])
+	assert.True(t, ids["streak_7"])
+	assert.True(t, ids["topic_finished"])
+
+	assert.Panics(t, func() {
+		mustParseAchievements([]byte(`[{"id":"x","metric":"unknown","threshold":1}]`))
+	})
+	assert.Panics(t, func() {
+		mustParseAchievements([]byte(`[{"id":"x","metric":"xp","threshold":1},{"id":"x","metric":"xp","threshold":2}]`))
+	})
+}
+
+// TestNewlyUnlocked tests unlocking achievements by metrics
+func TestNewlyUnlocked(t *testing.T) {
+	metrics := Metrics{MetricLearnedWords: 100, MetricStreak: 3}
+
+	var ids []string
+	for _, a := range NewlyUnlocked(metrics, map[string]bool{"first_word": true}) {
+		ids = append(ids, a.ID)
+	}
+	assert.Equal(t, []string{"words_100"}, ids)
+
+	assert.True(t, UsesMetric(MetricTopicsCompleted, map[string]bool{}))
+	assert.False(t, UsesMetric(MetricTopicsCompleted, map[string]bool{"topic_finished": true}))
+}
+
+// TestAchievementProgress tests that progress is capped at the threshold
+func TestAchievementProgress(t *testing.T) {
+	a := Achievement{Metric: MetricXP, Threshold: 1000}
+	assert.Equal(t, 250, a.Progress(Metrics{MetricXP: 250}))
+	assert.Equal(t, 1000, a.Progress(Metrics{MetricXP: 4000}))
+}
@@ -0,0 +1,21 @@
+package gamification
+
+// Event types, clients turn them into celebratory messages
+const (
+	EventXPGained            = "xp_gained"
+	EventLevelUp             = "level_up"
+	EventStreakExtended      = "streak_extended"
+	EventStreakFreezeUsed    = "streak_freeze_used"
+	EventStreakFreezeEarned  = "streak_freeze_earned"
+	EventAchievementUnlocked = "achievement_unlocked"
+)
+
+// Event is a noteworthy outcome of recorded activity
+type Event struct {
+	Type        string
+	XP          int          // XP gained, for xp_gained
+	Level       int          // level reached, for level_up
+	Streak      int          // current streak, for streak events
+	Freezes     int          // freezes used or left, for freeze events
+	Achievement *Achievement // for achievement_unlocked
+}
@@ -0,0 +1,131 @@
+package gamification
+
+import (
+	"time"
+
+	// Time zones of users must resolve in minimal containers without tzdata
+	_ "time/tzdata"
+
+	"fluently/go-backend/internal/config"
+)
+
+// DateLayout is the layout of streak days
+const DateLayout = "2006-01-02"
+
+// StreakRules configure streak freezes. A freeze covers one missed day and is earned
+// every FreezeEvery days of streak, up to MaxFreezes; MaxFreezes of 0 disables them.
+// Client times older than MaxBackdate count on the first day of that window.
+type StreakRules struct {
+	MaxFreezes  int
+	FreezeEvery int
+	MaxBackdate time.Duration
+}
+
+// ConfiguredRules returns the streak rules of the configuration
+func ConfiguredRules() StreakRules {
+	cfg := config.GetConfig().Gamification
+	return StreakRules{MaxFreezes: cfg.MaxFreezes, FreezeEvery: cfg.FreezeEvery, MaxBackdate: cfg.MaxBackdate}
+}
+
+// ActivityDay returns the day activity at t counts on. Clients can't backdate it further
+// than MaxBackdate from now, so a new account can't build a long streak in one session.
+func (r StreakRules) ActivityDay(t, now time.Time, loc *time.Location) time.Time {
+	day := Day(t, loc)
+	if r.MaxBackdate <= 0 {
+		return day
+	}
+	if earliest := Day(now.Add(-r.MaxBackdate), loc); day.Before(earliest) {
+		return earliest
+	}
+	return day
+}
+
+// Location resolves a user's time zone, falling back to STREAK_TIME_ZONE and then UTC
+func Location(name string) *time.Location {
+	for _, tz := range []string{name, config.GetConfig().Gamification.TimeZone} {
+		if tz == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// Streak is a run of consecutive days with activity
+type Streak struct {
+	Current int
+	Longest int
+	Freezes int       // freezes left
+	LastDay time.Time // last day with activity, zero before the first one
+}
+
+// StreakChange describes what recording a day did to the streak
+type StreakChange struct {
+	Extended     bool // the day was new, Current now includes it
+	Broken       bool // days were missed and the streak started over
+	FreezesUsed  int
+	FreezeEarned bool
+}
+
+// Day returns the calendar day of t in loc, as midnight UTC so days compare and subtract exactly
+func Day(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Record counts activity on a day. Days before the last recorded one (late offline
+// events) don't change the streak.
+func (s *Streak) Record(day time.Time, rules StreakRules) StreakChange {
+	var change StreakChange
+	if !s.LastDay.IsZero() && !day.After(s.LastDay) {
+		return change
+	}
+
+	missed := 0
+	if !s.LastDay.IsZero() {
+		missed = daysBetween(s.LastDay, day) - 1
+	}
+
+	switch {
+	case s.LastDay.IsZero():
+		s.Current = 1
+	case missed == 0:
+		s.Current++
+	case missed <= s.Freezes:
+		s.Freezes -= missed
+		change.FreezesUsed = missed
+		s.Current++
+	default:
+		s.Current = 1
+		change.Broken = true
+	}
+	s.LastDay = day
+	change.Extended = true
+
+	if rules.MaxFreezes > 0 && rules.FreezeEvery > 0 && s.Current%rules.FreezeEvery == 0 && s.Freezes < rules.MaxFreezes {
+		s.Freezes++
+		change.FreezeEarned = true
+	}
+	s.Longest = max(s.Longest, s.Current)
+
+	return change
+}
+
+// CurrentOn returns the streak as seen on a day: it's kept while today's activity can still
+// continue it, counting the freezes that would cover the missed days
+func (s Streak) CurrentOn(day time.Time) int {
+	if s.LastDay.IsZero() {
+		return 0
+	}
+	if missed := daysBetween(s.LastDay, day) - 1; missed > s.Freezes {
+		return 0
+	}
+	return s.Current
+}
+
+// daysBetween returns the number of days from one day to another
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
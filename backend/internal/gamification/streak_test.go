@@ -0,0 +1,112 @@
+package gamification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = StreakRules{MaxFreezes: 2, FreezeEvery: 7}
+
+// day returns a streak day from a date
+func day(t *testing.T, date string) time.Time {
+	d, err := time.Parse(DateLayout, date)
+	assert.NoError(t, err)
+	return d
+}
+
+// TestStreakRecord tests extending, repeating and breaking a streak
+func TestStreakRecord(t *testing.T) {
+	var s Streak
+
+	change := s.Record(day(t, "2025-01-01"), testRules)
+	assert.True(t, change.Extended)
+	assert.Equal(t, 1, s.Current)
+
+	change = s.Record(day(t, "2025-01-02"), testRules)
+	assert.True(t, change.Extended)
+	assert.Equal(t, 2, s.Current)
+
+	// Same and earlier days change nothing
+	assert.Equal(t, StreakChange{}, s.Record(day(t, "2025-01-02"), testRules))
+	assert.Equal(t, StreakChange{}, s.Record(day(t, "2024-12-31"), testRules))
+	assert.Equal(t, 2, s.Current)
+
+	change = s.Record(day(t, "2025-01-05"), testRules)
+	assert.True(t, change.Broken)
+	assert.Equal(t, 1, s.Current)
+	assert.Equal(t, 2, s.Longest)
+}
+
+// TestStreakFreezes tests earning freezes and spending them on missed days
+func TestStreakFreezes(t *testing.T) {
+	var s Streak
+	start := day(t, "2025-03-01")
+	for i := 0; i < 7; i++ {
+		s.Record(start.AddDate(0, 0, i), testRules)
+	}
+	assert.Equal(t, 7, s.Current)
+	assert.Equal(t, 1, s.Freezes)
+
+	// One missed day is covered by the freeze
+	change := s.Record(start.AddDate(0, 0, 8), testRules)
+	assert.False(t, change.Broken)
+	assert.Equal(t, 1, change.FreezesUsed)
+	assert.Equal(t, 8, s.Current)
+	assert.Equal(t, 0, s.Freezes)
+
+	// Without freezes left the streak breaks
+	change = s.Record(start.AddDate(0, 0, 10), testRules)
+	assert.True(t, change.Broken)
+	assert.Equal(t, 1, s.Current)
+	assert.Equal(t, 8, s.Longest)
+}
+
+// TestStreakFreezesDisabled tests that no freezes are earned with MaxFreezes of 0
+func TestStreakFreezesDisabled(t *testing.T) {
+	var s Streak
+	start := day(t, "2025-03-01")
+	for i := 0; i < 14; i++ {
+		assert.False(t, s.Record(start.AddDate(0, 0, i), StreakRules{FreezeEvery: 7}).FreezeEarned)
+	}
+	assert.Equal(t, 0, s.Freezes)
+}
+
+// TestStreakCurrentOn tests the streak shown before today's activity
+func TestStreakCurrentOn(t *testing.T) {
+	s := Streak{Current: 5, Freezes: 1, LastDay: day(t, "2025-01-10")}
+
+	assert.Equal(t, 5, s.CurrentOn(day(t, "2025-01-10")))
+	assert.Equal(t, 5, s.CurrentOn(day(t, "2025-01-11")))
+	assert.Equal(t, 5, s.CurrentOn(day(t, "2025-01-12")))
+	assert.Equal(t, 0, s.CurrentOn(day(t, "2025-01-13")))
+	assert.Equal(t, 0, Streak{}.CurrentOn(day(t, "2025-01-13")))
+}
+
+// TestDay tests that days follow the time zone
+func TestDay(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	at := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, day(t, "2025-01-01"), Day(at, time.UTC))
+	assert.Equal(t, day(t, "2025-01-02"), Day(at, loc))
+}
+
+// TestActivityDay tests that client times can't be backdated beyond the window
+func TestActivityDay(t *testing.T) {
+	rules := StreakRules{MaxBackdate: 72 * time.Hour}
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, day(t, "2025-01-09"), rules.ActivityDay(now.Add(-24*time.Hour), now, time.UTC))
+	assert.Equal(t, day(t, "2025-01-07"), rules.ActivityDay(now.AddDate(0, 0, -30), now, time.UTC))
+	assert.Equal(t, day(t, "2024-12-11"), StreakRules{}.ActivityDay(now.AddDate(0, 0, -30), now, time.UTC))
+
+	// A fresh streak backfilled over a month only counts the days of the window
+	var s Streak
+	for i := 30; i >= 0; i-- {
+		s.Record(rules.ActivityDay(now.AddDate(0, 0, -i), now, time.UTC), rules)
+	}
+	assert.Equal(t, 4, s.Current)
+}
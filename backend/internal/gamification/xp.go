@@ -0,0 +1,103 @@
+package gamification
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"fluently/go-backend/internal/config"
+)
+
+// Activity types
+const (
+	ActivityExercise    = "exercise"     // answered exercises, reported by clients
+	ActivityLesson      = "lesson"       // finished lesson with its answers, reported by clients
+	ActivityWordLearned = "word_learned" // word moved to the learned list by sync
+	ActivityWordMissed  = "word_missed"  // word answered badly, reported by sync
+)
+
+// XP rewards
+const (
+	XPCorrectAnswer = 10
+	XPWrongAnswer   = 2 // practice counts even when the answer is wrong
+	XPLesson        = 50
+	XPWordLearned   = 5
+	XPWordMissed    = 1
+)
+
+// maxAnswers bounds answers of one activity, so a client can't claim unlimited XP
+const maxAnswers = 200
+
+// levelStep is the XP of level 2, level n needs levelStep*(n-1)^2 XP
+const levelStep = 50
+
+// Activity is something a user did that earns XP and counts towards the streak
+type Activity struct {
+	Type    string
+	Key     string // idempotency key, activities with a seen key are ignored
+	Correct int    // correct answers of exercises and lessons
+	Total   int    // all answers of exercises and lessons
+	Time    time.Time
+}
+
+// Validate checks an activity reported by a client
+func (a Activity) Validate() error {
+	if a.Type != ActivityExercise && a.Type != ActivityLesson {
+		return errors.New("type must be exercise or lesson")
+	}
+	if a.Correct < 0 || a.Total < a.Correct || a.Total > maxAnswers {
+		return errors.New("answers must satisfy 0 <= correct <= total <= 200")
+	}
+	if a.Type == ActivityExercise && a.Total == 0 {
+		return errors.New("exercise must have at least one answer")
+	}
+	return nil
+}
+
+// XP returns the XP the activity earns
+func (a Activity) XP() int {
+	answers := a.Correct*XPCorrectAnswer + (a.Total-a.Correct)*XPWrongAnswer
+
+	switch a.Type {
+	case ActivityExercise:
+		return answers
+	case ActivityLesson:
+		return XPLesson + answers
+	case ActivityWordLearned:
+		return XPWordLearned
+	case ActivityWordMissed:
+		return XPWordMissed
+	}
+	return 0
+}
+
+// ClientReported reports whether the XP of the activity comes from answers a client claims
+func (a Activity) ClientReported() bool {
+	return a.Type == ActivityExercise || a.Type == ActivityLesson
+}
+
+// DailyXPCap returns the configured XP a day for client reported activity, 0 for no cap
+func DailyXPCap() int {
+	return config.GetConfig().Gamification.DailyXPCap
+}
+
+// CapXP returns the part of amount that fits under the daily cap after earned XP that day
+func CapXP(amount, earned, limit int) int {
+	if limit <= 0 {
+		return amount
+	}
+	return max(0, min(amount, limit-earned))
+}
+
+// Level returns the level reached with the XP, starting at 1
+func Level(xp int) int {
+	if xp <= 0 {
+		return 1
+	}
+	return int(math.Sqrt(float64(xp)/levelStep)) + 1
+}
+
+// LevelXP returns the XP a level starts at
+func LevelXP(level int) int {
+	return levelStep * (level - 1) * (level - 1)
+}
@@ -0,0 +1,53 @@
+package gamification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestActivityValidate tests validation of client activities
+func TestActivityValidate(t *testing.T) {
+	assert.NoError(t, Activity{Type: ActivityLesson, Correct: 8, Total: 10}.Validate())
+	assert.NoError(t, Activity{Type: ActivityLesson}.Validate())
+	assert.NoError(t, Activity{Type: ActivityExercise, Correct: 1, Total: 1}.Validate())
+
+	assert.Error(t, Activity{Type: ActivityWordLearned}.Validate())
+	assert.Error(t, Activity{Type: ActivityExercise}.Validate())
+	assert.Error(t, Activity{Type: ActivityLesson, Correct: 3, Total: 2}.Validate())
+	assert.Error(t, Activity{Type: ActivityLesson, Correct: -1, Total: 2}.Validate())
+	assert.Error(t, Activity{Type: ActivityLesson, Total: 201}.Validate())
+}
+
+// TestActivityXP tests XP rewards
+func TestActivityXP(t *testing.T) {
+	assert.Equal(t, 84, Activity{Type: ActivityExercise, Correct: 8, Total: 10}.XP())
+	assert.Equal(t, 134, Activity{Type: ActivityLesson, Correct: 8, Total: 10}.XP())
+	assert.Equal(t, XPWordLearned, Activity{Type: ActivityWordLearned}.XP())
+	assert.Equal(t, XPWordMissed, Activity{Type: ActivityWordMissed}.XP())
+}
+
+// TestLevel tests levels and the XP they start at
+func TestLevel(t *testing.T) {
+	assert.Equal(t, 1, Level(0))
+	assert.Equal(t, 1, Level(49))
+	assert.Equal(t, 2, Level(50))
+	assert.Equal(t, 3, Level(200))
+
+	for level := 1; level < 20; level++ {
+		assert.Equal(t, level, Level(LevelXP(level)))
+		assert.Equal(t, level, Level(LevelXP(level+1)-1))
+	}
+}
+
+// TestCapXP tests the daily cap of client reported XP
+func TestCapXP(t *testing.T) {
+	assert.Equal(t, 150, CapXP(150, 0, 1000))
+	assert.Equal(t, 100, CapXP(150, 900, 1000))
+	assert.Equal(t, 0, CapXP(150, 1000, 1000))
+	assert.Equal(t, 0, CapXP(150, 1200, 1000))
+	assert.Equal(t, 2050, CapXP(2050, 5000, 0), "0 disables the cap")
+
+	assert.True(t, Activity{Type: ActivityLesson}.ClientReported())
+	assert.False(t, Activity{Type: ActivityWordLearned}.ClientReported())
+}
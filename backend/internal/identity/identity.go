@@ -0,0 +1,111 @@
+// Package identity signs users in with external accounts. OpenID Connect issuers
+// (Apple, Google or any issuer from the configuration) are verified with their
+// discovery document and JWKS, OAuth 2.0 providers without ID tokens (GitHub,
+// Yandex) are asked for the user over their API with the access token.
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownProvider     = errors.New("unknown identity provider")
+	ErrIDTokenNotSupported = errors.New("identity provider doesn't issue ID tokens")
+	ErrInvalidToken        = errors.New("invalid ID token")
+	ErrExchange            = errors.New("authorization code exchange failed")
+)
+
+// providerName keeps provider names usable in URLs and identity rows
+var providerName = regexp.MustCompile(`^[a-z][a-z0-9-]{1,49}$`)
+
+// Identity is an external account of a user
+type Identity struct {
+	Provider      string
+	Subject       string // stable ID of the account at the provider
+	Email         string
+	EmailVerified bool // whether the provider vouches that the account owns the email
+	Name          string
+	AvatarURL     string
+}
+
+// Provider signs users in with their account at an external service
+type Provider interface {
+	// Name returns the name of the provider used in URLs and stored identities
+	Name() string
+	// AuthCodeURL returns the URL of the consent screen, the provider redirects back to redirectURI with a code
+	AuthCodeURL(ctx context.Context, state, redirectURI string) (string, error)
+	// Exchange trades an authorization code for the identity of the user
+	Exchange(ctx context.Context, code, redirectURI string) (*Identity, error)
+	// VerifyIDToken checks an ID token issued to one of our clients, like the one native apps get from the provider's SDK
+	VerifyIDToken(ctx context.Context, rawIDToken string) (*Identity, error)
+}
+
+// Registry holds the enabled providers
+type Registry struct {
+	providers map[string]Provider
+	names     []string
+}
+
+// NewRegistry creates a registry, provider names must be unique
+func NewRegistry(providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		if _, ok := r.providers[p.Name()]; ok {
+			return nil, fmt.Errorf("duplicate identity provider %q", p.Name())
+		}
+		r.providers[p.Name()] = p
+		r.names = append(r.names, p.Name())
+	}
+	return r, nil
+}
+
+// Get returns the provider with the name
+func (r *Registry) Get(name string) (Provider, error) {
+	if p, ok := r.providers[name]; ok {
+		return p, nil
+	}
+	return nil, ErrUnknownProvider
+}
+
+// Names returns the names of the enabled providers in the order they were registered
+func (r *Registry) Names() []string {
+	return r.names
+}
+
+// ParseOIDCProviders parses OpenID Connect issuers from the configuration, written as
+// "name|issuer|client_id|client_secret" and separated by semicolons
+func ParseOIDCProviders(s string) ([]OIDCConfig, error) {
+	var configs []OIDCConfig
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("OIDC provider %q must be name|issuer|client_id|client_secret", parts[0])
+		}
+		if !providerName.MatchString(parts[0]) {
+			return nil, fmt.Errorf("OIDC provider name %q must be lowercase letters, digits and dashes", parts[0])
+		}
+		if !strings.HasPrefix(parts[1], "https://") {
+			return nil, fmt.Errorf("issuer of OIDC provider %q must be an https URL", parts[0])
+		}
+		if parts[2] == "" {
+			return nil, fmt.Errorf("OIDC provider %q has no client ID", parts[0])
+		}
+
+		configs = append(configs, OIDCConfig{
+			Name:         parts[0],
+			Issuer:       parts[1],
+			ClientID:     parts[2],
+			ClientSecret: parts[3],
+		})
+	}
+	return configs, nil
+}
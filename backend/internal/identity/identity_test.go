@@ -0,0 +1,172 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIssuer serves a discovery document and the public key of its signing key
+type testIssuer struct {
+	*httptest.Server
+	key jwk.Key
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, "test-key"))
+	require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.RS256))
+
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/authorize",
+			"token_endpoint":         issuer.URL + "/token",
+			"jwks_uri":               issuer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		public, _ := key.PublicKey()
+		set := jwk.NewSet()
+		set.AddKey(public)
+		json.NewEncoder(w).Encode(set)
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// sign issues an ID token with the claims on top of valid defaults
+func (i *testIssuer) sign(t *testing.T, claims map[string]interface{}) string {
+	token := jwt.New()
+	defaults := map[string]interface{}{
+		jwt.IssuerKey:     i.URL,
+		jwt.SubjectKey:    "user-1",
+		jwt.AudienceKey:   "web-client",
+		jwt.IssuedAtKey:   time.Now(),
+		jwt.ExpirationKey: time.Now().Add(time.Hour),
+	}
+	for k, v := range defaults {
+		require.NoError(t, token.Set(k, v))
+	}
+	for k, v := range claims {
+		require.NoError(t, token.Set(k, v))
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, i.key))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+// TestVerifyIDToken tests accepted and rejected ID tokens
+func TestVerifyIDToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := NewOIDCProvider(OIDCConfig{
+		Name:      "test",
+		Issuer:    issuer.URL + "/",
+		ClientID:  "web-client",
+		Audiences: []string{"ios-app"},
+	}, issuer.Client())
+	ctx := context.Background()
+
+	identity, err := provider.VerifyIDToken(ctx, issuer.sign(t, map[string]interface{}{
+		"email":          "ivan@example.com",
+		"email_verified": "true",
+		"name":           "Ivan",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{
+		Provider:      "test",
+		Subject:       "user-1",
+		Email:         "ivan@example.com",
+		EmailVerified: true,
+		Name:          "Ivan",
+	}, identity)
+
+	// Tokens of the mobile apps are accepted too
+	_, err = provider.VerifyIDToken(ctx, issuer.sign(t, map[string]interface{}{jwt.AudienceKey: "ios-app"}))
+	assert.NoError(t, err)
+
+	for name, claims := range map[string]map[string]interface{}{
+		"other audience": {jwt.AudienceKey: "someone-else"},
+		"other issuer":   {jwt.IssuerKey: "https://evil.example.com"},
+		"expired":        {jwt.ExpirationKey: time.Now().Add(-time.Hour)},
+		"no subject":     {jwt.SubjectKey: ""},
+	} {
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	// Signed by a key the issuer doesn't publish
+	other := newTestIssuer(t)
+	forged := other.sign(t, map[string]interface{}{jwt.IssuerKey: issuer.URL})
+	_, err = provider.VerifyIDToken(ctx, forged)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// TestAuthCodeURL tests the consent screen URL built from the discovery document
+func TestAuthCodeURL(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := NewOIDCProvider(OIDCConfig{
+		Name:       "test",
+		Issuer:     issuer.URL,
+		ClientID:   "web-client",
+		AuthParams: map[string]string{"response_mode": "form_post"},
+	}, issuer.Client())
+
+	url, err := provider.AuthCodeURL(context.Background(), "st4te", "https://app.example.com/callback")
+	require.NoError(t, err)
+	assert.Contains(t, url, issuer.URL+"/authorize?")
+	assert.Contains(t, url, "state=st4te")
+	assert.Contains(t, url, "response_mode=form_post")
+	assert.Contains(t, url, "scope=openid+email+profile")
+}
+
+// TestParseOIDCProviders tests parsing issuers from the configuration
+func TestParseOIDCProviders(t *testing.T) {
+	configs, err := ParseOIDCProviders("keycloak|https://sso.example.com/realms/main|fluently|s3cret; gitlab|https://gitlab.com|abc|")
+	require.NoError(t, err)
+	if assert.Len(t, configs, 2) {
+		assert.Equal(t, OIDCConfig{Name: "keycloak", Issuer: "https://sso.example.com/realms/main", ClientID: "fluently", ClientSecret: "s3cret"}, configs[0])
+		assert.Equal(t, "gitlab", configs[1].Name)
+	}
+
+	for _, invalid := range []string{
+		"keycloak|https://sso.example.com",
+		"Keycloak|https://sso.example.com|id|secret",
+		"keycloak|http://sso.example.com|id|secret",
+		"keycloak|https://sso.example.com||secret",
+	} {
+		_, err := ParseOIDCProviders(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestRegistry tests looking up providers
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry(NewGitHubProvider("id", "secret", nil), NewYandexProvider("id", "secret", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github", "yandex"}, registry.Names())
+
+	_, err = registry.Get("apple")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	_, err = NewRegistry(NewGitHubProvider("id", "secret", nil), NewGitHubProvider("id", "secret", nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/yandex"
+)
+
+// OAuth2Provider signs users in with a provider that issues no ID tokens,
+// the user is fetched from the provider's API with the access token
+type OAuth2Provider struct {
+	name      string
+	config    oauth2.Config
+	client    *http.Client
+	fetchUser func(ctx context.Context, client *http.Client, token *oauth2.Token) (*Identity, error)
+}
+
+// NewGitHubProvider creates the GitHub provider
+func NewGitHubProvider(clientID, clientSecret string, client *http.Client) *OAuth2Provider {
+	return newOAuth2Provider("github", oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       []string{"read:user", "user:email"},
+		Endpoint:     github.Endpoint,
+	}, client, fetchGitHubUser)
+}
+
+// NewYandexProvider creates the Yandex ID provider
+func NewYandexProvider(clientID, clientSecret string, client *http.Client) *OAuth2Provider {
+	return newOAuth2Provider("yandex", oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       []string{"login:info", "login:email", "login:avatar"},
+		Endpoint:     yandex.Endpoint,
+	}, client, fetchYandexUser)
+}
+
+func newOAuth2Provider(name string, config oauth2.Config, client *http.Client, fetchUser func(context.Context, *http.Client, *oauth2.Token) (*Identity, error)) *OAuth2Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OAuth2Provider{name: name, config: config, client: client, fetchUser: fetchUser}
+}
+
+// Name returns the name of the provider
+func (p *OAuth2Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the URL of the consent screen of the provider
+func (p *OAuth2Provider) AuthCodeURL(_ context.Context, state, redirectURI string) (string, error) {
+	conf := p.config
+	conf.RedirectURL = redirectURI
+	return conf.AuthCodeURL(state), nil
+}
+
+// Exchange trades the code for an access token and fetches the user with it
+func (p *OAuth2Provider) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	conf := p.config
+	conf.RedirectURL = redirectURI
+
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+
+	identity, err := p.fetchUser(ctx, p.client, token)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s user: %w", p.name, err)
+	}
+	identity.Provider = p.name
+	return identity, nil
+}
+
+// VerifyIDToken isn't supported, these providers have no ID tokens
+func (p *OAuth2Provider) VerifyIDToken(context.Context, string) (*Identity, error) {
+	return nil, ErrIDTokenNotSupported
+}
+
+// getJSON sends the request with the authorization header and decodes the JSON response into v
+func getJSON(ctx context.Context, client *http.Client, url, authorization string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetchGitHubUser fetches the user and their primary email, GitHub reports whether it was verified
+func fetchGitHubUser(ctx context.Context, client *http.Client, token *oauth2.Token) (*Identity, error) {
+	authorization := "Bearer " + token.AccessToken
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user", authorization, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("missing user ID")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user/emails", authorization, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+	return identity, nil
+}
+
+// fetchYandexUser fetches the user from Yandex ID.
+// Yandex doesn't say whether the default email was verified, so it's never treated as verified.
+func fetchYandexUser(ctx context.Context, client *http.Client, token *oauth2.Token) (*Identity, error) {
+	var user struct {
+		ID              string `json:"id"`
+		Login           string `json:"login"`
+		RealName        string `json:"real_name"`
+		DisplayName     string `json:"display_name"`
+		DefaultEmail    string `json:"default_email"`
+		DefaultAvatarID string `json:"default_avatar_id"`
+		IsAvatarEmpty   bool   `json:"is_avatar_empty"`
+	}
+	if err := getJSON(ctx, client, "https://login.yandex.ru/info?format=json", "OAuth "+token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == "" {
+		return nil, fmt.Errorf("missing user ID")
+	}
+
+	identity := &Identity{
+		Subject: user.ID,
+		Email:   user.DefaultEmail,
+		Name:    user.RealName,
+	}
+	if identity.Name == "" {
+		identity.Name = user.DisplayName
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	if !user.IsAvatarEmpty && user.DefaultAvatarID != "" {
+		identity.AvatarURL = "https://avatars.yandex.net/get-yandex/" + user.DefaultAvatarID + "/islands-200"
+	}
+	return identity, nil
+}
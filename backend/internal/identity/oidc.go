@@ -0,0 +1,260 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// keysTTL is how long fetched signing keys are trusted before they are fetched again
+	keysTTL = time.Hour
+	// minKeysRefresh stops tokens with unknown key IDs from making us fetch keys on every request
+	minKeysRefresh = time.Minute
+	// maxTokenSkew tolerates ID tokens issued slightly ahead of our clock
+	maxTokenSkew = time.Minute
+)
+
+// OIDCConfig is the configuration of an OpenID Connect issuer
+type OIDCConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Audiences    []string          // more client IDs ID tokens may be issued to, like the ones of the mobile apps
+	Scopes       []string          // openid, email and profile by default
+	AuthParams   map[string]string // extra parameters of the consent screen URL
+}
+
+// discoveryDocument is the part of /.well-known/openid-configuration we use
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider signs users in with an OpenID Connect issuer.
+// The discovery document and signing keys are fetched on first use, so an issuer being down doesn't stop the app from starting.
+type OIDCProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          jwk.Set
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider creates a provider, client is used for discovery, keys and code exchange
+func NewOIDCProvider(cfg OIDCConfig, client *http.Client) *OIDCProvider {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCProvider{cfg: cfg, client: client}
+}
+
+// Name returns the name of the provider
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL returns the URL of the consent screen of the issuer
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, redirectURI string) (string, error) {
+	conf, err := p.oauthConfig(ctx, redirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	opts := make([]oauth2.AuthCodeOption, 0, len(p.cfg.AuthParams))
+	for key, value := range p.cfg.AuthParams {
+		opts = append(opts, oauth2.SetAuthURLParam(key, value))
+	}
+	return conf.AuthCodeURL(state, opts...), nil
+}
+
+// Exchange trades the code for tokens and verifies the ID token among them
+func (p *OIDCProvider) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	conf, err := p.oauthConfig(ctx, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: no id_token in the response", ErrExchange)
+	}
+	return p.VerifyIDToken(ctx, rawIDToken)
+}
+
+// VerifyIDToken checks the signature, issuer, audience and lifetime of an ID token
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken string) (*Identity, error) {
+	keys, err := p.keySet(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.parse(rawIDToken, keys)
+	if err != nil {
+		// The issuer may have rotated its keys since we fetched them
+		if keys, refreshErr := p.keySet(ctx, true); refreshErr == nil {
+			token, err = p.parse(rawIDToken, keys)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	audiences := append([]string{p.cfg.ClientID}, p.cfg.Audiences...)
+	if !slices.ContainsFunc(token.Audience(), func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return nil, fmt.Errorf("%w: issued to another client", ErrInvalidToken)
+	}
+	if token.Subject() == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+
+	claims := token.PrivateClaims()
+	identity := &Identity{
+		Provider:      p.cfg.Name,
+		Subject:       token.Subject(),
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          stringClaim(claims, "name"),
+		AvatarURL:     stringClaim(claims, "picture"),
+	}
+	return identity, nil
+}
+
+// parse verifies the token with the keys, keys without "alg" get it inferred from their type
+func (p *OIDCProvider) parse(rawIDToken string, keys jwk.Set) (jwt.Token, error) {
+	return jwt.Parse([]byte(rawIDToken),
+		jwt.WithKeySet(keys, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAcceptableSkew(maxTokenSkew),
+	)
+}
+
+// oauthConfig returns the OAuth 2.0 configuration with the endpoints of the issuer
+func (p *OIDCProvider) oauthConfig(ctx context.Context, redirectURI string) (*oauth2.Config, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  redirectURI,
+		Scopes:       p.cfg.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  doc.AuthorizationEndpoint,
+			TokenURL: doc.TokenEndpoint,
+		},
+	}, nil
+}
+
+// discover fetches the discovery document of the issuer once
+func (p *OIDCProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.discoverLocked(ctx)
+}
+
+func (p *OIDCProvider) discoverLocked(ctx context.Context) (*discoveryDocument, error) {
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch discovery document of %s: %w", p.cfg.Issuer, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch discovery document of %s: status %d", p.cfg.Issuer, resp.StatusCode)
+	}
+
+	var doc discoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode discovery document of %s: %w", p.cfg.Issuer, err)
+	}
+	// A document naming another issuer could make us trust tokens of that issuer
+	if strings.TrimSuffix(doc.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery document of %s is for issuer %q", p.cfg.Issuer, doc.Issuer)
+	}
+	if doc.JWKSURI == "" || doc.TokenEndpoint == "" || doc.AuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("discovery document of %s is incomplete", p.cfg.Issuer)
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+// keySet returns the signing keys of the issuer, refresh fetches them again unless they were fetched just now
+func (p *OIDCProvider) keySet(ctx context.Context, refresh bool) (jwk.Set, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil {
+		age := time.Since(p.keysFetchedAt)
+		if (!refresh && age < keysTTL) || age < minKeysRefresh {
+			return p.keys, nil
+		}
+	}
+
+	doc, err := p.discoverLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := jwk.Fetch(ctx, doc.JWKSURI, jwk.WithHTTPClient(p.client))
+	if err != nil {
+		return nil, fmt.Errorf("fetch signing keys of %s: %w", p.cfg.Issuer, err)
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return keys, nil
+}
+
+// stringClaim returns a string claim or an empty string
+func stringClaim(claims map[string]interface{}, key string) string {
+	value, _ := claims[key].(string)
+	return value
+}
+
+// boolClaim returns a boolean claim, Apple sends booleans as strings
+func boolClaim(claims map[string]interface{}, key string) bool {
+	switch value := claims[key].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
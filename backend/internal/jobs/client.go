@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const (
+	taskTimeout   = 2 * time.Minute
+	taskRetention = 24 * time.Hour // keep completed tasks visible in the admin API
+	uniqueTTL     = time.Hour      // a task for the same record is not enqueued twice while it waits
+)
+
+// RedisOpt returns asynq connection options for the Redis instance used by the rest of the backend
+func RedisOpt() asynq.RedisClientOpt {
+	opts := utils.Redis().Options()
+
+	return asynq.RedisClientOpt{
+		Addr:     opts.Addr,
+		Password: opts.Password,
+		DB:       opts.DB,
+	}
+}
+
+// Client enqueues background jobs
+type Client struct {
+	client   *asynq.Client
+	maxRetry int
+}
+
+// NewClient creates a new job client
+func NewClient(opt asynq.RedisConnOpt, maxRetry int) *Client {
+	return &Client{
+		client:   asynq.NewClient(opt),
+		maxRetry: maxRetry,
+	}
+}
+
+// Close closes the connection to Redis
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// EnqueueEnrichWord enqueues enrichment of a word. A word already waiting in the queue is not enqueued twice.
+func (c *Client) EnqueueEnrichWord(ctx context.Context, wordID uuid.UUID) error {
+	task, err := NewEnrichWordTask(wordID)
+	if err != nil {
+		return fmt.Errorf("failed to create enrich word task: %w", err)
+	}
+
+	return c.enqueue(ctx, task, QueueDefault)
+}
+
+// EnqueueEnrichSentence enqueues distractor generation for a sentence
+func (c *Client) EnqueueEnrichSentence(ctx context.Context, sentenceID uuid.UUID) error {
+	task, err := NewEnrichSentenceTask(sentenceID)
+	if err != nil {
+		return fmt.Errorf("failed to create enrich sentence task: %w", err)
+	}
+
+	return c.enqueue(ctx, task, QueueDefault)
+}
+
+// EnqueueBackfill enqueues enrichment of existing words and sentences with a low priority
+func (c *Client) EnqueueBackfill(ctx context.Context, wordIDs, sentenceIDs []uuid.UUID) (int, error) {
+	enqueued := 0
+	for _, id := range wordIDs {
+		task, err := NewEnrichWordTask(id)
+		if err != nil {
+			return enqueued, err
+		}
+		if err := c.enqueue(ctx, task, QueueLow); err != nil {
+			return enqueued, err
+		}
+		enqueued++
+	}
+
+	for _, id := range sentenceIDs {
+		task, err := NewEnrichSentenceTask(id)
+		if err != nil {
+			return enqueued, err
+		}
+		if err := c.enqueue(ctx, task, QueueLow); err != nil {
+			return enqueued, err
+		}
+		enqueued++
+	}
+
+	return enqueued, nil
+}
+
+// EnqueueAudioBackfill enqueues audio generation for existing words with a low priority
+func (c *Client) EnqueueAudioBackfill(ctx context.Context, wordIDs []uuid.UUID) (int, error) {
+	enqueued := 0
+	for _, id := range wordIDs {
+		task, err := NewGenerateAudioTask(id)
+		if err != nil {
+			return enqueued, err
+		}
+		if err := c.enqueue(ctx, task, QueueLow); err != nil {
+			return enqueued, err
+		}
+		enqueued++
+	}
+
+	return enqueued, nil
+}
+
+// enqueue enqueues a unique task, treating a task that is already waiting as success
+func (c *Client) enqueue(ctx context.Context, task *asynq.Task, queue string) error {
+	info, err := c.client.EnqueueContext(ctx, task,
+		asynq.Unique(uniqueTTL),
+		asynq.Queue(queue),
+		asynq.MaxRetry(c.maxRetry),
+		asynq.Timeout(taskTimeout),
+		asynq.Retention(taskRetention),
+	)
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		logger.Log.Debug("Job already enqueued", zap.String("type", task.Type()))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s: %w", task.Type(), err)
+	}
+
+	logger.Log.Debug("Job enqueued",
+		zap.String("task_id", info.ID),
+		zap.String("type", info.Type),
+		zap.String("queue", info.Queue))
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"errors"
+
+	"github.com/hibiken/asynq"
+)
+
+// ErrUnknownState is returned for a task state the inspector can't list
+var ErrUnknownState = errors.New("unknown task state")
+
+// Inspector inspects queues and tasks for the admin API
+type Inspector struct {
+	*asynq.Inspector
+}
+
+// NewInspector creates a new queue inspector
+func NewInspector(opt asynq.RedisConnOpt) *Inspector {
+	return &Inspector{Inspector: asynq.NewInspector(opt)}
+}
+
+// ListTasks returns a page of tasks of a queue in the given state.
+// The archived state holds tasks that ran out of retries (the dead-letter queue).
+func (i *Inspector) ListTasks(queue, state string, page, size int) ([]*asynq.TaskInfo, error) {
+	opts := []asynq.ListOption{asynq.Page(page), asynq.PageSize(size)}
+
+	switch state {
+	case "pending":
+		return i.ListPendingTasks(queue, opts...)
+	case "active":
+		return i.ListActiveTasks(queue, opts...)
+	case "scheduled":
+		return i.ListScheduledTasks(queue, opts...)
+	case "retry":
+		return i.ListRetryTasks(queue, opts...)
+	case "archived":
+		return i.ListArchivedTasks(queue, opts...)
+	case "completed":
+		return i.ListCompletedTasks(queue, opts...)
+	default:
+		return nil, ErrUnknownState
+	}
+}
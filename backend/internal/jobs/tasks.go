@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// Task types
+const (
+	TypeEnrichWord     = "enrich:word"
+	TypeEnrichSentence = "enrich:sentence"
+	TypeGenerateAudio  = "audio:word"
+)
+
+// Queues, processed with the given priority
+const (
+	QueueDefault = "default"
+	QueueLow     = "low"
+)
+
+// Queues lists queues with their priorities for the worker and the admin API
+var Queues = map[string]int{
+	QueueDefault: 3,
+	QueueLow:     1,
+}
+
+// EnrichWordPayload represents the enrich word task payload
+type EnrichWordPayload struct {
+	WordID uuid.UUID `json:"word_id"`
+}
+
+// EnrichSentencePayload represents the enrich sentence task payload
+type EnrichSentencePayload struct {
+	SentenceID uuid.UUID `json:"sentence_id"`
+}
+
+// GenerateAudioPayload represents the generate audio task payload
+type GenerateAudioPayload struct {
+	WordID uuid.UUID `json:"word_id"`
+}
+
+// NewEnrichWordTask creates a task that enriches a word and generates distractors for its sentences
+func NewEnrichWordTask(wordID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(EnrichWordPayload{WordID: wordID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeEnrichWord, payload), nil
+}
+
+// NewEnrichSentenceTask creates a task that generates distractors for a sentence
+func NewEnrichSentenceTask(sentenceID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(EnrichSentencePayload{SentenceID: sentenceID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeEnrichSentence, payload), nil
+}
+
+// NewGenerateAudioTask creates a task that stores audio of a word and its sentences, voicing those missing it
+func NewGenerateAudioTask(wordID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(GenerateAudioPayload{WordID: wordID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeGenerateAudio, payload), nil
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestEnrichTaskPayloads tests that task payloads round-trip
+func TestEnrichTaskPayloads(t *testing.T) {
+	id := uuid.New()
+
+	task, err := NewEnrichWordTask(id)
+	assert.NoError(t, err)
+	assert.Equal(t, TypeEnrichWord, task.Type())
+
+	var word EnrichWordPayload
+	assert.NoError(t, json.Unmarshal(task.Payload(), &word))
+	assert.Equal(t, id, word.WordID)
+
+	task, err = NewEnrichSentenceTask(id)
+	assert.NoError(t, err)
+	assert.Equal(t, TypeEnrichSentence, task.Type())
+
+	var sentence EnrichSentencePayload
+	assert.NoError(t, json.Unmarshal(task.Payload(), &sentence))
+	assert.Equal(t, id, sentence.SentenceID)
+
+	task, err = NewGenerateAudioTask(id)
+	assert.NoError(t, err)
+	assert.Equal(t, TypeGenerateAudio, task.Type())
+
+	var audio GenerateAudioPayload
+	assert.NoError(t, json.Unmarshal(task.Payload(), &audio))
+	assert.Equal(t, id, audio.WordID)
+}
+
+// TestListTasksUnknownState tests that unknown states are rejected before reaching Redis
+func TestListTasksUnknownState(t *testing.T) {
+	inspector := NewInspector(asynq.RedisClientOpt{Addr: "localhost:0"})
+	defer inspector.Close()
+
+	_, err := inspector.ListTasks(QueueDefault, "finished", 1, 10)
+	assert.ErrorIs(t, err, ErrUnknownState)
+}
+
+// TestSkipRetryIfNotFound tests that jobs for deleted records are not retried
+func TestSkipRetryIfNotFound(t *testing.T) {
+	assert.NoError(t, skipRetryIfNotFound(nil))
+	assert.ErrorIs(t, skipRetryIfNotFound(fmt.Errorf("failed to find word: %w", gorm.ErrRecordNotFound)), asynq.SkipRetry)
+}
@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"fluently/go-backend/internal/utils"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Worker processes background jobs
+type Worker struct {
+	server     *asynq.Server
+	mux        *asynq.ServeMux
+	enrichment *utils.WordEnrichmentService
+	audio      *utils.WordAudioService // nil when audio generation is disabled
+}
+
+// NewWorker creates a new job worker
+func NewWorker(opt asynq.RedisConnOpt, enrichment *utils.WordEnrichmentService, audio *utils.WordAudioService, concurrency int) *Worker {
+	w := &Worker{
+		mux:        asynq.NewServeMux(),
+		enrichment: enrichment,
+		audio:      audio,
+	}
+
+	w.server = asynq.NewServer(opt, asynq.Config{
+		Concurrency:  concurrency,
+		Queues:       Queues,
+		ErrorHandler: asynq.ErrorHandlerFunc(w.handleError),
+		Logger:       logger.Log.Sugar(),
+	})
+
+	w.mux.HandleFunc(TypeEnrichWord, w.handleEnrichWord)
+	w.mux.HandleFunc(TypeEnrichSentence, w.handleEnrichSentence)
+	w.mux.HandleFunc(TypeGenerateAudio, w.handleGenerateAudio)
+
+	return w
+}
+
+// Start starts processing jobs in the background
+func (w *Worker) Start() error {
+	return w.server.Start(w.mux)
+}
+
+// Shutdown waits for running jobs and stops the worker
+func (w *Worker) Shutdown() {
+	w.server.Shutdown()
+}
+
+// handleEnrichWord enriches a word with dictionary data and generates distractors for its sentences
+func (w *Worker) handleEnrichWord(ctx context.Context, t *asynq.Task) error {
+	var payload EnrichWordPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := w.enrichment.EnrichWordWithSentences(ctx, payload.WordID); err != nil {
+		return skipRetryIfNotFound(err)
+	}
+
+	// Dictionary audio is mirrored into storage and generated audio fills what the dictionary
+	// didn't have, failures are left to the audio backfill
+	if w.audio != nil {
+		if err := w.audio.FillWordAudio(ctx, payload.WordID); err != nil {
+			logger.Log.Warn("Failed to store word audio", zap.String("word_id", payload.WordID.String()), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// handleEnrichSentence generates distractors for a sentence
+func (w *Worker) handleEnrichSentence(ctx context.Context, t *asynq.Task) error {
+	var payload EnrichSentencePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return skipRetryIfNotFound(w.enrichment.EnrichSentenceInDatabase(ctx, payload.SentenceID.String()))
+}
+
+// handleGenerateAudio moves audio of a word and its sentences into storage, voicing those that have none
+func (w *Worker) handleGenerateAudio(ctx context.Context, t *asynq.Task) error {
+	if w.audio == nil {
+		return fmt.Errorf("media storage is not configured: %w", asynq.SkipRetry)
+	}
+
+	var payload GenerateAudioPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return skipRetryIfNotFound(w.audio.FillWordAudio(ctx, payload.WordID))
+}
+
+// handleError logs failed jobs, and jobs moved to the archive (dead-letter) queue as errors
+func (w *Worker) handleError(ctx context.Context, t *asynq.Task, err error) {
+	taskID, _ := asynq.GetTaskID(ctx)
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	fields := []zap.Field{
+		zap.String("task_id", taskID),
+		zap.String("type", t.Type()),
+		zap.Int("retried", retried),
+		zap.Int("max_retry", maxRetry),
+		zap.Error(err),
+	}
+
+	if retried >= maxRetry || errors.Is(err, asynq.SkipRetry) {
+		logger.Log.Error("Job moved to dead-letter queue", fields...)
+		return
+	}
+
+	logger.Log.Warn("Job failed, will be retried", fields...)
+}
+
+// skipRetryIfNotFound stops retrying jobs for records that were deleted in the meantime
+func skipRetryIfNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	return err
+}
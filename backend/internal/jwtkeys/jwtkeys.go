@@ -0,0 +1,400 @@
+// Package jwtkeys signs access tokens with asymmetric keys and publishes their
+// public halves as a JWKS, so other services verify tokens without holding a secret.
+//
+// Keys are PEM private keys (RSA, Ed25519 or P-256) in one directory, the file name
+// without ".pem" is the key ID put in the "kid" header. A new key is published in the JWKS
+// for a while before it starts signing, so verifiers with a cached set already know it; the
+// newest published key signs, older ones only verify until the tokens they signed have expired.
+// Instances sharing the directory pick up keys created by others on reload, or when a token
+// carries an unknown kid.
+package jwtkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+const (
+	// maxClockSkew tolerates tokens issued slightly ahead of our clock
+	maxClockSkew = time.Minute
+	// minReload stops tokens with unknown key IDs from making us read the directory on every request
+	minReload = 10 * time.Second
+	// kidLayout names generated keys after their creation time
+	kidLayout = "20060102T150405Z"
+)
+
+var (
+	ErrNoKeys     = errors.New("no signing keys")
+	ErrUnknownKey = errors.New("token signed with an unknown key")
+)
+
+// keyIDPattern keeps key IDs safe as file names and headers
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Config is the configuration of the key set
+type Config struct {
+	Dir       string
+	Algorithm jwa.SignatureAlgorithm // of generated keys, RS256 or EdDSA
+	TokenTTL  time.Duration          // lifetime of signed tokens, retired keys are kept that long
+	// PrePublish is how long a new key is only published before it signs,
+	// at least as long as verifiers cache the JWKS. Zero signs with a new key right away.
+	PrePublish time.Duration
+}
+
+// key is a signing key with its public half
+type key struct {
+	id        string
+	private   jwk.Key
+	public    jwk.Key
+	createdAt time.Time
+}
+
+// Manager holds the keys of the directory
+type Manager struct {
+	cfg Config
+
+	mu       sync.RWMutex
+	keys     []key // oldest first, the newest one past PrePublish signs
+	public   jwk.Set
+	loadedAt time.Time
+}
+
+// New loads the keys of the directory and generates the first one when it's empty
+func New(cfg Config) (*Manager, error) {
+	if cfg.Algorithm != jwa.RS256 && cfg.Algorithm != jwa.EdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q, use RS256 or EdDSA", cfg.Algorithm)
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create key directory: %w", err)
+	}
+
+	m := &Manager{cfg: cfg}
+	if err := m.Load(); err != nil {
+		return nil, err
+	}
+	if len(m.Keys()) == 0 {
+		if _, err := m.generate(time.Now()); err != nil {
+			return nil, err
+		}
+		if err := m.Load(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Load reads the keys of the directory again
+func (m *Manager) Load() error {
+	paths, err := filepath.Glob(filepath.Join(m.cfg.Dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keys := make([]key, 0, len(paths))
+	for _, path := range paths {
+		k, err := readKey(path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, *k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].createdAt.Equal(keys[j].createdAt) {
+			return keys[i].id < keys[j].id
+		}
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+
+	public := jwk.NewSet()
+	for _, k := range keys {
+		if err := public.AddKey(k.public); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.public = public
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Keys returns the IDs of the loaded keys, oldest first
+func (m *Manager) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.keys))
+	for _, k := range m.keys {
+		ids = append(ids, k.id)
+	}
+	return ids
+}
+
+// PublicSet returns the public keys for the JWKS endpoint
+func (m *Manager) PublicSet() jwk.Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.public
+}
+
+// Sign signs the claims with the newest key that has been published for PrePublish
+func (m *Manager) Sign(claims map[string]interface{}) (string, error) {
+	signer, err := m.signer(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.New()
+	for name, value := range claims {
+		if err := token.Set(name, value); err != nil {
+			return "", err
+		}
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(signer.private.Algorithm(), signer.private))
+	if err != nil {
+		return "", err
+	}
+	return string(signed), nil
+}
+
+// Verify checks the signature and lifetime of a token signed by one of the keys
+func (m *Manager) Verify(tokenString string) (jwt.Token, error) {
+	kid, err := KeyID(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if kid == "" {
+		return nil, ErrUnknownKey
+	}
+
+	if !m.hasKey(kid) {
+		// Another instance may have rotated since we last read the directory
+		m.mu.RLock()
+		stale := time.Since(m.loadedAt) > minReload
+		m.mu.RUnlock()
+		if stale {
+			if err := m.Load(); err != nil {
+				return nil, err
+			}
+		}
+		if !m.hasKey(kid) {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	return jwt.Parse([]byte(tokenString),
+		jwt.WithKeySet(m.PublicSet()),
+		jwt.WithValidate(true),
+		jwt.WithAcceptableSkew(maxClockSkew),
+	)
+}
+
+// Rotate generates a new key once the newest one is older than every, it signs PrePublish
+// later, and deletes retired keys once the tokens they signed have expired.
+// It returns whether a key was generated.
+func (m *Manager) Rotate(now time.Time, every time.Duration) (bool, error) {
+	if err := m.Load(); err != nil {
+		return false, err
+	}
+
+	m.mu.RLock()
+	keys := append([]key(nil), m.keys...)
+	m.mu.RUnlock()
+
+	rotated := false
+	if len(keys) == 0 || now.Sub(keys[len(keys)-1].createdAt) >= every {
+		k, err := m.generate(now)
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, *k)
+		rotated = true
+	}
+
+	// A key stopped signing when the next one did, its tokens expire a TokenTTL later
+	for i := 0; i < len(keys)-1; i++ {
+		if now.Sub(m.signsFrom(keys[i+1])) > m.cfg.TokenTTL+maxClockSkew {
+			if err := os.Remove(filepath.Join(m.cfg.Dir, keys[i].id+".pem")); err != nil && !os.IsNotExist(err) {
+				return rotated, err
+			}
+		}
+	}
+
+	return rotated, m.Load()
+}
+
+// signer returns the key signing at now: the newest one published for PrePublish.
+// Before any is, e.g. right after the first key was generated, the oldest key signs.
+func (m *Manager) signer(now time.Time) (key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.keys) == 0 {
+		return key{}, ErrNoKeys
+	}
+	if m.cfg.PrePublish <= 0 {
+		return m.keys[len(m.keys)-1], nil
+	}
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if !now.Before(m.signsFrom(m.keys[i])) {
+			return m.keys[i], nil
+		}
+	}
+	return m.keys[0], nil
+}
+
+// signsFrom is when a key starts signing
+func (m *Manager) signsFrom(k key) time.Time {
+	return k.createdAt.Add(max(m.cfg.PrePublish, 0))
+}
+
+// hasKey reports whether a key with the ID is loaded
+func (m *Manager) hasKey(kid string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, k := range m.keys {
+		if k.id == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// generate writes a new key named after its creation time
+func (m *Manager) generate(now time.Time) (*key, error) {
+	var raw interface{}
+	var err error
+	switch m.cfg.Algorithm {
+	case jwa.EdDSA:
+		_, raw, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		raw, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	id := now.UTC().Format(kidLayout)
+	path := filepath.Join(m.cfg.Dir, id+".pem")
+	// O_EXCL makes an instance rotating at the same moment fail instead of overwriting the key
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("write signing key: %w", err)
+	}
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("write signing key: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chtimes(path, now, now); err != nil {
+		return nil, err
+	}
+
+	return readKey(path)
+}
+
+// readKey reads a PEM private key, the algorithm follows from its type
+func readKey(path string) (*key, error) {
+	id := strings.TrimSuffix(filepath.Base(path), ".pem")
+	if !keyIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("signing key %s: name must be letters, digits, dots, dashes and underscores", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	private, err := jwk.ParseKey(data, jwk.WithPEM(true))
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", path, err)
+	}
+
+	var raw interface{}
+	if err := private.Raw(&raw); err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", path, err)
+	}
+	var alg jwa.SignatureAlgorithm
+	switch raw := raw.(type) {
+	case *rsa.PrivateKey:
+		if raw.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("signing key %s: RSA keys must be at least 2048 bits", path)
+		}
+		alg = jwa.RS256
+	case ed25519.PrivateKey:
+		alg = jwa.EdDSA
+	case *ecdsa.PrivateKey:
+		if raw.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("signing key %s: only P-256 EC keys are supported", path)
+		}
+		alg = jwa.ES256
+	default:
+		return nil, fmt.Errorf("signing key %s: not a private key", path)
+	}
+
+	for _, field := range []struct {
+		name  string
+		value interface{}
+	}{
+		{jwk.KeyIDKey, id},
+		{jwk.AlgorithmKey, alg},
+		{jwk.KeyUsageKey, jwk.ForSignature},
+	} {
+		if err := private.Set(field.name, field.value); err != nil {
+			return nil, err
+		}
+	}
+
+	public, err := private.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &key{id: id, private: private, public: public, createdAt: info.ModTime()}, nil
+}
+
+// KeyID returns the kid header of a token, empty for tokens without one
+func KeyID(tokenString string) (string, error) {
+	msg, err := jws.Parse([]byte(tokenString))
+	if err != nil {
+		return "", err
+	}
+	if len(msg.Signatures()) != 1 {
+		return "", errors.New("token must have one signature")
+	}
+	return msg.Signatures()[0].ProtectedHeaders().KeyID(), nil
+}
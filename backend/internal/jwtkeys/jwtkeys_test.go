@@ -0,0 +1,177 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClaims(exp time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":  "7d8c7f4e-0000-4000-8000-000000000001",
+		"role": "user",
+		"iat":  time.Now().Unix(),
+		"exp":  exp.Unix(),
+	}
+}
+
+// TestSignVerify tests signing with the newest key and verifying tokens of every loaded key
+func TestSignVerify(t *testing.T) {
+	for _, alg := range []jwa.SignatureAlgorithm{jwa.RS256, jwa.EdDSA} {
+		t.Run(alg.String(), func(t *testing.T) {
+			m, err := New(Config{Dir: t.TempDir(), Algorithm: alg, TokenTTL: time.Hour})
+			require.NoError(t, err)
+			require.Len(t, m.Keys(), 1)
+
+			token, err := m.Sign(testClaims(time.Now().Add(time.Hour)))
+			require.NoError(t, err)
+
+			kid, err := KeyID(token)
+			require.NoError(t, err)
+			assert.Equal(t, m.Keys()[0], kid)
+
+			parsed, err := m.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "7d8c7f4e-0000-4000-8000-000000000001", parsed.Subject())
+
+			expired, err := m.Sign(testClaims(time.Now().Add(-time.Hour)))
+			require.NoError(t, err)
+			_, err = m.Verify(expired)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestRotate tests that rotated keys keep verifying until their tokens expire
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(Config{Dir: dir, Algorithm: jwa.EdDSA, TokenTTL: time.Hour})
+	require.NoError(t, err)
+	first := m.Keys()[0]
+
+	old, err := m.Sign(testClaims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	rotated, err := m.Rotate(time.Now(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.False(t, rotated, "the key is fresh")
+
+	rotated, err = m.Rotate(time.Now().Add(25*time.Hour), 24*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, rotated)
+	require.Len(t, m.Keys(), 2)
+
+	token, err := m.Sign(testClaims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	kid, _ := KeyID(token)
+	assert.NotEqual(t, first, kid, "the new key signs")
+
+	_, err = m.Verify(old)
+	assert.NoError(t, err, "the retired key still verifies")
+
+	// Once tokens of the first key have expired it is deleted
+	_, err = m.Rotate(time.Now().Add(27*time.Hour), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{kid}, m.Keys())
+	_, err = os.Stat(filepath.Join(dir, first+".pem"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestPrePublish tests that a new key is only published until verifiers have had time to fetch it
+func TestPrePublish(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(Config{Dir: dir, Algorithm: jwa.EdDSA, TokenTTL: time.Hour, PrePublish: time.Hour})
+	require.NoError(t, err)
+	first := m.Keys()[0]
+
+	now := time.Now()
+	signer, err := m.signer(now)
+	require.NoError(t, err)
+	assert.Equal(t, first, signer.id, "the only key signs right away")
+
+	created := now.Add(time.Minute)
+	rotated, err := m.Rotate(created, time.Minute)
+	require.NoError(t, err)
+	require.True(t, rotated)
+	require.Len(t, m.Keys(), 2)
+	next := m.Keys()[1]
+	assert.Equal(t, 2, m.PublicSet().Len(), "the new key is published")
+
+	signer, err = m.signer(created.Add(59 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first, signer.id, "the new key doesn't sign before the pre-publish window passed")
+
+	signer, err = m.signer(created.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, next, signer.id)
+
+	// The first key is kept for a TokenTTL after the new key started signing, not after it was created
+	_, err = m.Rotate(created.Add(time.Hour+30*time.Minute), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, next}, m.Keys())
+
+	_, err = m.Rotate(created.Add(2*time.Hour+2*time.Minute), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{next}, m.Keys())
+}
+
+// TestVerifyReloads tests that a key created by another instance is picked up
+func TestVerifyReloads(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(Config{Dir: dir, Algorithm: jwa.EdDSA, TokenTTL: time.Hour})
+	require.NoError(t, err)
+	b, err := New(Config{Dir: dir, Algorithm: jwa.EdDSA, TokenTTL: time.Hour})
+	require.NoError(t, err)
+
+	_, err = a.Rotate(time.Now().Add(time.Hour), time.Minute)
+	require.NoError(t, err)
+	token, err := a.Sign(testClaims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	b.loadedAt = time.Time{}
+	_, err = b.Verify(token)
+	assert.NoError(t, err)
+
+	// A key of the same type the set doesn't hold is rejected
+	_, raw, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(raw)
+	otherDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(otherDir, "other.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	other, err := New(Config{Dir: otherDir, Algorithm: jwa.EdDSA, TokenTTL: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, other.Keys())
+
+	forged, err := other.Sign(testClaims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	_, err = b.Verify(forged)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+// TestPublicSet tests that the JWKS holds no private parts
+func TestPublicSet(t *testing.T) {
+	m, err := New(Config{Dir: t.TempDir(), Algorithm: jwa.RS256, TokenTTL: time.Hour})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(m.PublicSet())
+	require.NoError(t, err)
+
+	var set struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(data, &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, m.Keys()[0], set.Keys[0]["kid"])
+	assert.Equal(t, "RS256", set.Keys[0]["alg"])
+	assert.Equal(t, "sig", set.Keys[0]["use"])
+	assert.NotContains(t, set.Keys[0], "d")
+}
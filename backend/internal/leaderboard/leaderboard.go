@@ -0,0 +1,177 @@
+// Package leaderboard keeps weekly XP leaderboards in Redis sorted sets.
+// Every ISO week (Monday to Sunday, UTC) has its own sets, so boards roll over
+// on their own and old weeks expire.
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// retention is how long the sets of a week are kept after it starts, so last week stays readable
+const retention = 3 * 7 * 24 * time.Hour
+
+// Entry is a user's place on a board
+type Entry struct {
+	UserID uuid.UUID
+	XP     int
+	Rank   int // 1-based
+}
+
+// Board reads and writes the weekly leaderboards
+type Board struct {
+	rdb *goredis.Client
+}
+
+// New creates a board on a Redis client
+func New(rdb *goredis.Client) *Board {
+	return &Board{rdb: rdb}
+}
+
+// Week returns the ISO week of t, like 2025-W05
+func Week(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// WeekEnd returns when the week of t ends and the boards roll over
+func WeekEnd(t time.Time) time.Time {
+	t = t.UTC()
+	sinceMonday := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.Date()
+	return time.Date(y, m, d+7-sinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// allKey is the set of every user's weekly XP, friend boards read it
+func allKey(week string) string {
+	return "leaderboard:" + week + ":all"
+}
+
+// globalKey is the set of users shown on the global board, without those who opted out
+func globalKey(week string) string {
+	return "leaderboard:" + week + ":global"
+}
+
+// AddXP adds XP gained at now to the user's weekly score. Users who opted out
+// of the global board only get the score their friends see.
+func (b *Board) AddXP(ctx context.Context, userID uuid.UUID, xp int, public bool, now time.Time) error {
+	week := Week(now)
+	member := userID.String()
+
+	pipe := b.rdb.TxPipeline()
+	pipe.ZIncrBy(ctx, allKey(week), float64(xp), member)
+	pipe.Expire(ctx, allKey(week), retention)
+	if public {
+		pipe.ZIncrBy(ctx, globalKey(week), float64(xp), member)
+		pipe.Expire(ctx, globalKey(week), retention)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// SetPublic shows or hides the user on this week's global board
+func (b *Board) SetPublic(ctx context.Context, userID uuid.UUID, public bool, now time.Time) error {
+	week := Week(now)
+	member := userID.String()
+
+	if !public {
+		return b.rdb.ZRem(ctx, globalKey(week), member).Err()
+	}
+
+	score, err := b.rdb.ZScore(ctx, allKey(week), member).Result()
+	if err == goredis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pipe := b.rdb.TxPipeline()
+	pipe.ZAdd(ctx, globalKey(week), goredis.Z{Score: score, Member: member})
+	pipe.Expire(ctx, globalKey(week), retention)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Top returns the best users of a week's global board
+func (b *Board) Top(ctx context.Context, week string, limit int) ([]Entry, error) {
+	scores, err := b.rdb.ZRevRangeWithScores(ctx, globalKey(week), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(scores))
+	for i, z := range scores {
+		member, _ := z.Member.(string)
+		userID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{UserID: userID, XP: int(z.Score), Rank: i + 1})
+	}
+	return entries, nil
+}
+
+// Rank returns the user's place on a week's global board, nil if the user isn't on it
+func (b *Board) Rank(ctx context.Context, week string, userID uuid.UUID) (*Entry, error) {
+	member := userID.String()
+
+	rank, err := b.rdb.ZRevRank(ctx, globalKey(week), member).Result()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := b.rdb.ZScore(ctx, globalKey(week), member).Result()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{UserID: userID, XP: int(score), Rank: int(rank) + 1}, nil
+}
+
+// Among ranks the users by their weekly XP, users without XP this week rank last with 0
+func (b *Board) Among(ctx context.Context, week string, userIDs []uuid.UUID) ([]Entry, error) {
+	if len(userIDs) == 0 {
+		return []Entry{}, nil
+	}
+
+	members := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = id.String()
+	}
+
+	scores, err := b.rdb.ZMScore(ctx, allKey(week), members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(userIDs))
+	for i, id := range userIDs {
+		entries[i] = Entry{UserID: id, XP: int(scores[i])}
+	}
+	return rank(entries), nil
+}
+
+// rank sorts entries by XP and numbers them, users with equal XP share a place
+func rank(entries []Entry) []Entry {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].XP > entries[j].XP })
+	for i := range entries {
+		if i > 0 && entries[i].XP == entries[i-1].XP {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries
+}
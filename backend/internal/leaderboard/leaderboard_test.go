@@ -0,0 +1,37 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWeek tests ISO week names and rollover times
+func TestWeek(t *testing.T) {
+	sunday := time.Date(2025, 2, 2, 23, 59, 0, 0, time.UTC)
+	monday := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2025-W05", Week(sunday))
+	assert.Equal(t, "2025-W06", Week(monday))
+	assert.Equal(t, "2025-W01", Week(time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, monday, WeekEnd(sunday))
+	assert.Equal(t, monday.AddDate(0, 0, 7), WeekEnd(monday))
+	assert.Equal(t, monday, WeekEnd(time.Date(2025, 1, 29, 8, 0, 0, 0, time.UTC)))
+}
+
+// TestRank tests ordering and shared places
+func TestRank(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	entries := rank([]Entry{{UserID: a, XP: 10}, {UserID: b, XP: 50}, {UserID: c, XP: 10}, {UserID: d}})
+
+	assert.Equal(t, []Entry{
+		{UserID: b, XP: 50, Rank: 1},
+		{UserID: a, XP: 10, Rank: 2},
+		{UserID: c, XP: 10, Rank: 2},
+		{UserID: d, XP: 0, Rank: 4},
+	}, entries)
+}
@@ -70,6 +70,31 @@ func CustomAuthenticator(next http.Handler) http.Handler {
 	})
 }
 
+// RequireRole allows only users with one of the given roles, it must run after CustomAuthenticator
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r.Context())
+			if user == nil {
+				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			logger.Log.Warn("Forbidden: insufficient role",
+				zap.String("user_id", user.ID.String()),
+				zap.String("role", user.Role))
+			writeJSONError(w, "forbidden", http.StatusForbidden)
+		})
+	}
+}
+
 // GetUserFromContext retrieves the user from the context
 func GetUserFromContext(ctx context.Context) *models.User {
 	if user, ok := ctx.Value(UserContextKey).(*models.User); ok {
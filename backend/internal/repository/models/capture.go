@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Capture is a model for words captured by the user while reading, e.g. with the browser extension
+type Capture struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	WordID      uuid.UUID  `gorm:"type:uuid;not null"`
+	SentenceID  *uuid.UUID `gorm:"type:uuid"`
+	Selection   string     `gorm:"type:varchar(100);not null"` // text selected by the user
+	SourceURL   string     `gorm:"type:text"`
+	SourceTitle string     `gorm:"type:varchar(255)"`
+	Queued      bool       `gorm:"default:true"` // waiting to be added to the next lesson
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+
+	User     User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`      // user who captured the word
+	Word     Word      `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`      // captured word
+	Sentence *Sentence `gorm:"foreignKey:SentenceID;constraint:OnDelete:SET NULL"` // sentence the word was met in
+}
+
+// TableName returns the table name for Capture
+func (Capture) TableName() string {
+	return "captures"
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Classroom is a model for a teacher's class, students join it by code
+type Classroom struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TeacherID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	JoinCode  string    `gorm:"type:varchar(16);not null;unique"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	Teacher User `gorm:"foreignKey:TeacherID;constraint:OnDelete:CASCADE"` // user who teaches the class
+}
+
+// TableName returns the table name for Classroom
+func (Classroom) TableName() string {
+	return "classrooms"
+}
+
+// ClassroomMember is a model for students of a class
+type ClassroomMember struct {
+	ClassroomID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	JoinedAt    time.Time `gorm:"autoCreateTime"`
+
+	Classroom Classroom `gorm:"foreignKey:ClassroomID;constraint:OnDelete:CASCADE"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // student
+}
+
+// TableName returns the table name for ClassroomMember
+func (ClassroomMember) TableName() string {
+	return "classroom_members"
+}
+
+// Assignment is a model for words a teacher assigned to a class, a topic is stored as the list of its words
+type Assignment struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ClassroomID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Title       string     `gorm:"type:varchar(100);not null"`
+	TopicID     *uuid.UUID `gorm:"type:uuid"` // topic the words were taken from, nil for word lists
+	DueAt       time.Time  `gorm:"not null"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+
+	Classroom Classroom        `gorm:"foreignKey:ClassroomID;constraint:OnDelete:CASCADE"`
+	Topic     *Topic           `gorm:"foreignKey:TopicID;constraint:OnDelete:SET NULL"`
+	Words     []AssignmentWord `gorm:"foreignKey:AssignmentID;constraint:OnDelete:CASCADE"` // assignment has many words
+}
+
+// TableName returns the table name for Assignment
+func (Assignment) TableName() string {
+	return "assignments"
+}
+
+// AssignmentWord is a model for words of an assignment
+type AssignmentWord struct {
+	AssignmentID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WordID       uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+
+	Word Word `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for AssignmentWord
+func (AssignmentWord) TableName() string {
+	return "assignment_words"
+}
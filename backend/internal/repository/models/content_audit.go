@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Content audit actions
+const (
+	ContentActionWordUpdate    = "word.update"
+	ContentActionWordMerge     = "word.merge"
+	ContentActionTopicReparent = "topic.reparent"
+)
+
+// ContentAudit is a model for the audit log of changes admins made to shared content
+type ContentAudit struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ActorID    *uuid.UUID     `gorm:"type:uuid;index"`                 // admin who made the change, nil for the CLI
+	Actor      string         `gorm:"type:varchar(100);not null"`      // admin email or "cli:<os user>"
+	Action     string         `gorm:"type:varchar(50);not null;index"` // one of the ContentAction constants
+	EntityType string         `gorm:"type:varchar(20);not null"`       // word or topic
+	EntityID   uuid.UUID      `gorm:"type:uuid;not null;index"`
+	Changes    datatypes.JSON `gorm:"type:jsonb;not null"` // changed fields as {"field": [old, new]}, merged words for merges
+	CreatedAt  time.Time      `gorm:"autoCreateTime;index"`
+
+	User *User `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL"`
+}
+
+// TableName returns the table name for ContentAudit
+func (ContentAudit) TableName() string {
+	return "content_audits"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deck is a model for named user decks grouping global and personal words
+type Deck struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	Description string    `gorm:"type:text"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	User  User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // user who owns the deck
+	Words []DeckWord `gorm:"foreignKey:DeckID;constraint:OnDelete:CASCADE"` // deck has many words
+}
+
+// TableName returns the table name for Deck
+func (Deck) TableName() string {
+	return "decks"
+}
+
+// DeckWord is a model for words in a deck
+type DeckWord struct {
+	DeckID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WordID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AddedAt time.Time `gorm:"autoCreateTime"`
+
+	Word Word `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"` // word in the deck
+}
+
+// TableName returns the table name for DeckWord
+func (DeckWord) TableName() string {
+	return "deck_words"
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Friendship is a model for a friend connection, stored once in each direction
+type Friendship struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	FriendID  uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User   User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Friend User `gorm:"foreignKey:FriendID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for Friendship
+func (Friendship) TableName() string {
+	return "friendships"
+}
+
+// FriendInvite is a model for friend invite links, anyone with the token can accept until it expires
+type FriendInvite struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Token     string    `gorm:"type:varchar(64);not null;unique"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for FriendInvite
+func (FriendInvite) TableName() string {
+	return "friend_invites"
+}
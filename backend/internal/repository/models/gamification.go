@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStats is a model for a user's XP and daily streak
+type UserStats struct {
+	UserID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	XP             int        `gorm:"not null;default:0"`
+	CurrentStreak  int        `gorm:"not null;default:0"`
+	LongestStreak  int        `gorm:"not null;default:0"`
+	StreakFreezes  int        `gorm:"not null;default:0"` // freezes left, each covers a missed day
+	LastActiveDate *time.Time `gorm:"type:date"`          // last day with activity in the user's time zone
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for UserStats
+func (UserStats) TableName() string {
+	return "user_stats"
+}
+
+// XPEvent is a model for XP awarded for an activity
+type XPEvent struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_xp_events_user_key"`
+	IdempotencyKey *string   `gorm:"type:varchar(120);uniqueIndex:idx_xp_events_user_key"` // nil for activities without a key, prefixed by source
+	Type           string    `gorm:"type:varchar(20);not null"`
+	Amount         int       `gorm:"not null"`
+	Day            time.Time `gorm:"type:date;not null"` // day of the activity in the user's time zone
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for XPEvent
+func (XPEvent) TableName() string {
+	return "xp_events"
+}
+
+// UserAchievement is a model for an achievement unlocked by a user.
+// Achievements themselves are defined in the gamification package.
+type UserAchievement struct {
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AchievementID string    `gorm:"type:varchar(50);primaryKey"`
+	UnlockedAt    time.Time `gorm:"not null"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for UserAchievement
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
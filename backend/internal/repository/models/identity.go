@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity is a model for an external account a user signs in with, a user can link one per provider
+type UserIdentity struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_identities_user_provider"`
+	Provider   string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_user_provider;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"` // stable ID of the account at the provider
+	Email      string    `gorm:"type:varchar(100)"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	LastUsedAt time.Time
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for UserIdentity
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
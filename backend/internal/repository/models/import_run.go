@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Import run statuses
+const (
+	ImportRunApplied    = "applied"
+	ImportRunRolledBack = "rolled_back"
+)
+
+// Import item actions
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// Import item entity types
+const (
+	ImportEntityTopic      = "topic"
+	ImportEntityWord       = "word"
+	ImportEntitySentence   = "sentence"
+	ImportEntityPickOption = "pick_option"
+)
+
+// ImportRun is a model for a content import, kept to roll the import back
+type ImportRun struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Source       string     `gorm:"type:varchar(255);not null"` // imported file
+	Actor        string     `gorm:"type:varchar(100);not null"` // "cli:<os user>"
+	Status       string     `gorm:"type:varchar(20);not null"`  // one of the ImportRun constants
+	Created      int        `gorm:"not null;default:0"`         // words created
+	Updated      int        `gorm:"not null;default:0"`         // words updated
+	Skipped      int        `gorm:"not null;default:0"`         // rows without changes
+	Rejected     int        `gorm:"not null;default:0"`         // rows with errors
+	CreatedAt    time.Time  `gorm:"autoCreateTime;index"`
+	RolledBackAt *time.Time `gorm:"default:null"`
+
+	Items []ImportRunItem `gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for ImportRun
+func (ImportRun) TableName() string {
+	return "import_runs"
+}
+
+// ImportRunItem is a model for a single change made by an import run
+type ImportRunItem struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RunID      uuid.UUID      `gorm:"type:uuid;not null;index"`
+	Position   int            `gorm:"not null"`                  // order of the change in the run, rollback goes backwards
+	Line       int            `gorm:"not null"`                  // line or record of the imported file
+	EntityType string         `gorm:"type:varchar(20);not null"` // one of the ImportEntity constants
+	EntityID   uuid.UUID      `gorm:"type:uuid;not null"`
+	Action     string         `gorm:"type:varchar(20);not null"` // one of the ImportAction constants
+	Before     datatypes.JSON `gorm:"type:jsonb"`                // previous values of updated fields
+}
+
+// TableName returns the table name for ImportRunItem
+func (ImportRunItem) TableName() string {
+	return "import_run_items"
+}
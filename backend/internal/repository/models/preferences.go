@@ -8,16 +8,18 @@ import (
 
 // Preference is a model for user preferences
 type Preference struct {
-	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	UserID          uuid.UUID  `gorm:"type:uuid;not null"`
-	CEFRLevel       string     `gorm:"type:varchar(2);not null"`
-	FactEveryday    bool       `gorm:"default:false"`
-	Notifications   bool       `gorm:"default:false"`
-	NotificationsAt *time.Time `gorm:"type:timestamp;default:null"`
-	WordsPerDay     int        `gorm:"default:10"`
-	Goal            string     `gorm:"type:varchar(255)"`
-	Subscribed      bool       `gorm:"default:false"`
-	AvatarImageURL  string     `gorm:"type:text"`
+	ID                uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null"`
+	CEFRLevel         string     `gorm:"type:varchar(2);not null"`
+	FactEveryday      bool       `gorm:"default:false"`
+	Notifications     bool       `gorm:"default:false"`
+	NotificationsAt   *time.Time `gorm:"type:timestamp;default:null"`
+	WordsPerDay       int        `gorm:"default:10"`
+	Goal              string     `gorm:"type:varchar(255)"`
+	Subscribed        bool       `gorm:"default:false"`
+	AvatarImageURL    string     `gorm:"type:text"`
+	TimeZone          string     `gorm:"type:varchar(64)"` // IANA name, days of streaks are counted in it
+	LeaderboardOptOut bool       `gorm:"default:false"`    // hidden from the global leaderboard, friends still see the user
 }
 
 // TableName returns the table name for Preference
@@ -12,7 +12,11 @@ type Sentence struct {
 	Translation string    `gorm:"type:text"`
 	AudioURL    string    `gorm:"type:text"`
 
-	Word Word `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"` // sentence belongs to a word
+	UserID    *uuid.UUID `gorm:"type:uuid;index"` // user who captured the sentence, nil for shared sentences
+	SourceURL string     `gorm:"type:text"`       // page the sentence was captured from
+
+	Word Word  `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"` // sentence belongs to a word
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // user who captured the sentence
 }
 
 // TableName returns the table name for Sentence
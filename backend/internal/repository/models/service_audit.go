@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceTokenAudit is a model for the audit log of tokens our services minted on behalf of users
+type ServiceTokenAudit struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ServiceID  string    `gorm:"type:varchar(64);not null;index"` // ID of the service key that signed the request
+	Action     string    `gorm:"type:varchar(50);not null"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	TelegramID int64
+	RemoteAddr string    `gorm:"type:varchar(64)"`
+	RequestID  string    `gorm:"type:varchar(64)"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for ServiceTokenAudit
+func (ServiceTokenAudit) TableName() string {
+	return "service_token_audits"
+}
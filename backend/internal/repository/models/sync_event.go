@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sync event types
+const (
+	SyncEventLearned    = "learned"     // word was learned or reviewed
+	SyncEventNotLearned = "not_learned" // word was answered badly and goes back to the not learned list
+	SyncEventReviewed   = "reviewed"    // word was practiced, updates the progress of a learned word only
+	SyncEventChanged    = "changed"     // progress was written outside sync, through /progress or the learned words API
+)
+
+// Sync event statuses
+const (
+	SyncStatusApplied    = "applied"    // event changed the server state
+	SyncStatusSuperseded = "superseded" // a newer event for the same word already won
+	SyncStatusDuplicate  = "duplicate"  // event with the same idempotency key was already received
+	SyncStatusRejected   = "rejected"   // event is invalid and was not stored
+	SyncStatusSkipped    = "skipped"    // review of a word that isn't learned, nothing to update
+)
+
+// SyncEvent is a model for progress events pushed by offline clients.
+// Seq is a server-side cursor for the change feed.
+type SyncEvent struct {
+	Seq            int64     `gorm:"primaryKey;autoIncrement"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_sync_events_user_key;index:idx_sync_events_user_word"`
+	IdempotencyKey string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_sync_events_user_key"`
+	DeviceID       string    `gorm:"type:varchar(100)"`
+	Type           string    `gorm:"type:varchar(20);not null"`
+	WordID         uuid.UUID `gorm:"type:uuid;not null;index:idx_sync_events_user_word"`
+	ClientTime     time.Time `gorm:"not null"` // time the event happened on the client
+
+	ConfidenceScore *int
+	CntReviewed     *int
+
+	Applied   bool      `gorm:"not null"` // false when the event lost a conflict
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Word Word `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the table name for SyncEvent
+func (SyncEvent) TableName() string {
+	return "sync_events"
+}
+
+// Wins reports whether the event beats another event for the same word:
+// the later client time wins, equal times are broken by the idempotency key
+func (e *SyncEvent) Wins(other *SyncEvent) bool {
+	if !e.ClientTime.Equal(other.ClientTime) {
+		return e.ClientTime.After(other.ClientTime)
+	}
+
+	return e.IdempotencyKey > other.IdempotencyKey
+}
@@ -15,6 +15,9 @@ type Word struct {
 	AudioURL     string    `gorm:"type:text"`
 	Phonetic     string    `gorm:"type:varchar(100)"` // phonetic transcription
 
+	OwnerID *uuid.UUID `gorm:"type:uuid;index"`                                // owner of a personal word, nil for global words
+	Owner   *User      `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"` // user who created the personal word
+
 	TopicID *uuid.UUID `gorm:"type:uuid"`                                                        // foreign key to Topic
 	Topic   *Topic     `gorm:"foreignKey:TopicID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"` // Topic has many words
 
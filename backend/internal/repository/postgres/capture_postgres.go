@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CaptureRepository is a repository for captured words
+type CaptureRepository struct {
+	db *gorm.DB
+}
+
+// NewCaptureRepository creates a new instance of CaptureRepository
+func NewCaptureRepository(db *gorm.DB) *CaptureRepository {
+	return &CaptureRepository{db: db}
+}
+
+// Create creates a new capture
+func (r *CaptureRepository) Create(ctx context.Context, capture *models.Capture) error {
+	return r.db.WithContext(ctx).Create(capture).Error
+}
+
+// GetByID returns a capture by id with its word and sentence
+func (r *CaptureRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Capture, error) {
+	var capture models.Capture
+	err := r.db.WithContext(ctx).
+		Preload("Word").
+		Preload("Sentence").
+		First(&capture, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &capture, nil
+}
+
+// FindDuplicate returns an existing capture of the same word in the same sentence
+func (r *CaptureRepository) FindDuplicate(ctx context.Context, userID, wordID uuid.UUID, sentenceID *uuid.UUID) (*models.Capture, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Word").
+		Preload("Sentence").
+		Where("user_id = ? AND word_id = ?", userID, wordID)
+	if sentenceID != nil {
+		query = query.Where("sentence_id = ?", *sentenceID)
+	} else {
+		query = query.Where("sentence_id IS NULL")
+	}
+
+	var capture models.Capture
+	if err := query.First(&capture).Error; err != nil {
+		return nil, err
+	}
+
+	return &capture, nil
+}
+
+// ListByUserID returns a page of the user's captures sorted by capture time and the key of the next page, nil on the last page
+func (r *CaptureRepository) ListByUserID(ctx context.Context, userID uuid.UUID, page Page) ([]models.Capture, []string, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Word").
+		Preload("Sentence").
+		Where("user_id = ?", userID)
+
+	query, err := keysetQuery(query, "created_at", page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var captures []models.Capture
+	if err := query.Find(&captures).Error; err != nil {
+		return nil, nil, err
+	}
+
+	captures, next := nextKey(captures, page.Limit, func(c models.Capture) []string {
+		return []string{c.CreatedAt.UTC().Format(time.RFC3339Nano), c.ID.String()}
+	})
+	return captures, next, nil
+}
+
+// CountByUserID returns the number of the user's captures
+func (r *CaptureRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Capture{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// ListQueuedWords returns distinct words captured by the user that have not been put into a lesson yet, oldest first
+func (r *CaptureRepository) ListQueuedWords(ctx context.Context, userID uuid.UUID, limit int) ([]models.Word, error) {
+	subQuery := r.db.
+		Table("captures").
+		Select("word_id, MIN(created_at) AS captured_at").
+		Where("user_id = ? AND queued = ?", userID, true).
+		Group("word_id")
+
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Table("words").
+		Select("words.*").
+		Joins("JOIN (?) AS queued ON words.id = queued.word_id", subQuery).
+		Order("queued.captured_at ASC").
+		Limit(limit).
+		Find(&words).Error
+
+	return words, err
+}
+
+// MarkDequeued marks the user's captures of the given words as added to a lesson
+func (r *CaptureRepository) MarkDequeued(ctx context.Context, userID uuid.UUID, wordIDs []uuid.UUID) error {
+	if len(wordIDs) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&models.Capture{}).
+		Where("user_id = ? AND word_id IN ?", userID, wordIDs).
+		Update("queued", false).Error
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCaptureQueue tests de-duplication and the lesson queue of captured words
+func TestCaptureQueue(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Name:      "Capture User",
+		Email:     "capture-" + uuid.New().String()[:8] + "@example.com",
+		Role:      "user",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, userRepo.Create(ctx, user))
+
+	word := &models.Word{ID: uuid.New(), Word: "ephemeral", Translation: "мимолётный", PartOfSpeech: "adjective"}
+	assert.NoError(t, wordRepo.Create(ctx, word))
+
+	sentence := &models.Sentence{ID: uuid.New(), WordID: word.ID, Sentence: "Fame is ephemeral.", UserID: &user.ID}
+	assert.NoError(t, sentenceRepo.Create(ctx, sentence))
+
+	// Captured sentences are visible only to the user who captured them
+	shared, err := sentenceRepo.GetByWordID(ctx, word.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, shared)
+
+	own, err := sentenceRepo.GetByWordIDForUser(ctx, word.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, own, 1)
+
+	capture := &models.Capture{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		WordID:     word.ID,
+		SentenceID: &sentence.ID,
+		Selection:  "ephemeral",
+		SourceURL:  "https://example.com/article",
+		Queued:     true,
+	}
+	assert.NoError(t, captureRepo.Create(ctx, capture))
+
+	dup, err := captureRepo.FindDuplicate(ctx, user.ID, word.ID, &sentence.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, capture.ID, dup.ID)
+
+	_, err = captureRepo.FindDuplicate(ctx, user.ID, word.ID, nil)
+	assert.Error(t, err)
+
+	queued, err := captureRepo.ListQueuedWords(ctx, user.ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, queued, 1)
+	assert.Equal(t, word.ID, queued[0].ID)
+
+	assert.NoError(t, captureRepo.MarkDequeued(ctx, user.ID, []uuid.UUID{word.ID}))
+
+	queued, err = captureRepo.ListQueuedWords(ctx, user.ID, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, queued)
+
+	captures, next, err := captureRepo.ListByUserID(ctx, user.ID, Page{Sort: "created_at", Desc: true, Limit: 20})
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Len(t, captures, 1)
+	assert.Equal(t, "ephemeral", captures[0].Word.Word)
+	assert.NotNil(t, captures[0].Sentence)
+
+	// Newer captures come first and pages continue after the key of the last capture
+	later := &models.Capture{ID: uuid.New(), UserID: user.ID, WordID: word.ID, Selection: "ephemeral", CreatedAt: capture.CreatedAt.Add(time.Minute)}
+	assert.NoError(t, captureRepo.Create(ctx, later))
+
+	captures, next, err = captureRepo.ListByUserID(ctx, user.ID, Page{Sort: "created_at", Desc: true, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, captures, 1)
+	assert.Equal(t, later.ID, captures[0].ID)
+	assert.NotNil(t, next)
+
+	captures, next, err = captureRepo.ListByUserID(ctx, user.ID, Page{Sort: "created_at", Desc: true, After: next, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, captures, 1)
+	assert.Equal(t, capture.ID, captures[0].ID)
+	assert.Nil(t, next)
+}
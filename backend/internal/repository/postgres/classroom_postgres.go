@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssignmentProgress is a student's progress on the words of an assignment
+type AssignmentProgress struct {
+	UserID       uuid.UUID
+	AssignmentID uuid.UUID
+	Total        int
+	Learned      int
+	NotLearned   int // words the student got wrong and hasn't learned since
+}
+
+// ClassroomRepository is a repository for classrooms, their students and assignments
+type ClassroomRepository struct {
+	db *gorm.DB
+}
+
+// NewClassroomRepository creates a new instance of ClassroomRepository
+func NewClassroomRepository(db *gorm.DB) *ClassroomRepository {
+	return &ClassroomRepository{db: db}
+}
+
+// Create creates a classroom
+func (r *ClassroomRepository) Create(ctx context.Context, classroom *models.Classroom) error {
+	return r.db.WithContext(ctx).Create(classroom).Error
+}
+
+// GetByID returns a classroom by id
+func (r *ClassroomRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Classroom, error) {
+	var classroom models.Classroom
+	if err := r.db.WithContext(ctx).First(&classroom, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &classroom, nil
+}
+
+// GetByJoinCode returns a classroom by its join code
+func (r *ClassroomRepository) GetByJoinCode(ctx context.Context, code string) (*models.Classroom, error) {
+	var classroom models.Classroom
+	if err := r.db.WithContext(ctx).First(&classroom, "join_code = ?", code).Error; err != nil {
+		return nil, err
+	}
+
+	return &classroom, nil
+}
+
+// ListByTeacher returns classrooms taught by the user, oldest first
+func (r *ClassroomRepository) ListByTeacher(ctx context.Context, teacherID uuid.UUID) ([]models.Classroom, error) {
+	var classrooms []models.Classroom
+	err := r.db.WithContext(ctx).
+		Where("teacher_id = ?", teacherID).
+		Order("created_at ASC").
+		Find(&classrooms).Error
+
+	return classrooms, err
+}
+
+// ListByMember returns classrooms the user joined as a student with their teachers, oldest first
+func (r *ClassroomRepository) ListByMember(ctx context.Context, userID uuid.UUID) ([]models.Classroom, error) {
+	var classrooms []models.Classroom
+	err := r.db.WithContext(ctx).
+		Preload("Teacher").
+		Joins("JOIN classroom_members ON classroom_members.classroom_id = classrooms.id").
+		Where("classroom_members.user_id = ?", userID).
+		Order("classroom_members.joined_at ASC").
+		Find(&classrooms).Error
+
+	return classrooms, err
+}
+
+// AddMember adds a student to a classroom, joining twice is a no-op
+func (r *ClassroomRepository) AddMember(ctx context.Context, classroomID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ClassroomMember{ClassroomID: classroomID, UserID: userID}).Error
+}
+
+// RemoveMember removes a student from a classroom
+func (r *ClassroomRepository) RemoveMember(ctx context.Context, classroomID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		Delete(&models.ClassroomMember{}).Error
+}
+
+// IsMember reports whether the user is a student of the classroom
+func (r *ClassroomRepository) IsMember(ctx context.Context, classroomID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ClassroomMember{}).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// ListMembers returns the students of a classroom with their users, in joining order
+func (r *ClassroomRepository) ListMembers(ctx context.Context, classroomID uuid.UUID) ([]models.ClassroomMember, error) {
+	var members []models.ClassroomMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("classroom_id = ?", classroomID).
+		Order("joined_at ASC").
+		Find(&members).Error
+
+	return members, err
+}
+
+// CountMembers returns the number of students of a classroom
+func (r *ClassroomRepository) CountMembers(ctx context.Context, classroomID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ClassroomMember{}).
+		Where("classroom_id = ?", classroomID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// TopicWordIDs returns the IDs of global words of a topic
+func (r *ClassroomRepository) TopicWordIDs(ctx context.Context, topicID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Word{}).
+		Where("topic_id = ? AND owner_id IS NULL", topicID).
+		Pluck("id", &ids).Error
+
+	return ids, err
+}
+
+// AssignableWordIDs filters the IDs down to words the teacher may assign: global words and their personal words
+func (r *ClassroomRepository) AssignableWordIDs(ctx context.Context, teacherID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Word{}).
+		Where("id IN ?", ids).
+		Where("owner_id IS NULL OR owner_id = ?", teacherID).
+		Pluck("id", &result).Error
+
+	return result, err
+}
+
+// CreateAssignment creates an assignment with its words
+func (r *ClassroomRepository) CreateAssignment(ctx context.Context, assignment *models.Assignment, wordIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Words").Create(assignment).Error; err != nil {
+			return err
+		}
+
+		words := make([]models.AssignmentWord, 0, len(wordIDs))
+		for _, id := range wordIDs {
+			words = append(words, models.AssignmentWord{AssignmentID: assignment.ID, WordID: id})
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&words).Error
+	})
+}
+
+// GetAssignment returns an assignment by id
+func (r *ClassroomRepository) GetAssignment(ctx context.Context, id uuid.UUID) (*models.Assignment, error) {
+	var assignment models.Assignment
+	if err := r.db.WithContext(ctx).First(&assignment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+// DeleteAssignment deletes an assignment and its words
+func (r *ClassroomRepository) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", id).Delete(&models.AssignmentWord{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Assignment{}, "id = ?", id).Error
+	})
+}
+
+// ListAssignments returns the assignments of a classroom, soonest due first
+func (r *ClassroomRepository) ListAssignments(ctx context.Context, classroomID uuid.UUID) ([]models.Assignment, error) {
+	var assignments []models.Assignment
+	err := r.db.WithContext(ctx).
+		Preload("Words").
+		Where("classroom_id = ?", classroomID).
+		Order("due_at ASC").
+		Find(&assignments).Error
+
+	return assignments, err
+}
+
+// ListAssignedWords returns words assigned to the user in all their classrooms that they have not learned yet,
+// words of the soonest due assignments first
+func (r *ClassroomRepository) ListAssignedWords(ctx context.Context, userID uuid.UUID, limit int) ([]models.Word, error) {
+	learned := r.db.
+		Table("learned_words").
+		Select("word_id").
+		Where("user_id = ?", userID)
+
+	assigned := r.db.
+		Table("assignment_words").
+		Select("assignment_words.word_id, MIN(assignments.due_at) AS due_at").
+		Joins("JOIN assignments ON assignments.id = assignment_words.assignment_id").
+		Joins("JOIN classroom_members ON classroom_members.classroom_id = assignments.classroom_id").
+		Where("classroom_members.user_id = ?", userID).
+		Where("assignment_words.word_id NOT IN (?)", learned).
+		Group("assignment_words.word_id")
+
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Table("words").
+		Select("words.*").
+		Joins("JOIN (?) AS assigned ON words.id = assigned.word_id", assigned).
+		Order("assigned.due_at ASC").
+		Limit(limit).
+		Find(&words).Error
+
+	return words, err
+}
+
+// ListProgress returns the progress of every student of a classroom on every assignment,
+// counted from learned_words and not_learned_words
+func (r *ClassroomRepository) ListProgress(ctx context.Context, classroomID uuid.UUID) ([]AssignmentProgress, error) {
+	var progress []AssignmentProgress
+	err := r.db.WithContext(ctx).Raw(`SELECT m.user_id, a.id AS assignment_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE lw.word_id IS NOT NULL) AS learned,
+			COUNT(*) FILTER (WHERE lw.word_id IS NULL AND nlw.word_id IS NOT NULL) AS not_learned
+		FROM classroom_members m
+		JOIN assignments a ON a.classroom_id = m.classroom_id
+		JOIN assignment_words aw ON aw.assignment_id = a.id
+		LEFT JOIN (SELECT DISTINCT user_id, word_id FROM learned_words) lw
+			ON lw.user_id = m.user_id AND lw.word_id = aw.word_id
+		LEFT JOIN (SELECT DISTINCT user_id, word_id FROM not_learned_words) nlw
+			ON nlw.user_id = m.user_id AND nlw.word_id = aw.word_id
+		WHERE m.classroom_id = ?
+		GROUP BY m.user_id, a.id`, classroomID).
+		Scan(&progress).Error
+
+	return progress, err
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClassroomAssignments tests that assigned words go to students soonest due first
+// and that progress is counted from learned and not learned words
+func TestClassroomAssignments(t *testing.T) {
+	ctx := context.Background()
+	teacher := newFriendTestUser(t, "Teacher")
+	student := newFriendTestUser(t, "Student")
+	outsider := newFriendTestUser(t, "Outsider")
+
+	classroom := &models.Classroom{ID: uuid.New(), TeacherID: teacher.ID, Name: "7B", JoinCode: uuid.New().String()[:8]}
+	assert.NoError(t, classroomRepo.Create(ctx, classroom))
+	assert.NoError(t, classroomRepo.AddMember(ctx, classroom.ID, student.ID))
+	// Joining twice is a no-op
+	assert.NoError(t, classroomRepo.AddMember(ctx, classroom.ID, student.ID))
+
+	found, err := classroomRepo.GetByJoinCode(ctx, classroom.JoinCode)
+	assert.NoError(t, err)
+	assert.Equal(t, classroom.ID, found.ID)
+
+	soon := &models.Word{ID: uuid.New(), Word: "homework", Translation: "домашнее задание", PartOfSpeech: "noun"}
+	later := &models.Word{ID: uuid.New(), Word: "exam", Translation: "экзамен", PartOfSpeech: "noun"}
+	learned := &models.Word{ID: uuid.New(), Word: "lesson", Translation: "урок", PartOfSpeech: "noun"}
+	for _, w := range []*models.Word{soon, later, learned} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+
+	assert.NoError(t, classroomRepo.CreateAssignment(ctx, &models.Assignment{
+		ID: uuid.New(), ClassroomID: classroom.ID, Title: "Later", DueAt: time.Now().Add(48 * time.Hour),
+	}, []uuid.UUID{later.ID, learned.ID}))
+	first := &models.Assignment{ID: uuid.New(), ClassroomID: classroom.ID, Title: "Soon", DueAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, classroomRepo.CreateAssignment(ctx, first, []uuid.UUID{soon.ID}))
+
+	assert.NoError(t, learnedWordRepo.Create(ctx, &models.LearnedWords{
+		ID: uuid.New(), UserID: student.ID, WordID: learned.ID, LearnedAt: time.Now(),
+	}))
+	assert.NoError(t, notLearnedWordRepo.Create(ctx, &models.NotLearnedWords{
+		ID: uuid.New(), UserID: student.ID, WordID: later.ID,
+	}))
+
+	words, err := classroomRepo.ListAssignedWords(ctx, student.ID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, words, 2) {
+		assert.Equal(t, soon.ID, words[0].ID)
+		assert.Equal(t, later.ID, words[1].ID)
+	}
+
+	words, err = classroomRepo.ListAssignedWords(ctx, outsider.ID, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, words)
+
+	progress, err := classroomRepo.ListProgress(ctx, classroom.ID)
+	assert.NoError(t, err)
+	assert.Len(t, progress, 2)
+	for _, p := range progress {
+		assert.Equal(t, student.ID, p.UserID)
+		if p.AssignmentID == first.ID {
+			assert.Equal(t, AssignmentProgress{UserID: student.ID, AssignmentID: first.ID, Total: 1}, p)
+		} else {
+			assert.Equal(t, 2, p.Total)
+			assert.Equal(t, 1, p.Learned)
+			assert.Equal(t, 1, p.NotLearned)
+		}
+	}
+
+	assert.NoError(t, classroomRepo.RemoveMember(ctx, classroom.ID, student.ID))
+	member, err := classroomRepo.IsMember(ctx, classroom.ID, student.ID)
+	assert.NoError(t, err)
+	assert.False(t, member)
+}
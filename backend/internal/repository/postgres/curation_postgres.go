@@ -0,0 +1,434 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTopicCycle   = errors.New("topic can't be moved under itself or one of its subtopics")
+	ErrMergeInvalid = errors.New("merge needs a target and at least one other word")
+)
+
+// Actor is who makes a content change, recorded in the audit log
+type Actor struct {
+	UserID *uuid.UUID // nil for the CLI
+	Name   string
+}
+
+// WordFilter filters global words for curation
+type WordFilter struct {
+	CEFRLevel          string
+	TopicID            *uuid.UUID
+	Search             string // prefix of the word
+	MissingAudio       bool
+	MissingSentences   bool
+	MissingDistractors bool // words without pick options
+	Offset             int
+	Limit              int
+}
+
+// CuratedWord is a global word with the number of its sentences and pick options
+type CuratedWord struct {
+	models.Word
+	SentenceCount   int
+	DistractorCount int
+}
+
+// WordChanges are fields set on words by a bulk edit, nil fields stay as they are
+type WordChanges struct {
+	CEFRLevel    *string
+	PartOfSpeech *string
+	TopicID      *uuid.UUID
+	ClearTopic   bool // removes words from their topic, TopicID is ignored
+}
+
+// AuditFilter filters the content audit log
+type AuditFilter struct {
+	EntityID *uuid.UUID
+	Action   string
+	Offset   int
+	Limit    int
+}
+
+// fieldChange is the old and new value of a changed field
+type fieldChange [2]interface{}
+
+// CurationRepository is a repository for admin changes to global words and topics
+type CurationRepository struct {
+	db *gorm.DB
+}
+
+// NewCurationRepository creates a new instance of CurationRepository
+func NewCurationRepository(db *gorm.DB) *CurationRepository {
+	return &CurationRepository{db: db}
+}
+
+// ListWords returns a page of global words matching the filter, ordered by word, and the total count
+func (r *CurationRepository) ListWords(ctx context.Context, filter WordFilter) ([]CuratedWord, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Word{}).Where("words.owner_id IS NULL")
+
+	if filter.CEFRLevel != "" {
+		query = query.Where("words.cefr_level = ?", filter.CEFRLevel)
+	}
+	if filter.TopicID != nil {
+		query = query.Where("words.topic_id = ?", *filter.TopicID)
+	}
+	if filter.Search != "" {
+		pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(filter.Search)) + "%"
+		query = query.Where("LOWER(words.word) LIKE ?", pattern)
+	}
+	if filter.MissingAudio {
+		query = query.Where("COALESCE(words.audio_url, '') = ''")
+	}
+	if filter.MissingSentences {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM sentences s WHERE s.word_id = words.id)")
+	}
+	if filter.MissingDistractors {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM pick_options p WHERE p.word_id = words.id)")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []models.Word
+	err := query.
+		Order("words.word ASC").Order("words.id ASC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&words).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(words) == 0 {
+		return []CuratedWord{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(words))
+	for _, w := range words {
+		ids = append(ids, w.ID)
+	}
+
+	sentences, err := r.countByWord(ctx, "sentences", ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	distractors, err := r.countByWord(ctx, "pick_options", ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]CuratedWord, 0, len(words))
+	for _, w := range words {
+		result = append(result, CuratedWord{
+			Word:            w,
+			SentenceCount:   sentences[w.ID],
+			DistractorCount: distractors[w.ID],
+		})
+	}
+
+	return result, total, nil
+}
+
+// countByWord counts the rows of a table referencing each of the words
+func (r *CurationRepository) countByWord(ctx context.Context, table string, ids []uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		WordID uuid.UUID
+		Count  int
+	}
+	err := r.db.WithContext(ctx).
+		Table(table).
+		Select("word_id, COUNT(*) AS count").
+		Where("word_id IN ?", ids).
+		Group("word_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.WordID] = row.Count
+	}
+	return counts, nil
+}
+
+// BulkUpdateWords applies the changes to the global words with the ids, unknown ids are skipped.
+// Every word that actually changed gets an audit entry. It returns the number of changed words.
+func (r *CurationRepository) BulkUpdateWords(ctx context.Context, ids []uuid.UUID, changes WordChanges, actor Actor) (int, error) {
+	updated := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if changes.TopicID != nil && !changes.ClearTopic {
+			if err := tx.First(&models.Topic{}, "id = ?", *changes.TopicID).Error; err != nil {
+				return err
+			}
+		}
+
+		var words []models.Word
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND owner_id IS NULL", ids).
+			Find(&words).Error
+		if err != nil {
+			return err
+		}
+
+		for _, word := range words {
+			fields := map[string]interface{}{}
+			diff := map[string]fieldChange{}
+
+			if changes.CEFRLevel != nil && word.CEFRLevel != *changes.CEFRLevel {
+				fields["cefr_level"] = *changes.CEFRLevel
+				diff["cefr_level"] = fieldChange{word.CEFRLevel, *changes.CEFRLevel}
+			}
+			if changes.PartOfSpeech != nil && word.PartOfSpeech != *changes.PartOfSpeech {
+				fields["part_of_speech"] = *changes.PartOfSpeech
+				diff["part_of_speech"] = fieldChange{word.PartOfSpeech, *changes.PartOfSpeech}
+			}
+			if changes.ClearTopic && word.TopicID != nil {
+				fields["topic_id"] = nil
+				diff["topic_id"] = fieldChange{word.TopicID, nil}
+			} else if !changes.ClearTopic && changes.TopicID != nil && (word.TopicID == nil || *word.TopicID != *changes.TopicID) {
+				fields["topic_id"] = *changes.TopicID
+				diff["topic_id"] = fieldChange{word.TopicID, *changes.TopicID}
+			}
+
+			if len(fields) == 0 {
+				continue
+			}
+
+			if err := tx.Model(&models.Word{}).Where("id = ?", word.ID).Updates(fields).Error; err != nil {
+				return err
+			}
+			if err := createContentAudit(tx, actor, models.ContentActionWordUpdate, "word", word.ID, diff); err != nil {
+				return err
+			}
+			updated++
+		}
+
+		return nil
+	})
+
+	return updated, err
+}
+
+// MergeWords merges duplicate global words into the target. Sentences, pick options, progress,
+// captures, deck and assignment entries of the duplicates move to the target, empty fields of the
+// target are filled from the duplicates in the given order, then the duplicates are deleted.
+func (r *CurationRepository) MergeWords(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID, actor Actor) (*models.Word, error) {
+	seen := map[uuid.UUID]bool{targetID: true}
+	for _, id := range sourceIDs {
+		if seen[id] {
+			return nil, ErrMergeInvalid
+		}
+		seen[id] = true
+	}
+	if len(sourceIDs) == 0 {
+		return nil, ErrMergeInvalid
+	}
+
+	var target models.Word
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var words []models.Word
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND owner_id IS NULL", append([]uuid.UUID{targetID}, sourceIDs...)).
+			Find(&words).Error
+		if err != nil {
+			return err
+		}
+		if len(words) != len(sourceIDs)+1 {
+			return gorm.ErrRecordNotFound
+		}
+
+		byID := make(map[uuid.UUID]models.Word, len(words))
+		for _, w := range words {
+			byID[w.ID] = w
+		}
+		target = byID[targetID]
+
+		// Fill the blanks of the target from the duplicates
+		fields := map[string]interface{}{}
+		diff := map[string]interface{}{}
+		fill := func(column, current, value string) {
+			if current == "" && value != "" && fields[column] == nil {
+				fields[column] = value
+				diff[column] = fieldChange{current, value}
+			}
+		}
+		merged := make([]map[string]string, 0, len(sourceIDs))
+		for _, id := range sourceIDs {
+			source := byID[id]
+			merged = append(merged, map[string]string{"id": source.ID.String(), "word": source.Word})
+
+			fill("translation", target.Translation, source.Translation)
+			fill("context", target.Context, source.Context)
+			fill("cefr_level", target.CEFRLevel, source.CEFRLevel)
+			fill("audio_url", target.AudioURL, source.AudioURL)
+			fill("phonetic", target.Phonetic, source.Phonetic)
+			if target.TopicID == nil && source.TopicID != nil && fields["topic_id"] == nil {
+				fields["topic_id"] = *source.TopicID
+				diff["topic_id"] = fieldChange{nil, *source.TopicID}
+			}
+		}
+		diff["merged_words"] = merged
+
+		if len(fields) > 0 {
+			if err := tx.Model(&target).Updates(fields).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"sentences", "pick_options", "captures", "sync_events", "learned_words", "not_learned_words"} {
+			if err := tx.Exec("UPDATE "+table+" SET word_id = ? WHERE word_id IN ?", targetID, sourceIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		// A user who had learned several of the duplicates keeps the most revised progress
+		err = tx.Exec(`DELETE FROM learned_words a USING learned_words b
+			WHERE a.word_id = ? AND b.word_id = a.word_id AND b.user_id = a.user_id
+			AND (a.count_of_revisions < b.count_of_revisions
+				OR (a.count_of_revisions = b.count_of_revisions AND a.id > b.id))`, targetID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`DELETE FROM not_learned_words a USING not_learned_words b
+			WHERE a.word_id = ? AND b.word_id = a.word_id AND b.user_id = a.user_id AND a.id > b.id`, targetID).Error
+		if err != nil {
+			return err
+		}
+
+		// Deck and assignment entries are keyed by word, the ones of the duplicates go with them
+		err = tx.Exec(`INSERT INTO deck_words (deck_id, word_id, added_at)
+			SELECT deck_id, ?::uuid, MIN(added_at) FROM deck_words WHERE word_id IN ? GROUP BY deck_id
+			ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`INSERT INTO assignment_words (assignment_id, word_id)
+			SELECT DISTINCT assignment_id, ?::uuid FROM assignment_words WHERE word_id IN ?
+			ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.Word{}, "id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+
+		if err := createContentAudit(tx, actor, models.ContentActionWordMerge, "word", targetID, diff); err != nil {
+			return err
+		}
+
+		return tx.First(&target, "id = ?", targetID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// ReparentTopic moves a topic under another one, nil parent makes it a main topic
+func (r *CurationRepository) ReparentTopic(ctx context.Context, topicID uuid.UUID, parentID *uuid.UUID, actor Actor) (*models.Topic, error) {
+	var topic models.Topic
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&topic, "id = ?", topicID).Error; err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			if *parentID == topicID {
+				return ErrTopicCycle
+			}
+			if err := tx.First(&models.Topic{}, "id = ?", *parentID).Error; err != nil {
+				return err
+			}
+
+			// The topic must not be an ancestor of its new parent
+			var cycles int64
+			err := tx.Raw(`WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM topics WHERE id = ?
+				UNION
+				SELECT t.id, t.parent_id FROM topics t JOIN ancestors a ON t.id = a.parent_id
+			) SELECT COUNT(*) FROM ancestors WHERE id = ?`, *parentID, topicID).Scan(&cycles).Error
+			if err != nil {
+				return err
+			}
+			if cycles > 0 {
+				return ErrTopicCycle
+			}
+		}
+
+		if sameParent(topic.ParentID, parentID) {
+			return nil
+		}
+
+		diff := map[string]fieldChange{"parent_id": {topic.ParentID, parentID}}
+		if err := tx.Model(&topic).Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+		topic.ParentID = parentID
+
+		return createContentAudit(tx, actor, models.ContentActionTopicReparent, "topic", topicID, diff)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &topic, nil
+}
+
+// ListAudits returns a page of the audit log matching the filter, newest first, and the total count
+func (r *CurationRepository) ListAudits(ctx context.Context, filter AuditFilter) ([]models.ContentAudit, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ContentAudit{})
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var audits []models.ContentAudit
+	err := query.
+		Order("created_at DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&audits).Error
+
+	return audits, total, err
+}
+
+// createContentAudit records a change in the audit log
+func createContentAudit(tx *gorm.DB, actor Actor, action, entityType string, entityID uuid.UUID, changes interface{}) error {
+	return tx.Create(&models.ContentAudit{
+		ActorID:    actor.UserID,
+		Actor:      actor.Name,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    ToJSON(changes),
+	}).Error
+}
+
+// sameParent reports whether both parent ids are nil or equal
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestCurationListAndBulkEdit tests filtering words for curation and auditing bulk edits
+func TestCurationListAndBulkEdit(t *testing.T) {
+	ctx := context.Background()
+	admin := newFriendTestUser(t, "Curator")
+	actor := Actor{UserID: &admin.ID, Name: admin.Email}
+
+	topic := &models.Topic{ID: uuid.New(), Title: "Curation"}
+	assert.NoError(t, topicRepo.Create(ctx, topic))
+
+	complete := &models.Word{ID: uuid.New(), Word: "harbor", PartOfSpeech: "noun", CEFRLevel: "B1", AudioURL: "audio/harbor.mp3", TopicID: &topic.ID}
+	bare := &models.Word{ID: uuid.New(), Word: "anchor", PartOfSpeech: "noun", CEFRLevel: "B1", TopicID: &topic.ID}
+	for _, w := range []*models.Word{complete, bare} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+	assert.NoError(t, sentenceRepo.Create(ctx, &models.Sentence{ID: uuid.New(), WordID: complete.ID, Sentence: "The ship left the harbor."}))
+
+	words, total, err := curationRepo.ListWords(ctx, WordFilter{TopicID: &topic.ID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, words, 2) {
+		assert.Equal(t, bare.ID, words[0].ID, "ordered by word")
+		assert.Equal(t, 1, words[1].SentenceCount)
+	}
+
+	words, total, err = curationRepo.ListWords(ctx, WordFilter{TopicID: &topic.ID, MissingAudio: true, MissingSentences: true, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, words, 1) {
+		assert.Equal(t, bare.ID, words[0].ID)
+	}
+
+	level := "B2"
+	updated, err := curationRepo.BulkUpdateWords(ctx, []uuid.UUID{complete.ID, bare.ID, uuid.New()}, WordChanges{CEFRLevel: &level}, actor)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated)
+
+	// Words already at the level aren't changed again
+	updated, err = curationRepo.BulkUpdateWords(ctx, []uuid.UUID{complete.ID}, WordChanges{CEFRLevel: &level}, actor)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated)
+
+	missing := uuid.New()
+	_, err = curationRepo.BulkUpdateWords(ctx, []uuid.UUID{bare.ID}, WordChanges{TopicID: &missing}, actor)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	audits, total, err := curationRepo.ListAudits(ctx, AuditFilter{EntityID: &bare.ID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, audits, 1) {
+		assert.Equal(t, models.ContentActionWordUpdate, audits[0].Action)
+		assert.Equal(t, admin.Email, audits[0].Actor)
+		assert.JSONEq(t, `{"cefr_level": ["B1", "B2"]}`, string(audits[0].Changes))
+	}
+}
+
+// TestCurationMergeWords tests that sentences and progress of duplicates move to the target
+func TestCurationMergeWords(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Learner")
+	actor := Actor{Name: "cli:test"}
+
+	target := &models.Word{ID: uuid.New(), Word: "colour", PartOfSpeech: "noun"}
+	duplicate := &models.Word{ID: uuid.New(), Word: "color", PartOfSpeech: "noun", Translation: "цвет", CEFRLevel: "A1"}
+	for _, w := range []*models.Word{target, duplicate} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+	assert.NoError(t, sentenceRepo.Create(ctx, &models.Sentence{ID: uuid.New(), WordID: duplicate.ID, Sentence: "What color is it?"}))
+	for _, lw := range []*models.LearnedWords{
+		{ID: uuid.New(), UserID: user.ID, WordID: target.ID, LearnedAt: time.Now(), CountOfRevisions: 1},
+		{ID: uuid.New(), UserID: user.ID, WordID: duplicate.ID, LearnedAt: time.Now(), CountOfRevisions: 3},
+	} {
+		assert.NoError(t, learnedWordRepo.Create(ctx, lw))
+	}
+
+	_, err := curationRepo.MergeWords(ctx, target.ID, []uuid.UUID{target.ID}, actor)
+	assert.ErrorIs(t, err, ErrMergeInvalid)
+	_, err = curationRepo.MergeWords(ctx, target.ID, []uuid.UUID{uuid.New()}, actor)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	merged, err := curationRepo.MergeWords(ctx, target.ID, []uuid.UUID{duplicate.ID}, actor)
+	assert.NoError(t, err)
+	assert.Equal(t, "цвет", merged.Translation, "empty fields are filled from the duplicate")
+	assert.Equal(t, "A1", merged.CEFRLevel)
+
+	_, err = wordRepo.GetByID(ctx, duplicate.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	var sentences int64
+	db.Model(&models.Sentence{}).Where("word_id = ?", target.ID).Count(&sentences)
+	assert.Equal(t, int64(1), sentences)
+
+	// The user keeps one progress entry, the most revised one
+	learned, err := learnedWordRepo.GetByUserWordID(ctx, user.ID, target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, learned.CountOfRevisions)
+	var entries int64
+	db.Model(&models.LearnedWords{}).Where("user_id = ? AND word_id = ?", user.ID, target.ID).Count(&entries)
+	assert.Equal(t, int64(1), entries)
+
+	audits, _, err := curationRepo.ListAudits(ctx, AuditFilter{EntityID: &target.ID, Action: models.ContentActionWordMerge, Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, audits, 1) {
+		assert.Nil(t, audits[0].ActorID)
+		assert.Equal(t, "cli:test", audits[0].Actor)
+	}
+}
+
+// TestCurationReparentTopic tests moving topics and rejecting cycles
+func TestCurationReparentTopic(t *testing.T) {
+	ctx := context.Background()
+	actor := Actor{Name: "cli:test"}
+
+	root := &models.Topic{ID: uuid.New(), Title: "Nature"}
+	child := &models.Topic{ID: uuid.New(), Title: "Animals", ParentID: &root.ID}
+	grandchild := &models.Topic{ID: uuid.New(), Title: "Birds", ParentID: &child.ID}
+	for _, topic := range []*models.Topic{root, child, grandchild} {
+		assert.NoError(t, topicRepo.Create(ctx, topic))
+	}
+
+	_, err := curationRepo.ReparentTopic(ctx, root.ID, &grandchild.ID, actor)
+	assert.ErrorIs(t, err, ErrTopicCycle)
+	_, err = curationRepo.ReparentTopic(ctx, root.ID, &root.ID, actor)
+	assert.ErrorIs(t, err, ErrTopicCycle)
+
+	moved, err := curationRepo.ReparentTopic(ctx, grandchild.ID, &root.ID, actor)
+	assert.NoError(t, err)
+	assert.Equal(t, root.ID, *moved.ParentID)
+
+	moved, err = curationRepo.ReparentTopic(ctx, child.ID, nil, actor)
+	assert.NoError(t, err)
+	assert.Nil(t, moved.ParentID)
+
+	audits, total, err := curationRepo.ListAudits(ctx, AuditFilter{Action: models.ContentActionTopicReparent, Limit: 10})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, total, int64(2))
+	assert.NotEmpty(t, audits)
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeckRepository is a repository for decks
+type DeckRepository struct {
+	db *gorm.DB
+}
+
+// NewDeckRepository creates a new instance of DeckRepository
+func NewDeckRepository(db *gorm.DB) *DeckRepository {
+	return &DeckRepository{db: db}
+}
+
+// Create creates a new deck
+func (r *DeckRepository) Create(ctx context.Context, deck *models.Deck) error {
+	return r.db.WithContext(ctx).Create(deck).Error
+}
+
+// GetByID returns a deck by id
+func (r *DeckRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Deck, error) {
+	var deck models.Deck
+	if err := r.db.WithContext(ctx).First(&deck, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &deck, nil
+}
+
+// ListByUserID returns all decks of a user
+func (r *DeckRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.Deck, error) {
+	var decks []models.Deck
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&decks).Error
+
+	return decks, err
+}
+
+// Update updates a deck
+func (r *DeckRepository) Update(ctx context.Context, deck *models.Deck) error {
+	return r.db.WithContext(ctx).Save(deck).Error
+}
+
+// Delete deletes a deck and its word links
+func (r *DeckRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("deck_id = ?", id).Delete(&models.DeckWord{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Deck{}, "id = ?", id).Error
+	})
+}
+
+// AddWord adds a word to a deck, adding an existing word is a no-op
+func (r *DeckRepository) AddWord(ctx context.Context, deckID, wordID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.DeckWord{DeckID: deckID, WordID: wordID}).Error
+}
+
+// RemoveWord removes a word from a deck
+func (r *DeckRepository) RemoveWord(ctx context.Context, deckID, wordID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("deck_id = ? AND word_id = ?", deckID, wordID).
+		Delete(&models.DeckWord{}).Error
+}
+
+// ListWords returns the words of a deck in the order they were added
+func (r *DeckRepository) ListWords(ctx context.Context, deckID uuid.UUID) ([]models.Word, error) {
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Table("words").
+		Select("words.*").
+		Joins("JOIN deck_words ON words.id = deck_words.word_id").
+		Where("deck_words.deck_id = ?", deckID).
+		Order("deck_words.added_at ASC").
+		Find(&words).Error
+
+	return words, err
+}
+
+// CountWords returns the number of words in a deck
+func (r *DeckRepository) CountWords(ctx context.Context, deckID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.DeckWord{}).
+		Where("deck_id = ?", deckID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// GetWordsForLesson returns random deck words the user has not learned yet,
+// topped up with learned deck words for review when the deck is mostly learned
+func (r *DeckRepository) GetWordsForLesson(ctx context.Context, deckID, userID uuid.UUID, limit int) ([]models.Word, error) {
+	subQuery := r.db.
+		Table("learned_words").
+		Select("word_id").
+		Where("user_id = ?", userID)
+
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Table("words").
+		Select("words.*").
+		Joins("JOIN deck_words ON words.id = deck_words.word_id").
+		Where("deck_words.deck_id = ?", deckID).
+		Where("words.id NOT IN (?)", subQuery).
+		Order("RANDOM()").
+		Limit(limit).
+		Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(words) >= limit {
+		return words, nil
+	}
+
+	var review []models.Word
+	err = r.db.WithContext(ctx).
+		Table("words").
+		Select("words.*").
+		Joins("JOIN deck_words ON words.id = deck_words.word_id").
+		Where("deck_words.deck_id = ?", deckID).
+		Where("words.id IN (?)", subQuery).
+		Order("RANDOM()").
+		Limit(limit - len(words)).
+		Find(&review).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return append(words, review...), nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDeckWords tests adding, listing and removing deck words
+func TestDeckWords(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Name:      "Deck User",
+		Email:     "deck-" + uuid.New().String()[:8] + "@example.com",
+		Role:      "user",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, userRepo.Create(ctx, user))
+
+	deck := &models.Deck{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Name:   "Travel",
+	}
+	assert.NoError(t, deckRepo.Create(ctx, deck))
+
+	global := &models.Word{ID: uuid.New(), Word: "ticket", Translation: "билет", PartOfSpeech: "noun"}
+	personal := &models.Word{ID: uuid.New(), Word: "layover", Translation: "пересадка", PartOfSpeech: "noun", OwnerID: &user.ID}
+	assert.NoError(t, wordRepo.Create(ctx, global))
+	assert.NoError(t, wordRepo.Create(ctx, personal))
+
+	assert.NoError(t, deckRepo.AddWord(ctx, deck.ID, global.ID))
+	assert.NoError(t, deckRepo.AddWord(ctx, deck.ID, personal.ID))
+	// Adding the same word twice is a no-op
+	assert.NoError(t, deckRepo.AddWord(ctx, deck.ID, global.ID))
+
+	count, err := deckRepo.CountWords(ctx, deck.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Learned words are only used to top up the lesson
+	assert.NoError(t, learnedWordRepo.Create(ctx, &models.LearnedWords{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		WordID:    global.ID,
+		LearnedAt: time.Now(),
+	}))
+
+	words, err := deckRepo.GetWordsForLesson(ctx, deck.ID, user.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, words, 1)
+	assert.Equal(t, personal.ID, words[0].ID)
+
+	words, err = deckRepo.GetWordsForLesson(ctx, deck.ID, user.ID, 5)
+	assert.NoError(t, err)
+	assert.Len(t, words, 2)
+
+	assert.NoError(t, deckRepo.RemoveWord(ctx, deck.ID, personal.ID))
+
+	words, err = deckRepo.ListWords(ctx, deck.ID)
+	assert.NoError(t, err)
+	assert.Len(t, words, 1)
+	assert.Equal(t, global.ID, words[0].ID)
+
+	assert.NoError(t, deckRepo.Delete(ctx, deck.ID))
+	_, err = deckRepo.GetByID(ctx, deck.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// topicSubtreeIDs selects the id of a topic and of all its subtopics
+const topicSubtreeIDs = `WITH RECURSIVE subtree AS (
+	SELECT id FROM topics WHERE id = ?
+	UNION
+	SELECT t.id FROM topics t JOIN subtree s ON t.parent_id = s.id
+) SELECT id FROM subtree`
+
+// ExportFilter filters exported global words
+type ExportFilter struct {
+	TopicID    *uuid.UUID // words of the topic and its subtopics
+	CEFRLevels []string
+}
+
+// ExportedWord is a global word with its topic path, shared sentences and their pick options
+type ExportedWord struct {
+	models.Word
+	Topics    []string // topic path, main topic first
+	Sentences []WordImportSentence
+}
+
+// ExportedTopic is a topic with its path and the number of its own global words
+type ExportedTopic struct {
+	ID    uuid.UUID
+	Path  []string // main topic first, ending with the topic
+	Words int
+}
+
+// ExportRepository is a repository for exporting global words in the import format
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+// NewExportRepository creates a new instance of ExportRepository
+func NewExportRepository(db *gorm.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// topicPaths returns the paths of all topics by id
+func (r *ExportRepository) topicPaths(ctx context.Context) (map[uuid.UUID][]string, error) {
+	var topics []models.Topic
+	if err := r.db.WithContext(ctx).Find(&topics).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Topic, len(topics))
+	for _, t := range topics {
+		byID[t.ID] = t
+	}
+
+	paths := make(map[uuid.UUID][]string, len(topics))
+	for _, t := range topics {
+		path := []string{t.Title}
+		// The length check stops at cycles left by manual edits
+		for parentID := t.ParentID; parentID != nil && len(path) <= len(topics); {
+			parent, ok := byID[*parentID]
+			if !ok {
+				break
+			}
+			path = append([]string{parent.Title}, path...)
+			parentID = parent.ParentID
+		}
+		paths[t.ID] = path
+	}
+	return paths, nil
+}
+
+// ExportWords passes global words matching the filter to fn in batches, sorted by word so exports diff well
+func (r *ExportRepository) ExportWords(ctx context.Context, filter ExportFilter, batchSize int, fn func([]ExportedWord) error) error {
+	paths, err := r.topicPaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := Page{Sort: "word", Limit: batchSize}
+	for {
+		query := r.db.WithContext(ctx).Where("owner_id IS NULL")
+		if len(filter.CEFRLevels) > 0 {
+			query = query.Where("cefr_level IN ?", filter.CEFRLevels)
+		}
+		if filter.TopicID != nil {
+			query = query.Where("topic_id IN ("+topicSubtreeIDs+")", *filter.TopicID)
+		}
+		query, err := keysetQuery(query, "word", page)
+		if err != nil {
+			return err
+		}
+
+		var words []models.Word
+		if err := query.Find(&words).Error; err != nil {
+			return err
+		}
+		words, next := nextKey(words, page.Limit, func(w models.Word) []string {
+			return []string{w.Word, w.ID.String()}
+		})
+		if len(words) == 0 {
+			return nil
+		}
+
+		batch, err := r.exportBatch(ctx, words, paths)
+		if err != nil {
+			return err
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		if next == nil {
+			return nil
+		}
+		page.After = next
+	}
+}
+
+// exportBatch loads the shared sentences and pick options of the words
+func (r *ExportRepository) exportBatch(ctx context.Context, words []models.Word, paths map[uuid.UUID][]string) ([]ExportedWord, error) {
+	ids := make([]uuid.UUID, 0, len(words))
+	for _, w := range words {
+		ids = append(ids, w.ID)
+	}
+
+	var sentences []models.Sentence
+	err := r.db.WithContext(ctx).
+		Where("word_id IN ? AND user_id IS NULL", ids).
+		Order("sentence, id").
+		Find(&sentences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sentenceIDs := make([]uuid.UUID, 0, len(sentences))
+	for _, s := range sentences {
+		sentenceIDs = append(sentenceIDs, s.ID)
+	}
+	options := map[uuid.UUID][]string{}
+	if len(sentenceIDs) > 0 {
+		var picks []models.PickOption
+		if err := r.db.WithContext(ctx).Where("sentence_id IN ?", sentenceIDs).Find(&picks).Error; err != nil {
+			return nil, err
+		}
+		for _, p := range picks {
+			if _, ok := options[p.SentenceID]; !ok {
+				options[p.SentenceID] = p.Option
+			}
+		}
+	}
+
+	bySentence := map[uuid.UUID][]WordImportSentence{}
+	for _, s := range sentences {
+		bySentence[s.WordID] = append(bySentence[s.WordID], WordImportSentence{
+			Sentence:    s.Sentence,
+			Translation: s.Translation,
+			Options:     options[s.ID],
+		})
+	}
+
+	batch := make([]ExportedWord, 0, len(words))
+	for _, w := range words {
+		exported := ExportedWord{Word: w, Sentences: bySentence[w.ID]}
+		if w.TopicID != nil {
+			exported.Topics = paths[*w.TopicID]
+		}
+		batch = append(batch, exported)
+	}
+	return batch, nil
+}
+
+// ExportTopics returns the topic and its subtopics, or all topics, sorted by path
+func (r *ExportRepository) ExportTopics(ctx context.Context, topicID *uuid.UUID) ([]ExportedTopic, error) {
+	paths, err := r.topicPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Topic{})
+	if topicID != nil {
+		query = query.Where("id IN ("+topicSubtreeIDs+")", *topicID)
+	}
+	var ids []uuid.UUID
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		TopicID uuid.UUID
+		Words   int
+	}
+	err = r.db.WithContext(ctx).Model(&models.Word{}).
+		Select("topic_id, count(*) AS words").
+		Where("owner_id IS NULL AND topic_id IS NOT NULL").
+		Group("topic_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	words := make(map[uuid.UUID]int, len(counts))
+	for _, c := range counts {
+		words[c.TopicID] = c.Words
+	}
+
+	topics := make([]ExportedTopic, 0, len(ids))
+	for _, id := range ids {
+		topics = append(topics, ExportedTopic{ID: id, Path: paths[id], Words: words[id]})
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return strings.Join(topics[i].Path, "\x00") < strings.Join(topics[j].Path, "\x00")
+	})
+	return topics, nil
+}
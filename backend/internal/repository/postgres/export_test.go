@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportWords tests that exported words carry their topic path, sentences and options, filtered by topic and CEFR level
+func TestExportWords(t *testing.T) {
+	ctx := context.Background()
+
+	rows := []WordImportRow{
+		{Line: 2, Word: "tide", PartOfSpeech: "noun", CEFRLevel: "B2", Topics: []string{"Ocean", "Shore"},
+			Sentences: []WordImportSentence{{Sentence: "The tide is high.", Translation: "Прилив высокий.", Options: []string{"tide", "tides"}}}},
+		{Line: 3, Word: "anchor", PartOfSpeech: "noun", CEFRLevel: "B1", Topics: []string{"Ocean"}},
+		{Line: 4, Word: "desk", PartOfSpeech: "noun", CEFRLevel: "A1", Topics: []string{"Office"}},
+	}
+	_, err := importRepo.Import(ctx, rows, ImportOptions{Source: "export.csv", Actor: "cli:test"})
+	require.NoError(t, err)
+
+	var ocean models.Topic
+	require.NoError(t, db.Where("title = ? AND parent_id IS NULL", "Ocean").First(&ocean).Error)
+
+	var exported []ExportedWord
+	collect := func(batch []ExportedWord) error {
+		exported = append(exported, batch...)
+		return nil
+	}
+
+	// A batch of one pages through all words in order
+	require.NoError(t, exportRepo.ExportWords(ctx, ExportFilter{TopicID: &ocean.ID}, 1, collect))
+	if assert.Len(t, exported, 2) {
+		assert.Equal(t, "anchor", exported[0].Word.Word)
+		assert.Equal(t, []string{"Ocean"}, exported[0].Topics)
+		assert.Equal(t, "tide", exported[1].Word.Word)
+		assert.Equal(t, []string{"Ocean", "Shore"}, exported[1].Topics)
+		assert.Equal(t, rows[0].Sentences, exported[1].Sentences)
+	}
+
+	exported = nil
+	require.NoError(t, exportRepo.ExportWords(ctx, ExportFilter{TopicID: &ocean.ID, CEFRLevels: []string{"A1", "B2"}}, 100, collect))
+	if assert.Len(t, exported, 1) {
+		assert.Equal(t, "tide", exported[0].Word.Word)
+	}
+
+	topics, err := exportRepo.ExportTopics(ctx, &ocean.ID)
+	require.NoError(t, err)
+	if assert.Len(t, topics, 2) {
+		assert.Equal(t, []string{"Ocean"}, topics[0].Path)
+		assert.Equal(t, 1, topics[0].Words)
+		assert.Equal(t, []string{"Ocean", "Shore"}, topics[1].Path)
+		assert.Equal(t, 1, topics[1].Words)
+	}
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FriendRepository is a repository for friends and friend invites
+type FriendRepository struct {
+	db *gorm.DB
+}
+
+// NewFriendRepository creates a new instance of FriendRepository
+func NewFriendRepository(db *gorm.DB) *FriendRepository {
+	return &FriendRepository{db: db}
+}
+
+// CreateInvite creates a friend invite
+func (r *FriendRepository) CreateInvite(ctx context.Context, invite *models.FriendInvite) error {
+	return r.db.WithContext(ctx).Create(invite).Error
+}
+
+// GetInvite finds an invite by its token, expired invites are not found
+func (r *FriendRepository) GetInvite(ctx context.Context, token string) (*models.FriendInvite, error) {
+	var invite models.FriendInvite
+	err := r.db.WithContext(ctx).
+		Where("token = ? AND expires_at > ?", token, time.Now()).
+		First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// DeleteExpiredInvites deletes expired friend invites
+func (r *FriendRepository) DeleteExpiredInvites(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&models.FriendInvite{}).Error
+}
+
+// Add makes two users friends, adding existing friends is a no-op
+func (r *FriendRepository) Add(ctx context.Context, userID, friendID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create([]models.Friendship{
+			{UserID: userID, FriendID: friendID},
+			{UserID: friendID, FriendID: userID},
+		}).Error
+}
+
+// Remove ends a friendship for both users
+func (r *FriendRepository) Remove(ctx context.Context, userID, friendID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, friendID, friendID, userID).
+		Delete(&models.Friendship{}).Error
+}
+
+// List returns the user's friendships with the friends, oldest first
+func (r *FriendRepository) List(ctx context.Context, userID uuid.UUID) ([]models.Friendship, error) {
+	var friendships []models.Friendship
+	err := r.db.WithContext(ctx).
+		Preload("Friend").
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&friendships).Error
+
+	return friendships, err
+}
+
+// ListIDs returns the IDs of the user's friends
+func (r *FriendRepository) ListIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Friendship{}).
+		Where("user_id = ?", userID).
+		Pluck("friend_id", &ids).Error
+
+	return ids, err
+}
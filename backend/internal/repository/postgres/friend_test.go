@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFriendTestUser creates a user for friend tests
+func newFriendTestUser(t *testing.T, name string) *models.User {
+	user := &models.User{
+		ID:        uuid.New(),
+		Name:      name,
+		Email:     "friend-" + uuid.New().String()[:8] + "@example.com",
+		Role:      "user",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, userRepo.Create(context.Background(), user))
+	return user
+}
+
+// TestFriends tests adding, listing and removing friends in both directions
+func TestFriends(t *testing.T) {
+	ctx := context.Background()
+	alice := newFriendTestUser(t, "Alice")
+	bob := newFriendTestUser(t, "Bob")
+
+	assert.NoError(t, friendRepo.Add(ctx, alice.ID, bob.ID))
+	// Adding existing friends is a no-op
+	assert.NoError(t, friendRepo.Add(ctx, bob.ID, alice.ID))
+
+	friends, err := friendRepo.List(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Len(t, friends, 1)
+	assert.Equal(t, bob.ID, friends[0].FriendID)
+	assert.Equal(t, "Bob", friends[0].Friend.Name)
+
+	ids, err := friendRepo.ListIDs(ctx, bob.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{alice.ID}, ids)
+
+	assert.NoError(t, friendRepo.Remove(ctx, bob.ID, alice.ID))
+	ids, err = friendRepo.ListIDs(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+// TestFriendInvites tests that expired invites can't be accepted
+func TestFriendInvites(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Inviter")
+
+	active := &models.FriendInvite{Token: "active-" + uuid.New().String()[:8], UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	expired := &models.FriendInvite{Token: "expired-" + uuid.New().String()[:8], UserID: user.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+	assert.NoError(t, friendRepo.CreateInvite(ctx, active))
+	assert.NoError(t, friendRepo.CreateInvite(ctx, expired))
+
+	invite, err := friendRepo.GetInvite(ctx, active.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, invite.UserID)
+
+	_, err = friendRepo.GetInvite(ctx, expired.Token)
+	assert.Error(t, err)
+
+	assert.NoError(t, friendRepo.DeleteExpiredInvites(ctx))
+	_, err = friendRepo.GetInvite(ctx, active.Token)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"fluently/go-backend/internal/gamification"
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GamificationRepository is a repository for XP, streaks and achievements
+type GamificationRepository struct {
+	db *gorm.DB
+}
+
+// NewGamificationRepository creates a new instance of GamificationRepository
+func NewGamificationRepository(db *gorm.DB) *GamificationRepository {
+	return &GamificationRepository{db: db}
+}
+
+// GetStats returns the user's stats, zero stats if the user has no activity yet
+func (r *GamificationRepository) GetStats(ctx context.Context, userID uuid.UUID) (*models.UserStats, error) {
+	return getStats(r.db.WithContext(ctx), userID)
+}
+
+// ListAchievements returns the achievements the user has unlocked
+func (r *GamificationRepository) ListAchievements(ctx context.Context, userID uuid.UUID) ([]models.UserAchievement, error) {
+	var unlocked []models.UserAchievement
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("unlocked_at").
+		Find(&unlocked).Error
+
+	return unlocked, err
+}
+
+// GetMetrics returns the user's values of all achievement metrics
+func (r *GamificationRepository) GetMetrics(ctx context.Context, userID uuid.UUID) (gamification.Metrics, error) {
+	stats, err := r.GetStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return metrics(r.db.WithContext(ctx), stats, nil)
+}
+
+// Location returns the time zone streak days of the user are counted in
+func (r *GamificationRepository) Location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	return location(r.db.WithContext(ctx), userID)
+}
+
+// Record awards XP for activities, counts their days towards the streak and unlocks
+// achievements the user reached. Activities with an already recorded key are skipped.
+// Days are bounded by the backdate window and client reported XP by the daily cap.
+// Activities of one user are recorded one at a time, like sync events.
+func (r *GamificationRepository) Record(ctx context.Context, userID uuid.UUID, activities []gamification.Activity) (*models.UserStats, []gamification.Event, error) {
+	var stats *models.UserStats
+	var events []gamification.Event
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "gamification:"+userID.String()).Error; err != nil {
+			return err
+		}
+
+		var err error
+		stats, err = getStats(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		loc, err := location(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		// Days are counted in order, so late offline activities don't break the streak
+		sorted := append([]gamification.Activity(nil), activities...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+		streak := gamification.Streak{
+			Current: stats.CurrentStreak,
+			Longest: stats.LongestStreak,
+			Freezes: stats.StreakFreezes,
+		}
+		if stats.LastActiveDate != nil {
+			streak.LastDay = gamification.Day(*stats.LastActiveDate, time.UTC)
+		}
+
+		rules := gamification.ConfiguredRules()
+		xpCap := gamification.DailyXPCap()
+		now := time.Now()
+		oldLevel := gamification.Level(stats.XP)
+		recorded, gained, extended := 0, 0, false
+
+		for _, a := range sorted {
+			event := models.XPEvent{
+				ID:     uuid.New(),
+				UserID: userID,
+				Type:   a.Type,
+				Amount: a.XP(),
+				Day:    rules.ActivityDay(a.Time, now, loc),
+			}
+
+			if a.Key != "" {
+				key := a.Key
+				event.IdempotencyKey = &key
+
+				var count int64
+				if err := tx.Model(&models.XPEvent{}).Where("user_id = ? AND idempotency_key = ?", userID, key).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+			}
+
+			if a.ClientReported() && xpCap > 0 {
+				var earned int
+				err := tx.Model(&models.XPEvent{}).
+					Select("COALESCE(SUM(amount), 0)").
+					Where("user_id = ? AND day = ? AND type IN ?", userID, event.Day, []string{gamification.ActivityExercise, gamification.ActivityLesson}).
+					Scan(&earned).Error
+				if err != nil {
+					return err
+				}
+				event.Amount = gamification.CapXP(event.Amount, earned, xpCap)
+			}
+
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+			recorded++
+			gained += event.Amount
+
+			change := streak.Record(event.Day, rules)
+			extended = extended || change.Extended
+			if change.FreezesUsed > 0 {
+				events = append(events, gamification.Event{Type: gamification.EventStreakFreezeUsed, Streak: streak.Current, Freezes: change.FreezesUsed})
+			}
+			if change.FreezeEarned {
+				events = append(events, gamification.Event{Type: gamification.EventStreakFreezeEarned, Streak: streak.Current, Freezes: streak.Freezes})
+			}
+		}
+
+		if recorded == 0 {
+			return nil
+		}
+
+		stats.XP += gained
+		stats.CurrentStreak = streak.Current
+		stats.LongestStreak = streak.Longest
+		stats.StreakFreezes = streak.Freezes
+		if !streak.LastDay.IsZero() {
+			lastDay := streak.LastDay
+			stats.LastActiveDate = &lastDay
+		}
+		if err := tx.Save(stats).Error; err != nil {
+			return err
+		}
+
+		if gained > 0 {
+			events = append(events, gamification.Event{Type: gamification.EventXPGained, XP: gained})
+		}
+		if level := gamification.Level(stats.XP); level > oldLevel {
+			events = append(events, gamification.Event{Type: gamification.EventLevelUp, Level: level})
+		}
+		if extended {
+			events = append(events, gamification.Event{Type: gamification.EventStreakExtended, Streak: stats.CurrentStreak})
+		}
+
+		unlocked, err := r.unlock(tx, stats)
+		if err != nil {
+			return err
+		}
+		for i := range unlocked {
+			events = append(events, gamification.Event{Type: gamification.EventAchievementUnlocked, Achievement: &unlocked[i]})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, events, nil
+}
+
+// unlock stores achievements the user reached and returns them
+func (r *GamificationRepository) unlock(tx *gorm.DB, stats *models.UserStats) ([]gamification.Achievement, error) {
+	var ids []string
+	if err := tx.Model(&models.UserAchievement{}).Where("user_id = ?", stats.UserID).Pluck("achievement_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	have := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		have[id] = true
+	}
+
+	values, err := metrics(tx, stats, have)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := gamification.NewlyUnlocked(values, have)
+	now := time.Now()
+	for _, a := range unlocked {
+		if err := tx.Create(&models.UserAchievement{UserID: stats.UserID, AchievementID: a.ID, UnlockedAt: now}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return unlocked, nil
+}
+
+// getStats loads the user's stats or returns new zero stats
+func getStats(db *gorm.DB, userID uuid.UUID) (*models.UserStats, error) {
+	var stats models.UserStats
+	err := db.First(&stats, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.UserStats{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// location loads the user's time zone from the preferences
+func location(db *gorm.DB, userID uuid.UUID) (*time.Location, error) {
+	var timeZones []string
+	if err := db.Model(&models.Preference{}).Where("user_id = ?", userID).Limit(1).Pluck("time_zone", &timeZones).Error; err != nil {
+		return nil, err
+	}
+	if len(timeZones) == 0 {
+		return gamification.Location(""), nil
+	}
+	return gamification.Location(timeZones[0]), nil
+}
+
+// metrics computes achievement metrics. Metrics only used by unlocked achievements
+// are skipped when unlocked is given.
+func metrics(db *gorm.DB, stats *models.UserStats, unlocked map[string]bool) (gamification.Metrics, error) {
+	values := gamification.Metrics{
+		gamification.MetricXP:     stats.XP,
+		gamification.MetricStreak: stats.LongestStreak,
+	}
+	needed := func(metric string) bool {
+		return unlocked == nil || gamification.UsesMetric(metric, unlocked)
+	}
+
+	if needed(gamification.MetricLearnedWords) {
+		var count int64
+		if err := db.Model(&models.LearnedWords{}).Where("user_id = ?", stats.UserID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		values[gamification.MetricLearnedWords] = int(count)
+	}
+
+	if needed(gamification.MetricLessons) {
+		var count int64
+		if err := db.Model(&models.XPEvent{}).Where("user_id = ? AND type = ?", stats.UserID, gamification.ActivityLesson).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		values[gamification.MetricLessons] = int(count)
+	}
+
+	if needed(gamification.MetricTopicsCompleted) {
+		var count int64
+		err := db.Raw(`SELECT COUNT(*) FROM (
+				SELECT w.topic_id FROM words w
+				LEFT JOIN learned_words lw ON lw.word_id = w.id AND lw.user_id = ?
+				WHERE w.topic_id IS NOT NULL AND w.owner_id IS NULL
+				GROUP BY w.topic_id
+				HAVING COUNT(lw.id) = COUNT(*)
+			) completed`, stats.UserID).Scan(&count).Error
+		if err != nil {
+			return nil, err
+		}
+		values[gamification.MetricTopicsCompleted] = int(count)
+	}
+
+	return values, nil
+}
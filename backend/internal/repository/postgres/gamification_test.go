@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/gamification"
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGamificationRecord tests XP, streak days, idempotency and achievements of recorded activity
+func TestGamificationRecord(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Name:      "Streak User",
+		Email:     "streak-" + uuid.New().String()[:8] + "@example.com",
+		Role:      "user",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, userRepo.Create(ctx, user))
+
+	yesterday := time.Now().UTC().Add(-24 * time.Hour)
+	lesson := gamification.Activity{Type: gamification.ActivityLesson, Key: "lesson-1", Correct: 5, Total: 5, Time: yesterday}
+
+	stats, events, err := gamificationRepo.Record(ctx, user.ID, []gamification.Activity{lesson})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, stats.XP)
+	assert.Equal(t, 1, stats.CurrentStreak)
+
+	types := map[string]bool{}
+	for _, e := range events {
+		types[e.Type] = true
+		if e.Type == gamification.EventAchievementUnlocked {
+			assert.Equal(t, "first_lesson", e.Achievement.ID)
+		}
+	}
+	assert.True(t, types[gamification.EventLevelUp])
+	assert.True(t, types[gamification.EventStreakExtended])
+	assert.True(t, types[gamification.EventAchievementUnlocked])
+
+	// Resending the same activity awards nothing
+	stats, events, err = gamificationRepo.Record(ctx, user.ID, []gamification.Activity{lesson})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, stats.XP)
+	assert.Empty(t, events)
+
+	// Activity on the next day extends the streak
+	stats, _, err = gamificationRepo.Record(ctx, user.ID, []gamification.Activity{
+		{Type: gamification.ActivityExercise, Correct: 1, Total: 1, Time: time.Now().UTC()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 110, stats.XP)
+	assert.Equal(t, 2, stats.CurrentStreak)
+	assert.Equal(t, 2, stats.LongestStreak)
+
+	unlocked, err := gamificationRepo.ListAchievements(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, unlocked, 1)
+	assert.Equal(t, "first_lesson", unlocked[0].AchievementID)
+
+	// Client reported XP is capped per day, 10 XP of today's exercise leave 990 of 1000
+	stats, _, err = gamificationRepo.Record(ctx, user.ID, []gamification.Activity{
+		{Type: gamification.ActivityLesson, Key: "lesson-2", Correct: 200, Total: 200, Time: time.Now().UTC()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1100, stats.XP)
+
+	// A month old lesson counts on the first day of the backdate window and doesn't touch the streak
+	stats, _, err = gamificationRepo.Record(ctx, user.ID, []gamification.Activity{
+		{Type: gamification.ActivityLesson, Key: "lesson-old", Time: time.Now().UTC().AddDate(0, -1, 0)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1150, stats.XP)
+	assert.Equal(t, 2, stats.CurrentStreak)
+
+	var oldest models.XPEvent
+	assert.NoError(t, db.Where("user_id = ? AND idempotency_key = ?", user.ID, "lesson-old").First(&oldest).Error)
+	assert.Equal(t, gamification.Day(time.Now().Add(-72*time.Hour), time.UTC), oldest.Day.UTC())
+}
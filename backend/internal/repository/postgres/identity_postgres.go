@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdentityRepository is a repository for external accounts linked to users
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new instance of IdentityRepository
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// Create links an external account to a user
+func (r *IdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// GetBySubject finds the identity of an account at the provider
+func (r *IdentityRepository) GetBySubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		First(&identity, "provider = ? AND subject = ?", provider, subject).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// GetByUserProvider finds the account of the provider linked to a user
+func (r *IdentityRepository) GetByUserProvider(ctx context.Context, userID uuid.UUID, provider string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).
+		First(&identity, "user_id = ? AND provider = ?", userID, provider).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// ListByUserID returns the accounts linked to a user
+func (r *IdentityRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error
+
+	return identities, err
+}
+
+// CountByUserID counts the accounts linked to a user
+func (r *IdentityRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.UserIdentity{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// Touch updates when the identity was last used to sign in
+func (r *IdentityRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+// Delete unlinks the account of the provider from a user. Unlinking Google also clears the
+// Google ID of the user, /auth/google would link the account again otherwise.
+func (r *IdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND provider = ?", userID, provider).
+			Delete(&models.UserIdentity{}).Error
+		if err != nil || provider != "google" {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"google_id": "",
+				"provider": gorm.Expr(`CASE WHEN provider <> 'google' THEN provider
+					WHEN password_hash <> '' THEN 'password'
+					WHEN telegram_id IS NOT NULL THEN 'telegram'
+					ELSE COALESCE((SELECT provider FROM user_identities WHERE user_id = ? ORDER BY created_at LIMIT 1), '') END`, userID),
+			}).Error
+	})
+}
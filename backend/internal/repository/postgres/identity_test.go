@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdentities tests linking, finding and unlinking external accounts
+func TestIdentities(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Linked")
+	other := newFriendTestUser(t, "Other")
+
+	for _, provider := range []string{"github", "yandex"} {
+		assert.NoError(t, identityRepo.Create(ctx, &models.UserIdentity{
+			UserID:     user.ID,
+			Provider:   provider,
+			Subject:    "12345",
+			LastUsedAt: time.Now(),
+		}))
+	}
+
+	// An account at the provider belongs to one user only
+	assert.Error(t, identityRepo.Create(ctx, &models.UserIdentity{UserID: other.ID, Provider: "github", Subject: "12345"}))
+	// And a user links one account per provider
+	assert.Error(t, identityRepo.Create(ctx, &models.UserIdentity{UserID: user.ID, Provider: "github", Subject: "67890"}))
+
+	identity, err := identityRepo.GetBySubject(ctx, "github", "12345")
+	assert.NoError(t, err)
+	if assert.NotNil(t, identity) {
+		assert.Equal(t, user.ID, identity.UserID)
+		assert.Equal(t, user.Email, identity.User.Email)
+		assert.NoError(t, identityRepo.Touch(ctx, identity.ID))
+	}
+
+	count, err := identityRepo.CountByUserID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	assert.NoError(t, identityRepo.Delete(ctx, user.ID, "github"))
+	_, err = identityRepo.GetByUserProvider(ctx, user.ID, "github")
+	assert.Error(t, err)
+
+	identities, err := identityRepo.ListByUserID(ctx, user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, identities, 1) {
+		assert.Equal(t, "yandex", identities[0].Provider)
+	}
+}
+
+// TestUnlinkGoogleIdentity tests that unlinking Google clears the Google ID of the user
+func TestUnlinkGoogleIdentity(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Google")
+	user.GoogleID = "google-sub"
+	user.Provider = "google"
+	assert.NoError(t, userRepo.Update(ctx, user))
+	assert.NoError(t, identityRepo.Create(ctx, &models.UserIdentity{UserID: user.ID, Provider: "google", Subject: "google-sub"}))
+	assert.NoError(t, identityRepo.Create(ctx, &models.UserIdentity{UserID: user.ID, Provider: "github", Subject: "google-user"}))
+
+	assert.NoError(t, identityRepo.Delete(ctx, user.ID, "google"))
+
+	unlinked, err := userRepo.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, unlinked.GoogleID)
+	assert.Equal(t, "github", unlinked.Provider)
+}
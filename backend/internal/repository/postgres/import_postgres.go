@@ -0,0 +1,525 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImportRolledBack = errors.New("import run is already rolled back")
+
+	// errDryRun rolls back the transaction of a dry run
+	errDryRun = errors.New("dry run")
+)
+
+// Row results of an import, in addition to the create and update item actions
+const (
+	ImportResultSkip   = "skip"
+	ImportResultReject = "reject"
+)
+
+// defaultPartOfSpeech is set on words created without a part of speech, enrichment fills it in later
+const defaultPartOfSpeech = "unknown"
+
+// WordImportRow is a validated row of an import file.
+// Empty fields are left as they are on existing words.
+type WordImportRow struct {
+	Line         int // line or record of the file, for reports
+	Word         string
+	Translation  string
+	PartOfSpeech string // part of the natural key when set
+	CEFRLevel    string
+	Context      string
+	Topics       []string // topic path, main topic first
+	Sentences    []WordImportSentence
+}
+
+// WordImportSentence is an example sentence of an imported word with the options of its exercise
+type WordImportSentence struct {
+	Sentence    string   `json:"sentence"`
+	Translation string   `json:"translation,omitempty"`
+	Options     []string `json:"options,omitempty"` // pick options, added when the sentence has none
+}
+
+// ImportOptions configure an import run
+type ImportOptions struct {
+	Source string // imported file
+	Actor  string
+	DryRun bool // report the changes without keeping them
+
+	Rejected int    // rows rejected before the import, counted in the run
+	Progress func() // called after each row
+}
+
+// ImportResult is what an import did with a row
+type ImportResult struct {
+	Line           int
+	Word           string
+	Action         string               // create, update, skip or reject
+	Changes        map[string][2]string // updated fields as old and new value
+	SentencesAdded int
+	OptionsAdded   int    // sentences that got pick options
+	Error          string // reason of a reject
+}
+
+// ImportReport sums up an import run
+type ImportReport struct {
+	RunID          *uuid.UUID // nil for dry runs
+	Created        int
+	Updated        int
+	Skipped        int
+	Rejected       int
+	TopicsCreated  int
+	SentencesAdded int
+	OptionsAdded   int
+	Results        []ImportResult
+}
+
+// RollbackReport sums up what rolling back an import run undid
+type RollbackReport struct {
+	WordsDeleted     int
+	WordsRestored    int
+	SentencesDeleted int
+	OptionsDeleted   int
+	TopicsDeleted    int
+	TopicsKept       int // created topics that have words or subtopics from elsewhere by now
+	WordsKept        int // created words that learners have progress, decks or assignments with by now
+}
+
+// ImportRepository is a repository for importing global words
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+// NewImportRepository creates a new instance of ImportRepository
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// importRun keeps the state of a run while its rows are applied
+type importRun struct {
+	tx     *gorm.DB
+	run    *models.ImportRun
+	topics map[string]uuid.UUID // topic path to id
+	items  []models.ImportRunItem
+}
+
+// Import upserts global words by their natural key: the word, its part of speech and topic.
+// Rows without a part of speech match the only word with the same value and topic.
+// New words are created, changed fields of existing words are updated and rows without changes are skipped.
+// A row that fails is rejected without affecting the others. Every change is recorded for Rollback,
+// a dry run reports the changes without keeping them.
+func (r *ImportRepository) Import(ctx context.Context, rows []WordImportRow, opts ImportOptions) (*ImportReport, error) {
+	report := &ImportReport{Rejected: opts.Rejected, Results: make([]ImportResult, 0, len(rows))}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run := &importRun{
+			tx:     tx,
+			run:    &models.ImportRun{Source: opts.Source, Actor: opts.Actor, Status: models.ImportRunApplied},
+			topics: map[string]uuid.UUID{},
+		}
+		if err := tx.Create(run.run).Error; err != nil {
+			return err
+		}
+
+		keys := make(map[string]int, len(rows))
+		for _, row := range rows {
+			if err := tx.SavePoint("import_row").Error; err != nil {
+				return err
+			}
+			items := len(run.items)
+
+			// The second row with a key would overwrite the first one
+			key := strings.Join(append([]string{strings.ToLower(row.Word), row.PartOfSpeech}, row.Topics...), "\x00")
+			var result ImportResult
+			var err error
+			if line, ok := keys[key]; ok {
+				err = fmt.Errorf("same word, part of speech and topic as line %d", line)
+			} else {
+				keys[key] = row.Line
+				result, err = run.apply(row)
+			}
+			if err != nil {
+				if err := tx.RollbackTo("import_row").Error; err != nil {
+					return err
+				}
+				run.discard(items)
+				result = ImportResult{Line: row.Line, Word: row.Word, Action: ImportResultReject, Error: err.Error()}
+			} else if err := tx.Exec("RELEASE SAVEPOINT import_row").Error; err != nil {
+				return err
+			}
+
+			switch result.Action {
+			case models.ImportActionCreate:
+				report.Created++
+			case models.ImportActionUpdate:
+				report.Updated++
+			case ImportResultSkip:
+				report.Skipped++
+			case ImportResultReject:
+				report.Rejected++
+			}
+			report.SentencesAdded += result.SentencesAdded
+			report.OptionsAdded += result.OptionsAdded
+			report.Results = append(report.Results, result)
+			if opts.Progress != nil {
+				opts.Progress()
+			}
+		}
+
+		for _, item := range run.items {
+			if item.EntityType == models.ImportEntityTopic {
+				report.TopicsCreated++
+			}
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+
+		for i := range run.items {
+			run.items[i].RunID = run.run.ID
+			run.items[i].Position = i
+		}
+		if len(run.items) > 0 {
+			if err := tx.CreateInBatches(run.items, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		err := tx.Model(run.run).Updates(map[string]interface{}{
+			"created":  report.Created,
+			"updated":  report.Updated,
+			"skipped":  report.Skipped,
+			"rejected": report.Rejected,
+		}).Error
+		if err != nil {
+			return err
+		}
+		report.RunID = &run.run.ID
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// record remembers a change for rollback
+func (run *importRun) record(line int, entityType string, id uuid.UUID, action string, before map[string]string) {
+	item := models.ImportRunItem{Line: line, EntityType: entityType, EntityID: id, Action: action}
+	if before != nil {
+		item.Before = ToJSON(before)
+	}
+	run.items = append(run.items, item)
+}
+
+// discard forgets the changes from the item on, after they were rolled back
+func (run *importRun) discard(from int) {
+	for _, item := range run.items[from:] {
+		if item.EntityType != models.ImportEntityTopic {
+			continue
+		}
+		for key, id := range run.topics {
+			if id == item.EntityID {
+				delete(run.topics, key)
+			}
+		}
+	}
+	run.items = run.items[:from]
+}
+
+// apply creates or updates the word of a row with its topics and sentences
+func (run *importRun) apply(row WordImportRow) (ImportResult, error) {
+	result := ImportResult{Line: row.Line, Word: row.Word, Action: ImportResultSkip}
+
+	topicID, err := run.topic(row.Line, row.Topics)
+	if err != nil {
+		return result, err
+	}
+
+	query := run.tx.Where("owner_id IS NULL AND lower(word) = lower(?)", row.Word)
+	if topicID != nil {
+		query = query.Where("topic_id = ?", *topicID)
+	} else {
+		query = query.Where("topic_id IS NULL")
+	}
+	if row.PartOfSpeech != "" {
+		query = query.Where("part_of_speech = ?", row.PartOfSpeech)
+	}
+	var matches []models.Word
+	if err := query.Limit(2).Find(&matches).Error; err != nil {
+		return result, err
+	}
+
+	var word models.Word
+	switch len(matches) {
+	case 0:
+		word = models.Word{
+			Word:         row.Word,
+			Translation:  row.Translation,
+			PartOfSpeech: row.PartOfSpeech,
+			Context:      row.Context,
+			CEFRLevel:    row.CEFRLevel,
+			TopicID:      topicID,
+		}
+		if word.PartOfSpeech == "" {
+			word.PartOfSpeech = defaultPartOfSpeech
+		}
+		if err := run.tx.Create(&word).Error; err != nil {
+			return result, err
+		}
+		run.record(row.Line, models.ImportEntityWord, word.ID, models.ImportActionCreate, nil)
+		result.Action = models.ImportActionCreate
+
+	case 1:
+		word = matches[0]
+		before := map[string]string{}
+		fields := map[string]interface{}{}
+		result.Changes = map[string][2]string{}
+		for _, f := range []struct{ column, old, new string }{
+			{"translation", word.Translation, row.Translation},
+			{"cefr_level", word.CEFRLevel, row.CEFRLevel},
+			{"context", word.Context, row.Context},
+		} {
+			if f.new == "" || f.new == f.old {
+				continue
+			}
+			before[f.column] = f.old
+			fields[f.column] = f.new
+			result.Changes[f.column] = [2]string{f.old, f.new}
+		}
+		if len(fields) > 0 {
+			if err := run.tx.Model(&models.Word{}).Where("id = ?", word.ID).Updates(fields).Error; err != nil {
+				return result, err
+			}
+			run.record(row.Line, models.ImportEntityWord, word.ID, models.ImportActionUpdate, before)
+			result.Action = models.ImportActionUpdate
+		}
+
+	default:
+		return result, fmt.Errorf("several words %q in the topic, set the part of speech", row.Word)
+	}
+
+	for _, imported := range row.Sentences {
+		var sentence models.Sentence
+		err := run.tx.Where("word_id = ? AND sentence = ?", word.ID, imported.Sentence).
+			Limit(1).Find(&sentence).Error
+		if err != nil {
+			return result, err
+		}
+		if sentence.ID == uuid.Nil {
+			sentence = models.Sentence{WordID: word.ID, Sentence: imported.Sentence, Translation: imported.Translation}
+			if err := run.tx.Create(&sentence).Error; err != nil {
+				return result, err
+			}
+			run.record(row.Line, models.ImportEntitySentence, sentence.ID, models.ImportActionCreate, nil)
+			result.SentencesAdded++
+		}
+
+		if len(imported.Options) == 0 {
+			continue
+		}
+		var options int64
+		if err := run.tx.Model(&models.PickOption{}).Where("sentence_id = ?", sentence.ID).Count(&options).Error; err != nil {
+			return result, err
+		}
+		if options > 0 {
+			continue
+		}
+		option := models.PickOption{ID: uuid.New(), WordID: word.ID, SentenceID: sentence.ID, Option: imported.Options}
+		if err := run.tx.Create(&option).Error; err != nil {
+			return result, err
+		}
+		run.record(row.Line, models.ImportEntityPickOption, option.ID, models.ImportActionCreate, nil)
+		result.OptionsAdded++
+	}
+	if result.Action == ImportResultSkip && result.SentencesAdded+result.OptionsAdded > 0 {
+		result.Action = models.ImportActionUpdate
+	}
+
+	return result, nil
+}
+
+// topic returns the id of the last topic of the path, creating missing topics
+func (run *importRun) topic(line int, path []string) (*uuid.UUID, error) {
+	var parentID *uuid.UUID
+	for i, title := range path {
+		key := strings.Join(path[:i+1], "\x00")
+		if id, ok := run.topics[key]; ok {
+			parentID = &id
+			continue
+		}
+
+		var topic models.Topic
+		query := run.tx.Where("title = ?", title)
+		if parentID != nil {
+			query = query.Where("parent_id = ?", *parentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+		err := query.First(&topic).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			topic = models.Topic{Title: title, ParentID: parentID}
+			if err := run.tx.Create(&topic).Error; err != nil {
+				return nil, err
+			}
+			run.record(line, models.ImportEntityTopic, topic.ID, models.ImportActionCreate, nil)
+		} else if err != nil {
+			return nil, err
+		}
+
+		run.topics[key] = topic.ID
+		parentID = &topic.ID
+	}
+
+	return parentID, nil
+}
+
+// ListRuns returns the latest import runs, newest first
+func (r *ImportRepository) ListRuns(ctx context.Context, limit int) ([]models.ImportRun, error) {
+	var runs []models.ImportRun
+	err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// Rollback undoes an import run in reverse order: created pick options, sentences and words are deleted, updated
+// words get their previous values back and created topics are deleted unless something else uses them.
+// Created words that learners have progress, decks or assignments with are kept with their sentences.
+// Changes made to the words after the import are lost.
+func (r *ImportRepository) Rollback(ctx context.Context, runID uuid.UUID) (*RollbackReport, error) {
+	report := &RollbackReport{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var run models.ImportRun
+		if err := tx.First(&run, "id = ?", runID).Error; err != nil {
+			return err
+		}
+		if run.Status == models.ImportRunRolledBack {
+			return ErrImportRolledBack
+		}
+
+		var items []models.ImportRunItem
+		if err := tx.Where("run_id = ?", runID).Order("position DESC").Find(&items).Error; err != nil {
+			return err
+		}
+
+		// Deleting a word cascades to learner progress, decks and assignments, such words stay with their sentences
+		kept, err := usedImportedWords(tx, items)
+		if err != nil {
+			return err
+		}
+		report.WordsKept = len(kept)
+
+		for _, item := range items {
+			switch {
+			case item.EntityType == models.ImportEntitySentence:
+				query := tx.Where("id = ?", item.EntityID)
+				if len(kept) > 0 {
+					query = query.Where("word_id NOT IN ?", kept)
+				}
+				res := query.Delete(&models.Sentence{})
+				if res.Error != nil {
+					return res.Error
+				}
+				report.SentencesDeleted += int(res.RowsAffected)
+
+			case item.EntityType == models.ImportEntityPickOption:
+				query := tx.Where("id = ?", item.EntityID)
+				if len(kept) > 0 {
+					query = query.Where("word_id NOT IN ?", kept)
+				}
+				res := query.Delete(&models.PickOption{})
+				if res.Error != nil {
+					return res.Error
+				}
+				report.OptionsDeleted += int(res.RowsAffected)
+
+			case item.EntityType == models.ImportEntityWord && item.Action == models.ImportActionCreate:
+				if slices.Contains(kept, item.EntityID) {
+					continue
+				}
+				res := tx.Delete(&models.Word{}, "id = ?", item.EntityID)
+				if res.Error != nil {
+					return res.Error
+				}
+				report.WordsDeleted += int(res.RowsAffected)
+
+			case item.EntityType == models.ImportEntityWord:
+				var before map[string]interface{}
+				if err := json.Unmarshal(item.Before, &before); err != nil {
+					return err
+				}
+				res := tx.Model(&models.Word{}).Where("id = ?", item.EntityID).Updates(before)
+				if res.Error != nil {
+					return res.Error
+				}
+				report.WordsRestored += int(res.RowsAffected)
+
+			case item.EntityType == models.ImportEntityTopic:
+				var used int64
+				err := tx.Raw(`SELECT
+					(SELECT count(*) FROM words WHERE topic_id = @id) +
+					(SELECT count(*) FROM topics WHERE parent_id = @id)`,
+					map[string]interface{}{"id": item.EntityID}).Scan(&used).Error
+				if err != nil {
+					return err
+				}
+				if used > 0 {
+					report.TopicsKept++
+					continue
+				}
+				res := tx.Delete(&models.Topic{}, "id = ?", item.EntityID)
+				if res.Error != nil {
+					return res.Error
+				}
+				report.TopicsDeleted += int(res.RowsAffected)
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&run).Updates(map[string]interface{}{
+			"status":         models.ImportRunRolledBack,
+			"rolled_back_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// usedImportedWords returns the words created by the run items that learners have
+// progress, decks or assignments with
+func usedImportedWords(tx *gorm.DB, items []models.ImportRunItem) ([]uuid.UUID, error) {
+	var created []uuid.UUID
+	for _, item := range items {
+		if item.EntityType == models.ImportEntityWord && item.Action == models.ImportActionCreate {
+			created = append(created, item.EntityID)
+		}
+	}
+	if len(created) == 0 {
+		return nil, nil
+	}
+
+	var used []uuid.UUID
+	err := tx.Raw(`SELECT word_id FROM learned_words WHERE word_id IN @ids
+		UNION SELECT word_id FROM not_learned_words WHERE word_id IN @ids
+		UNION SELECT word_id FROM deck_words WHERE word_id IN @ids
+		UNION SELECT word_id FROM assignment_words WHERE word_id IN @ids`,
+		map[string]interface{}{"ids": created}).Scan(&used).Error
+
+	return used, err
+}
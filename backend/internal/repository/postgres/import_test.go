@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestImportUpsert tests that imports create, update and skip words by natural key and can be repeated
+func TestImportUpsert(t *testing.T) {
+	ctx := context.Background()
+	opts := ImportOptions{Source: "words.csv", Actor: "cli:test"}
+
+	rows := []WordImportRow{
+		{Line: 2, Word: "lighthouse", Translation: "маяк", CEFRLevel: "B2", Topics: []string{"Coast", "Buildings"},
+			Sentences: []WordImportSentence{{Sentence: "The lighthouse guides ships.", Translation: "Маяк ведёт корабли.", Options: []string{"guide", "guides", "guided"}}}},
+		{Line: 3, Word: "pier", Translation: "пирс", PartOfSpeech: "noun", Topics: []string{"Coast"}},
+		{Line: 4, Word: "Pier", Translation: "причал", PartOfSpeech: "noun", Topics: []string{"Coast"}},
+	}
+
+	// A dry run reports the changes and keeps nothing
+	report, err := importRepo.Import(ctx, rows, ImportOptions{Source: "words.csv", Actor: "cli:test", DryRun: true})
+	assert.NoError(t, err)
+	assert.Nil(t, report.RunID)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.TopicsCreated)
+	var count int64
+	db.Model(&models.Word{}).Where("word = ?", "lighthouse").Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	first, err := importRepo.Import(ctx, rows, opts)
+	assert.NoError(t, err)
+	if assert.NotNil(t, first.RunID) {
+		assert.Equal(t, 2, first.Created)
+		assert.Equal(t, 1, first.Rejected)
+		assert.Equal(t, 1, first.SentencesAdded)
+		assert.Equal(t, 1, first.OptionsAdded)
+		assert.Equal(t, ImportResultReject, first.Results[2].Action)
+		assert.Contains(t, first.Results[2].Error, "line 3")
+	}
+
+	var lighthouse models.Word
+	assert.NoError(t, db.Where("word = ?", "lighthouse").First(&lighthouse).Error)
+	assert.Equal(t, "unknown", lighthouse.PartOfSpeech)
+
+	// The same file again changes nothing, an edited row updates its word
+	again, err := importRepo.Import(ctx, rows[:2], opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, again.Skipped)
+
+	lighthouse.PartOfSpeech = "noun"
+	assert.NoError(t, db.Save(&lighthouse).Error)
+	edited := []WordImportRow{{Line: 2, Word: "lighthouse", Translation: "маяк", CEFRLevel: "B1", Topics: []string{"Coast", "Buildings"}}}
+	second, err := importRepo.Import(ctx, edited, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, second.Updated, "rows without a part of speech match the enriched word")
+	assert.Equal(t, map[string][2]string{"cefr_level": {"B2", "B1"}}, second.Results[0].Changes)
+
+	// Rolling back the update restores the level, rolling back the first run deletes its words and topics
+	_, err = importRepo.Rollback(ctx, *second.RunID)
+	assert.NoError(t, err)
+	assert.NoError(t, db.First(&lighthouse, "id = ?", lighthouse.ID).Error)
+	assert.Equal(t, "B2", lighthouse.CEFRLevel)
+
+	_, err = importRepo.Rollback(ctx, *second.RunID)
+	assert.ErrorIs(t, err, ErrImportRolledBack)
+
+	undone, err := importRepo.Rollback(ctx, *first.RunID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, undone.WordsDeleted)
+	assert.Equal(t, 1, undone.SentencesDeleted)
+	assert.Equal(t, 1, undone.OptionsDeleted)
+	assert.Equal(t, 2, undone.TopicsDeleted)
+	assert.ErrorIs(t, db.First(&models.Word{}, "id = ?", lighthouse.ID).Error, gorm.ErrRecordNotFound)
+
+	_, err = importRepo.Rollback(ctx, uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// TestImportRollbackKeepsUsedWords tests that rollback keeps created words learners already have progress with
+func TestImportRollbackKeepsUsedWords(t *testing.T) {
+	ctx := context.Background()
+
+	rows := []WordImportRow{
+		{Line: 2, Word: "beacon", PartOfSpeech: "noun", Topics: []string{"Signals"},
+			Sentences: []WordImportSentence{{Sentence: "The beacon was lit.", Options: []string{"beacon", "beacons"}}}},
+		{Line: 3, Word: "flare", PartOfSpeech: "noun", Topics: []string{"Signals"}},
+	}
+	report, err := importRepo.Import(ctx, rows, ImportOptions{Source: "signals.csv", Actor: "cli:test"})
+	assert.NoError(t, err)
+
+	var beacon models.Word
+	assert.NoError(t, db.Where("word = ? AND owner_id IS NULL", "beacon").First(&beacon).Error)
+
+	user := models.User{ID: uuid.New(), Email: "learner-" + uuid.New().String()[:8] + "@example.com", Role: "user", IsActive: true}
+	assert.NoError(t, db.Create(&user).Error)
+	assert.NoError(t, db.Create(&models.LearnedWords{ID: uuid.New(), UserID: user.ID, WordID: beacon.ID}).Error)
+
+	undone, err := importRepo.Rollback(ctx, *report.RunID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, undone.WordsKept)
+	assert.Equal(t, 1, undone.WordsDeleted)
+	assert.Equal(t, 0, undone.SentencesDeleted)
+	assert.Equal(t, 0, undone.OptionsDeleted)
+	assert.Equal(t, 1, undone.TopicsKept)
+
+	var count int64
+	db.Model(&models.LearnedWords{}).Where("word_id = ?", beacon.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+	db.Model(&models.Sentence{}).Where("word_id = ?", beacon.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
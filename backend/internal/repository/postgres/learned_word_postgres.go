@@ -57,18 +57,33 @@ func (r *LearnedWordRepository) IsLearned(ctx context.Context, userID, wordID uu
 
 // Create creates a new learned word
 func (r *LearnedWordRepository) Create(ctx context.Context, lw *models.LearnedWords) error {
-	return r.db.WithContext(ctx).Create(lw).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(lw).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, lw.UserID, lw.WordID)
+	})
 }
 
 // Update updates a learned word
 func (r *LearnedWordRepository) Update(ctx context.Context, lw *models.LearnedWords) error {
-	return r.db.WithContext(ctx).Save(lw).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(lw).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, lw.UserID, lw.WordID)
+	})
 }
 
 // Delete deletes a learned word
 func (r *LearnedWordRepository) Delete(ctx context.Context, userID, wordID uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Delete(&models.LearnedWords{}, "user_id = ? AND word_id = ?", userID, wordID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Delete(&models.LearnedWords{}, "user_id = ? AND word_id = ?", userID, wordID)
+		if deleted.Error != nil || deleted.RowsAffected == 0 {
+			return deleted.Error
+		}
+		return recordChange(tx, userID, wordID)
+	})
 }
 
 // GetRecentlyLearnedWords returns recently learned words for a user with word details
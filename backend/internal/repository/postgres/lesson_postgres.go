@@ -290,6 +290,7 @@ func (r *LessonRepository) getWordsByTopicID(ctx context.Context, userID uuid.UU
 		Model(&models.Word{}).
 		Where("cefr_level = ?", cefrLevel).
 		Where("topic_id = ?", topicID).
+		Where("owner_id IS NULL OR owner_id = ?", userID).
 		Where("id NOT IN (?)", subQuery).
 		Order("RANDOM()").
 		Limit(limit).
@@ -310,6 +311,7 @@ func (r *LessonRepository) getWordsByTopicIDs(ctx context.Context, userID uuid.U
 		Model(&models.Word{}).
 		Where("cefr_level = ?", cefrLevel).
 		Where("topic_id IN ?", topicIDs).
+		Where("owner_id IS NULL OR owner_id = ?", userID).
 		Where("id NOT IN (?)", subQuery).
 		Order("RANDOM()").
 		Limit(limit).
@@ -329,6 +331,7 @@ func (r *LessonRepository) getRandomWordsByCEFRLevelOnly(ctx context.Context, us
 	err := r.db.WithContext(ctx).
 		Model(&models.Word{}).
 		Where("cefr_level = ?", cefrLevel).
+		Where("owner_id IS NULL OR owner_id = ?", userID).
 		Where("id NOT IN (?)", subQuery).
 		Order("RANDOM()").
 		Limit(limit).
@@ -24,6 +24,17 @@ var (
 	learnedWordRepo    *LearnedWordRepository
 	notLearnedWordRepo *NotLearnedWordRepository
 	refreshTokenRepo   *RefreshTokenRepository
+	deckRepo           *DeckRepository
+	captureRepo        *CaptureRepository
+	syncRepo           *SyncRepository
+	gamificationRepo   *GamificationRepository
+	friendRepo         *FriendRepository
+	classroomRepo      *ClassroomRepository
+	serviceAuditRepo   *ServiceAuditRepository
+	identityRepo       *IdentityRepository
+	curationRepo       *CurationRepository
+	importRepo         *ImportRepository
+	exportRepo         *ExportRepository
 )
 
 // Main function for testing postgres operations
@@ -50,10 +61,31 @@ func TestMain(m *testing.M) {
 		&models.LearnedWords{},
 		&models.NotLearnedWords{},
 		&models.RefreshToken{},
+		&models.Deck{},
+		&models.DeckWord{},
+		&models.Capture{},
+		&models.SyncEvent{},
+		&models.UserStats{},
+		&models.XPEvent{},
+		&models.UserAchievement{},
+		&models.Friendship{},
+		&models.FriendInvite{},
+		&models.Classroom{},
+		&models.ClassroomMember{},
+		&models.Assignment{},
+		&models.AssignmentWord{},
+		&models.ServiceTokenAudit{},
+		&models.UserIdentity{},
+		&models.ContentAudit{},
+		&models.ImportRun{},
+		&models.ImportRunItem{},
 	)
 	if err != nil {
 		panic("failed to migrate test database")
 	}
+	if err := EnsureSearchIndexes(db); err != nil {
+		panic("failed to create search indexes")
+	}
 
 	// Initialize repositories
 	userRepo = NewUserRepository(db)
@@ -65,6 +97,17 @@ func TestMain(m *testing.M) {
 	learnedWordRepo = NewLearnedWordRepository(db)
 	notLearnedWordRepo = NewNotLearnedWordRepository(db)
 	refreshTokenRepo = NewRefreshTokenRepository(db)
+	deckRepo = NewDeckRepository(db)
+	captureRepo = NewCaptureRepository(db)
+	syncRepo = NewSyncRepository(db)
+	gamificationRepo = NewGamificationRepository(db)
+	friendRepo = NewFriendRepository(db)
+	classroomRepo = NewClassroomRepository(db)
+	serviceAuditRepo = NewServiceAuditRepository(db)
+	identityRepo = NewIdentityRepository(db)
+	curationRepo = NewCurationRepository(db)
+	importRepo = NewImportRepository(db)
+	exportRepo = NewExportRepository(db)
 
 	// Clear all tables before test
 	db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
@@ -76,6 +119,23 @@ func TestMain(m *testing.M) {
 	db.Exec("TRUNCATE TABLE learned_words RESTART IDENTITY CASCADE")
 	db.Exec("TRUNCATE TABLE not_learned_words RESTART IDENTITY CASCADE")
 	db.Exec("TRUNCATE TABLE refresh_tokens RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE decks RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE deck_words RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE captures RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE sync_events RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE user_stats RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE xp_events RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE user_achievements RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE friendships RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE friend_invites RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE classrooms RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE classroom_members RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE assignments RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE assignment_words RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE service_token_audits RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE user_identities RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE content_audits RESTART IDENTITY CASCADE")
+	db.Exec("TRUNCATE TABLE import_runs RESTART IDENTITY CASCADE")
 
 	// Run tests
 	code := m.Run()
@@ -33,14 +33,35 @@ func (r *NotLearnedWordRepository) Exists(ctx context.Context, userID, wordID uu
 	return true, nil
 }
 
+// ListByUserID returns a list of not learned words for a user
+func (r *NotLearnedWordRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.NotLearnedWords, error) {
+	var words []models.NotLearnedWords
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&words).Error
+
+	return words, err
+}
+
 // Create creates a new not learned word
 func (r *NotLearnedWordRepository) Create(ctx context.Context, nlw *models.NotLearnedWords) error {
-	return r.db.WithContext(ctx).Create(nlw).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(nlw).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, nlw.UserID, nlw.WordID)
+	})
 }
 
 func (r *NotLearnedWordRepository) DeleteIfExists(ctx context.Context, userID, wordID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("user_id = ? AND word_id = ?", userID, wordID).
-		Delete(&models.NotLearnedWords{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Where("user_id = ? AND word_id = ?", userID, wordID).
+			Delete(&models.NotLearnedWords{})
+		if deleted.Error != nil || deleted.RowsAffected == 0 {
+			return deleted.Error
+		}
+		return recordChange(tx, userID, wordID)
+	})
 }
 
 // GetRecentlyNotLearnedWords returns recently not learned words for a user with word details
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for cursors and page keys that don't match the sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page is a keyset page: up to Limit rows after the After key in the sort order.
+// Keys are the sort value and the id of a row, so rows with equal sort values are neither skipped nor repeated.
+type Page struct {
+	Sort  string
+	Desc  bool
+	After []string // key of the last row of the previous page, nil for the first page
+	Limit int
+}
+
+// keysetQuery orders the query by the sort expression and id, skips rows up to the page key and
+// fetches one row more than the limit to tell whether there is a next page
+func keysetQuery(query *gorm.DB, expr string, page Page) (*gorm.DB, error) {
+	op, dir := ">", "ASC"
+	if page.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	if page.After != nil {
+		if len(page.After) != 2 {
+			return nil, ErrInvalidCursor
+		}
+		id, err := uuid.Parse(page.After[1])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", expr, op), page.After[0], id)
+	}
+
+	return query.Order(expr + " " + dir).Order("id " + dir).Limit(page.Limit + 1), nil
+}
+
+// nextKey trims the extra row fetched by keysetQuery and returns the key of the last row kept,
+// nil when there is no next page
+func nextKey[T any](rows []T, limit int, key func(T) []string) ([]T, []string) {
+	if len(rows) <= limit {
+		return rows, nil
+	}
+	rows = rows[:limit]
+	return rows, key(rows[limit-1])
+}
@@ -68,6 +68,12 @@ func (r *PreferenceRepository) Update(ctx context.Context, id uuid.UUID, req *sc
 	if req.AvatarImageURL != nil {
 		updates["avatar_image_url"] = *req.AvatarImageURL
 	}
+	if req.TimeZone != nil {
+		updates["time_zone"] = *req.TimeZone
+	}
+	if req.LeaderboardOptOut != nil {
+		updates["leaderboard_opt_out"] = *req.LeaderboardOptOut
+	}
 
 	if len(updates) == 0 {
 		return nil
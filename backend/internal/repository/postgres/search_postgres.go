@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Words are English with Russian translations, each side is parsed with its own dictionary.
+// The expressions must stay identical to the ones of the indexes for the planner to use them.
+const (
+	wordSearchVector = `(setweight(to_tsvector('english', coalesce(words.word, '')), 'A') || ` +
+		`setweight(to_tsvector('russian', coalesce(words.translation, '')), 'B'))`
+	sentenceSearchVector = `(to_tsvector('english', coalesce(sentences.sentence, '')) || ` +
+		`to_tsvector('russian', coalesce(sentences.translation, '')))`
+
+	// minClosestSimilarity is the trigram similarity a word needs to stand in for another one, "color" for "colour"
+	minClosestSimilarity = 0.4
+)
+
+// searchIndexes are created by EnsureSearchIndexes, AutoMigrate can't express them
+var searchIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_words_search ON words USING GIN (` + wordSearchVector + `)`,
+	`CREATE INDEX IF NOT EXISTS idx_words_word_trgm ON words USING GIN (lower(word) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_words_translation_trgm ON words USING GIN (lower(translation) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_sentences_search ON sentences USING GIN (` + sentenceSearchVector + `)`,
+}
+
+// EnsureSearchIndexes enables pg_trgm and creates the full-text and trigram indexes used by search
+func EnsureSearchIndexes(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+	for _, stmt := range searchIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WordSearchResult is a word found by search with its relevance
+type WordSearchResult struct {
+	models.Word
+	Score float64
+}
+
+// prefixQuery turns user input into a tsquery matching all terms, the last one as a prefix for autocomplete
+func prefixQuery(q string) string {
+	terms := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += ":*"
+	return strings.Join(terms, " & ")
+}
+
+// isCyrillic reports whether the text has Cyrillic letters, so it's searched as a translation
+func isCyrillic(q string) bool {
+	for _, r := range q {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Search finds global words and personal words of the user by word, translation or example sentence.
+// Terms match by stem and the last term as a prefix, so "runn" finds "running"; words that are
+// close by trigrams match too, so "recieve" finds "receive". Matches in the language of the query rank first.
+func (r *WordRepository) Search(ctx context.Context, userID uuid.UUID, q string, limit int) ([]WordSearchResult, error) {
+	raw := strings.ToLower(strings.TrimSpace(q))
+	if raw == "" {
+		return []WordSearchResult{}, nil
+	}
+	tsquery := prefixQuery(raw)
+
+	// ts_rank weights are {D, C, B, A}: words are A, translations B
+	weights, column := "{0.1, 0.2, 0.4, 1.0}", "lower(words.word)"
+	if isCyrillic(raw) {
+		weights, column = "{0.1, 0.2, 1.0, 0.4}", "lower(coalesce(words.translation, ''))"
+	}
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(raw) + "%"
+
+	var hits []struct {
+		ID    uuid.UUID
+		Score float64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		WITH q AS (
+			SELECT to_tsquery('english', @tsquery) || to_tsquery('russian', @tsquery) AS tsq
+		)
+		SELECT words.id,
+			ts_rank(CAST(@weights AS float4[]), `+wordSearchVector+`, q.tsq)
+			+ similarity(`+column+`, @raw)
+			+ CASE WHEN lower(words.word) = @raw OR lower(words.translation) = @raw THEN 1
+				WHEN `+column+` LIKE @pattern THEN 0.5 ELSE 0 END::float8 AS score
+		FROM words, q
+		WHERE (words.owner_id IS NULL OR words.owner_id = @user)
+		AND (
+			`+wordSearchVector+` @@ q.tsq
+			OR lower(words.word) LIKE @pattern
+			OR lower(words.translation) LIKE @pattern
+			OR lower(words.word) % @raw
+			OR lower(words.translation) % @raw
+			OR EXISTS (
+				SELECT 1 FROM sentences
+				WHERE sentences.word_id = words.id
+				AND (sentences.user_id IS NULL OR sentences.user_id = @user)
+				AND `+sentenceSearchVector+` @@ q.tsq
+			)
+		)
+		ORDER BY score DESC, length(words.word), words.word
+		LIMIT @limit`,
+		map[string]interface{}{
+			"tsquery": tsquery,
+			"weights": weights,
+			"raw":     raw,
+			"pattern": pattern,
+			"user":    userID,
+			"limit":   limit,
+		}).Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return []WordSearchResult{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	var words []models.Word
+	if err := r.db.WithContext(ctx).Find(&words, "id IN ?", ids).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Word, len(words))
+	for _, w := range words {
+		byID[w.ID] = w
+	}
+
+	results := make([]WordSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if w, ok := byID[hit.ID]; ok {
+			results = append(results, WordSearchResult{Word: w, Score: hit.Score})
+		}
+	}
+	return results, nil
+}
+
+// FindClosest returns the global word matching the value best: the exact word, then one with the
+// same stem ("runs" for "run"), then the most similar spelling ("color" for "colour").
+// It returns gorm.ErrRecordNotFound when no word is close enough.
+func (r *WordRepository) FindClosest(ctx context.Context, value string) (*models.Word, error) {
+	raw := strings.ToLower(strings.TrimSpace(value))
+	if raw == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var word models.Word
+	err := r.db.WithContext(ctx).
+		Where("owner_id IS NULL").
+		Where(`lower(word) = @raw
+			OR `+wordSearchVector+` @@ plainto_tsquery('english', @raw)
+			OR (lower(word) % @raw AND similarity(lower(word), @raw) >= @min)`,
+			map[string]interface{}{"raw": raw, "min": minClosestSimilarity}).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: "lower(word) = ? DESC, " + wordSearchVector + " @@ plainto_tsquery('english', ?) DESC, " +
+				"similarity(lower(word), ?) DESC, length(word) ASC, word ASC",
+			Vars:               []interface{}{raw, raw, raw},
+			WithoutParentheses: true,
+		}}).
+		Take(&word).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &word, nil
+}
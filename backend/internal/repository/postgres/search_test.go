@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestPrefixQuery tests turning user input into a prefix tsquery
+func TestPrefixQuery(t *testing.T) {
+	assert.Equal(t, "", prefixQuery("  !? "))
+	assert.Equal(t, "runn:*", prefixQuery("Runn"))
+	assert.Equal(t, "take & of:*", prefixQuery("take-of"))
+	assert.Equal(t, "кош:*", prefixQuery("кош"))
+	assert.True(t, isCyrillic("кошка"))
+	assert.False(t, isCyrillic("cat"))
+}
+
+// TestSearchWords tests prefix, fuzzy, translation and sentence matches
+func TestSearchWords(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Searcher")
+	other := newFriendTestUser(t, "Stranger")
+
+	receive := &models.Word{ID: uuid.New(), Word: "receive", Translation: "получать", PartOfSpeech: "verb"}
+	receipt := &models.Word{ID: uuid.New(), Word: "receipt", Translation: "квитанция", PartOfSpeech: "noun"}
+	harbor := &models.Word{ID: uuid.New(), Word: "harbor", Translation: "гавань", PartOfSpeech: "noun"}
+	personal := &models.Word{ID: uuid.New(), Word: "recess", Translation: "перерыв", PartOfSpeech: "noun", OwnerID: &other.ID}
+	for _, w := range []*models.Word{receive, receipt, harbor, personal} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+	assert.NoError(t, sentenceRepo.Create(ctx, &models.Sentence{ID: uuid.New(), WordID: harbor.ID, Sentence: "Boats anchored for the night."}))
+
+	ids := func(results []WordSearchResult) []uuid.UUID {
+		out := make([]uuid.UUID, 0, len(results))
+		for _, r := range results {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	results, err := wordRepo.Search(ctx, user.ID, "rece", 10)
+	assert.NoError(t, err)
+	assert.Contains(t, ids(results), receive.ID)
+	assert.Contains(t, ids(results), receipt.ID)
+	assert.NotContains(t, ids(results), personal.ID, "personal words of other users are hidden")
+
+	results, err = wordRepo.Search(ctx, user.ID, "recieve", 10)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, results) {
+		assert.Equal(t, receive.ID, results[0].ID, "typos match by similarity")
+	}
+
+	results, err = wordRepo.Search(ctx, user.ID, "гавань", 10)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, results) {
+		assert.Equal(t, harbor.ID, results[0].ID)
+		assert.Greater(t, results[0].Score, 1.0, "exact matches rank first")
+	}
+
+	results, err = wordRepo.Search(ctx, user.ID, "boat", 10)
+	assert.NoError(t, err)
+	assert.Contains(t, ids(results), harbor.ID, "words match by their sentences")
+
+	results, err = wordRepo.Search(ctx, user.ID, "  ", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+// TestFindClosest tests resolving words by exact value, stem and spelling
+func TestFindClosest(t *testing.T) {
+	ctx := context.Background()
+
+	colour := &models.Word{ID: uuid.New(), Word: "colour", PartOfSpeech: "noun"}
+	run := &models.Word{ID: uuid.New(), Word: "run", PartOfSpeech: "verb"}
+	for _, w := range []*models.Word{colour, run} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+
+	word, err := wordRepo.FindClosest(ctx, "Run")
+	assert.NoError(t, err)
+	assert.Equal(t, run.ID, word.ID)
+
+	word, err = wordRepo.FindClosest(ctx, "runs")
+	assert.NoError(t, err)
+	assert.Equal(t, run.ID, word.ID)
+
+	word, err = wordRepo.FindClosest(ctx, "colours")
+	assert.NoError(t, err)
+	assert.Equal(t, colour.ID, word.ID)
+
+	_, err = wordRepo.FindClosest(ctx, "xylophone")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -53,13 +53,39 @@ func (r *SentenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	return &s, nil
 }
 
-// GetByWordID returns a list of sentences for a word
+// GetByWordID returns a list of shared sentences for a word
 func (r *SentenceRepository) GetByWordID(ctx context.Context, wordID uuid.UUID) ([]models.Sentence, error) {
 	var sentences []models.Sentence
-	err := r.db.WithContext(ctx).Find(&sentences, "word_id = ?", wordID).Error
+	err := r.db.WithContext(ctx).Find(&sentences, "word_id = ? AND user_id IS NULL", wordID).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return sentences, nil
 }
+
+// GetByWordIDForUser returns shared sentences for a word together with the ones the user captured, captured first
+func (r *SentenceRepository) GetByWordIDForUser(ctx context.Context, wordID, userID uuid.UUID) ([]models.Sentence, error) {
+	var sentences []models.Sentence
+	err := r.db.WithContext(ctx).
+		Where("word_id = ?", wordID).
+		Where("user_id IS NULL OR user_id = ?", userID).
+		Order("user_id IS NULL").
+		Find(&sentences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sentences, nil
+}
+
+// GetUserSentence returns a sentence captured by the user for a word by its text
+func (r *SentenceRepository) GetUserSentence(ctx context.Context, userID, wordID uuid.UUID, text string) (*models.Sentence, error) {
+	var s models.Sentence
+	err := r.db.WithContext(ctx).First(&s, "user_id = ? AND word_id = ? AND sentence = ?", userID, wordID, text).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
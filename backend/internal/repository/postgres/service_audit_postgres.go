@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ServiceAuditRepository is a repository for the audit log of tokens minted by services
+type ServiceAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceAuditRepository creates a new instance of ServiceAuditRepository
+func NewServiceAuditRepository(db *gorm.DB) *ServiceAuditRepository {
+	return &ServiceAuditRepository{db: db}
+}
+
+// Create records a minted token
+func (r *ServiceAuditRepository) Create(ctx context.Context, audit *models.ServiceTokenAudit) error {
+	return r.db.WithContext(ctx).Create(audit).Error
+}
+
+// ListByUserID returns tokens minted on behalf of the user, newest first
+func (r *ServiceAuditRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]models.ServiceTokenAudit, error) {
+	var audits []models.ServiceTokenAudit
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&audits).Error
+
+	return audits, err
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestServiceAudit tests recording and listing minted tokens
+func TestServiceAudit(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Audited")
+
+	for _, action := range []string{"telegram.get_tokens", "telegram.get_tokens"} {
+		assert.NoError(t, serviceAuditRepo.Create(ctx, &models.ServiceTokenAudit{
+			ServiceID:  "telegram-bot-1",
+			Action:     action,
+			UserID:     user.ID,
+			TelegramID: 42,
+		}))
+	}
+
+	audits, err := serviceAuditRepo.ListByUserID(ctx, user.ID, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, audits, 1) {
+		assert.Equal(t, "telegram-bot-1", audits[0].ServiceID)
+		assert.Equal(t, int64(42), audits[0].TelegramID)
+	}
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SyncChange is a word whose progress changed after a cursor
+type SyncChange struct {
+	WordID uuid.UUID
+	Seq    int64
+}
+
+// SyncRepository is a repository for offline progress sync
+type SyncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository creates a new instance of SyncRepository
+func NewSyncRepository(db *gorm.DB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// Apply stores an event and applies it to learned_words and not_learned_words
+// unless a newer event for the same word already won. Events of one user are
+// applied one at a time, so the result doesn't depend on request interleaving.
+// moved reports whether the word changed lists, reviews of a learned word don't.
+func (r *SyncRepository) Apply(ctx context.Context, event *models.SyncEvent) (status string, moved bool, err error) {
+	status = models.SyncStatusApplied
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", event.UserID.String()).Error; err != nil {
+			return err
+		}
+
+		var existing models.SyncEvent
+		err := tx.First(&existing, "user_id = ? AND idempotency_key = ?", event.UserID, event.IdempotencyKey).Error
+		if err == nil {
+			status = models.SyncStatusDuplicate
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var lw *models.LearnedWords
+		var learned models.LearnedWords
+		err = tx.First(&learned, "user_id = ? AND word_id = ?", event.UserID, event.WordID).Error
+		if err == nil {
+			lw = &learned
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// A review only updates progress, it never learns or unlearns a word
+		if event.Type == models.SyncEventReviewed && lw == nil {
+			status = models.SyncStatusSkipped
+			return tx.Create(event).Error
+		}
+
+		newer, err := r.isNewer(tx, event, lw)
+		if err != nil {
+			return err
+		}
+
+		event.Applied = newer
+		if !newer {
+			status = models.SyncStatusSuperseded
+			return tx.Create(event).Error
+		}
+
+		switch event.Type {
+		case models.SyncEventLearned:
+			moved = lw == nil
+			err = applyLearned(tx, event, lw)
+		case models.SyncEventNotLearned:
+			moved, err = applyNotLearned(tx, event)
+		case models.SyncEventReviewed:
+			err = applyReviewed(tx, event, lw)
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return status, moved, nil
+}
+
+// isNewer checks the event against the last applied event for the word, and against
+// the learned word itself for progress written before sync existed
+func (r *SyncRepository) isNewer(tx *gorm.DB, event *models.SyncEvent, lw *models.LearnedWords) (bool, error) {
+	var last models.SyncEvent
+	err := tx.
+		Where("user_id = ? AND word_id = ? AND applied", event.UserID, event.WordID).
+		Order("client_time DESC, idempotency_key DESC").
+		First(&last).Error
+	if err == nil && !event.Wins(&last) {
+		return false, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	if lw != nil && lw.LastReviewed.After(event.ClientTime) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// applyLearned creates or updates the learned word. Revision counts only grow,
+// so a device that missed some reviews can't decrease them.
+func applyLearned(tx *gorm.DB, event *models.SyncEvent, lw *models.LearnedWords) error {
+	if lw == nil {
+		lw = &models.LearnedWords{
+			ID:        uuid.New(),
+			UserID:    event.UserID,
+			WordID:    event.WordID,
+			LearnedAt: event.ClientTime,
+		}
+	}
+
+	lw.LastReviewed = event.ClientTime
+	if event.ConfidenceScore != nil {
+		lw.ConfidenceScore = *event.ConfidenceScore
+	}
+	if event.CntReviewed != nil && *event.CntReviewed > lw.CountOfRevisions {
+		lw.CountOfRevisions = *event.CntReviewed
+	}
+
+	if err := tx.Save(lw).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("user_id = ? AND word_id = ?", event.UserID, event.WordID).
+		Delete(&models.NotLearnedWords{}).Error
+}
+
+// applyReviewed counts a review of a learned word with its confidence, a failed review
+// lowers the confidence and keeps the word learned with its revisions
+func applyReviewed(tx *gorm.DB, event *models.SyncEvent, lw *models.LearnedWords) error {
+	lw.LastReviewed = event.ClientTime
+	lw.CountOfRevisions++
+	if event.ConfidenceScore != nil {
+		lw.ConfidenceScore = *event.ConfidenceScore
+	}
+
+	return tx.Save(lw).Error
+}
+
+// applyNotLearned moves the word back to the not learned list and reports whether it wasn't there
+func applyNotLearned(tx *gorm.DB, event *models.SyncEvent) (bool, error) {
+	deleted := tx.Where("user_id = ? AND word_id = ?", event.UserID, event.WordID).
+		Delete(&models.LearnedWords{})
+	if deleted.Error != nil {
+		return false, deleted.Error
+	}
+
+	var count int64
+	if err := tx.Model(&models.NotLearnedWords{}).
+		Where("user_id = ? AND word_id = ?", event.UserID, event.WordID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return deleted.RowsAffected > 0, nil
+	}
+
+	return true, tx.Create(&models.NotLearnedWords{
+		ID:     uuid.New(),
+		UserID: event.UserID,
+		WordID: event.WordID,
+	}).Error
+}
+
+// recordChange adds progress written outside sync to the change feed, so offline clients
+// pull it too. It's an applied event, a client event older than the write loses to it.
+func recordChange(tx *gorm.DB, userID, wordID uuid.UUID) error {
+	return tx.Create(&models.SyncEvent{
+		UserID:         userID,
+		IdempotencyKey: "server:" + uuid.NewString(),
+		DeviceID:       "server",
+		Type:           models.SyncEventChanged,
+		WordID:         wordID,
+		ClientTime:     time.Now(),
+		Applied:        true,
+	}).Error
+}
+
+// ListChanges returns words whose progress changed after the cursor, in cursor order
+func (r *SyncRepository) ListChanges(ctx context.Context, userID uuid.UUID, cursor int64, limit int) ([]SyncChange, error) {
+	var changes []SyncChange
+	err := r.db.WithContext(ctx).
+		Model(&models.SyncEvent{}).
+		Select("word_id, MAX(seq) AS seq").
+		Where("user_id = ? AND applied", userID).
+		Group("word_id").
+		Having("MAX(seq) > ?", cursor).
+		Order("seq ASC").
+		Limit(limit).
+		Scan(&changes).Error
+
+	return changes, err
+}
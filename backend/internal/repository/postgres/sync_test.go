@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSyncConflicts tests idempotency and last-writer-wins resolution of sync events
+func TestSyncConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Name:      "Sync User",
+		Email:     "sync-" + uuid.New().String()[:8] + "@example.com",
+		Role:      "user",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, userRepo.Create(ctx, user))
+
+	word := &models.Word{ID: uuid.New(), Word: "harbor", Translation: "гавань", PartOfSpeech: "noun"}
+	assert.NoError(t, wordRepo.Create(ctx, word))
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	confidence := 80
+
+	learned := &models.SyncEvent{
+		UserID:          user.ID,
+		IdempotencyKey:  "phone-1",
+		Type:            models.SyncEventLearned,
+		WordID:          word.ID,
+		ClientTime:      base.Add(10 * time.Minute),
+		ConfidenceScore: &confidence,
+	}
+	status, moved, err := syncRepo.Apply(ctx, learned)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusApplied, status)
+	assert.True(t, moved, "the word moved to the learned list")
+
+	// Resending the same event is a no-op
+	status, moved, err = syncRepo.Apply(ctx, &models.SyncEvent{
+		UserID:         user.ID,
+		IdempotencyKey: "phone-1",
+		Type:           models.SyncEventLearned,
+		WordID:         word.ID,
+		ClientTime:     base.Add(10 * time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusDuplicate, status)
+	assert.False(t, moved)
+
+	// An older event from another device loses
+	status, moved, err = syncRepo.Apply(ctx, &models.SyncEvent{
+		UserID:         user.ID,
+		IdempotencyKey: "tablet-1",
+		Type:           models.SyncEventNotLearned,
+		WordID:         word.ID,
+		ClientTime:     base,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusSuperseded, status)
+	assert.False(t, moved)
+
+	isLearned, err := learnedWordRepo.IsLearned(ctx, user.ID, word.ID)
+	assert.NoError(t, err)
+	assert.True(t, isLearned)
+
+	// A newer one wins and moves the word back to not learned
+	status, moved, err = syncRepo.Apply(ctx, &models.SyncEvent{
+		UserID:         user.ID,
+		IdempotencyKey: "tablet-2",
+		Type:           models.SyncEventNotLearned,
+		WordID:         word.ID,
+		ClientTime:     base.Add(20 * time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusApplied, status)
+	assert.True(t, moved)
+
+	isLearned, err = learnedWordRepo.IsLearned(ctx, user.ID, word.ID)
+	assert.NoError(t, err)
+	assert.False(t, isLearned)
+
+	exists, err := notLearnedWordRepo.Exists(ctx, user.ID, word.ID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// Another miss of a not learned word applies without moving it
+	status, moved, err = syncRepo.Apply(ctx, &models.SyncEvent{
+		UserID:         user.ID,
+		IdempotencyKey: "tablet-3",
+		Type:           models.SyncEventNotLearned,
+		WordID:         word.ID,
+		ClientTime:     base.Add(30 * time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusApplied, status)
+	assert.False(t, moved)
+
+	changes, err := syncRepo.ListChanges(ctx, user.ID, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, word.ID, changes[0].WordID)
+
+	changes, err = syncRepo.ListChanges(ctx, user.ID, changes[0].Seq, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+// TestSyncReview tests that reviews update learned words only and never change their list
+func TestSyncReview(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{ID: uuid.New(), Name: "Review User", Email: "review-" + uuid.New().String()[:8] + "@example.com", Role: "user", IsActive: true}
+	assert.NoError(t, userRepo.Create(ctx, user))
+	word := &models.Word{ID: uuid.New(), Word: "lantern", PartOfSpeech: "noun"}
+	assert.NoError(t, wordRepo.Create(ctx, word))
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	low, high := 20, 90
+
+	// A review of a word that isn't learned doesn't learn it
+	status, moved, err := syncRepo.Apply(ctx, &models.SyncEvent{UserID: user.ID, IdempotencyKey: "review-1", Type: models.SyncEventReviewed, WordID: word.ID, ClientTime: base, ConfidenceScore: &high})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusSkipped, status)
+	assert.False(t, moved)
+	isLearned, err := learnedWordRepo.IsLearned(ctx, user.ID, word.ID)
+	assert.NoError(t, err)
+	assert.False(t, isLearned)
+
+	revisions := 5
+	_, _, err = syncRepo.Apply(ctx, &models.SyncEvent{UserID: user.ID, IdempotencyKey: "learned-1", Type: models.SyncEventLearned, WordID: word.ID, ClientTime: base.Add(time.Minute), ConfidenceScore: &high, CntReviewed: &revisions})
+	assert.NoError(t, err)
+
+	// A failed review lowers the confidence and keeps the word with its revisions
+	status, moved, err = syncRepo.Apply(ctx, &models.SyncEvent{UserID: user.ID, IdempotencyKey: "review-2", Type: models.SyncEventReviewed, WordID: word.ID, ClientTime: base.Add(2 * time.Minute), ConfidenceScore: &low})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusApplied, status)
+	assert.False(t, moved)
+
+	lw, err := learnedWordRepo.GetByUserWordID(ctx, user.ID, word.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, low, lw.ConfidenceScore)
+	assert.Equal(t, 6, lw.CountOfRevisions)
+}
+
+// TestSyncChangesOfOtherWrites tests that progress written through the learned words API shows up in the change feed
+func TestSyncChangesOfOtherWrites(t *testing.T) {
+	ctx := context.Background()
+
+	user := &models.User{ID: uuid.New(), Name: "Feed User", Email: "feed-" + uuid.New().String()[:8] + "@example.com", Role: "user", IsActive: true}
+	assert.NoError(t, userRepo.Create(ctx, user))
+	learned := &models.Word{ID: uuid.New(), Word: "compass", PartOfSpeech: "noun"}
+	assert.NoError(t, wordRepo.Create(ctx, learned))
+	missed := &models.Word{ID: uuid.New(), Word: "anchor", PartOfSpeech: "noun"}
+	assert.NoError(t, wordRepo.Create(ctx, missed))
+
+	lw := &models.LearnedWords{ID: uuid.New(), UserID: user.ID, WordID: learned.ID, LearnedAt: time.Now(), LastReviewed: time.Now()}
+	assert.NoError(t, learnedWordRepo.Create(ctx, lw))
+	assert.NoError(t, notLearnedWordRepo.Create(ctx, &models.NotLearnedWords{ID: uuid.New(), UserID: user.ID, WordID: missed.ID}))
+
+	changes, err := syncRepo.ListChanges(ctx, user.ID, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, learned.ID, changes[0].WordID)
+		assert.Equal(t, missed.ID, changes[1].WordID)
+	}
+	cursor := changes[len(changes)-1].Seq
+
+	// Updates and deletes move the word past the cursor
+	lw.ConfidenceScore = 70
+	assert.NoError(t, learnedWordRepo.Update(ctx, lw))
+	assert.NoError(t, notLearnedWordRepo.DeleteIfExists(ctx, user.ID, missed.ID))
+
+	changes, err = syncRepo.ListChanges(ctx, user.ID, cursor, 10)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	cursor = changes[len(changes)-1].Seq
+
+	assert.NoError(t, learnedWordRepo.Delete(ctx, user.ID, learned.ID))
+	changes, err = syncRepo.ListChanges(ctx, user.ID, cursor, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, learned.ID, changes[0].WordID)
+	}
+
+	// An offline event older than the write loses to it
+	status, _, err := syncRepo.Apply(ctx, &models.SyncEvent{UserID: user.ID, IdempotencyKey: "stale-1", Type: models.SyncEventLearned, WordID: learned.ID, ClientTime: time.Now().Add(-time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStatusSuperseded, status)
+}
@@ -64,28 +64,60 @@ func (r *TopicRepository) GetSiblingsByParentID(ctx context.Context, parentID uu
 // GetTopicHierarchy returns the full path from root to the given topic
 func (r *TopicRepository) GetTopicHierarchy(ctx context.Context, topicID uuid.UUID) ([]models.Topic, error) {
 	var hierarchy []models.Topic
-
-	currentTopic, err := r.GetByID(ctx, topicID)
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE path AS (
+			SELECT id, title, parent_id, 0 AS depth FROM topics WHERE id = ?
+			UNION ALL
+			SELECT topics.id, topics.title, topics.parent_id, path.depth + 1
+			FROM topics JOIN path ON topics.id = path.parent_id
+		)
+		SELECT id, title, parent_id FROM path ORDER BY depth DESC`, topicID).
+		Scan(&hierarchy).Error
 	if err != nil {
 		return nil, err
 	}
-
-	// Add current topic
-	hierarchy = append(hierarchy, *currentTopic)
-
-	// Traverse up the hierarchy
-	for currentTopic.ParentID != nil {
-		parentTopic, err := r.GetByID(ctx, *currentTopic.ParentID)
-		if err != nil {
-			return nil, err
-		}
-		hierarchy = append([]models.Topic{*parentTopic}, hierarchy...)
-		currentTopic = parentTopic
+	if len(hierarchy) == 0 {
+		return nil, gorm.ErrRecordNotFound
 	}
 
 	return hierarchy, nil
 }
 
+// TopicWithRoot is a topic with the title of its main topic
+type TopicWithRoot struct {
+	ID        uuid.UUID
+	Title     string
+	RootTitle string // title of the main topic, the topic's own title for main topics
+}
+
+// GetWithRoots returns the topics with the given ids and their main topics by topic id, in one query.
+// Ids of missing topics are left out.
+func (r *TopicRepository) GetWithRoots(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]TopicWithRoot, error) {
+	topics := make(map[uuid.UUID]TopicWithRoot, len(ids))
+	if len(ids) == 0 {
+		return topics, nil
+	}
+
+	var rows []TopicWithRoot
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE up AS (
+			SELECT id AS topic_id, title AS topic_title, id, title, parent_id FROM topics WHERE id IN ?
+			UNION ALL
+			SELECT up.topic_id, up.topic_title, topics.id, topics.title, topics.parent_id
+			FROM topics JOIN up ON topics.id = up.parent_id
+		)
+		SELECT topic_id AS id, topic_title AS title, title AS root_title FROM up WHERE parent_id IS NULL`, ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		topics[row.ID] = row
+	}
+	return topics, nil
+}
+
 // GetMainTopics returns all main topics (topics with no parent)
 func (r *TopicRepository) GetMainTopics(ctx context.Context) ([]models.Topic, error) {
 	var topics []models.Topic
@@ -121,14 +153,33 @@ func (r *TopicRepository) GetAll(ctx context.Context) ([]models.Topic, error) {
 	return topics, nil
 }
 
-// GetAllStartingWithCapital returns all topics that start with a capital letter
-func (r *TopicRepository) GetAllStartingWithCapital(ctx context.Context) ([]models.Topic, error) {
+// TopicListFilter filters topics
+type TopicListFilter struct {
+	ParentID *uuid.UUID // direct subtopics of the topic
+	Root     bool       // main topics only, ParentID is ignored
+}
+
+// List returns a page of topics matching the filter sorted by title and the key of the next page, nil on the last page
+func (r *TopicRepository) List(ctx context.Context, filter TopicListFilter, page Page) ([]models.Topic, []string, error) {
+	query := r.db.WithContext(ctx).Model(&models.Topic{})
+	if filter.Root {
+		query = query.Where("parent_id IS NULL")
+	} else if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	}
+
+	query, err := keysetQuery(query, "title", page)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var topics []models.Topic
-	if err := r.db.WithContext(ctx).
-		Where("title ~ '^[A-Z]'").
-		Find(&topics).Error; err != nil {
-		return nil, err
+	if err := query.Find(&topics).Error; err != nil {
+		return nil, nil, err
 	}
 
-	return topics, nil
+	topics, next := nextKey(topics, page.Limit, func(t models.Topic) []string {
+		return []string{t.Title, t.ID.String()}
+	})
+	return topics, next, nil
 }
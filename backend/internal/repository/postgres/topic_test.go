@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"fluently/go-backend/internal/repository/models"
 
@@ -52,3 +53,58 @@ func TestNestedTopics(t *testing.T) {
 	assert.NotNil(t, found.ParentID)
 	assert.Equal(t, main.ID, *found.ParentID)
 }
+
+// TestTopicTree tests word counts and progress rolled up from subtopics
+func TestTopicTree(t *testing.T) {
+	ctx := context.Background()
+	user := newFriendTestUser(t, "Climber")
+
+	root := &models.Topic{ID: uuid.New(), Title: "Travel"}
+	child := &models.Topic{ID: uuid.New(), Title: "Airport", ParentID: &root.ID}
+	for _, topic := range []*models.Topic{root, child} {
+		assert.NoError(t, topicRepo.Create(ctx, topic))
+	}
+
+	ticket := &models.Word{ID: uuid.New(), Word: "ticket", PartOfSpeech: "noun", CEFRLevel: "A1", TopicID: &root.ID}
+	gate := &models.Word{ID: uuid.New(), Word: "gate", PartOfSpeech: "noun", CEFRLevel: "A2", TopicID: &child.ID}
+	luggage := &models.Word{ID: uuid.New(), Word: "luggage", PartOfSpeech: "noun", CEFRLevel: "A2", TopicID: &child.ID}
+	for _, w := range []*models.Word{ticket, gate, luggage} {
+		assert.NoError(t, wordRepo.Create(ctx, w))
+	}
+	assert.NoError(t, learnedWordRepo.Create(ctx, &models.LearnedWords{ID: uuid.New(), UserID: user.ID, WordID: gate.ID, LearnedAt: time.Now()}))
+	assert.NoError(t, notLearnedWordRepo.Create(ctx, &models.NotLearnedWords{ID: uuid.New(), UserID: user.ID, WordID: ticket.ID}))
+
+	roots, err := topicRepo.GetTree(ctx, user.ID)
+	assert.NoError(t, err)
+
+	var node *TopicNode
+	for _, r := range roots {
+		if r.ID == root.ID {
+			node = r
+		}
+	}
+	if assert.NotNil(t, node) {
+		assert.Equal(t, 3, node.Words)
+		assert.Equal(t, map[string]int{"A1": 1, "A2": 2}, node.WordsByLevel)
+		assert.Equal(t, 1, node.Learned)
+		assert.Equal(t, 1, node.NotLearned)
+		if assert.Len(t, node.Children, 1) {
+			assert.Equal(t, child.ID, node.Children[0].ID)
+			assert.Equal(t, 2, node.Children[0].Words)
+			assert.Equal(t, 0, node.Children[0].NotLearned)
+		}
+	}
+
+	topics, err := topicRepo.GetWithRoots(ctx, []uuid.UUID{child.ID, root.ID, uuid.New()})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 2)
+	assert.Equal(t, "Travel", topics[child.ID].RootTitle)
+	assert.Equal(t, "Airport", topics[child.ID].Title)
+	assert.Equal(t, "Travel", topics[root.ID].RootTitle)
+
+	hierarchy, err := topicRepo.GetTopicHierarchy(ctx, child.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, hierarchy, 2) {
+		assert.Equal(t, root.ID, hierarchy[0].ID)
+	}
+}
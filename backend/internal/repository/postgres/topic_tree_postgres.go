@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TopicNode is a topic of the tree with the progress of a user.
+// Counts cover the topic and all of its subtopics.
+type TopicNode struct {
+	ID           uuid.UUID
+	Title        string
+	ParentID     *uuid.UUID
+	Words        int            // global words of the topic
+	WordsByLevel map[string]int // global words by CEFR level, words without a level are only in Words
+	Learned      int            // words the user has learned
+	NotLearned   int            // words the user has marked as not learned
+	Children     []*TopicNode
+}
+
+// topicTreeRow is a row of the tree query, one per topic and CEFR level of its words
+type topicTreeRow struct {
+	ID         uuid.UUID
+	Title      string
+	ParentID   *uuid.UUID
+	CEFRLevel  *string `gorm:"column:cefr_level"`
+	Words      int
+	Learned    int
+	NotLearned int
+}
+
+// GetTree returns the main topics with their subtopics, sorted by title, with word counts and
+// the progress of the user. Topics whose parent is missing are left out.
+func (r *TopicRepository) GetTree(ctx context.Context, userID uuid.UUID) ([]*TopicNode, error) {
+	var rows []topicTreeRow
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id, title, parent_id, 0 AS depth FROM topics WHERE parent_id IS NULL
+			UNION ALL
+			SELECT topics.id, topics.title, topics.parent_id, tree.depth + 1
+			FROM topics JOIN tree ON topics.parent_id = tree.id
+		),
+		levels AS (
+			SELECT topic_id, cefr_level, count(*) AS words
+			FROM words
+			WHERE topic_id IS NOT NULL AND owner_id IS NULL
+			GROUP BY topic_id, cefr_level
+		),
+		progress AS (
+			SELECT words.topic_id,
+				count(DISTINCT learned_words.word_id) AS learned,
+				count(DISTINCT not_learned_words.word_id) AS not_learned
+			FROM words
+			LEFT JOIN learned_words ON learned_words.word_id = words.id AND learned_words.user_id = @user
+			LEFT JOIN not_learned_words ON not_learned_words.word_id = words.id AND not_learned_words.user_id = @user
+			WHERE words.topic_id IS NOT NULL
+			GROUP BY words.topic_id
+		)
+		SELECT tree.id, tree.title, tree.parent_id, NULLIF(levels.cefr_level, '') AS cefr_level,
+			coalesce(levels.words, 0) AS words,
+			coalesce(progress.learned, 0) AS learned,
+			coalesce(progress.not_learned, 0) AS not_learned
+		FROM tree
+		LEFT JOIN levels ON levels.topic_id = tree.id
+		LEFT JOIN progress ON progress.topic_id = tree.id
+		ORDER BY tree.depth, tree.title, tree.id`,
+		map[string]interface{}{"user": userID}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows come parents first, so a parent is always known before its children
+	nodes := make(map[uuid.UUID]*TopicNode)
+	var order []*TopicNode
+	var roots []*TopicNode
+	for _, row := range rows {
+		node, ok := nodes[row.ID]
+		if !ok {
+			node = &TopicNode{
+				ID:           row.ID,
+				Title:        row.Title,
+				ParentID:     row.ParentID,
+				WordsByLevel: map[string]int{},
+				Learned:      row.Learned,
+				NotLearned:   row.NotLearned,
+				Children:     []*TopicNode{},
+			}
+			nodes[row.ID] = node
+			order = append(order, node)
+			if row.ParentID == nil {
+				roots = append(roots, node)
+			} else {
+				parent := nodes[*row.ParentID]
+				parent.Children = append(parent.Children, node)
+			}
+		}
+		node.Words += row.Words
+		if row.CEFRLevel != nil {
+			node.WordsByLevel[*row.CEFRLevel] += row.Words
+		}
+	}
+
+	// Add up the counts from the deepest topics to the main ones
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		if node.ParentID == nil {
+			continue
+		}
+		parent := nodes[*node.ParentID]
+		parent.Words += node.Words
+		parent.Learned += node.Learned
+		parent.NotLearned += node.NotLearned
+		for level, count := range node.WordsByLevel {
+			parent.WordsByLevel[level] += count
+		}
+	}
+
+	if roots == nil {
+		roots = []*TopicNode{}
+	}
+	return roots, nil
+}
@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"fluently/go-backend/internal/repository/models"
 )
@@ -20,14 +22,56 @@ func NewWordRepository(db *gorm.DB) *WordRepository {
 	return &WordRepository{db: db}
 }
 
-// ListWords returns all words
-func (r *WordRepository) ListWords(ctx context.Context) ([]models.Word, error) {
+// WordListFilter filters global words
+type WordListFilter struct {
+	CEFRLevel    string
+	PartOfSpeech string
+	TopicID      *uuid.UUID // words of the topic and all its subtopics
+}
+
+// wordSorts are the sort keys of word listings with their SQL expression and value
+var wordSorts = map[string]struct {
+	expr  string
+	value func(models.Word) string
+}{
+	"word":           {"word", func(w models.Word) string { return w.Word }},
+	"cefr_level":     {"COALESCE(cefr_level, '')", func(w models.Word) string { return w.CEFRLevel }},
+	"part_of_speech": {"part_of_speech", func(w models.Word) string { return w.PartOfSpeech }},
+}
+
+// ListWords returns a page of global words matching the filter and the key of the next page,
+// nil on the last page. Pages are sorted by word, cefr_level or part_of_speech.
+func (r *WordRepository) ListWords(ctx context.Context, filter WordListFilter, page Page) ([]models.Word, []string, error) {
+	sort, ok := wordSorts[page.Sort]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown word sort %q", page.Sort)
+	}
+
+	query := r.db.WithContext(ctx).Where("owner_id IS NULL")
+	if filter.CEFRLevel != "" {
+		query = query.Where("cefr_level = ?", filter.CEFRLevel)
+	}
+	if filter.PartOfSpeech != "" {
+		query = query.Where("part_of_speech = ?", filter.PartOfSpeech)
+	}
+	if filter.TopicID != nil {
+		query = query.Where("topic_id IN ("+topicSubtreeIDs+")", *filter.TopicID)
+	}
+
+	query, err := keysetQuery(query, sort.expr, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var words []models.Word
-	if err := r.db.WithContext(ctx).Find(&words).Error; err != nil {
-		return nil, err
+	if err := query.Find(&words).Error; err != nil {
+		return nil, nil, err
 	}
 
-	return words, nil
+	words, next := nextKey(words, page.Limit, func(w models.Word) []string {
+		return []string{sort.value(w), w.ID.String()}
+	})
+	return words, next, nil
 }
 
 // GetByID returns a word by id
@@ -40,26 +84,122 @@ func (r *WordRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Wor
 	return &word, nil
 }
 
-// GetByValue returns a word by value
+// GetByIDs returns the words with the ids, missing ids are skipped
+func (r *WordRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Word, error) {
+	var words []models.Word
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&words).Error
+
+	return words, err
+}
+
+// GetByValue returns a global word by value
 func (r *WordRepository) GetByValue(ctx context.Context, value string) (*models.Word, error) {
 	var word models.Word
-	if err := r.db.WithContext(ctx).First(&word, "word = ?", value).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&word, "word = ? AND owner_id IS NULL", value).Error; err != nil {
+		return nil, err
+	}
+
+	return &word, nil
+}
+
+// GetPersonalByValue returns a personal word of the owner by value
+func (r *WordRepository) GetPersonalByValue(ctx context.Context, ownerID uuid.UUID, value string) (*models.Word, error) {
+	var word models.Word
+	if err := r.db.WithContext(ctx).First(&word, "word = ? AND owner_id = ?", value, ownerID).Error; err != nil {
 		return nil, err
 	}
 
 	return &word, nil
 }
 
-// GetByWordTranslationPair returns a word by word and translation
+// GetByWordTranslationPair returns a global word by word and translation
 func (r *WordRepository) GetByWordTranslationPair(ctx context.Context, word, translation string) (*models.Word, error) {
 	var wordModel models.Word
-	if err := r.db.WithContext(ctx).First(&wordModel, "word = ? AND translation = ?", word, translation).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&wordModel, "word = ? AND translation = ? AND owner_id IS NULL", word, translation).Error; err != nil {
 		return nil, err
 	}
 
 	return &wordModel, nil
 }
 
+// GetPersonalByWordTranslationPair returns a personal word of the owner by word and translation
+func (r *WordRepository) GetPersonalByWordTranslationPair(ctx context.Context, ownerID uuid.UUID, word, translation string) (*models.Word, error) {
+	var wordModel models.Word
+	if err := r.db.WithContext(ctx).First(&wordModel, "word = ? AND translation = ? AND owner_id = ?", word, translation, ownerID).Error; err != nil {
+		return nil, err
+	}
+
+	return &wordModel, nil
+}
+
+// ListByOwner returns personal words of a user with their sentences
+func (r *WordRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Word, error) {
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Preload("Sentences").
+		Where("owner_id = ?", ownerID).
+		Order("word ASC").
+		Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// Lookup returns global words and personal words of the user starting with the query,
+// exact matches first, with sentences visible to the user preloaded
+func (r *WordRepository) Lookup(ctx context.Context, userID uuid.UUID, query string, limit int) ([]models.Word, error) {
+	query = strings.ToLower(query)
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Preload("Sentences", "user_id IS NULL OR user_id = ?", userID).
+		Where("(owner_id IS NULL OR owner_id = ?) AND LOWER(word) LIKE ?", userID, pattern).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "LOWER(word) = ? DESC, LENGTH(word) ASC, word ASC",
+			Vars:               []interface{}{query},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// GetByIDWithSentences returns a word by id with its sentences preloaded
+func (r *WordRepository) GetByIDWithSentences(ctx context.Context, id uuid.UUID) (*models.Word, error) {
+	var word models.Word
+	if err := r.db.WithContext(ctx).Preload("Sentences").First(&word, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &word, nil
+}
+
+// ListByIDsWithSentences returns words by ids with their sentences preloaded
+func (r *WordRepository) ListByIDsWithSentences(ctx context.Context, ids []uuid.UUID) ([]models.Word, error) {
+	var words []models.Word
+	if len(ids) == 0 {
+		return words, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Sentences").
+		Where("id IN ?", ids).
+		Order("word ASC").
+		Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
 // GetRandomWordsByCEFRLevel returns random words by cefr level
 func (r *WordRepository) GetRandomWordsByCEFRLevel(ctx context.Context, cefrLevel string, limit int) ([]models.Word, error) {
 	var words []models.Word
@@ -68,6 +208,7 @@ func (r *WordRepository) GetRandomWordsByCEFRLevel(ctx context.Context, cefrLeve
 
 	err := r.db.WithContext(ctx).
 		Where("cefr_level = ?", cefrLevel).
+		Where("owner_id IS NULL").
 		Order("RANDOM()").
 		Limit(limit).
 		Find(&words).Error
@@ -94,6 +235,7 @@ func (r *WordRepository) GetRandomWordsByTopicIDs(ctx context.Context, topicIDs
 		Model(&models.Word{}).
 		Where("cefr_level = ?", cefrLevel).
 		Where("topic_id IN ?", topicIDs).
+		Where("owner_id IS NULL OR owner_id = ?", userID).
 		Where("id NOT IN (?)", subQuery).
 		Order("RANDOM()").
 		Limit(limit).
@@ -114,6 +256,7 @@ func (r *WordRepository) GetDayWord(ctx context.Context, cefrLevel string, userI
 
 	err := r.db.WithContext(ctx).
 		Where("cefr_level = ?", cefrLevel).
+		Where("owner_id IS NULL").
 		Order("RANDOM()").
 		First(&word).Error
 
@@ -144,6 +287,7 @@ func (r *WordRepository) GetRandomWordsWithTopic(ctx context.Context, limit int)
 	var words []models.Word
 	err := r.db.WithContext(ctx).
 		Preload("Topic").
+		Where("owner_id IS NULL").
 		Order("RANDOM()").
 		Limit(limit).
 		Find(&words).Error
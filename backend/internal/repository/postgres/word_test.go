@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/repository/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWordLookup tests prefix lookup ordering and visibility of personal words
+func TestWordLookup(t *testing.T) {
+	ctx := context.Background()
+
+	newUser := func(name string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Name:      name,
+			Email:     "lookup-" + uuid.New().String()[:8] + "@example.com",
+			Role:      "user",
+			IsActive:  true,
+			CreatedAt: time.Now(),
+		}
+		assert.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	owner := newUser("Lookup Owner")
+	other := newUser("Lookup Other")
+
+	for _, value := range []string{"runner", "run", "running"} {
+		assert.NoError(t, wordRepo.Create(ctx, &models.Word{ID: uuid.New(), Word: value, PartOfSpeech: "verb"}))
+	}
+	assert.NoError(t, wordRepo.Create(ctx, &models.Word{ID: uuid.New(), Word: "runway", OwnerID: &owner.ID}))
+
+	words, err := wordRepo.Lookup(ctx, owner.ID, "Run", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, words, 4) {
+		assert.Equal(t, "run", words[0].Word)
+	}
+
+	// Personal words of other users are not visible
+	words, err = wordRepo.Lookup(ctx, other.ID, "runw", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, words)
+
+	// LIKE wildcards in the query are matched literally
+	words, err = wordRepo.Lookup(ctx, owner.ID, "r%", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, words)
+}
+
+// TestListWordsPages tests walking the pages of a topic subtree without skipping words with equal sort values
+func TestListWordsPages(t *testing.T) {
+	ctx := context.Background()
+
+	parent := &models.Topic{ID: uuid.New(), Title: "Paging"}
+	child := &models.Topic{ID: uuid.New(), Title: "Paging child", ParentID: &parent.ID}
+	for _, topic := range []*models.Topic{parent, child} {
+		assert.NoError(t, topicRepo.Create(ctx, topic))
+	}
+
+	created := map[uuid.UUID]bool{}
+	for i, value := range []string{"alpha", "beta", "beta", "gamma", "delta"} {
+		topicID := &parent.ID
+		if i%2 == 1 {
+			topicID = &child.ID
+		}
+		word := &models.Word{ID: uuid.New(), Word: value, PartOfSpeech: "noun", CEFRLevel: "A2", TopicID: topicID}
+		assert.NoError(t, wordRepo.Create(ctx, word))
+		created[word.ID] = true
+	}
+
+	filter := WordListFilter{TopicID: &parent.ID}
+	page := Page{Sort: "word", Desc: true, Limit: 2}
+	var seen []string
+	for i := 0; i < 5; i++ {
+		words, next, err := wordRepo.ListWords(ctx, filter, page)
+		assert.NoError(t, err)
+		for _, w := range words {
+			assert.True(t, created[w.ID])
+			seen = append(seen, w.Word)
+		}
+		if next == nil {
+			break
+		}
+		page.After = next
+	}
+	assert.Equal(t, []string{"gamma", "delta", "beta", "beta", "alpha"}, seen)
+
+	words, _, err := wordRepo.ListWords(ctx, WordListFilter{TopicID: &child.ID}, Page{Sort: "word", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, words, 2, "only the subtree of the child")
+
+	_, _, err = wordRepo.ListWords(ctx, filter, Page{Sort: "word", Limit: 10, After: []string{"beta", "not-a-uuid"}})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,34 @@
+package schemas
+
+import "time"
+
+// CaptureRequest is a request body for capturing a word met while reading
+type CaptureRequest struct {
+	Word        string  `json:"word" binding:"required"` // selected word or phrase
+	Sentence    string  `json:"sentence"`                // sentence surrounding the selection
+	Translation *string `json:"translation"`             // translation of the selection, used for new personal words
+	SourceURL   string  `json:"source_url"`
+	SourceTitle string  `json:"source_title"`
+}
+
+// CaptureResponse is a response for a captured word
+type CaptureResponse struct {
+	ID          string    `json:"id"`
+	WordID      string    `json:"word_id"`
+	Word        string    `json:"word"`
+	Translation string    `json:"translation"`
+	Personal    bool      `json:"personal"` // word was created as a personal word
+	Sentence    string    `json:"sentence,omitempty"`
+	SourceURL   string    `json:"source_url,omitempty"`
+	SourceTitle string    `json:"source_title,omitempty"`
+	Queued      bool      `json:"queued"` // word is waiting for the next lesson
+	Duplicate   bool      `json:"duplicate,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CaptureListResponse is a response with a page of the capture inbox
+type CaptureListResponse struct {
+	Items      []CaptureResponse `json:"items"`
+	Total      int64             `json:"total"`                 // captures in the inbox
+	NextCursor string            `json:"next_cursor,omitempty"` // pass as cursor to get the next page, empty on the last page
+}
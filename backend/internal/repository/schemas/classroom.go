@@ -0,0 +1,76 @@
+package schemas
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateClassroomRequest is a request body for creating a classroom
+type CreateClassroomRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// JoinClassroomRequest is a request body for joining a classroom
+type JoinClassroomRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// ClassroomResponse is a response for a classroom
+type ClassroomResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	TeacherID   uuid.UUID `json:"teacher_id"`
+	TeacherName string    `json:"teacher_name,omitempty"`
+	JoinCode    string    `json:"join_code,omitempty"` // only shown to the teacher
+	IsTeacher   bool      `json:"is_teacher"`
+	Students    int64     `json:"students,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateAssignmentRequest is a request body for assigning a topic or a word list, exactly one of them is set
+type CreateAssignmentRequest struct {
+	Title   string      `json:"title"`
+	TopicID *uuid.UUID  `json:"topic_id,omitempty"`
+	WordIDs []uuid.UUID `json:"word_ids,omitempty"`
+	DueAt   time.Time   `json:"due_at" binding:"required"`
+}
+
+// AssignmentResponse is a response for an assignment
+type AssignmentResponse struct {
+	ID        uuid.UUID   `json:"id"`
+	Title     string      `json:"title"`
+	TopicID   *uuid.UUID  `json:"topic_id,omitempty"`
+	WordIDs   []uuid.UUID `json:"word_ids"`
+	DueAt     time.Time   `json:"due_at"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// AssignmentProgressResponse is a student's progress on an assignment
+type AssignmentProgressResponse struct {
+	AssignmentID uuid.UUID `json:"assignment_id"`
+	Title        string    `json:"title"`
+	DueAt        time.Time `json:"due_at"`
+	Total        int       `json:"total"`
+	Learned      int       `json:"learned"`
+	NotLearned   int       `json:"not_learned"` // answered wrong and not learned since
+	Completed    bool      `json:"completed"`
+	Overdue      bool      `json:"overdue"` // past due and not completed
+}
+
+// StudentProgressResponse is a student's progress on all assignments of a classroom
+type StudentProgressResponse struct {
+	UserID      uuid.UUID                    `json:"user_id"`
+	Name        string                       `json:"name"`
+	JoinedAt    time.Time                    `json:"joined_at"`
+	Total       int                          `json:"total"`
+	Learned     int                          `json:"learned"`
+	NotLearned  int                          `json:"not_learned"`
+	Assignments []AssignmentProgressResponse `json:"assignments"`
+}
+
+// ClassroomProgressResponse is the teacher's dashboard of a classroom
+type ClassroomProgressResponse struct {
+	ClassroomID uuid.UUID                 `json:"classroom_id"`
+	Students    []StudentProgressResponse `json:"students"`
+}
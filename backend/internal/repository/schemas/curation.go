@@ -0,0 +1,67 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AdminWordResponse is a response for a global word with what it lacks for lessons
+type AdminWordResponse struct {
+	WordResponse
+	TopicID         *string `json:"topic_id,omitempty"`
+	SentenceCount   int     `json:"sentence_count"`
+	DistractorCount int     `json:"distractor_count"`
+}
+
+// AdminWordListResponse is a response with a page of global words
+type AdminWordListResponse struct {
+	Words []AdminWordResponse `json:"words"`
+	Total int64               `json:"total"`
+	Page  int                 `json:"page"`
+	Limit int                 `json:"limit"`
+}
+
+// BulkWordUpdateRequest is a request body for changing several words at once, omitted fields stay as they are
+type BulkWordUpdateRequest struct {
+	IDs          []string `json:"ids"`
+	CEFRLevel    *string  `json:"cefr_level,omitempty"`
+	PartOfSpeech *string  `json:"part_of_speech,omitempty"`
+	TopicID      *string  `json:"topic_id,omitempty"`
+	ClearTopic   bool     `json:"clear_topic,omitempty"` // removes the words from their topic
+}
+
+// BulkWordUpdateResponse is a response for a bulk edit
+type BulkWordUpdateResponse struct {
+	Updated int `json:"updated"` // words that actually changed
+}
+
+// MergeWordsRequest is a request body for merging duplicate words into one
+type MergeWordsRequest struct {
+	TargetID  string   `json:"target_id"`
+	SourceIDs []string `json:"source_ids"` // duplicates, deleted after the merge
+}
+
+// ReparentTopicRequest is a request body for moving a topic, an empty parent makes it a main topic
+type ReparentTopicRequest struct {
+	ParentID *string `json:"parent_id"`
+}
+
+// ContentAuditResponse is a response for an entry of the content audit log
+type ContentAuditResponse struct {
+	ID         string          `json:"id"`
+	ActorID    *string         `json:"actor_id,omitempty"`
+	Actor      string          `json:"actor" example:"admin@example.com"`
+	Action     string          `json:"action" example:"word.update"`
+	EntityType string          `json:"entity_type" example:"word"`
+	EntityID   string          `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ContentAuditListResponse is a response with a page of the content audit log
+type ContentAuditListResponse struct {
+	Entries []ContentAuditResponse `json:"entries"`
+	Total   int64                  `json:"total"`
+	Page    int                    `json:"page"`
+	Limit   int                    `json:"limit"`
+}
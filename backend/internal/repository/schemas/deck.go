@@ -0,0 +1,28 @@
+package schemas
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeckRequest is a request body for creating or updating a deck
+type DeckRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+}
+
+// AddDeckWordRequest is a request body for adding a word to a deck
+type AddDeckWordRequest struct {
+	WordID uuid.UUID `json:"word_id" binding:"required"`
+}
+
+// DeckResponse is a response for a deck
+type DeckResponse struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	WordCount   int64          `json:"word_count"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Words       []WordResponse `json:"words,omitempty"`
+}
@@ -0,0 +1,44 @@
+package schemas
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FriendResponse is a response for a friend
+type FriendResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Since  time.Time `json:"since"`
+}
+
+// FriendInviteResponse is a response for a created invite, clients share it as a link
+type FriendInviteResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AcceptFriendInviteRequest is a request body for accepting an invite
+type AcceptFriendInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// LeaderboardEntry is a user's place on a leaderboard
+type LeaderboardEntry struct {
+	Rank   int       `json:"rank"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	XP     int       `json:"xp"`
+	IsMe   bool      `json:"is_me"`
+}
+
+// LeaderboardResponse is a response with the weekly XP leaderboard
+type LeaderboardResponse struct {
+	Scope   string             `json:"scope" enums:"global,friends"`
+	Week    string             `json:"week" example:"2025-W05"`
+	EndsAt  time.Time          `json:"ends_at"` // when the week ends and scores start over
+	Entries []LeaderboardEntry `json:"entries"`
+	Me      *LeaderboardEntry  `json:"me,omitempty"`     // the user's place, also when outside the entries
+	Hidden  bool               `json:"hidden,omitempty"` // the user opted out of the global leaderboard
+}
@@ -0,0 +1,63 @@
+package schemas
+
+import (
+	"time"
+)
+
+// ActivityRequest is a request body for reporting exercises or a finished lesson
+type ActivityRequest struct {
+	IdempotencyKey string     `json:"idempotency_key,omitempty"` // resending the same key awards nothing
+	Type           string     `json:"type" binding:"required" enums:"exercise,lesson"`
+	Correct        int        `json:"correct"`
+	Total          int        `json:"total"`
+	ClientTime     *time.Time `json:"client_time,omitempty"` // when it happened, now if omitted
+}
+
+// GamificationStatsResponse is a response with the user's XP, level and streak
+type GamificationStatsResponse struct {
+	XP             int    `json:"xp"`
+	Level          int    `json:"level"`
+	LevelXP        int    `json:"level_xp"`      // XP the current level starts at
+	NextLevelXP    int    `json:"next_level_xp"` // XP the next level starts at
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	StreakFreezes  int    `json:"streak_freezes"`
+	LastActiveDate string `json:"last_active_date,omitempty" example:"2025-01-31"`
+	TimeZone       string `json:"time_zone" example:"Europe/Moscow"`
+}
+
+// AchievementResponse is a response for an achievement and the user's progress towards it
+type AchievementResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Metric      string     `json:"metric"`
+	Threshold   int        `json:"threshold"`
+	Progress    int        `json:"progress"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// AchievementsResponse is a response with the user's stats and all achievements
+type AchievementsResponse struct {
+	Stats        GamificationStatsResponse `json:"stats"`
+	Achievements []AchievementResponse     `json:"achievements"`
+}
+
+// GamificationEvent is a noteworthy outcome of an activity, for celebratory messages
+type GamificationEvent struct {
+	Type        string               `json:"type" enums:"xp_gained,level_up,streak_extended,streak_freeze_used,streak_freeze_earned,achievement_unlocked"`
+	XP          int                  `json:"xp,omitempty"`
+	Level       int                  `json:"level,omitempty"`
+	Streak      int                  `json:"streak,omitempty"`
+	Freezes     int                  `json:"freezes,omitempty"`
+	Achievement *AchievementResponse `json:"achievement,omitempty"`
+}
+
+// ActivityResponse is a response for a reported activity
+type ActivityResponse struct {
+	XPGained int                       `json:"xp_gained"`
+	Stats    GamificationStatsResponse `json:"stats"`
+	Events   []GamificationEvent       `json:"events"`
+}
@@ -0,0 +1,35 @@
+package schemas
+
+import "time"
+
+// IdentityAuthRequest is a request body for signing in or linking with an external account,
+// either an ID token from the provider's SDK or an authorization code with the redirect URI it was issued for
+type IdentityAuthRequest struct {
+	IDToken     string `json:"id_token,omitempty"`
+	Code        string `json:"code,omitempty"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+// IdentityProvidersResponse is a response with the providers users can sign in with
+type IdentityProvidersResponse struct {
+	Providers []string `json:"providers" example:"apple,github"`
+}
+
+// IdentityResponse is a response for an external account linked to the user
+type IdentityResponse struct {
+	Provider   string    `json:"provider" example:"github"`
+	Email      string    `json:"email,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// IdentityListResponse is a response with the linked accounts and the providers that can be linked
+type IdentityListResponse struct {
+	Identities []IdentityResponse `json:"identities"`
+	Providers  []string           `json:"providers"`
+}
+
+// AuthorizeURLResponse is a response with the consent screen URL of a provider
+type AuthorizeURLResponse struct {
+	URL string `json:"url"`
+}
@@ -0,0 +1,46 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobQueueResponse is a response for a job queue state
+type JobQueueResponse struct {
+	Queue     string `json:"queue"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"` // tasks that ran out of retries
+	Completed int    `json:"completed"`
+	Processed int    `json:"processed"` // processed today
+	Failed    int    `json:"failed"`    // failed today
+	Paused    bool   `json:"paused"`
+}
+
+// JobTaskResponse is a response for a job task
+type JobTaskResponse struct {
+	ID            string          `json:"id"`
+	Queue         string          `json:"queue"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	State         string          `json:"state"`
+	MaxRetry      int             `json:"max_retry"`
+	Retried       int             `json:"retried"`
+	LastError     string          `json:"last_error,omitempty"`
+	LastFailedAt  *time.Time      `json:"last_failed_at,omitempty"`
+	NextProcessAt *time.Time      `json:"next_process_at,omitempty"`
+	CompletedAt   *time.Time      `json:"completed_at,omitempty"`
+}
+
+// JobBackfillRequest is a request body for enqueueing enrichment of existing records
+type JobBackfillRequest struct {
+	Limit int `json:"limit"` // max words and max sentences to enqueue, defaults to 100
+}
+
+// JobCountResponse is a response for bulk job operations
+type JobCountResponse struct {
+	Count int `json:"count"`
+}
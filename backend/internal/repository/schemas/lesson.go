@@ -12,10 +12,11 @@ type LessonResponse struct {
 
 // Lesson information
 type LessonInfo struct {
-	StartedAt      string `json:"started_at"`
-	WordsPerLesson int    `json:"words_per_lesson"`
-	TotalWords     int    `json:"total_words"`
-	CEFRLevel      string `json:"cefr_level"`
+	StartedAt      string     `json:"started_at"`
+	WordsPerLesson int        `json:"words_per_lesson"`
+	TotalWords     int        `json:"total_words"`
+	CEFRLevel      string     `json:"cefr_level"`
+	DeckID         *uuid.UUID `json:"deck_id,omitempty"`
 }
 
 // Card with word and sentences
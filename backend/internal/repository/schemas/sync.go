@@ -0,0 +1,51 @@
+package schemas
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncEventRequest is a progress event recorded by a client, possibly while offline
+type SyncEventRequest struct {
+	IdempotencyKey  string    `json:"idempotency_key" binding:"required"` // unique per event, resending the same key is a no-op
+	Type            string    `json:"type" binding:"required" enums:"learned,not_learned,reviewed"`
+	WordID          uuid.UUID `json:"word_id" binding:"required"`
+	ClientTime      time.Time `json:"client_time" binding:"required"` // when the event happened on the client
+	ConfidenceScore *int      `json:"confidence_score,omitempty"`
+	CntReviewed     *int      `json:"cnt_reviewed,omitempty"`
+}
+
+// SyncRequest is a request body for pushing events and pulling changes in one round trip
+type SyncRequest struct {
+	DeviceID string             `json:"device_id"`
+	Cursor   int64              `json:"cursor"` // last cursor received from the server, 0 on first sync
+	Events   []SyncEventRequest `json:"events"`
+}
+
+// SyncEventResult is the outcome of a pushed event
+type SyncEventResult struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Status         string `json:"status" enums:"applied,superseded,duplicate,rejected,skipped"`
+	Error          string `json:"error,omitempty"`
+}
+
+// SyncChange is the current server state of a word changed after the cursor
+type SyncChange struct {
+	Seq             int64      `json:"seq"`
+	WordID          uuid.UUID  `json:"word_id"`
+	State           string     `json:"state" enums:"learned,not_learned,none"`
+	LearnedAt       *time.Time `json:"learned_at,omitempty"`
+	LastReviewed    *time.Time `json:"last_reviewed,omitempty"`
+	ConfidenceScore int        `json:"confidence_score"`
+	CntReviewed     int        `json:"cnt_reviewed"`
+}
+
+// SyncResponse is a response with event results and the change feed
+type SyncResponse struct {
+	Results []SyncEventResult   `json:"results,omitempty"`
+	Changes []SyncChange        `json:"changes"`
+	Cursor  int64               `json:"cursor"`           // pass it back on the next sync
+	HasMore bool                `json:"has_more"`         // more changes are available after the cursor
+	Events  []GamificationEvent `json:"events,omitempty"` // XP, streak and achievements earned by the pushed events
+}
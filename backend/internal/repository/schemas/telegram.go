@@ -40,3 +40,20 @@ type TelegramUnlinkRequest struct {
 type TelegramTokenRequest struct {
 	TelegramID int64 `json:"telegram_id" binding:"required"`
 }
+
+// TelegramWidgetData is the user data the Telegram Login Widget passes to its callback
+type TelegramWidgetData struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	PhotoURL  string `json:"photo_url,omitempty"`
+	AuthDate  int64  `json:"auth_date"`
+	Hash      string `json:"hash"`
+}
+
+// TelegramAuthRequest is a request body for signing in with Telegram, exactly one of the fields is set
+type TelegramAuthRequest struct {
+	Widget   *TelegramWidgetData `json:"widget,omitempty"`    // Login Widget on the website
+	InitData string              `json:"init_data,omitempty"` // raw Telegram.WebApp.initData of a Mini App
+}
@@ -13,7 +13,25 @@ type TopicResponse struct {
 	ParentID string `json:"parent_id"`
 }
 
-// TopicTitleResponse is a response for a topic that only includes the title
-type TopicTitleResponse struct {
-	Title string `json:"title"`
+// TopicListResponse is a response with a page of topics
+type TopicListResponse struct {
+	Topics     []TopicResponse `json:"topics"`
+	NextCursor string          `json:"next_cursor,omitempty"` // pass as cursor to get the next page, empty on the last page
+}
+
+// TopicTreeNodeResponse is a topic of the tree with word counts and the user's progress, including subtopics
+type TopicTreeNodeResponse struct {
+	ID           string                  `json:"id"`
+	Title        string                  `json:"title"`
+	ParentID     string                  `json:"parent_id,omitempty"`
+	Words        int                     `json:"words"`
+	WordsByLevel map[string]int          `json:"words_by_level"` // CEFR level to number of words
+	Learned      int                     `json:"learned"`
+	NotLearned   int                     `json:"not_learned"`
+	Children     []TopicTreeNodeResponse `json:"children"`
+}
+
+// TopicTreeResponse is a response with the main topics and their subtopics
+type TopicTreeResponse struct {
+	Topics []TopicTreeNodeResponse `json:"topics"`
 }
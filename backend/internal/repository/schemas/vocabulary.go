@@ -0,0 +1,12 @@
+package schemas
+
+// VocabularyImportResponse is a response for a vocabulary import
+type VocabularyImportResponse struct {
+	Total      int      `json:"total"`       // rows found in the file
+	Matched    int      `json:"matched"`     // rows matched to existing words
+	Created    int      `json:"created"`     // personal words created for unmatched rows
+	Learned    int      `json:"learned"`     // words marked as learned
+	NotLearned int      `json:"not_learned"` // words marked as not learned
+	Skipped    int      `json:"skipped"`     // rows that could not be imported
+	Errors     []string `json:"errors,omitempty"`
+}
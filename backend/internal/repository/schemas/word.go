@@ -25,4 +25,52 @@ type WordResponse struct {
 	PartOfSpeech string  `json:"part_of_speech"`
 	Context      *string `json:"context,omitempty"`
 	AudioURL     *string `json:"audio_url,omitempty"`
+	Phonetic     *string `json:"phonetic,omitempty"`
+	OwnerID      *string `json:"owner_id,omitempty"`
+}
+
+// WordListResponse is a response with a page of words
+type WordListResponse struct {
+	Words      []WordResponse `json:"words"`
+	NextCursor string         `json:"next_cursor,omitempty"` // pass as cursor to get the next page, empty on the last page
+}
+
+// WordLookupResponse is a word found by lookup with its example sentences
+type WordLookupResponse struct {
+	WordResponse
+	Sentences []SentenceResponse `json:"sentences"`
+}
+
+// SearchResultResponse is a word found by search
+type SearchResultResponse struct {
+	WordResponse
+	Score float64 `json:"score"` // relevance, higher is better
+}
+
+// SearchResponse is a response with the words found by search, best matches first
+type SearchResponse struct {
+	Query   string                 `json:"query"`
+	Results []SearchResultResponse `json:"results"`
+}
+
+// PersonalSentenceRequest is an example sentence of a personal word
+type PersonalSentenceRequest struct {
+	Sentence    string `json:"sentence" binding:"required"`
+	Translation string `json:"translation"`
+}
+
+// PersonalWordRequest is a request body for creating or updating a personal word
+type PersonalWordRequest struct {
+	Word         string                    `json:"word" binding:"required"`
+	Translation  string                    `json:"translation" binding:"required"`
+	PartOfSpeech *string                   `json:"part_of_speech"`
+	CEFRLevel    *string                   `json:"cefr_level"`
+	Context      *string                   `json:"context"`
+	Sentences    []PersonalSentenceRequest `json:"sentences"`
+}
+
+// PersonalWordResponse is a response for a personal word
+type PersonalWordResponse struct {
+	WordResponse
+	Sentences []SentenceResponse `json:"sentences"`
 }
@@ -16,8 +16,14 @@ import (
 
 	"fluently/go-backend/internal/api/v1/handlers"
 	"fluently/go-backend/internal/api/v1/routes"
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/identity"
+	"fluently/go-backend/internal/jobs"
+	"fluently/go-backend/internal/leaderboard"
 	authMiddleware "fluently/go-backend/internal/middleware"
 	"fluently/go-backend/internal/repository/postgres"
+	"fluently/go-backend/internal/serviceauth"
+	"fluently/go-backend/internal/storage"
 	"fluently/go-backend/internal/utils"
 	"fluently/go-backend/pkg/logger"
 
@@ -137,8 +143,8 @@ func flexibleJWTVerifier(next http.Handler) http.Handler {
 			}
 		}
 
-		// Verify and parse the token
-		token, err := utils.TokenAuth.Decode(tokenString)
+		// Verify the signature against the signing keys and check the lifetime
+		token, err := utils.ParseJWT(tokenString)
 		if err != nil {
 			logger.Log.Error("JWT decode error",
 				zap.Error(err),
@@ -194,6 +200,61 @@ func min(a, b int) int {
 	return b
 }
 
+// newIdentityProviders enables the external sign in providers that have a client ID configured
+func newIdentityProviders(cfg *config.Config) (*identity.Registry, error) {
+	var providers []identity.Provider
+
+	if cfg.Google.WebClientID != "" {
+		var audiences []string
+		for _, clientID := range []string{cfg.Google.IosClientID, cfg.Google.AndroidClientID} {
+			if clientID != "" {
+				audiences = append(audiences, fmt.Sprintf("%s.apps.googleusercontent.com", clientID))
+			}
+		}
+		providers = append(providers, identity.NewOIDCProvider(identity.OIDCConfig{
+			Name:         "google",
+			Issuer:       "https://accounts.google.com",
+			ClientID:     cfg.Google.WebClientID,
+			ClientSecret: config.GoogleOAuthConfig().ClientSecret,
+			Audiences:    audiences,
+		}, nil))
+	}
+
+	ids := cfg.Identity
+	if ids.AppleClientID != "" {
+		var audiences []string
+		if ids.AppleBundleID != "" {
+			audiences = append(audiences, ids.AppleBundleID)
+		}
+		providers = append(providers, identity.NewOIDCProvider(identity.OIDCConfig{
+			Name:         "apple",
+			Issuer:       "https://appleid.apple.com",
+			ClientID:     ids.AppleClientID,
+			ClientSecret: ids.AppleClientSecret,
+			Audiences:    audiences,
+			Scopes:       []string{"openid", "name", "email"},
+			// Apple requires form posts when asking for the name or email
+			AuthParams: map[string]string{"response_mode": "form_post"},
+		}, nil))
+	}
+	if ids.YandexClientID != "" {
+		providers = append(providers, identity.NewYandexProvider(ids.YandexClientID, ids.YandexClientSecret, nil))
+	}
+	if ids.GitHubClientID != "" {
+		providers = append(providers, identity.NewGitHubProvider(ids.GitHubClientID, ids.GitHubClientSecret, nil))
+	}
+
+	issuers, err := identity.ParseOIDCProviders(ids.OIDCProviders)
+	if err != nil {
+		return nil, err
+	}
+	for _, issuer := range issuers {
+		providers = append(providers, identity.NewOIDCProvider(issuer, nil))
+	}
+
+	return identity.NewRegistry(providers...)
+}
+
 // InitRoutes initializes routes
 func InitRoutes(db *gorm.DB, r *chi.Mux) {
 	// Initialize JWT auth
@@ -210,21 +271,33 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
+	identityProviders, err := newIdentityProviders(config.GetConfig())
+	if err != nil {
+		logger.Log.Fatal("Invalid identity provider configuration", zap.Error(err))
+	}
+
 	authHandlers := &handlers.Handlers{
 		UserRepo:         postgres.NewUserRepository(db),
 		UserPrefRepo:     postgres.NewPreferenceRepository(db),
 		RefreshTokenRepo: postgres.NewRefreshTokenRepository(db),
+		IdentityRepo:     postgres.NewIdentityRepository(db),
+		Providers:        identityProviders,
+		Redis:            utils.Redis(),
 	}
 
 	// Initialize link token repository for cleanup task
 	linkTokenRepo := postgres.NewLinkTokenRepository(db)
 
-	// Start cleanup task for expired tokens (every hour)
-	utils.StartTokenCleanupTask(linkTokenRepo, time.Hour)
+	// Start cleanup task for expired tokens and friend invites (every hour)
+	utils.StartTokenCleanupTask(linkTokenRepo, postgres.NewFriendRepository(db), time.Hour)
 
 	// Public routes (NO AUTHENTICATION REQUIRED)
 	routes.RegisterAuthRoutes(r, authHandlers)
 
+	// Public keys of access tokens, other services verify tokens with them
+	r.Get("/.well-known/jwks.json", handlers.JWKSHandler)
+	utils.StartJWTKeyRotationTask(config.GetConfig().Auth.JWTKeyRotation, time.Hour)
+
 	// Prometheus metrics endpoint
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -243,6 +316,12 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 		http.ServeFile(w, r, "/app/static/auth-success.html")
 	})
 
+	// Media kept on the filesystem is served by the API, access is checked by URL signatures
+	media := utils.Media()
+	if fsMedia, ok := media.(*storage.FSStorage); ok {
+		r.Handle(storage.MediaPath+"*", fsMedia)
+	}
+
 	userRepo := postgres.NewUserRepository(db)
 	wordRepo := postgres.NewWordRepository(db)
 	sentenceRepo := postgres.NewSentenceRepository(db)
@@ -253,10 +332,19 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 	lessonRepo := postgres.NewLessonRepository(db)
 	chatHistoryRepo := postgres.NewChatHistoryRepository(db)
 	notLearnedWordRepo := postgres.NewNotLearnedWordRepository(db)
+	deckRepo := postgres.NewDeckRepository(db)
+	captureRepo := postgres.NewCaptureRepository(db)
+	syncRepo := postgres.NewSyncRepository(db)
+	gamificationRepo := postgres.NewGamificationRepository(db)
+	friendRepo := postgres.NewFriendRepository(db)
+	classroomRepo := postgres.NewClassroomRepository(db)
+	board := leaderboard.New(utils.Redis())
 
 	thesaurusClient := utils.NewThesaurusClient(utils.ThesaurusClientConfig{})
 	llmClient := utils.NewLLMClient(utils.LLMClientConfig{})
 	distractorClient := utils.NewDistractorClient(utils.DistractorClientConfig{})
+	enrichmentService := utils.NewWordEnrichmentService(db)
+	jobClient := jobs.NewClient(jobs.RedisOpt(), config.GetConfig().Jobs.MaxRetry)
 
 	chatHistoryHandler := &handlers.ChatHistoryHandler{Repo: chatHistoryRepo}
 
@@ -265,10 +353,22 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 		UserRepo:         userRepo,
 		LinkTokenRepo:    linkTokenRepo,
 		RefreshTokenRepo: postgres.NewRefreshTokenRepository(db),
+		AuditRepo:        postgres.NewServiceAuditRepository(db),
 	}
 
+	// Machine endpoints reject every request until service keys are configured
+	serviceCfg := config.GetConfig().Service
+	serviceKeys, err := serviceauth.ParseKeys(serviceCfg.Keys)
+	if err != nil {
+		logger.Log.Fatal("Invalid SERVICE_KEYS", zap.Error(err))
+	}
+	if len(serviceKeys) == 0 {
+		logger.Log.Warn("SERVICE_KEYS is empty, the Telegram bot can't call /telegram endpoints")
+	}
+	serviceAuth := authMiddleware.ServiceAuthenticator(serviceKeys, serviceCfg.MaxSkew, utils.Redis())
+
 	// Register telegram routes now that handler is initialized
-	routes.RegisterTelegramRoutes(r, telegramHandler)
+	routes.RegisterTelegramRoutes(r, telegramHandler, serviceAuth)
 
 	// Protected routes using flexible JWT authentication
 	r.Route("/api/v1", func(r chi.Router) {
@@ -278,14 +378,63 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 
 		// Protected API routes
 		routes.RegisterUserRoutes(r, &handlers.UserHandler{Repo: userRepo})
-		routes.RegisterWordRoutes(r, &handlers.WordHandler{Repo: wordRepo})
-		routes.RegisterSentenceRoutes(r, &handlers.SentenceHandler{Repo: sentenceRepo})
+		routes.RegisterTelegramAccountRoutes(r, telegramHandler)
+		routes.RegisterIdentityRoutes(r, authHandlers)
+		routes.RegisterWordRoutes(r, &handlers.WordHandler{Repo: wordRepo, Jobs: jobClient})
+		routes.RegisterSearchRoutes(r, &handlers.SearchHandler{Repo: wordRepo})
+		routes.RegisterSentenceRoutes(r, &handlers.SentenceHandler{Repo: sentenceRepo, Jobs: jobClient})
 		routes.RegisterLearnedWordRoutes(r, &handlers.LearnedWordHandler{Repo: learnedWordRepo})
 		routes.RegisterNotLearnedWordRoutes(r, &handlers.NotLearnedWordHandler{
 			Repo:     notLearnedWordRepo,
 			WordRepo: wordRepo,
 		})
-		routes.RegisterPreferencesRoutes(r, &handlers.PreferenceHandler{Repo: preferenceRepo})
+		routes.RegisterVocabularyRoutes(r, &handlers.VocabularyHandler{
+			WordRepo:           wordRepo,
+			SentenceRepo:       sentenceRepo,
+			LearnedWordRepo:    learnedWordRepo,
+			NotLearnedWordRepo: notLearnedWordRepo,
+			Jobs:               jobClient,
+		})
+		routes.RegisterPersonalWordRoutes(r, &handlers.PersonalWordHandler{
+			Repo:         wordRepo,
+			SentenceRepo: sentenceRepo,
+			Jobs:         jobClient,
+		})
+		routes.RegisterDeckRoutes(r, &handlers.DeckHandler{
+			Repo:     deckRepo,
+			WordRepo: wordRepo,
+		})
+		routes.RegisterCaptureRoutes(r, &handlers.CaptureHandler{
+			Repo:         captureRepo,
+			WordRepo:     wordRepo,
+			SentenceRepo: sentenceRepo,
+			Jobs:         jobClient,
+		})
+		routes.RegisterSyncRoutes(r, &handlers.SyncHandler{
+			Repo:               syncRepo,
+			WordRepo:           wordRepo,
+			LearnedWordRepo:    learnedWordRepo,
+			NotLearnedWordRepo: notLearnedWordRepo,
+			Gamification:       gamificationRepo,
+			PreferenceRepo:     preferenceRepo,
+			Leaderboard:        board,
+			LLMClient:          llmClient,
+			Redis:              utils.Redis(),
+		})
+		routes.RegisterGamificationRoutes(r, &handlers.GamificationHandler{
+			Repo:           gamificationRepo,
+			PreferenceRepo: preferenceRepo,
+			Leaderboard:    board,
+		})
+		routes.RegisterFriendRoutes(r, &handlers.FriendHandler{Repo: friendRepo, UserRepo: userRepo})
+		routes.RegisterLeaderboardRoutes(r, &handlers.LeaderboardHandler{
+			Board:          board,
+			FriendRepo:     friendRepo,
+			UserRepo:       userRepo,
+			PreferenceRepo: preferenceRepo,
+		})
+		routes.RegisterClassroomRoutes(r, &handlers.ClassroomHandler{Repo: classroomRepo, TopicRepo: topicRepo})
+		routes.RegisterPreferencesRoutes(r, &handlers.PreferenceHandler{Repo: preferenceRepo, Media: media, Leaderboard: board})
 		routes.RegisterPickOptionRoutes(r, &handlers.PickOptionHandler{Repo: pickOptionRepo})
 		routes.RegisterTopicRoutes(r, &handlers.TopicHandler{Repo: topicRepo})
 		routes.RegisterProgressRoutes(r, &handlers.ProgressHandler{
@@ -312,6 +461,9 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 			Repo:               lessonRepo,
 			LearnedWordRepo:    learnedWordRepo,
 			NotLearnedWordRepo: notLearnedWordRepo,
+			DeckRepo:           deckRepo,
+			CaptureRepo:        captureRepo,
+			ClassroomRepo:      classroomRepo,
 			ThesaurusClient:    thesaurusClient,
 		})
 
@@ -335,5 +487,13 @@ func InitRoutes(db *gorm.DB, r *chi.Mux) {
 		routes.RegisterChatRoutes(r, chatHandler, chatHistoryHandler)
 		routes.RegisterDistractorRoutes(r, distractorHandler)
 		routes.RegisterThesaurusRoutes(r, thesaurusHandler)
+
+		routes.RegisterJobRoutes(r, &handlers.JobHandler{
+			Jobs:       jobClient,
+			Inspector:  jobs.NewInspector(jobs.RedisOpt()),
+			Enrichment: enrichmentService,
+			Audio:      utils.NewWordAudioService(db),
+		})
+		routes.RegisterCurationRoutes(r, &handlers.CurationHandler{Repo: postgres.NewCurationRepository(db)})
 	})
 }
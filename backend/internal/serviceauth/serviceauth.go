@@ -0,0 +1,142 @@
+// Package serviceauth signs and verifies requests between our own services,
+// like the Telegram bot calling the machine endpoints under /telegram.
+//
+// A request is signed with HMAC-SHA256 over its method, path, timestamp, a random
+// nonce and the SHA-256 of its body. Every key has an ID and a list of scopes, and
+// several keys can be active at once, so a key is rotated by adding a new one,
+// switching the service to it and removing the old one.
+package serviceauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a signed request
+const (
+	KeyIDHeader     = "X-Service-Key"
+	TimestampHeader = "X-Service-Timestamp" // unix seconds
+	NonceHeader     = "X-Service-Nonce"
+	SignatureHeader = "X-Service-Signature" // hex HMAC-SHA256
+)
+
+// Scopes of the Telegram bot
+const (
+	ScopeTelegramLink   = "telegram:link"   // create link tokens and check link status
+	ScopeTelegramTokens = "telegram:tokens" // mint user tokens for linked Telegram accounts
+)
+
+// minSecretLength keeps guessable secrets out of the configuration
+const minSecretLength = 32
+
+var (
+	ErrMissingSignature = errors.New("missing service signature")
+	ErrUnknownKey       = errors.New("unknown service key")
+	ErrExpired          = errors.New("service signature expired")
+	ErrBadSignature     = errors.New("invalid service signature")
+)
+
+// Key is a service credential
+type Key struct {
+	ID     string
+	Secret string
+	Scopes []string
+}
+
+// HasScope reports whether the key grants the scope
+func (k Key) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// ParseKeys parses keys from the configuration, written as
+// "id:secret:scope1,scope2" and separated by semicolons
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("service key %q must be id:secret:scopes", parts[0])
+		}
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("duplicate service key %q", parts[0])
+		}
+		if len(parts[1]) < minSecretLength {
+			return nil, fmt.Errorf("secret of service key %q must be at least %d characters", parts[0], minSecretLength)
+		}
+
+		key := Key{ID: parts[0], Secret: parts[1]}
+		for _, scope := range strings.Split(parts[2], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				key.Scopes = append(key.Scopes, scope)
+			}
+		}
+		if len(key.Scopes) == 0 {
+			return nil, fmt.Errorf("service key %q has no scopes", parts[0])
+		}
+
+		seen[key.ID] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sign returns the signature of a request
+func Sign(secret, method, path string, timestamp int64, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		method,
+		path,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a request with its already read body and returns the key it was signed with.
+// Replays within maxSkew are not detected here, callers remember nonces for that long.
+func Verify(keys []Key, r *http.Request, body []byte, now time.Time, maxSkew time.Duration) (*Key, error) {
+	keyID := r.Header.Get(KeyIDHeader)
+	signature := r.Header.Get(SignatureHeader)
+	nonce := r.Header.Get(NonceHeader)
+	if keyID == "" || signature == "" || nonce == "" {
+		return nil, ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return nil, ErrMissingSignature
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > maxSkew || d < -maxSkew {
+		return nil, ErrExpired
+	}
+
+	i := slices.IndexFunc(keys, func(k Key) bool { return k.ID == keyID })
+	if i < 0 {
+		return nil, ErrUnknownKey
+	}
+	key := keys[i]
+
+	expected := Sign(key.Secret, r.Method, r.URL.RequestURI(), timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return nil, ErrBadSignature
+	}
+	return &key, nil
+}
@@ -0,0 +1,80 @@
+package serviceauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// TestSign tests the signature against a fixed vector, the Telegram bot checks the same one
+func TestSign(t *testing.T) {
+	signature := Sign(testSecret, "POST", "/telegram/get-tokens", 1735689600, "n0nce", []byte(`{"telegram_id":42}`))
+	assert.Equal(t, "137cf4d5e67bb46d0685a925ff2b549d9cfc0556554607c052716005b82b9f9e", signature)
+}
+
+// TestParseKeys tests parsing keys from the configuration
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("bot-2025:" + testSecret + ":telegram:link,telegram:tokens; bot-old:" + testSecret + ":telegram:link")
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "bot-2025", keys[0].ID)
+		assert.True(t, keys[0].HasScope(ScopeTelegramTokens))
+		assert.False(t, keys[1].HasScope(ScopeTelegramTokens))
+	}
+
+	keys, err = ParseKeys("")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, invalid := range []string{
+		"bot:short:telegram:link",
+		"bot:" + testSecret,
+		"bot:" + testSecret + ":",
+		"bot:" + testSecret + ":a;bot:" + testSecret + ":b",
+	} {
+		_, err := ParseKeys(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestVerify tests accepted and rejected requests
+func TestVerify(t *testing.T) {
+	keys := []Key{{ID: "bot", Secret: testSecret, Scopes: []string{ScopeTelegramLink}}}
+	now := time.Unix(1735689600, 0)
+	body := []byte(`{"telegram_id":42}`)
+
+	newRequest := func(keyID string, timestamp time.Time, signedBody []byte) *http.Request {
+		r := httptest.NewRequest("POST", "/telegram/check-status", strings.NewReader(string(body)))
+		r.Header.Set(KeyIDHeader, keyID)
+		r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		r.Header.Set(NonceHeader, "n0nce")
+		r.Header.Set(SignatureHeader, Sign(testSecret, "POST", "/telegram/check-status", timestamp.Unix(), "n0nce", signedBody))
+		return r
+	}
+
+	key, err := Verify(keys, newRequest("bot", now, body), body, now, 5*time.Minute)
+	assert.NoError(t, err)
+	if assert.NotNil(t, key) {
+		assert.Equal(t, "bot", key.ID)
+	}
+
+	_, err = Verify(keys, newRequest("other", now, body), body, now, 5*time.Minute)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = Verify(keys, newRequest("bot", now.Add(-10*time.Minute), body), body, now, 5*time.Minute)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	_, err = Verify(keys, newRequest("bot", now, []byte(`{"telegram_id":1}`)), body, now, 5*time.Minute)
+	assert.ErrorIs(t, err, ErrBadSignature)
+
+	unsigned := httptest.NewRequest("POST", "/telegram/check-status", nil)
+	_, err = Verify(keys, unsigned, nil, now, 5*time.Minute)
+	assert.ErrorIs(t, err, ErrMissingSignature)
+}
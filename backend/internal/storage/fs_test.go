@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFSStorageObjects tests storing, reading and deleting objects on disk
+func TestFSStorageObjects(t *testing.T) {
+	s, err := NewFSStorage(t.TempDir(), "", "secret")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, s.Put(ctx, "avatars/u/a.jpg", []byte("jpeg"), "image/jpeg"))
+
+	data, err := s.Get(ctx, "avatars/u/a.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), data)
+
+	exists, err := s.Exists(ctx, "avatars/u/a.jpg")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, s.Delete(ctx, "avatars/u/a.jpg"))
+	assert.NoError(t, s.Delete(ctx, "avatars/u/a.jpg"))
+
+	_, err = s.Get(ctx, "avatars/u/a.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, key := range []string{"", "../secret", "a/../../b", "/etc/passwd", "a//b"} {
+		assert.Error(t, s.Put(ctx, key, []byte("x"), ""), key)
+	}
+}
+
+// TestFSStorageSignedURL tests serving objects by signed URLs
+func TestFSStorageSignedURL(t *testing.T) {
+	s, err := NewFSStorage(t.TempDir(), "https://api.example.com/", "secret")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put(context.Background(), "audio/a.mp3", []byte("ID3"), "audio/mpeg"))
+
+	signed, err := s.SignedURL("audio/a.mp3", time.Minute)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "api.example.com", u.Host)
+	assert.Equal(t, "/media/audio/a.mp3", u.Path)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := serve(u.RequestURI())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ID3", rec.Body.String())
+	assert.Equal(t, "audio/mpeg", rec.Header().Get("Content-Type"))
+
+	// Another key with the same signature
+	query := u.Query()
+	assert.Equal(t, http.StatusForbidden, serve("/media/audio/b.mp3?"+query.Encode()).Code)
+
+	// Expired link
+	expired, err := s.SignedURL("audio/a.mp3", -time.Minute)
+	assert.NoError(t, err)
+	u, _ = url.Parse(expired)
+	assert.Equal(t, http.StatusForbidden, serve(u.RequestURI()).Code)
+}
+
+// TestIsKey tests telling storage keys from external URLs
+func TestIsKey(t *testing.T) {
+	assert.True(t, IsKey("audio/a.mp3"))
+	assert.False(t, IsKey("https://api.dictionaryapi.dev/media/a.mp3"))
+	assert.False(t, IsKey(""))
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fluently/go-backend/internal/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// ErrNotFound is returned when the object doesn't exist
+var ErrNotFound = errors.New("object not found")
+
+const maxObjectSize = 50 << 20
+
+// S3Client stores and reads objects of a single bucket
+type S3Client struct {
+	client     *minio.Client
+	bucket     string
+	publicURL  string
+	publicBase bool // objects are publicly readable from publicURL and aren't presigned
+}
+
+// NewS3Client creates a new S3 client
+func NewS3Client(cfg config.StorageConfig) (*S3Client, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket are required")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || endpoint.Path != "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	c := &S3Client{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+	}
+	if c.publicURL == "" {
+		c.publicURL = bucketURL(endpoint, cfg.Bucket, cfg.PathStyle)
+	} else {
+		c.publicBase = true
+	}
+
+	return c, nil
+}
+
+// Put uploads an object
+func (c *S3Client) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := c.client.PutObject(ctx, c.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+
+	return nil
+}
+
+// Get downloads an object
+func (c *S3Client) Get(ctx context.Context, key string) ([]byte, error) {
+	object, err := c.client.GetObject(ctx, c.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, maxObjectSize+1))
+	if isNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	if len(data) > maxObjectSize {
+		return nil, fmt.Errorf("object is larger than %d bytes", maxObjectSize)
+	}
+
+	return data, nil
+}
+
+// Exists checks if an object exists
+func (c *S3Client) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.client.StatObject(ctx, c.bucket, key, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	return true, nil
+}
+
+// Delete removes an object, deleting a missing one isn't an error
+func (c *S3Client) Delete(ctx context.Context, key string) error {
+	err := c.client.RemoveObject(ctx, c.bucket, key, minio.RemoveObjectOptions{})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+
+	return nil
+}
+
+// URL returns the public URL of an object
+func (c *S3Client) URL(key string) string {
+	return c.publicURL + "/" + s3utils.EncodePath(key)
+}
+
+// SignedURL returns a URL granting read access to an object for ttl. With S3_PUBLIC_URL
+// set the bucket is assumed to be public and the plain public URL is returned.
+func (c *S3Client) SignedURL(key string, ttl time.Duration) (string, error) {
+	if c.publicBase {
+		return c.URL(key), nil
+	}
+
+	signed, err := c.client.PresignedGetObject(context.Background(), c.bucket, key, ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign object URL: %w", err)
+	}
+
+	return signed.String(), nil
+}
+
+// bucketURL is the base URL of the bucket
+func bucketURL(endpoint *url.URL, bucket string, pathStyle bool) string {
+	if pathStyle {
+		return endpoint.Scheme + "://" + endpoint.Host + "/" + bucket
+	}
+	return endpoint.Scheme + "://" + bucket + "." + endpoint.Host
+}
+
+// isNotFound reports whether an S3 error means the object or bucket is missing
+func isNotFound(err error) bool {
+	return err != nil && minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,36 @@
+// Package storage keeps media (audio, avatars) in S3-compatible object storage (AWS S3,
+// MinIO, R2) or, when none is configured, in a local directory served by the API
+package storage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"fluently/go-backend/internal/config"
+)
+
+// Storage stores objects by key and hands out URLs clients can read them from
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL the object can be read from for ttl
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// New creates the configured storage: S3 when an endpoint is set, a directory otherwise
+func New(cfg config.StorageConfig) (Storage, error) {
+	if cfg.Endpoint != "" {
+		return NewS3Client(cfg)
+	}
+	return NewFSStorage(cfg.Dir, cfg.BaseURL, cfg.SigningKey)
+}
+
+// IsKey reports whether a stored media reference is a storage key rather than
+// an external URL. Empty references are neither.
+func IsKey(ref string) bool {
+	return ref != "" && !strings.Contains(ref, "://")
+}
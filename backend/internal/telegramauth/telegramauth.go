@@ -0,0 +1,190 @@
+// Package telegramauth verifies users signed in by Telegram: the Login Widget
+// on the website and initData of Mini Apps. Both are signed with the bot token,
+// see https://core.telegram.org/widgets/login and https://core.telegram.org/bots/webapps.
+package telegramauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxClockSkew tolerates auth dates slightly ahead of our clock
+const maxClockSkew = time.Minute
+
+var (
+	ErrNoBotToken  = errors.New("telegram bot token is not configured")
+	ErrMissingHash = errors.New("missing hash")
+	ErrInvalidHash = errors.New("invalid hash")
+	ErrExpired     = errors.New("auth data expired")
+	ErrInvalidData = errors.New("invalid auth data")
+	ErrMissingUser = errors.New("missing user")
+)
+
+// User is a Telegram user who signed in
+type User struct {
+	ID        int64     `json:"id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	PhotoURL  string    `json:"photo_url,omitempty"`
+	AuthDate  time.Time `json:"-"`
+}
+
+// Name returns the full name of the user, or the username when they have none
+func (u User) Name() string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" {
+		name = u.Username
+	}
+	return name
+}
+
+// WidgetData is what the Login Widget passes to its callback
+type WidgetData struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	PhotoURL  string `json:"photo_url,omitempty"`
+	AuthDate  int64  `json:"auth_date"`
+	Hash      string `json:"hash"`
+}
+
+// VerifyWidget checks Login Widget data, the key is the SHA-256 of the bot token
+func VerifyWidget(botToken string, data WidgetData, now time.Time, maxAge time.Duration) (*User, error) {
+	if botToken == "" {
+		return nil, ErrNoBotToken
+	}
+	if data.Hash == "" {
+		return nil, ErrMissingHash
+	}
+	if data.ID == 0 {
+		return nil, ErrMissingUser
+	}
+
+	// The widget leaves out empty fields, so they aren't part of the signed data either
+	fields := map[string]string{
+		"id":        strconv.FormatInt(data.ID, 10),
+		"auth_date": strconv.FormatInt(data.AuthDate, 10),
+	}
+	for key, value := range map[string]string{
+		"first_name": data.FirstName,
+		"last_name":  data.LastName,
+		"username":   data.Username,
+		"photo_url":  data.PhotoURL,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	secret := sha256.Sum256([]byte(botToken))
+	if !checkHash(secret[:], fields, data.Hash) {
+		return nil, ErrInvalidHash
+	}
+
+	authDate, err := checkAuthDate(data.AuthDate, now, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &User{
+		ID:        data.ID,
+		FirstName: data.FirstName,
+		LastName:  data.LastName,
+		Username:  data.Username,
+		PhotoURL:  data.PhotoURL,
+		AuthDate:  authDate,
+	}, nil
+}
+
+// VerifyInitData checks Mini App initData, the key is the HMAC-SHA256 of the bot token keyed with "WebAppData"
+func VerifyInitData(botToken, initData string, now time.Time, maxAge time.Duration) (*User, error) {
+	if botToken == "" {
+		return nil, ErrNoBotToken
+	}
+
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, ErrInvalidData
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return nil, ErrMissingHash
+	}
+
+	fields := make(map[string]string, len(values))
+	for key := range values {
+		if key != "hash" {
+			fields[key] = values.Get(key)
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte("WebAppData"))
+	mac.Write([]byte(botToken))
+	if !checkHash(mac.Sum(nil), fields, hash) {
+		return nil, ErrInvalidHash
+	}
+
+	authDateUnix, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidData
+	}
+	authDate, err := checkAuthDate(authDateUnix, now, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	if values.Get("user") == "" {
+		return nil, ErrMissingUser
+	}
+	var user User
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return nil, ErrMissingUser
+	}
+	user.AuthDate = authDate
+
+	return &user, nil
+}
+
+// checkHash compares the hash with the HMAC-SHA256 of the data-check-string:
+// fields sorted by key as key=value lines
+func checkHash(secret []byte, fields map[string]string, hash string) bool {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+"="+fields[key])
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(hash)))
+}
+
+// checkAuthDate rejects data signed too long ago, so leaked data can't be used forever
+func checkAuthDate(unix int64, now time.Time, maxAge time.Duration) (time.Time, error) {
+	authDate := time.Unix(unix, 0)
+	if unix <= 0 || authDate.After(now.Add(maxClockSkew)) {
+		return time.Time{}, ErrInvalidData
+	}
+	if now.Sub(authDate) > maxAge {
+		return time.Time{}, ErrExpired
+	}
+	return authDate, nil
+}
@@ -0,0 +1,83 @@
+package telegramauth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBotToken = "123456:ABC-test-token"
+
+var signedAt = time.Unix(1735689600, 0)
+
+// TestVerifyWidget tests Login Widget data against a hash computed with openssl
+func TestVerifyWidget(t *testing.T) {
+	data := WidgetData{
+		ID:        42,
+		FirstName: "Ivan",
+		Username:  "ivan",
+		AuthDate:  signedAt.Unix(),
+		Hash:      "c6bb68bb321b223fa35e49bbd54635e2725d351b971568092ff4259e9f1dc4e4",
+	}
+
+	user, err := VerifyWidget(testBotToken, data, signedAt.Add(time.Hour), 24*time.Hour)
+	assert.NoError(t, err)
+	if assert.NotNil(t, user) {
+		assert.Equal(t, int64(42), user.ID)
+		assert.Equal(t, "Ivan", user.Name())
+		assert.Equal(t, signedAt, user.AuthDate)
+	}
+
+	_, err = VerifyWidget(testBotToken, data, signedAt.Add(48*time.Hour), 24*time.Hour)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	_, err = VerifyWidget("654321:other-bot", data, signedAt, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidHash)
+
+	forged := data
+	forged.ID = 43
+	_, err = VerifyWidget(testBotToken, forged, signedAt, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidHash)
+
+	_, err = VerifyWidget("", data, signedAt, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrNoBotToken)
+}
+
+// TestVerifyInitData tests Mini App initData against a hash computed with openssl
+func TestVerifyInitData(t *testing.T) {
+	values := url.Values{
+		"query_id":  {"AAH"},
+		"user":      {`{"id":42,"first_name":"Ivan","username":"ivan"}`},
+		"auth_date": {"1735689600"},
+		"hash":      {"c75f11d1a6f7dbdae27113cb498787937ff60962f2cd38b621f176ad4072529f"},
+	}
+
+	user, err := VerifyInitData(testBotToken, values.Encode(), signedAt.Add(time.Minute), time.Hour)
+	assert.NoError(t, err)
+	if assert.NotNil(t, user) {
+		assert.Equal(t, int64(42), user.ID)
+		assert.Equal(t, "ivan", user.Username)
+	}
+
+	// Widget hashes are keyed differently, one can't pass for the other
+	_, err = VerifyWidget(testBotToken, WidgetData{
+		ID: 42, FirstName: "Ivan", Username: "ivan", AuthDate: signedAt.Unix(), Hash: values.Get("hash"),
+	}, signedAt, time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidHash)
+
+	tampered := url.Values{}
+	for k, v := range values {
+		tampered[k] = v
+	}
+	tampered.Set("user", `{"id":1,"first_name":"Ivan","username":"ivan"}`)
+	_, err = VerifyInitData(testBotToken, tampered.Encode(), signedAt, time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidHash)
+
+	_, err = VerifyInitData(testBotToken, values.Encode(), signedAt.Add(2*time.Hour), time.Hour)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	_, err = VerifyInitData(testBotToken, "auth_date=1735689600", signedAt, time.Hour)
+	assert.ErrorIs(t, err, ErrMissingHash)
+}
@@ -0,0 +1,470 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// ankiFieldSeparator separates note fields in the notes.flds column
+const ankiFieldSeparator = "\x1f"
+
+// ankiCardTypeReview is the card type of cards that graduated from learning
+const ankiCardTypeReview = 2
+
+// ankiFields are the note fields of decks written by WriteAnkiPackage
+var ankiFields = []string{"Word", "Translation", "Sentence", "SentenceTranslation", "Audio"}
+
+var (
+	ankiSoundTagRegex  = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	ankiLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>`)
+	ankiHTMLTagRegex   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ankiSchema is the legacy (schema 11) collection layout that every Anki version can import
+const ankiSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+// ReadAnkiPackage reads notes of an Anki .apkg package as vocabulary entries.
+// A note is treated as learned when any of its cards has graduated to review.
+// The collection is unpacked to disk, packages whose collection exceeds maxSize bytes are rejected.
+func ReadAnkiPackage(ctx context.Context, data []byte, maxSize int64) ([]VocabularyEntry, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid apkg archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	// collection.anki21 holds the real data, collection.anki2 is a stub for old clients when both exist
+	collection := files["collection.anki21"]
+	if collection == nil {
+		collection = files["collection.anki2"]
+	}
+	if collection == nil {
+		if files["collection.anki21b"] != nil {
+			return nil, errors.New("apkg uses the compressed format of Anki 2.1.50+, export it with \"Support older Anki versions\" enabled")
+		}
+		return nil, errors.New("apkg does not contain a collection")
+	}
+
+	dir, err := os.MkdirTemp("", "apkg-import-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.sqlite")
+	if err := extractZipFile(collection, path, maxSize); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open collection: %w", err)
+	}
+	defer db.Close()
+
+	fieldNames, err := readAnkiFieldNames(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT n.mid, n.flds, COALESCE(MAX(c.type), 0)
+		FROM notes n
+		LEFT JOIN cards c ON c.nid = n.id
+		GROUP BY n.id
+		ORDER BY n.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []VocabularyEntry
+	for rows.Next() {
+		var (
+			modelID  int64
+			flds     string
+			cardType int
+		)
+		if err := rows.Scan(&modelID, &flds, &cardType); err != nil {
+			return nil, fmt.Errorf("failed to read note: %w", err)
+		}
+
+		entry := ankiNoteToEntry(strings.Split(flds, ankiFieldSeparator), fieldNames[modelID])
+		if entry.Word == "" {
+			continue
+		}
+
+		entry.Status = VocabularyStatusNotLearned
+		if cardType == ankiCardTypeReview {
+			entry.Status = VocabularyStatusLearned
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// WriteAnkiPackage writes entries as an Anki .apkg package with a single deck.
+// Audio is referenced by URL instead of being bundled as media.
+func WriteAnkiPackage(ctx context.Context, w io.Writer, deckName string, entries []VocabularyEntry) error {
+	dir, err := os.MkdirTemp("", "apkg-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeAnkiCollection(ctx, path, deckName, entries); err != nil {
+		return err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	f, err := archive.Create("collection.anki2")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(collection); err != nil {
+		return err
+	}
+
+	media, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := media.Write([]byte("{}")); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// writeAnkiCollection creates the sqlite collection file of a package
+func writeAnkiCollection(ctx context.Context, path, deckName string, entries []VocabularyEntry) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, ankiSchema); err != nil {
+		return fmt.Errorf("failed to create collection schema: %w", err)
+	}
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	deckID := now.UnixMilli()
+	modelID := deckID + 1
+
+	conf, models, decks, dconf, err := ankiCollectionConfig(now, deckID, modelID, deckName)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		dayStart.Unix(), now.UnixMilli(), now.UnixMilli(), conf, models, decks, dconf,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	for i, entry := range entries {
+		id := deckID + int64(i) + 2
+		fields := ankiEntryToFields(entry)
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`,
+			id, ankiGUID(entry), modelID, now.Unix(), strings.Join(fields, ankiFieldSeparator), fields[0], ankiChecksum(entry.Word),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write note: %w", err)
+		}
+
+		// Learned words become review cards due tomorrow, the rest stay new in file order
+		cardType, queue, due, interval, factor, reps := 0, 0, i+1, 0, 0, 0
+		if entry.Status == VocabularyStatusLearned {
+			cardType, queue, due, interval, factor, reps = ankiCardTypeReview, ankiCardTypeReview, 1, 1, 2500, 1
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, 0, '')`,
+			id, id, deckID, now.Unix(), cardType, queue, due, interval, factor, reps,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write card: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ankiCollectionConfig builds the JSON columns of the col table
+func ankiCollectionConfig(now time.Time, deckID, modelID int64, deckName string) (conf, models, decks, dconf string, err error) {
+	fields := make([]map[string]any, len(ankiFields))
+	for i, name := range ankiFields {
+		fields[i] = map[string]any{
+			"name": name, "ord": i, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+
+	model := map[string]any{
+		"id":    modelID,
+		"name":  "Fluently",
+		"type":  0,
+		"mod":   now.Unix(),
+		"usn":   -1,
+		"sortf": 0,
+		"did":   deckID,
+		"flds":  fields,
+		"tmpls": []map[string]any{{
+			"name":  "Card 1",
+			"ord":   0,
+			"qfmt":  "{{Word}}",
+			"afmt":  "{{FrontSide}}<hr id=answer>{{Translation}}<br><br>{{Sentence}}<br><i>{{SentenceTranslation}}</i>{{#Audio}}<br><audio controls src=\"{{Audio}}\"></audio>{{/Audio}}",
+			"did":   nil,
+			"bqfmt": "",
+			"bafmt": "",
+		}},
+		"css":       ".card { font-family: arial; font-size: 20px; text-align: center; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []any{},
+		"req":       []any{[]any{0, "any", []int{0}}},
+	}
+
+	deck := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "mod": now.Unix(), "usn": -1, "desc": "", "dyn": 0, "conf": 1,
+			"collapsed": false, "browserCollapsed": false, "extendNew": 0, "extendRev": 0,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+
+	values := []any{
+		map[string]any{
+			"nextPos": 1, "estTimes": true, "activeDecks": []int64{deckID}, "sortType": "noteFld", "timeLim": 0,
+			"sortBackwards": false, "addToCur": true, "curDeck": deckID, "newSpread": 0, "dueCounts": true,
+			"curModel": strconv.FormatInt(modelID, 10), "collapseTime": 1200,
+		},
+		map[string]any{strconv.FormatInt(modelID, 10): model},
+		map[string]any{"1": deck(1, "Default"), strconv.FormatInt(deckID, 10): deck(deckID, deckName)},
+		map[string]any{"1": map[string]any{
+			"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+			"new":   map[string]any{"bury": false, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 0}, "order": 1, "perDay": 20},
+			"lapse": map[string]any{"delays": []int{10}, "leechAction": 1, "leechFails": 8, "minInt": 1, "mult": 0},
+			"rev":   map[string]any{"bury": false, "ease4": 1.3, "maxIvl": 36500, "perDay": 200, "hardFactor": 1.2},
+		}},
+	}
+
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("failed to marshal collection config: %w", err)
+		}
+		encoded[i] = string(data)
+	}
+
+	return encoded[0], encoded[1], encoded[2], encoded[3], nil
+}
+
+// readAnkiFieldNames returns the lower-cased field names of every note type in the collection
+func readAnkiFieldNames(ctx context.Context, db *sql.DB) (map[int64][]string, error) {
+	var raw string
+	if err := db.QueryRowContext(ctx, "SELECT models FROM col").Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to read note types: %w", err)
+	}
+
+	var models map[string]struct {
+		Flds []struct {
+			Name string `json:"name"`
+		} `json:"flds"`
+	}
+	if err := json.Unmarshal([]byte(raw), &models); err != nil {
+		return nil, fmt.Errorf("failed to parse note types: %w", err)
+	}
+
+	names := make(map[int64][]string, len(models))
+	for id, model := range models {
+		modelID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		for _, f := range model.Flds {
+			names[modelID] = append(names[modelID], strings.ToLower(f.Name))
+		}
+	}
+
+	return names, nil
+}
+
+// ankiNoteToEntry maps note fields to an entry by field name, falling back to the field order
+func ankiNoteToEntry(fields, names []string) VocabularyEntry {
+	var (
+		entry                          VocabularyEntry
+		sentences, sentenceTranslation string
+		positional                     []string
+	)
+
+	for i, value := range fields {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+
+		switch {
+		case strings.Contains(name, "sentence") && strings.Contains(name, "translation"),
+			strings.Contains(name, "example") && strings.Contains(name, "translation"):
+			sentenceTranslation = value
+		case strings.Contains(name, "sentence"), strings.Contains(name, "example"):
+			sentences = value
+		case strings.Contains(name, "audio"), strings.Contains(name, "sound"):
+			entry.AudioURL = stripAnkiHTML(value)
+		default:
+			positional = append(positional, value)
+		}
+	}
+
+	if len(positional) > 0 {
+		entry.Word = stripAnkiHTML(positional[0])
+	}
+	if len(positional) > 1 {
+		entry.Translation = stripAnkiHTML(positional[1])
+	}
+	if sentences == "" && len(positional) > 2 {
+		sentences = positional[2]
+	}
+
+	translations := ankiLineBreakRegex.Split(sentenceTranslation, -1)
+	for i, text := range ankiLineBreakRegex.Split(sentences, -1) {
+		sentence := VocabularySentence{Sentence: stripAnkiHTML(text)}
+		if sentence.Sentence == "" {
+			continue
+		}
+		if i < len(translations) {
+			sentence.Translation = stripAnkiHTML(translations[i])
+		}
+		entry.Sentences = append(entry.Sentences, sentence)
+	}
+
+	return entry
+}
+
+// ankiEntryToFields renders an entry as note fields in the ankiFields order
+func ankiEntryToFields(entry VocabularyEntry) []string {
+	sentences := make([]string, 0, len(entry.Sentences))
+	translations := make([]string, 0, len(entry.Sentences))
+	for _, s := range entry.Sentences {
+		sentences = append(sentences, html.EscapeString(s.Sentence))
+		translations = append(translations, html.EscapeString(s.Translation))
+	}
+
+	return []string{
+		html.EscapeString(entry.Word),
+		html.EscapeString(entry.Translation),
+		strings.Join(sentences, "<br>"),
+		strings.Join(translations, "<br>"),
+		html.EscapeString(entry.AudioURL),
+	}
+}
+
+// stripAnkiHTML turns a field value into plain text
+func stripAnkiHTML(value string) string {
+	value = ankiSoundTagRegex.ReplaceAllString(value, "")
+	value = ankiLineBreakRegex.ReplaceAllString(value, " ")
+	value = ankiHTMLTagRegex.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// ankiGUID derives a stable note guid so re-imported decks update existing notes
+func ankiGUID(entry VocabularyEntry) string {
+	sum := sha1.Sum([]byte(entry.Word + ankiFieldSeparator + entry.Translation))
+	return base64.RawStdEncoding.EncodeToString(sum[:8])
+}
+
+// ankiChecksum is the first 8 hex digits of the sha1 of the sort field, as Anki computes it
+func ankiChecksum(value string) int64 {
+	sum := sha1.Sum([]byte(stripAnkiHTML(value)))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+// extractZipFile writes a single archive member to path, failing once it exceeds maxSize bytes
+func extractZipFile(f *zip.File, path string, maxSize int64) error {
+	if f.UncompressedSize64 > uint64(maxSize) {
+		return fmt.Errorf("%s is larger than %d bytes", f.Name, maxSize)
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	// the header size can lie, so the copy is bounded as well
+	n, err := io.Copy(dst, io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	if n > maxSize {
+		return fmt.Errorf("%s is larger than %d bytes", f.Name, maxSize)
+	}
+
+	return dst.Close()
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testApkgMaxSize is the unpacked collection limit used in tests
+const testApkgMaxSize = 16 << 20
+
+// TestAnkiPackageRoundTrip tests that written packages are read back with fields and learned state
+func TestAnkiPackageRoundTrip(t *testing.T) {
+	entries := []VocabularyEntry{
+		{
+			Word:        "harbor",
+			Translation: "гавань <порт>",
+			Status:      VocabularyStatusLearned,
+			AudioURL:    "https://example.com/harbor.mp3",
+			Sentences: []VocabularySentence{
+				{Sentence: "The harbor is calm & quiet.", Translation: "Гавань спокойна."},
+				{Sentence: "Ships left the harbor.", Translation: "Корабли покинули гавань."},
+			},
+		},
+		{Word: "run", Translation: "бежать", Status: VocabularyStatusNotLearned},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteAnkiPackage(context.Background(), &buf, "Fluently", entries))
+
+	parsed, err := ReadAnkiPackage(context.Background(), buf.Bytes(), testApkgMaxSize)
+	require.NoError(t, err)
+	assert.Equal(t, entries, parsed)
+}
+
+// TestReadAnkiPackageMalformed tests that broken, unsupported and oversized packages are rejected
+func TestReadAnkiPackageMalformed(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := ReadAnkiPackage(ctx, []byte("not a zip archive"), testApkgMaxSize)
+	assert.ErrorContains(t, err, "invalid apkg archive")
+
+	_, err = ReadAnkiPackage(ctx, testZip(t, map[string][]byte{"media": []byte("{}")}), testApkgMaxSize)
+	assert.ErrorContains(t, err, "does not contain a collection")
+
+	_, err = ReadAnkiPackage(ctx, testZip(t, map[string][]byte{"collection.anki21b": []byte("zstd")}), testApkgMaxSize)
+	assert.ErrorContains(t, err, "Support older Anki versions")
+
+	_, err = ReadAnkiPackage(ctx, testZip(t, map[string][]byte{"collection.anki2": []byte("not a sqlite database")}), testApkgMaxSize)
+	assert.Error(t, err)
+
+	// a highly compressible collection stays small in the archive but exceeds the unpacked limit
+	_, err = ReadAnkiPackage(ctx, testZip(t, map[string][]byte{"collection.anki2": make([]byte, 1<<20)}), 1<<10)
+	assert.ErrorContains(t, err, "larger than 1024 bytes")
+}
+
+// testZip builds an in-memory zip archive with the given files
+func testZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register decoders of accepted avatar formats
+	_ "image/gif"
+	_ "image/png"
+)
+
+// maxAvatarPixels bounds the decoded size of an avatar, so small files can't expand
+// into huge images
+const maxAvatarPixels = 40_000_000
+
+// avatarQuality is the JPEG quality of processed avatars
+const avatarQuality = 85
+
+var (
+	// ErrUnsupportedImage is returned for files that aren't JPEG, PNG or GIF images
+	ErrUnsupportedImage = errors.New("unsupported image format, use JPEG, PNG or GIF")
+	// ErrImageTooLarge is returned for images with too many pixels
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+// ProcessAvatar crops an uploaded image to a centered square, scales it down to size
+// pixels and re-encodes it as JPEG. Re-encoding also strips metadata such as EXIF location.
+func ProcessAvatar(data []byte, size int) ([]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if format != "jpeg" && format != "png" && format != "gif" {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	// Centered square, flattened on white since JPEG has no transparency
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(square, square.Bounds(), src, origin, draw.Over)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scaleDown(square, size), &jpeg.Options{Quality: avatarQuality}); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// scaleDown resizes a square image to size pixels by averaging the source pixels
+// each target pixel covers. Images already no larger than size are returned as they are.
+func scaleDown(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	if size <= 0 || side <= size {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -10,8 +10,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// CleanupExpiredTokens deletes expired link tokens
-func CleanupExpiredTokens(repo *postgres.LinkTokenRepository) {
+// CleanupExpiredTokens deletes expired link tokens and friend invites
+func CleanupExpiredTokens(repo *postgres.LinkTokenRepository, friendRepo *postgres.FriendRepository) {
 	ctx := context.Background()
 
 	if err := repo.DeleteExpired(ctx); err != nil {
@@ -19,14 +19,18 @@ func CleanupExpiredTokens(repo *postgres.LinkTokenRepository) {
 	} else {
 		logger.Log.Info("Successfully cleaned up expired link tokens")
 	}
+
+	if err := friendRepo.DeleteExpiredInvites(ctx); err != nil {
+		logger.Log.Error("Failed to cleanup expired friend invites", zap.Error(err))
+	}
 }
 
 // StartTokenCleanupTask starts a periodic token cleanup task
-func StartTokenCleanupTask(repo *postgres.LinkTokenRepository, interval time.Duration) {
+func StartTokenCleanupTask(repo *postgres.LinkTokenRepository, friendRepo *postgres.FriendRepository, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	go func() {
 		for range ticker.C {
-			CleanupExpiredTokens(repo)
+			CleanupExpiredTokens(repo, friendRepo)
 		}
 	}()
 
@@ -2,12 +2,16 @@ package utils
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"fluently/go-backend/internal/middleware"
 	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
@@ -25,6 +29,78 @@ func ParseIntParam(r *http.Request, param string) (int, error) {
 	return strconv.Atoi(chi.URLParam(r, param))
 }
 
+// ListParams are the paging and sort query parameters of list endpoints
+type ListParams struct {
+	Limit int
+	Sort  string   // one of the allowed sort keys
+	Desc  bool     // the sort was prefixed with "-"
+	After []string // key of the last item of the previous page, nil for the first page
+}
+
+// listCursor is the opaque next_cursor handed to clients
+type listCursor struct {
+	Sort string   `json:"s"`
+	Key  []string `json:"k"`
+}
+
+// ParseListParams parses the limit, sort and cursor query parameters.
+// sorts are the allowed sort keys, the first one is the default; "-key" sorts descending,
+// so a first key of "-created_at" lists newest first by default.
+func ParseListParams(r *http.Request, defaultLimit, maxLimit int, sorts ...string) (ListParams, error) {
+	query := r.URL.Query()
+	params := ListParams{Limit: defaultLimit}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return ListParams{}, errors.New("invalid limit")
+		}
+		params.Limit = min(limit, maxLimit)
+	}
+
+	sort := query.Get("sort")
+	if sort == "" && len(sorts) > 0 {
+		sort = sorts[0]
+	}
+	params.Desc = strings.HasPrefix(sort, "-")
+	params.Sort = strings.TrimPrefix(sort, "-")
+	allowed := false
+	for _, s := range sorts {
+		allowed = allowed || strings.TrimPrefix(s, "-") == params.Sort
+	}
+	if !allowed {
+		return ListParams{}, errors.New("invalid sort")
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		data, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			return ListParams{}, postgres.ErrInvalidCursor
+		}
+		var cursor listCursor
+		if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || len(cursor.Key) == 0 {
+			return ListParams{}, postgres.ErrInvalidCursor
+		}
+		params.After = cursor.Key
+	}
+
+	return params, nil
+}
+
+// NextCursor encodes the key of the last item of a page, empty when there is no next page
+func (p ListParams) NextCursor(key []string) string {
+	if key == nil {
+		return ""
+	}
+
+	sort := p.Sort
+	if p.Desc {
+		sort = "-" + sort
+	}
+	data, _ := json.Marshal(listCursor{Sort: sort, Key: key})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
 // GetCurrentUser retrieves the current authenticated user from context
 // This works with the new go-chi/jwtauth system
 func GetCurrentUser(ctx context.Context) (*models.User, error) {
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"fluently/go-backend/internal/repository/postgres"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseListParams tests defaults, limits and sorts of list query parameters
+func TestParseListParams(t *testing.T) {
+	parse := func(query string) (ListParams, error) {
+		return ParseListParams(httptest.NewRequest("GET", "/words?"+query, nil), 50, 200, "word", "cefr_level")
+	}
+
+	params, err := parse("")
+	require.NoError(t, err)
+	assert.Equal(t, ListParams{Limit: 50, Sort: "word"}, params)
+
+	params, err = parse("limit=1000&sort=-cefr_level")
+	require.NoError(t, err)
+	assert.Equal(t, 200, params.Limit)
+	assert.Equal(t, "cefr_level", params.Sort)
+	assert.True(t, params.Desc)
+
+	for _, query := range []string{"limit=0", "limit=ten", "sort=id", "cursor=!!", "cursor=e30"} {
+		_, err := parse(query)
+		assert.Error(t, err, query)
+	}
+
+	newestFirst := func(query string) (ListParams, error) {
+		return ParseListParams(httptest.NewRequest("GET", "/capture?"+query, nil), 20, 100, "-created_at")
+	}
+	params, err = newestFirst("")
+	require.NoError(t, err)
+	assert.Equal(t, ListParams{Limit: 20, Sort: "created_at", Desc: true}, params)
+
+	params, err = newestFirst("sort=created_at")
+	require.NoError(t, err)
+	assert.False(t, params.Desc)
+}
+
+// TestListCursor tests that cursors round trip and only fit the sort they were issued for
+func TestListCursor(t *testing.T) {
+	first, err := ParseListParams(httptest.NewRequest("GET", "/words?sort=-word", nil), 50, 200, "word")
+	require.NoError(t, err)
+	assert.Empty(t, first.NextCursor(nil), "no cursor on the last page")
+
+	cursor := first.NextCursor([]string{"apple", "7d8c7f4e-0000-4000-8000-000000000001"})
+	require.NotEmpty(t, cursor)
+
+	next, err := ParseListParams(httptest.NewRequest("GET", "/words?sort=-word&cursor="+url.QueryEscape(cursor), nil), 50, 200, "word")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apple", "7d8c7f4e-0000-4000-8000-000000000001"}, next.After)
+
+	_, err = ParseListParams(httptest.NewRequest("GET", "/words?sort=word&cursor="+url.QueryEscape(cursor), nil), 50, 200, "word")
+	assert.ErrorIs(t, err, postgres.ErrInvalidCursor)
+}
@@ -1,29 +1,58 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/jwtkeys"
 	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/pkg/logger"
 
 	"crypto/rand"
 	"encoding/base64"
 
 	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"go.uber.org/zap"
 )
 
+// TokenAuth signs and verifies HS256 tokens with JWT_SECRET, it's nil when the secret isn't set
 var TokenAuth *jwtauth.JWTAuth
 
-// InitJWTAuth initializes the JWT authenticator with the secret from config
+// SigningKeys signs tokens with asymmetric keys, it's nil unless JWT_KEYS_DIR is set
+var SigningKeys *jwtkeys.Manager
+
+// InitJWTAuth initializes JWT signing from config: asymmetric keys when JWT_KEYS_DIR is set, the HS256 secret otherwise
 func InitJWTAuth() {
 	cfg := config.GetConfig()
-	if cfg.Auth.JWTSecret == "" {
-		panic("JWT_SECRET environment variable is required but not set")
+	if cfg.Auth.JWTSecret == "" && cfg.Auth.JWTKeysDir == "" {
+		panic("JWT_KEYS_DIR or JWT_SECRET environment variable is required but not set")
+	}
+
+	// With signing keys the secret stays only to accept tokens issued before the switch
+	if cfg.Auth.JWTSecret != "" {
+		TokenAuth = jwtauth.New("HS256", []byte(cfg.Auth.JWTSecret), nil)
+	}
+
+	if cfg.Auth.JWTKeysDir != "" {
+		keys, err := jwtkeys.New(jwtkeys.Config{
+			Dir:        cfg.Auth.JWTKeysDir,
+			Algorithm:  jwa.SignatureAlgorithm(cfg.Auth.JWTSigningAlg),
+			TokenTTL:   cfg.Auth.JWTExpiration,
+			PrePublish: cfg.Auth.JWTKeyPrePublish,
+		})
+		if err != nil {
+			panic("failed to load JWT signing keys: " + err.Error())
+		}
+		SigningKeys = keys
 	}
-	TokenAuth = jwtauth.New("HS256", []byte(cfg.Auth.JWTSecret), nil)
 }
 
-// GenerateJWT creates a signed JWT string for the provided user using go-chi/jwtauth
+// GenerateJWT creates a signed JWT string for the provided user
 func GenerateJWT(user *models.User) (string, error) {
 	cfg := config.GetConfig()
 
@@ -35,10 +64,92 @@ func GenerateJWT(user *models.User) (string, error) {
 		"iat":   time.Now().Unix(),
 	}
 
+	if SigningKeys != nil {
+		return SigningKeys.Sign(claims)
+	}
+
 	_, tokenString, err := TokenAuth.Encode(claims)
 	return tokenString, err
 }
 
+// ParseJWT verifies the signature and lifetime of an access token.
+// Tokens with a kid header are checked against the signing keys, ones without against the HS256 secret.
+func ParseJWT(tokenString string) (jwt.Token, error) {
+	kid, err := jwtkeys.KeyID(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if kid != "" {
+		if SigningKeys == nil {
+			return nil, jwtkeys.ErrUnknownKey
+		}
+		return SigningKeys.Verify(tokenString)
+	}
+
+	if TokenAuth == nil {
+		return nil, errors.New("tokens without kid are not accepted")
+	}
+	token, err := TokenAuth.Decode(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := jwt.Validate(token, jwt.WithAcceptableSkew(time.Minute)); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// JWKS returns the public keys tokens are signed with, empty while tokens are signed with the HS256 secret
+func JWKS() jwk.Set {
+	if SigningKeys == nil {
+		return jwk.NewSet()
+	}
+	return SigningKeys.PublicSet()
+}
+
+// StartJWTKeyRotationTask periodically rotates the signing keys. Instances sharing the key directory
+// take a Redis lock so only one of them generates the new key, the others pick it up on reload.
+func StartJWTKeyRotationTask(every, interval time.Duration) {
+	if SigningKeys == nil || every <= 0 {
+		return
+	}
+
+	rotate := func() {
+		ctx := context.Background()
+		lock, err := redisLocker().Obtain(ctx, "lock:jwt:rotate", time.Minute, nil)
+		if err != nil {
+			// Someone else is rotating or Redis is down, reading the directory is still safe
+			if err := SigningKeys.Load(); err != nil {
+				logger.Log.Error("Failed to reload JWT signing keys", zap.Error(err))
+			}
+			return
+		}
+		defer lock.Release(ctx)
+
+		rotated, err := SigningKeys.Rotate(time.Now(), every)
+		if err != nil {
+			logger.Log.Error("Failed to rotate JWT signing keys", zap.Error(err))
+			return
+		}
+		if rotated {
+			logger.Log.Info("Rotated JWT signing key", zap.Strings("keys", SigningKeys.Keys()))
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		rotate()
+		for range ticker.C {
+			rotate()
+		}
+	}()
+
+	logger.Log.Info("Started JWT key rotation task",
+		zap.Duration("rotation", every),
+		zap.Duration("interval", interval))
+}
+
 // GenerateRefreshToken generates a random refresh token
 func GenerateRefreshToken() (string, error) {
 	b := make([]byte, 32)
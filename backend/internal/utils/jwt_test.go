@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"fluently/go-backend/internal/jwtkeys"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseJWT tests verifying tokens of the signing keys next to legacy HS256 ones
+func TestParseJWT(t *testing.T) {
+	keys, err := jwtkeys.New(jwtkeys.Config{Dir: t.TempDir(), Algorithm: jwa.EdDSA, TokenTTL: time.Hour})
+	require.NoError(t, err)
+	TokenAuth = jwtauth.New("HS256", []byte("legacy-secret"), nil)
+	SigningKeys = keys
+	t.Cleanup(func() { TokenAuth, SigningKeys = nil, nil })
+
+	claims := func(exp time.Time) map[string]interface{} {
+		return map[string]interface{}{"sub": "user", "exp": exp.Unix()}
+	}
+
+	signed, err := keys.Sign(claims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	token, err := ParseJWT(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "user", token.Subject())
+
+	// Tokens issued with the secret before the switch stay valid until they expire
+	_, legacy, err := TokenAuth.Encode(claims(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	_, err = ParseJWT(legacy)
+	assert.NoError(t, err)
+
+	_, expired, err := TokenAuth.Encode(claims(time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	_, err = ParseJWT(expired)
+	assert.Error(t, err)
+
+	// Once the secret is removed only the signing keys are trusted
+	TokenAuth = nil
+	_, err = ParseJWT(legacy)
+	assert.Error(t, err)
+	_, err = ParseJWT(signed)
+	assert.NoError(t, err)
+}
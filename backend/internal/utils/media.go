@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/storage"
+	"fluently/go-backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	mediaOnce    sync.Once
+	mediaStorage storage.Storage
+)
+
+// Media returns the singleton media storage configured from the environment, or nil
+// when it can't be created
+func Media() storage.Storage {
+	mediaOnce.Do(func() {
+		s, err := storage.New(config.GetConfig().Storage)
+		if err != nil {
+			logger.Log.Error("Failed to initialize media storage", zap.Error(err))
+			return
+		}
+		mediaStorage = s
+	})
+	return mediaStorage
+}
+
+// MediaURL resolves a stored media reference for clients: storage keys become signed
+// URLs, external URLs and empty references are returned as they are
+func MediaURL(ref string) string {
+	if !storage.IsKey(ref) {
+		return ref
+	}
+
+	media := Media()
+	if media == nil {
+		return ""
+	}
+
+	signed, err := media.SignedURL(ref, config.GetConfig().Storage.URLTTL)
+	if err != nil {
+		logger.Log.Warn("Failed to sign media URL", zap.String("key", ref), zap.Error(err))
+		return ""
+	}
+
+	return signed
+}
+
+// mediaHTTPClient downloads remote media
+var mediaHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// MirrorRemoteAudio copies audio from an external URL into storage and returns its key.
+// The key is derived from the URL, so mirroring the same URL twice downloads it once.
+func MirrorRemoteAudio(ctx context.Context, media storage.Storage, remoteURL string) (string, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("invalid audio URL %q", remoteURL)
+	}
+
+	sum := sha256.Sum256([]byte(remoteURL))
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext == "" || len(ext) > 5 {
+		ext = ".mp3"
+	}
+	key := "audio/" + hex.EncodeToString(sum[:]) + ext
+
+	exists, err := media.Exists(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to check audio in storage: %w", err)
+	}
+	if exists {
+		return key, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := mediaHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download audio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("audio download failed with status %d", resp.StatusCode)
+	}
+
+	maxSize := config.GetConfig().Media.AudioMaxSize
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	if int64(len(audio)) > maxSize {
+		return "", fmt.Errorf("audio is larger than %d bytes", maxSize)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !isAudioType(contentType) {
+		// Some hosts serve audio as application/octet-stream, trust the content then
+		contentType = http.DetectContentType(audio)
+		if !isAudioType(contentType) {
+			return "", fmt.Errorf("unexpected audio content type %q", contentType)
+		}
+	}
+
+	if err := media.Put(ctx, key, audio, contentType); err != nil {
+		return "", fmt.Errorf("failed to upload audio: %w", err)
+	}
+
+	return key, nil
+}
+
+// isAudioType reports whether a media type is audio
+func isAudioType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "audio/") || mediaType == "application/ogg"
+}
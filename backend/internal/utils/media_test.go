@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fluently/go-backend/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProcessAvatar tests cropping and scaling avatars to JPEG squares
+func TestProcessAvatar(t *testing.T) {
+	// Wide image: red left and right margins around a blue square
+	src := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 600; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 150 && x < 450 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var in bytes.Buffer
+	assert.NoError(t, png.Encode(&in, src))
+
+	out, err := ProcessAvatar(in.Bytes(), 128)
+	assert.NoError(t, err)
+
+	avatar, err := jpeg.Decode(bytes.NewReader(out))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 128, 128), avatar.Bounds())
+
+	// Only the centered blue square is kept
+	r, _, b, _ := avatar.At(2, 64).RGBA()
+	assert.Less(t, r, b)
+
+	_, err = ProcessAvatar([]byte("<svg></svg>"), 128)
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+// TestProcessAvatarKeepsSmallImages tests that small images aren't scaled up
+func TestProcessAvatarKeepsSmallImages(t *testing.T) {
+	var in bytes.Buffer
+	assert.NoError(t, png.Encode(&in, image.NewNRGBA(image.Rect(0, 0, 40, 64))))
+
+	out, err := ProcessAvatar(in.Bytes(), 256)
+	assert.NoError(t, err)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 40, cfg.Width)
+	assert.Equal(t, 40, cfg.Height)
+}
+
+// TestMirrorRemoteAudio tests copying remote audio into storage once
+func TestMirrorRemoteAudio(t *testing.T) {
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		if strings.HasSuffix(r.URL.Path, ".html") {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+			return
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("ID3audio"))
+	}))
+	defer server.Close()
+
+	media, err := storage.NewFSStorage(t.TempDir(), "", "secret")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	key, err := MirrorRemoteAudio(ctx, media, server.URL+"/media/apple-us.mp3")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "audio/") && strings.HasSuffix(key, ".mp3"), key)
+
+	data, err := media.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("ID3audio"), data)
+
+	again, err := MirrorRemoteAudio(ctx, media, server.URL+"/media/apple-us.mp3")
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+	assert.Equal(t, 1, downloads)
+
+	_, err = MirrorRemoteAudio(ctx, media, server.URL+"/error.html")
+	assert.Error(t, err)
+
+	_, err = MirrorRemoteAudio(ctx, media, "file:///etc/passwd")
+	assert.Error(t, err)
+}
+
+// TestMediaURLKeepsExternalURLs tests that only storage keys are resolved
+func TestMediaURLKeepsExternalURLs(t *testing.T) {
+	assert.Equal(t, "", MediaURL(""))
+	assert.Equal(t, "https://example.com/a.png", MediaURL("https://example.com/a.png"))
+}
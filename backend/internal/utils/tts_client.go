@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ttsKeyPrefix is the prefix of generated audio in object storage
+const ttsKeyPrefix = "tts/"
+
+// maxTTSAudioSize is the largest audio read from the speech server
+const maxTTSAudioSize = 10 << 20
+
+// TTSClient voices text with an OpenAI-compatible /v1/audio/speech server.
+// It's the "http" provider of the Telegram bot, so both find each other's audio in storage.
+type TTSClient struct {
+	url        string
+	apiKey     string
+	model      string
+	voice      string
+	httpClient *http.Client
+}
+
+// TTSClientConfig holds configuration for the TTSClient
+type TTSClientConfig struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Voice   string
+	Timeout time.Duration
+}
+
+// ttsSpeechRequest is the request body of the speech endpoint
+type ttsSpeechRequest struct {
+	Model          string `json:"model"`
+	Input          string `json:"input"`
+	Voice          string `json:"voice"`
+	ResponseFormat string `json:"response_format"`
+}
+
+// NewTTSClient creates a new TTS client
+func NewTTSClient(config TTSClientConfig) *TTSClient {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &TTSClient{
+		url:    strings.TrimRight(config.BaseURL, "/") + "/v1/audio/speech",
+		apiKey: config.APIKey,
+		model:  config.Model,
+		voice:  config.Voice,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// Name identifies the provider, model and voice the same way the bot does
+func (c *TTSClient) Name() string {
+	return "http:" + c.model + ":" + c.voice
+}
+
+// Synthesize returns the text voiced as OGG/Opus
+func (c *TTSClient) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	body, err := json.Marshal(ttsSpeechRequest{Model: c.model, Input: text, Voice: c.voice, ResponseFormat: "opus"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("TTS request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxTTSAudioSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("TTS returned empty audio")
+	}
+
+	return audio, nil
+}
+
+// TTSAudioKey returns the storage key of text voiced by a provider.
+// It must match tts.Key of the Telegram bot, both are tested against testdata/tts_keys.json.
+func TTSAudioKey(provider, language, text string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	sum := sha256.Sum256([]byte(provider + "\n" + language + "\n" + normalized))
+	return ttsKeyPrefix + hex.EncodeToString(sum[:]) + ".ogg"
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTTSAudioKeyMatchesBot tests keys against the vectors the Telegram bot's tts.Key is tested with
+func TestTTSAudioKeyMatchesBot(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/tts_keys.json")
+	require.NoError(t, err)
+
+	var vectors []struct {
+		Provider string `json:"provider"`
+		Language string `json:"language"`
+		Text     string `json:"text"`
+		Key      string `json:"key"`
+	}
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		assert.Equal(t, v.Key, TTSAudioKey(v.Provider, v.Language, v.Text), v.Text)
+	}
+
+	// the provider name is part of the key, the bot names the same HTTP provider identically
+	client := NewTTSClient(TTSClientConfig{BaseURL: "http://tts:8000", Model: "tts-1", Voice: "alloy"})
+	assert.Equal(t, "http:tts-1:alloy", client.Name())
+}
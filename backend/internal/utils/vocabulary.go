@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Vocabulary statuses used in import and export files
+const (
+	VocabularyStatusLearned    = "learned"
+	VocabularyStatusNotLearned = "not_learned"
+)
+
+// vocabularyCSVHeader is the header written by WriteVocabularyCSV
+var vocabularyCSVHeader = []string{"word", "translation", "part_of_speech", "cefr_level", "status", "audio_url", "sentences"}
+
+// VocabularySentence is an example sentence of a vocabulary entry
+type VocabularySentence struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation,omitempty"`
+	AudioURL    string `json:"audio_url,omitempty"`
+}
+
+// VocabularyEntry is a single word of a user's vocabulary in import and export files
+type VocabularyEntry struct {
+	Word         string
+	Translation  string
+	PartOfSpeech string
+	CEFRLevel    string
+	Status       string
+	AudioURL     string
+	Sentences    []VocabularySentence
+}
+
+// NormalizeVocabularyStatus maps the status spellings found in exported decks to a known status.
+// An empty string is returned for unknown values.
+func NormalizeVocabularyStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "learned", "known", "mastered", "review", "1", "true", "yes":
+		return VocabularyStatusLearned
+	case "not_learned", "not learned", "learning", "new", "unknown", "0", "false", "no":
+		return VocabularyStatusNotLearned
+	default:
+		return ""
+	}
+}
+
+// ParseVocabularyCSV parses a CSV or TSV vocabulary file.
+// Files with a header row may use any subset of the export columns in any order,
+// files without one are read as "word, translation[, sentence]" the way Quizlet exports them.
+func ParseVocabularyCSV(r io.Reader, comma rune) ([]VocabularyEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	// tabs count as leading space, trimming them would swallow empty TSV fields
+	reader.TrimLeadingSpace = comma != '\t'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	columns := map[string]int{"word": 0, "translation": 1, "sentences": 2}
+	if header := parseVocabularyHeader(records[0]); header != nil {
+		columns = header
+		records = records[1:]
+	}
+
+	var entries []VocabularyEntry
+	for _, record := range records {
+		entry := VocabularyEntry{
+			Word:         vocabularyField(record, columns, "word"),
+			Translation:  vocabularyField(record, columns, "translation"),
+			PartOfSpeech: vocabularyField(record, columns, "part_of_speech"),
+			CEFRLevel:    strings.ToLower(vocabularyField(record, columns, "cefr_level")),
+			Status:       NormalizeVocabularyStatus(vocabularyField(record, columns, "status")),
+			AudioURL:     vocabularyField(record, columns, "audio_url"),
+			Sentences:    parseVocabularySentences(vocabularyField(record, columns, "sentences")),
+		}
+
+		if entry.Word == "" && entry.Translation == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// WriteVocabularyCSV writes entries in the format accepted by ParseVocabularyCSV
+func WriteVocabularyCSV(w io.Writer, entries []VocabularyEntry, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if err := writer.Write(vocabularyCSVHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		sentences := ""
+		if len(entry.Sentences) > 0 {
+			data, err := json.Marshal(entry.Sentences)
+			if err != nil {
+				return fmt.Errorf("failed to marshal sentences: %w", err)
+			}
+			sentences = string(data)
+		}
+
+		record := []string{
+			entry.Word,
+			entry.Translation,
+			entry.PartOfSpeech,
+			entry.CEFRLevel,
+			entry.Status,
+			entry.AudioURL,
+			sentences,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// parseVocabularyHeader returns column positions if the record is a header row, nil otherwise
+func parseVocabularyHeader(record []string) map[string]int {
+	aliases := map[string]string{
+		"word":           "word",
+		"term":           "word",
+		"front":          "word",
+		"translation":    "translation",
+		"definition":     "translation",
+		"back":           "translation",
+		"part_of_speech": "part_of_speech",
+		"pos":            "part_of_speech",
+		"cefr_level":     "cefr_level",
+		"cefr":           "cefr_level",
+		"status":         "status",
+		"learned":        "status",
+		"audio_url":      "audio_url",
+		"audio":          "audio_url",
+		"sentences":      "sentences",
+		"sentence":       "sentences",
+		"example":        "sentences",
+	}
+
+	columns := make(map[string]int)
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if column, ok := aliases[name]; ok {
+			if _, exists := columns[column]; !exists {
+				columns[column] = i
+			}
+		}
+	}
+
+	if _, ok := columns["word"]; !ok {
+		return nil
+	}
+
+	return columns
+}
+
+// vocabularyField returns a trimmed field of the record by column name
+func vocabularyField(record []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+// parseVocabularySentences parses the sentences column.
+// It accepts the export format, the [["en", "ru"], ...] pairs used by the import CLI and plain text.
+func parseVocabularySentences(value string) []VocabularySentence {
+	if value == "" {
+		return nil
+	}
+
+	var sentences []VocabularySentence
+	if err := json.Unmarshal([]byte(value), &sentences); err == nil {
+		return sentences
+	}
+
+	// a failed unmarshal can leave decoded elements behind, pairs start from an empty list
+	sentences = nil
+	var pairs [][]string
+	if err := json.Unmarshal([]byte(value), &pairs); err == nil {
+		for _, pair := range pairs {
+			if len(pair) == 0 || pair[0] == "" {
+				continue
+			}
+			sentence := VocabularySentence{Sentence: pair[0]}
+			if len(pair) > 1 {
+				sentence.Translation = pair[1]
+			}
+			sentences = append(sentences, sentence)
+		}
+		return sentences
+	}
+
+	return []VocabularySentence{{Sentence: value}}
+}
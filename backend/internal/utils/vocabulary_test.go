@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseVocabularyHeader tests that header aliases map to columns and headerless files are detected
+func TestParseVocabularyHeader(t *testing.T) {
+	columns := parseVocabularyHeader([]string{"\ufeffTerm", " Definition ", "POS", "cefr", "learned", "audio", "example", "front"})
+	assert.Equal(t, map[string]int{
+		"word":           0,
+		"translation":    1,
+		"part_of_speech": 2,
+		"cefr_level":     3,
+		"status":         4,
+		"audio_url":      5,
+		"sentences":      6,
+	}, columns)
+
+	assert.Equal(t, map[string]int{"word": 1, "translation": 0}, parseVocabularyHeader([]string{"back", "front", "notes"}))
+	assert.Nil(t, parseVocabularyHeader([]string{"translation", "sentence"}))
+	assert.Nil(t, parseVocabularyHeader([]string{"harbor", "гавань"}))
+}
+
+// TestParseVocabularyCSV tests header and headerless files, statuses and sentence formats
+func TestParseVocabularyCSV(t *testing.T) {
+	entries, err := ParseVocabularyCSV(strings.NewReader(
+		"word,translation,status,cefr_level,sentences\n"+
+			"harbor,гавань,known,B1,\"[[\"\"The harbor is calm.\"\", \"\"Гавань спокойна.\"\"]]\"\n"+
+			"run,бежать,learning,,I run every day.\n"+
+			",,,,\n"+
+			"walk,,maybe,,\n",
+	), ',')
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, VocabularyEntry{
+		Word:        "harbor",
+		Translation: "гавань",
+		CEFRLevel:   "b1",
+		Status:      VocabularyStatusLearned,
+		Sentences:   []VocabularySentence{{Sentence: "The harbor is calm.", Translation: "Гавань спокойна."}},
+	}, entries[0])
+	assert.Equal(t, VocabularyStatusNotLearned, entries[1].Status)
+	assert.Equal(t, []VocabularySentence{{Sentence: "I run every day."}}, entries[1].Sentences)
+	assert.Empty(t, entries[2].Status)
+
+	// Quizlet exports have no header, the columns are word, translation and an optional sentence
+	entries, err = ParseVocabularyCSV(strings.NewReader("harbor\tгавань\nrun\tбежать\tI run.\n"), '\t')
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "harbor", entries[0].Word)
+	assert.Equal(t, "гавань", entries[0].Translation)
+	assert.Equal(t, []VocabularySentence{{Sentence: "I run."}}, entries[1].Sentences)
+
+	_, err = ParseVocabularyCSV(strings.NewReader(""), ',')
+	assert.Error(t, err)
+}
+
+// TestVocabularyCSVRoundTrip tests that written files are parsed back unchanged
+func TestVocabularyCSVRoundTrip(t *testing.T) {
+	entries := []VocabularyEntry{
+		{
+			Word:         "harbor",
+			Translation:  "гавань, порт",
+			PartOfSpeech: "noun",
+			CEFRLevel:    "b1",
+			Status:       VocabularyStatusLearned,
+			AudioURL:     "https://example.com/harbor.mp3",
+			Sentences:    []VocabularySentence{{Sentence: `The "harbor" is calm.`, Translation: "Гавань спокойна."}},
+		},
+		{Word: "run", Translation: "бежать", Status: VocabularyStatusNotLearned},
+	}
+
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteVocabularyCSV(&buf, entries, comma))
+
+		parsed, err := ParseVocabularyCSV(&buf, comma)
+		require.NoError(t, err)
+		assert.Equal(t, entries, parsed)
+	}
+}
+
+// TestNormalizeVocabularyStatus tests the status spellings of exported decks
+func TestNormalizeVocabularyStatus(t *testing.T) {
+	assert.Equal(t, VocabularyStatusLearned, NormalizeVocabularyStatus(" Mastered "))
+	assert.Equal(t, VocabularyStatusLearned, NormalizeVocabularyStatus("1"))
+	assert.Equal(t, VocabularyStatusNotLearned, NormalizeVocabularyStatus("Not Learned"))
+	assert.Equal(t, VocabularyStatusNotLearned, NormalizeVocabularyStatus("new"))
+	assert.Empty(t, NormalizeVocabularyStatus("maybe"))
+}
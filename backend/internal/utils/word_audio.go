@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fluently/go-backend/internal/config"
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/storage"
+	"fluently/go-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WordAudioService keeps audio of words and sentences in media storage: it mirrors
+// audio hosted by third parties and fills missing audio with generated speech
+type WordAudioService struct {
+	tts      *TTSClient // nil when TTS isn't configured, missing audio is then left missing
+	storage  storage.Storage
+	language string
+	db       *gorm.DB
+}
+
+// NewWordAudioService creates a new word audio service. It returns nil when media
+// storage isn't available.
+func NewWordAudioService(db *gorm.DB) *WordAudioService {
+	media := Media()
+	if media == nil {
+		return nil
+	}
+
+	cfg := config.GetConfig()
+	s := &WordAudioService{
+		storage:  media,
+		language: cfg.TTS.Language,
+		db:       db,
+	}
+	if cfg.TTS.URL != "" {
+		s.tts = NewTTSClient(TTSClientConfig{
+			BaseURL: cfg.TTS.URL,
+			APIKey:  cfg.TTS.APIKey,
+			Model:   cfg.TTS.Model,
+			Voice:   cfg.TTS.Voice,
+			Timeout: cfg.TTS.Timeout,
+		})
+	}
+
+	return s
+}
+
+// FillWordAudio moves audio of a word and its sentences into storage, generating
+// it for those that don't have any yet
+func (s *WordAudioService) FillWordAudio(ctx context.Context, wordID uuid.UUID) error {
+	var word models.Word
+	if err := s.db.WithContext(ctx).Preload("Sentences").First(&word, "id = ?", wordID).Error; err != nil {
+		return fmt.Errorf("failed to find word: %w", err)
+	}
+
+	if key, err := s.audioKey(ctx, word.AudioURL, word.Word); err != nil {
+		return err
+	} else if key != word.AudioURL {
+		if err := s.db.WithContext(ctx).Model(&word).Update("audio_url", key).Error; err != nil {
+			return fmt.Errorf("failed to save word audio: %w", err)
+		}
+	}
+
+	for i := range word.Sentences {
+		sentence := &word.Sentences[i]
+		if sentence.Sentence == "" {
+			continue
+		}
+
+		key, err := s.audioKey(ctx, sentence.AudioURL, sentence.Sentence)
+		if err != nil {
+			return err
+		}
+		if key == sentence.AudioURL {
+			continue
+		}
+		if err := s.db.WithContext(ctx).Model(sentence).Update("audio_url", key).Error; err != nil {
+			return fmt.Errorf("failed to save sentence audio: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListWordsMissingAudio returns ids of words whose audio or whose sentences' audio is
+// missing (when it can be generated) or hosted outside of storage
+func (s *WordAudioService) ListWordsMissingAudio(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	missing := "audio_url LIKE '%://%'"
+	if s.tts != nil {
+		missing = "(audio_url = '' OR audio_url LIKE '%://%')"
+	}
+
+	var ids []uuid.UUID
+	err := s.db.WithContext(ctx).
+		Model(&models.Word{}).
+		Where(missing+" OR EXISTS (SELECT 1 FROM sentences WHERE sentences.word_id = words.id AND sentences.sentence != '' AND "+
+			strings.ReplaceAll(missing, "audio_url", "sentences.audio_url")+")").
+		Limit(limit).
+		Pluck("id", &ids).Error
+
+	return ids, err
+}
+
+// audioKey returns the storage key of audio for the text. Keys are kept, external audio
+// is mirrored (falling back to generated speech when it's gone) and missing audio is
+// generated. The reference is returned unchanged when there is nothing to do.
+func (s *WordAudioService) audioKey(ctx context.Context, ref, text string) (string, error) {
+	if storage.IsKey(ref) {
+		return ref, nil
+	}
+
+	if ref != "" {
+		key, err := MirrorRemoteAudio(ctx, s.storage, ref)
+		if err == nil {
+			return key, nil
+		}
+		logger.Log.Warn("Failed to mirror remote audio", zap.String("url", ref), zap.Error(err))
+	}
+
+	if s.tts == nil {
+		return ref, nil
+	}
+
+	return s.generate(ctx, text)
+}
+
+// generate returns the key of the voiced text, generating and uploading it unless
+// it's already in storage (the Telegram bot may have voiced it before)
+func (s *WordAudioService) generate(ctx context.Context, text string) (string, error) {
+	key := TTSAudioKey(s.tts.Name(), s.language, text)
+
+	exists, err := s.storage.Exists(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to check audio in storage: %w", err)
+	}
+
+	if !exists {
+		audio, err := s.tts.Synthesize(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		if err := s.storage.Put(ctx, key, audio, "audio/ogg"); err != nil {
+			return "", fmt.Errorf("failed to upload audio: %w", err)
+		}
+	}
+
+	return key, nil
+}
@@ -129,6 +129,55 @@ func (s *WordEnrichmentService) EnrichWordInDatabase(ctx context.Context, wordID
 	return nil
 }
 
+// EnrichWordWithSentences enriches a word with dictionary data and generates distractors for its sentences
+func (s *WordEnrichmentService) EnrichWordWithSentences(ctx context.Context, wordID uuid.UUID) error {
+	if err := s.EnrichWordInDatabase(ctx, wordID.String()); err != nil {
+		return err
+	}
+
+	var sentences []models.Sentence
+	if err := s.db.WithContext(ctx).Where("word_id = ?", wordID).Find(&sentences).Error; err != nil {
+		return fmt.Errorf("failed to find sentences: %w", err)
+	}
+
+	for i := range sentences {
+		if err := s.EnrichSentenceWithDistractors(ctx, &sentences[i]); err != nil {
+			logger.Log.Warn("Failed to enrich word sentence",
+				zap.String("word_id", wordID.String()),
+				zap.String("sentence_id", sentences[i].ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// ListWordsNeedingEnrichment returns ids of words missing dictionary data
+func (s *WordEnrichmentService) ListWordsNeedingEnrichment(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := s.db.WithContext(ctx).
+		Model(&models.Word{}).
+		Where("(phonetic = ? OR audio_url = ? OR part_of_speech = ?) AND part_of_speech != ?",
+			"", "", "unknown", "unknown_processed").
+		Limit(limit).
+		Pluck("id", &ids).Error
+
+	return ids, err
+}
+
+// ListSentencesNeedingDistractors returns ids of sentences without distractor options
+func (s *WordEnrichmentService) ListSentencesNeedingDistractors(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := s.db.WithContext(ctx).
+		Table("sentences AS s").
+		Joins("LEFT JOIN pick_options po ON s.id = po.sentence_id").
+		Where("po.sentence_id IS NULL AND s.sentence != ''").
+		Limit(limit).
+		Pluck("s.id", &ids).Error
+
+	return ids, err
+}
+
 // BatchEnrichWords enriches multiple words with dictionary data
 func (s *WordEnrichmentService) BatchEnrichWords(ctx context.Context, words []*models.Word, rateLimitDelay time.Duration) []error {
 	var errors []error
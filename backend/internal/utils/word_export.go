@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fluently/go-backend/internal/repository/postgres"
+)
+
+// ErrTopicTooDeep is returned for words whose topic path doesn't fit the topic columns
+var ErrTopicTooDeep = errors.New("topic path is deeper than the topic, subtopic and subsubtopic columns")
+
+// wordExportHeader are the columns of exported words, DefaultWordImportMapping reads them back
+var wordExportHeader = []string{"word", "part_of_speech", "translation", "CEFR_level", "context", "topic", "subtopic", "subsubtopic", "sentences"}
+
+// exportedWordRecord is an exported word in NDJSON, with the keys of the CSV columns
+type exportedWordRecord struct {
+	Word         string                        `json:"word"`
+	PartOfSpeech string                        `json:"part_of_speech,omitempty"`
+	Translation  string                        `json:"translation,omitempty"`
+	CEFRLevel    string                        `json:"CEFR_level,omitempty"`
+	Context      string                        `json:"context,omitempty"`
+	Topic        string                        `json:"topic,omitempty"`
+	Subtopic     string                        `json:"subtopic,omitempty"`
+	Subsubtopic  string                        `json:"subsubtopic,omitempty"`
+	Sentences    []postgres.WordImportSentence `json:"sentences,omitempty"`
+}
+
+// WordExportWriter writes global words in a format the import reads back
+type WordExportWriter struct {
+	w   io.Writer
+	csv *csv.Writer
+}
+
+// NewWordExportWriter creates a writer for CSV, TSV or NDJSON, CSV and TSV files start with the header
+func NewWordExportWriter(w io.Writer, format string) (*WordExportWriter, error) {
+	writer := &WordExportWriter{w: w}
+	switch format {
+	case WordImportCSV, WordImportTSV:
+		writer.csv = csv.NewWriter(w)
+		if format == WordImportTSV {
+			writer.csv.Comma = '\t'
+		}
+		if err := writer.csv.Write(wordExportHeader); err != nil {
+			return nil, err
+		}
+	case WordImportNDJSON:
+	default:
+		return nil, fmt.Errorf("unsupported export format %q, use csv, tsv or ndjson", format)
+	}
+	return writer, nil
+}
+
+// Write writes a word with its topic path, sentences and pick options
+func (e *WordExportWriter) Write(word postgres.ExportedWord) error {
+	if len(word.Topics) > 3 {
+		return ErrTopicTooDeep
+	}
+	topics := make([]string, 3)
+	copy(topics, word.Topics)
+
+	record := exportedWordRecord{
+		Word:         word.Word.Word,
+		PartOfSpeech: word.PartOfSpeech,
+		Translation:  word.Translation,
+		CEFRLevel:    word.CEFRLevel,
+		Context:      word.Context,
+		Topic:        topics[0],
+		Subtopic:     topics[1],
+		Subsubtopic:  topics[2],
+		Sentences:    word.Sentences,
+	}
+
+	if e.csv == nil {
+		line, err := marshalExport(record)
+		if err != nil {
+			return err
+		}
+		_, err = e.w.Write(line)
+		return err
+	}
+
+	sentences := ""
+	if len(record.Sentences) > 0 {
+		line, err := marshalExport(record.Sentences)
+		if err != nil {
+			return err
+		}
+		sentences = strings.TrimSuffix(string(line), "\n")
+	}
+	return e.csv.Write([]string{
+		record.Word, record.PartOfSpeech, record.Translation, record.CEFRLevel, record.Context,
+		record.Topic, record.Subtopic, record.Subsubtopic, sentences,
+	})
+}
+
+// Flush writes buffered words
+func (e *WordExportWriter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	return nil
+}
+
+// marshalExport encodes a value as a JSON line, keeping <, > and & readable
+func marshalExport(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTopicExport writes topics with their paths, joined by " / " in CSV and TSV
+func WriteTopicExport(w io.Writer, format string, topics []postgres.ExportedTopic) error {
+	switch format {
+	case WordImportCSV, WordImportTSV:
+		writer := csv.NewWriter(w)
+		if format == WordImportTSV {
+			writer.Comma = '\t'
+		}
+		if err := writer.Write([]string{"id", "path", "words"}); err != nil {
+			return err
+		}
+		for _, t := range topics {
+			if err := writer.Write([]string{t.ID.String(), strings.Join(t.Path, " / "), strconv.Itoa(t.Words)}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case WordImportNDJSON:
+		for _, t := range topics {
+			line, err := marshalExport(struct {
+				ID    string   `json:"id"`
+				Path  []string `json:"path"`
+				Words int      `json:"words"`
+			}{t.ID.String(), t.Path, t.Words})
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q, use csv, tsv or ndjson", format)
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"fluently/go-backend/internal/repository/models"
+	"fluently/go-backend/internal/repository/postgres"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWordExportRoundTrip tests that exported words are imported back unchanged with the default mapping
+func TestWordExportRoundTrip(t *testing.T) {
+	words := []postgres.ExportedWord{
+		{
+			Word:   models.Word{Word: "harbor", PartOfSpeech: "noun", Translation: "гавань, порт", CEFRLevel: "B1", Context: "sea"},
+			Topics: []string{"Travel", "Sea"},
+			Sentences: []postgres.WordImportSentence{
+				{Sentence: `The "harbor" is <calm> & quiet.`, Translation: "Гавань спокойна.", Options: []string{"harbor", "harbors"}},
+			},
+		},
+		{Word: models.Word{Word: "run", PartOfSpeech: "verb"}},
+	}
+
+	for _, format := range []string{WordImportCSV, WordImportTSV, WordImportNDJSON} {
+		var out bytes.Buffer
+		writer, err := NewWordExportWriter(&out, format)
+		require.NoError(t, err)
+		for _, w := range words {
+			require.NoError(t, writer.Write(w))
+		}
+		require.NoError(t, writer.Flush())
+
+		header, records, err := ReadWordImportRecords(&out, format)
+		require.NoError(t, err, format)
+		require.Len(t, records, len(words), format)
+
+		mapping := DefaultWordImportMapping()
+		if header != nil {
+			require.NoError(t, mapping.Check(header))
+		}
+		for i, record := range records {
+			row, err := mapping.Row(record)
+			require.NoError(t, err, format)
+			assert.Equal(t, words[i].Word.Word, row.Word, format)
+			assert.Equal(t, words[i].PartOfSpeech, row.PartOfSpeech, format)
+			assert.Equal(t, words[i].Translation, row.Translation, format)
+			assert.Equal(t, words[i].CEFRLevel, row.CEFRLevel, format)
+			assert.Equal(t, words[i].Context, row.Context, format)
+			assert.Equal(t, words[i].Topics, row.Topics, format)
+			assert.Equal(t, words[i].Sentences, row.Sentences, format)
+		}
+	}
+
+	writer, err := NewWordExportWriter(&bytes.Buffer{}, WordImportCSV)
+	require.NoError(t, err)
+	assert.ErrorIs(t, writer.Write(postgres.ExportedWord{Word: models.Word{Word: "deep"}, Topics: []string{"a", "b", "c", "d"}}), ErrTopicTooDeep)
+
+	_, err = NewWordExportWriter(&bytes.Buffer{}, WordImportJSON)
+	assert.Error(t, err)
+}
@@ -0,0 +1,448 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"fluently/go-backend/internal/repository/postgres"
+)
+
+// Word import file formats
+const (
+	WordImportCSV    = "csv"
+	WordImportTSV    = "tsv"
+	WordImportJSON   = "json"   // array of objects
+	WordImportNDJSON = "ndjson" // one object per line
+)
+
+// Sizes of the words and topics columns
+const (
+	maxImportWord         = 30
+	maxImportTranslation  = 255
+	maxImportPartOfSpeech = 30
+	maxImportContext      = 100
+	maxImportTopic        = 100
+)
+
+var importCEFRLevels = map[string]bool{"A1": true, "A2": true, "B1": true, "B2": true, "C1": true, "C2": true}
+
+// WordImportMapping maps the fields of a word to columns of an import file, or keys of JSON records.
+// Column names are matched case-insensitively, unmapped fields are left as they are on existing words.
+type WordImportMapping struct {
+	Word         string   `json:"word"`
+	Translation  string   `json:"translation,omitempty"`
+	PartOfSpeech string   `json:"part_of_speech,omitempty"`
+	CEFRLevel    string   `json:"cefr_level,omitempty"`
+	Context      string   `json:"context,omitempty"`
+	Topics       []string `json:"topics,omitempty"`    // columns of the topic path, main topic first
+	Sentences    string   `json:"sentences,omitempty"` // [["sentence", "translation"], ...] or [{"sentence": ..., "translation": ..., "options": [...]}]
+
+	// Optional are mapped columns that may be missing from the file
+	Optional []string `json:"optional,omitempty"`
+
+	// Defaults are values of fields that are empty in a row: translation, part_of_speech, cefr_level or context
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// DefaultWordImportMapping returns the mapping of the columns of the dictionary CSV and of exported words
+func DefaultWordImportMapping() WordImportMapping {
+	return WordImportMapping{
+		Word:         "word",
+		Translation:  "translation",
+		PartOfSpeech: "part_of_speech",
+		CEFRLevel:    "CEFR_level",
+		Context:      "context",
+		Topics:       []string{"topic", "subtopic", "subsubtopic"},
+		Sentences:    "sentences",
+		Optional:     []string{"part_of_speech", "context"}, // the dictionary CSV has no such columns
+	}
+}
+
+// LoadWordImportMapping reads a mapping from JSON
+func LoadWordImportMapping(r io.Reader) (WordImportMapping, error) {
+	var m WordImportMapping
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return m, fmt.Errorf("invalid mapping: %w", err)
+	}
+	if m.Word == "" {
+		return m, errors.New("invalid mapping: word column is required")
+	}
+	for field := range m.Defaults {
+		switch field {
+		case "translation", "part_of_speech", "cefr_level", "context":
+		default:
+			return m, fmt.Errorf("invalid mapping: no default for %q", field)
+		}
+	}
+
+	return m, nil
+}
+
+// columns returns the mapped columns, lowercased
+func (m WordImportMapping) columns() []string {
+	columns := []string{m.Word, m.Translation, m.PartOfSpeech, m.CEFRLevel, m.Context, m.Sentences}
+	columns = append(columns, m.Topics...)
+
+	var mapped []string
+	for _, c := range columns {
+		if c != "" {
+			mapped = append(mapped, strings.ToLower(strings.TrimSpace(c)))
+		}
+	}
+	return mapped
+}
+
+// Check returns an error naming the mapped columns missing from the header of a CSV or TSV file
+func (m WordImportMapping) Check(header []string) error {
+	present := map[string]bool{}
+	for _, c := range m.Optional {
+		present[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	for _, h := range header {
+		present[strings.ToLower(strings.TrimSpace(h))] = true
+	}
+
+	var missing []string
+	for _, c := range m.columns() {
+		if !present[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("columns missing from the file: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// WordImportFormat detects the format of an import file from its name, empty when unknown
+func WordImportFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return WordImportCSV
+	case ".tsv", ".tab":
+		return WordImportTSV
+	case ".json":
+		return WordImportJSON
+	case ".ndjson", ".jsonl":
+		return WordImportNDJSON
+	default:
+		return ""
+	}
+}
+
+// WordImportRecord is a raw record of an import file
+type WordImportRecord struct {
+	Line   int               // line of a CSV, TSV or NDJSON file, position in a JSON array
+	Fields map[string]string // values by lowercased column name
+	Values []string          // values of a CSV or TSV row in file order
+	Raw    json.RawMessage   // JSON object of a JSON or NDJSON record
+}
+
+// ReadWordImportRecords reads all records of an import file, with the header for CSV and TSV files
+func ReadWordImportRecords(r io.Reader, format string) ([]string, []WordImportRecord, error) {
+	switch format {
+	case WordImportCSV:
+		return readImportCSV(r, ',')
+	case WordImportTSV:
+		return readImportCSV(r, '\t')
+	case WordImportJSON:
+		var objects []json.RawMessage
+		if err := json.NewDecoder(r).Decode(&objects); err != nil {
+			return nil, nil, fmt.Errorf("failed to read JSON array: %w", err)
+		}
+		records := make([]WordImportRecord, 0, len(objects))
+		for i, raw := range objects {
+			record, err := importJSONRecord(i+1, raw)
+			if err != nil {
+				return nil, nil, err
+			}
+			records = append(records, record)
+		}
+		return nil, records, nil
+	case WordImportNDJSON:
+		var records []WordImportRecord
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		for line := 1; scanner.Scan(); line++ {
+			raw := bytes.TrimSpace(scanner.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+			record, err := importJSONRecord(line, append(json.RawMessage(nil), raw...))
+			if err != nil {
+				return nil, nil, err
+			}
+			records = append(records, record)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return nil, records, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q, use csv, tsv, json or ndjson", format)
+	}
+}
+
+// readImportCSV reads a CSV or TSV file with a header row
+func readImportCSV(r io.Reader, comma rune) ([]string, []WordImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var records []WordImportRecord
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		fields := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(values) {
+				fields[strings.ToLower(strings.TrimSpace(column))] = strings.TrimSpace(values[i])
+			}
+		}
+		records = append(records, WordImportRecord{Line: line, Fields: fields, Values: values})
+	}
+
+	return header, records, nil
+}
+
+// importJSONRecord reads a JSON object, nested values are kept as JSON text
+func importJSONRecord(line int, raw json.RawMessage) (WordImportRecord, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return WordImportRecord{}, fmt.Errorf("record %d: not a JSON object", line)
+	}
+
+	fields := make(map[string]string, len(object))
+	for key, value := range object {
+		var s string
+		switch {
+		case string(value) == "null":
+		case json.Unmarshal(value, &s) == nil:
+		default:
+			s = string(value)
+		}
+		fields[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(s)
+	}
+
+	return WordImportRecord{Line: line, Fields: fields, Raw: raw}, nil
+}
+
+// field returns the value of a mapped column, or the default of the field
+func (m WordImportMapping) field(record WordImportRecord, column, name string) string {
+	value := ""
+	if column != "" {
+		value = record.Fields[strings.ToLower(strings.TrimSpace(column))]
+	}
+	if value == "" {
+		value = m.Defaults[name]
+	}
+	return value
+}
+
+// Row validates a record and maps it to a row to import
+func (m WordImportMapping) Row(record WordImportRecord) (postgres.WordImportRow, error) {
+	row := postgres.WordImportRow{
+		Line:         record.Line,
+		Word:         m.field(record, m.Word, "word"),
+		Translation:  m.field(record, m.Translation, "translation"),
+		PartOfSpeech: strings.ToLower(m.field(record, m.PartOfSpeech, "part_of_speech")),
+		CEFRLevel:    strings.ToUpper(m.field(record, m.CEFRLevel, "cefr_level")),
+		Context:      m.field(record, m.Context, "context"),
+	}
+
+	switch {
+	case row.Word == "":
+		return row, errors.New("word is empty")
+	case utf8.RuneCountInString(row.Word) > maxImportWord:
+		return row, fmt.Errorf("word is longer than %d characters", maxImportWord)
+	case utf8.RuneCountInString(row.Translation) > maxImportTranslation:
+		return row, fmt.Errorf("translation is longer than %d characters", maxImportTranslation)
+	case utf8.RuneCountInString(row.PartOfSpeech) > maxImportPartOfSpeech:
+		return row, fmt.Errorf("part of speech is longer than %d characters", maxImportPartOfSpeech)
+	case utf8.RuneCountInString(row.Context) > maxImportContext:
+		return row, fmt.Errorf("context is longer than %d characters", maxImportContext)
+	case row.CEFRLevel != "" && !importCEFRLevels[row.CEFRLevel]:
+		return row, fmt.Errorf("invalid CEFR level %q", row.CEFRLevel)
+	}
+
+	for _, column := range m.Topics {
+		title := m.field(record, column, "")
+		if title == "" {
+			continue
+		}
+		if utf8.RuneCountInString(title) > maxImportTopic {
+			return row, fmt.Errorf("topic %q is longer than %d characters", title, maxImportTopic)
+		}
+		row.Topics = append(row.Topics, title)
+	}
+
+	sentences, err := parseImportSentences(m.field(record, m.Sentences, ""))
+	if err != nil {
+		return row, err
+	}
+	row.Sentences = sentences
+
+	return row, nil
+}
+
+// parseImportSentences parses the sentences column, plain text is a single sentence
+func parseImportSentences(value string) ([]postgres.WordImportSentence, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(value, "[") {
+		return []postgres.WordImportSentence{{Sentence: value}}, nil
+	}
+
+	normalized := []byte(normalizeJSONQuotes(value))
+	var sentences []postgres.WordImportSentence
+
+	var objects []postgres.WordImportSentence
+	if err := json.Unmarshal(normalized, &objects); err == nil {
+		for _, o := range objects {
+			if o.Sentence != "" {
+				sentences = append(sentences, o)
+			}
+		}
+		return sentences, nil
+	}
+
+	var pairs [][]string
+	if err := json.Unmarshal(normalized, &pairs); err != nil {
+		return nil, fmt.Errorf("invalid sentences: %w", err)
+	}
+	for _, pair := range pairs {
+		if len(pair) == 0 || pair[0] == "" {
+			continue
+		}
+		sentence := postgres.WordImportSentence{Sentence: pair[0]}
+		if len(pair) > 1 {
+			sentence.Translation = pair[1]
+		}
+		sentences = append(sentences, sentence)
+	}
+	return sentences, nil
+}
+
+// normalizeJSONQuotes converts JSON written with mixed quotes to valid JSON,
+// [["text", 'текст'], ['text2', 'текст2']] becomes [["text", "текст"], ["text2", "текст2"]]
+func normalizeJSONQuotes(value string) string {
+	result := make([]rune, 0, len(value))
+	runes := []rune(value)
+	inString := false
+	currentQuote := rune(0)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// Keep escaped characters as they are
+		if r == '\\' && inString && i+1 < len(runes) {
+			result = append(result, r, runes[i+1])
+			i++
+			continue
+		}
+
+		switch {
+		case r != '"' && r != '\'':
+			result = append(result, r)
+		case !inString:
+			result = append(result, '"')
+			inString = true
+			currentQuote = r
+		case r == currentQuote:
+			result = append(result, '"')
+			inString = false
+			currentQuote = 0
+		case r == '"':
+			// Double quote inside a single-quoted string
+			result = append(result, '\\', '"')
+		default:
+			result = append(result, r)
+		}
+	}
+
+	return string(result)
+}
+
+// WordImportRejects writes rejected records with the reason, in a format that can be fixed and imported again:
+// CSV and TSV files get an error column, JSON records are written as NDJSON with an error key
+type WordImportRejects struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+}
+
+// NewWordImportRejects creates a rejects writer for records of the format
+func NewWordImportRejects(w io.Writer, format string, header []string) (*WordImportRejects, error) {
+	rejects := &WordImportRejects{format: format, w: w}
+	switch format {
+	case WordImportCSV, WordImportTSV:
+		rejects.csv = csv.NewWriter(w)
+		if format == WordImportTSV {
+			rejects.csv.Comma = '\t'
+		}
+		if err := rejects.csv.Write(append(append([]string{}, header...), "error")); err != nil {
+			return nil, err
+		}
+	}
+	return rejects, nil
+}
+
+// Write writes a rejected record
+func (r *WordImportRejects) Write(record WordImportRecord, reason string) error {
+	if r.csv != nil {
+		return r.csv.Write(append(append([]string{}, record.Values...), reason))
+	}
+
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(record.Raw, &object); err != nil {
+		return err
+	}
+	object["error"], _ = json.Marshal(reason)
+
+	line, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+// Flush writes buffered rejects
+func (r *WordImportRejects) Flush() error {
+	if r.csv != nil {
+		r.csv.Flush()
+		return r.csv.Error()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWordImportDictionaryCSV tests that the default mapping reads the dictionary CSV by column names
+func TestWordImportDictionaryCSV(t *testing.T) {
+	file := `,Total,word,topic,subtopic,subsubtopic,CEFR_level,translation,sentences
+0,12.5,harbor,Travel,Sea,,b1,гавань,"[['The ship left the harbor.', ""Корабль покинул гавань.""]]"
+1,3,,Travel,,,A1,,
+2,1,anchor,Travel,Sea,,Z9,якорь,
+`
+	header, records, err := ReadWordImportRecords(strings.NewReader(file), WordImportCSV)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	mapping := DefaultWordImportMapping()
+	require.NoError(t, mapping.Check(header))
+
+	row, err := mapping.Row(records[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, row.Line)
+	assert.Equal(t, "harbor", row.Word)
+	assert.Equal(t, "B1", row.CEFRLevel)
+	assert.Equal(t, []string{"Travel", "Sea"}, row.Topics)
+	if assert.Len(t, row.Sentences, 1) {
+		assert.Equal(t, "The ship left the harbor.", row.Sentences[0].Sentence)
+		assert.Equal(t, "Корабль покинул гавань.", row.Sentences[0].Translation)
+	}
+
+	_, err = mapping.Row(records[1])
+	assert.EqualError(t, err, "word is empty")
+	_, err = mapping.Row(records[2])
+	assert.EqualError(t, err, `invalid CEFR level "Z9"`)
+
+	assert.EqualError(t, mapping.Check([]string{"word", "translation"}),
+		"columns missing from the file: cefr_level, sentences, topic, subtopic, subsubtopic")
+}
+
+// TestWordImportMapping tests custom mappings with defaults over NDJSON records
+func TestWordImportMapping(t *testing.T) {
+	mapping, err := LoadWordImportMapping(strings.NewReader(`{
+		"word": "English", "translation": "Russian", "part_of_speech": "POS",
+		"topics": ["Category"], "sentences": "examples",
+		"defaults": {"cefr_level": "a2"}
+	}`))
+	require.NoError(t, err)
+
+	_, err = LoadWordImportMapping(strings.NewReader(`{"translation": "ru"}`))
+	assert.Error(t, err, "word is required")
+	_, err = LoadWordImportMapping(strings.NewReader(`{"word": "en", "defaults": {"word": "x"}}`))
+	assert.Error(t, err)
+
+	file := `{"English": "run", "Russian": "бежать", "POS": "Verb", "Category": "Sport", "examples": [{"sentence": "I run.", "translation": "Я бегу."}]}
+
+{"English": "walk", "Russian": null, "cefr_level": "B1"}
+`
+	_, records, err := ReadWordImportRecords(strings.NewReader(file), WordImportNDJSON)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 3, records[1].Line, "blank lines count")
+
+	row, err := mapping.Row(records[0])
+	require.NoError(t, err)
+	assert.Equal(t, "verb", row.PartOfSpeech)
+	assert.Equal(t, "A2", row.CEFRLevel)
+	assert.Equal(t, []string{"Sport"}, row.Topics)
+	assert.Len(t, row.Sentences, 1)
+
+	row, err = mapping.Row(records[1])
+	require.NoError(t, err)
+	assert.Equal(t, "", row.Translation)
+	assert.Equal(t, "A2", row.CEFRLevel, "unmapped keys are ignored")
+}
+
+// TestWordImportRejects tests that rejects keep the original record with the reason
+func TestWordImportRejects(t *testing.T) {
+	header, records, err := ReadWordImportRecords(strings.NewReader("word\ttranslation\nhello\tпривет\n"), WordImportTSV)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	rejects, err := NewWordImportRejects(&out, WordImportTSV, header)
+	require.NoError(t, err)
+	require.NoError(t, rejects.Write(records[0], "duplicate"))
+	require.NoError(t, rejects.Flush())
+	assert.Equal(t, "word\ttranslation\terror\nhello\tпривет\tduplicate\n", out.String())
+
+	_, records, err = ReadWordImportRecords(strings.NewReader(`[{"word": "hello", "n": 1}]`), WordImportJSON)
+	require.NoError(t, err)
+
+	out.Reset()
+	rejects, err = NewWordImportRejects(&out, WordImportJSON, nil)
+	require.NoError(t, err)
+	require.NoError(t, rejects.Write(records[0], "duplicate"))
+	assert.Equal(t, `{"error":"duplicate","n":1,"word":"hello"}`+"\n", out.String())
+}
@@ -52,12 +52,44 @@ extension APIService: UserDataAPI {
         let path = "api/v1/topics"
         let method = "GET"
 
-        let request = try makeAuthorizedRequest(
-            path: path,
-            method: method,
-            body: Optional<String>.none
-        )
+        // Topics are listed in pages, follow next_cursor until the last one
+        var goals: [[String: String]] = []
+        var cursor: String?
+        repeat {
+            var request = try makeAuthorizedRequest(
+                path: path,
+                method: method,
+                body: Optional<String>.none
+            )
 
-        return try await fetchAndDecode(request: request)
+            var queryItems = [
+                URLQueryItem(name: "root", value: "true"),
+                URLQueryItem(name: "limit", value: "200")
+            ]
+            if let cursor {
+                queryItems.append(URLQueryItem(name: "cursor", value: cursor))
+            }
+            if let url = request.url, var components = URLComponents(url: url, resolvingAgainstBaseURL: false) {
+                components.queryItems = queryItems
+                request.url = components.url
+            }
+
+            let page: TopicsPage = try await fetchAndDecode(request: request)
+            goals.append(contentsOf: page.topics)
+            cursor = page.nextCursor?.isEmpty == false ? page.nextCursor : nil
+        } while cursor != nil
+
+        return goals
+    }
+}
+
+// MARK: - Topics Page
+private struct TopicsPage: Decodable {
+    let topics: [[String: String]]
+    let nextCursor: String?
+
+    enum CodingKeys: String, CodingKey {
+        case topics
+        case nextCursor = "next_cursor"
     }
 }
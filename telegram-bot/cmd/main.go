@@ -62,7 +62,10 @@ func main() {
 	logger.Info("Redis connection established")
 
 	// Initialize API client
-	apiClient := api.NewClient(cfg.API.BaseURL, logger)
+	if cfg.API.APIKeyID == "" || cfg.API.APIKey == "" {
+		logger.Warn("API_KEY_ID or API_KEY is not set, the backend will reject account linking and logins")
+	}
+	apiClient := api.NewClient(cfg.API.BaseURL, cfg.API.APIKeyID, cfg.API.APIKey, logger)
 
 	// Initialize task scheduler
 	scheduler := tasks.NewScheduler(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, logger)
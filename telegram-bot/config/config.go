@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -10,16 +11,37 @@ import (
 )
 
 type Config struct {
-	Bot    BotConfig
-	Logger LoggerConfig
-	Redis  RedisConfig
-	API    APIConfig
-	Asynq  AsynqConfig
-	TTS    TTSConfig
+	Bot     BotConfig
+	Webhook WebhookConfig
+	Logger  LoggerConfig
+	Redis   RedisConfig
+	API     APIConfig
+	Asynq   AsynqConfig
+	TTS     TTSConfig
+	STT     STTConfig
+	S3      S3Config
 }
 
 type BotConfig struct {
-	Token string
+	Token          string
+	Mode           string // "polling" or "webhook"
+	HandlerTimeout int    // seconds a single update may take
+	RateLimit      int    // updates per user allowed within RateWindow
+	RateWindow     int    // seconds
+}
+
+// WebhookConfig configures receiving updates over HTTP instead of long polling
+type WebhookConfig struct {
+	URL            string // public URL registered with setWebhook
+	Secret         string // checked against X-Telegram-Bot-Api-Secret-Token
+	Listen         string
+	Path           string
+	Cert           string // self-signed public certificate uploaded to Telegram
+	TLSCert        string // certificate and key to terminate TLS in the bot itself
+	TLSKey         string
+	MaxConnections int
+	DropPending    bool
+	DeleteOnStop   bool // keep false when several replicas share the webhook
 }
 
 type LoggerConfig struct {
@@ -34,9 +56,10 @@ type RedisConfig struct {
 }
 
 type APIConfig struct {
-	BaseURL string
-	APIKey  string
-	Timeout int
+	BaseURL  string
+	APIKeyID string // ID of the service key the backend knows the bot by
+	APIKey   string // secret signing requests to the backend's /telegram endpoints
+	Timeout  int
 }
 
 type AsynqConfig struct {
@@ -46,8 +69,39 @@ type AsynqConfig struct {
 	Concurrency   int
 }
 
+// TTSConfig configures the text-to-speech provider and where generated audio is cached
 type TTSConfig struct {
-	CacheDir string
+	Provider string // "google", "http", "exec" or "silent"
+	URL      string // OpenAI-compatible speech server for the "http" provider
+	APIKey   string
+	Model    string
+	Voice    string
+	Command  string // command line for the "exec" provider, e.g. "espeak-ng -v {lang} --stdout --stdin"
+	Language string
+	Timeout  int
+	FFmpeg   string // ffmpeg binary used to encode OGG/Opus voice notes
+	Storage  string // "redis" or "s3"
+	CacheTTL int    // days audio is kept in Redis
+}
+
+// S3Config configures S3-compatible object storage (AWS S3, MinIO, etc.)
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // base URL objects are served from, defaults to the bucket URL
+	PathStyle bool   // bucket in the path instead of the host name, required by MinIO
+}
+
+// STTConfig configures a Whisper-compatible speech-to-text service, voice input is off without URL
+type STTConfig struct {
+	URL      string
+	APIKey   string
+	Model    string
+	Language string
+	Timeout  int
 }
 
 var cfg *Config
@@ -83,7 +137,23 @@ func Init() {
 
 	cfg = &Config{
 		Bot: BotConfig{
-			Token: viper.GetString("BOT_TOKEN"),
+			Token:          viper.GetString("BOT_TOKEN"),
+			Mode:           viper.GetString("BOT_MODE"),
+			HandlerTimeout: viper.GetInt("BOT_HANDLER_TIMEOUT"),
+			RateLimit:      viper.GetInt("BOT_RATE_LIMIT"),
+			RateWindow:     viper.GetInt("BOT_RATE_WINDOW"),
+		},
+		Webhook: WebhookConfig{
+			URL:            viper.GetString("WEBHOOK_URL"),
+			Secret:         viper.GetString("WEBHOOK_SECRET"),
+			Listen:         viper.GetString("WEBHOOK_LISTEN"),
+			Path:           viper.GetString("WEBHOOK_PATH"),
+			Cert:           viper.GetString("WEBHOOK_CERT"),
+			TLSCert:        viper.GetString("WEBHOOK_TLS_CERT"),
+			TLSKey:         viper.GetString("WEBHOOK_TLS_KEY"),
+			MaxConnections: viper.GetInt("WEBHOOK_MAX_CONNECTIONS"),
+			DropPending:    viper.GetBool("WEBHOOK_DROP_PENDING"),
+			DeleteOnStop:   viper.GetBool("WEBHOOK_DELETE_ON_STOP"),
 		},
 		Logger: LoggerConfig{
 			Level: viper.GetString("LOG_LEVEL"),
@@ -96,9 +166,10 @@ func Init() {
 		},
 
 		API: APIConfig{
-			BaseURL: viper.GetString("API_BASE_URL"),
-			APIKey:  viper.GetString("API_KEY"),
-			Timeout: viper.GetInt("API_TIMEOUT"),
+			BaseURL:  viper.GetString("API_BASE_URL"),
+			APIKeyID: viper.GetString("API_KEY_ID"),
+			APIKey:   viper.GetString("API_KEY"),
+			Timeout:  viper.GetInt("API_TIMEOUT"),
 		},
 		Asynq: AsynqConfig{
 			RedisAddr:     viper.GetString("ASYNQ_REDIS_ADDR"),
@@ -107,11 +178,58 @@ func Init() {
 			Concurrency:   viper.GetInt("ASYNQ_CONCURRENCY"),
 		},
 		TTS: TTSConfig{
-			CacheDir: viper.GetString("TTS_CACHE_DIR"),
+			Provider: viper.GetString("TTS_PROVIDER"),
+			URL:      viper.GetString("TTS_URL"),
+			APIKey:   viper.GetString("TTS_API_KEY"),
+			Model:    viper.GetString("TTS_MODEL"),
+			Voice:    viper.GetString("TTS_VOICE"),
+			Command:  viper.GetString("TTS_COMMAND"),
+			Language: viper.GetString("TTS_LANGUAGE"),
+			Timeout:  viper.GetInt("TTS_TIMEOUT"),
+			FFmpeg:   viper.GetString("TTS_FFMPEG"),
+			Storage:  viper.GetString("TTS_STORAGE"),
+			CacheTTL: viper.GetInt("TTS_CACHE_TTL_DAYS"),
+		},
+		STT: STTConfig{
+			URL:      viper.GetString("STT_URL"),
+			APIKey:   viper.GetString("STT_API_KEY"),
+			Model:    viper.GetString("STT_MODEL"),
+			Language: viper.GetString("STT_LANGUAGE"),
+			Timeout:  viper.GetInt("STT_TIMEOUT"),
+		},
+		S3: S3Config{
+			Endpoint:  viper.GetString("S3_ENDPOINT"),
+			Region:    viper.GetString("S3_REGION"),
+			Bucket:    viper.GetString("S3_BUCKET"),
+			AccessKey: viper.GetString("S3_ACCESS_KEY"),
+			SecretKey: viper.GetString("S3_SECRET_KEY"),
+			PublicURL: viper.GetString("S3_PUBLIC_URL"),
+			PathStyle: viper.GetBool("S3_PATH_STYLE"),
 		},
 	}
 
 	// Set defaults
+	if cfg.Bot.Mode == "" {
+		cfg.Bot.Mode = "polling"
+	}
+	if cfg.Bot.HandlerTimeout == 0 {
+		cfg.Bot.HandlerTimeout = 60
+	}
+	if cfg.Bot.RateLimit == 0 {
+		cfg.Bot.RateLimit = 20
+	}
+	if cfg.Bot.RateWindow == 0 {
+		cfg.Bot.RateWindow = 10
+	}
+	if cfg.Webhook.Listen == "" {
+		cfg.Webhook.Listen = ":8080"
+	}
+	if cfg.Webhook.Path == "" {
+		cfg.Webhook.Path = "/webhook"
+		if u, err := url.Parse(cfg.Webhook.URL); err == nil && u.Path != "" {
+			cfg.Webhook.Path = u.Path
+		}
+	}
 	if cfg.API.Timeout == 0 {
 		cfg.API.Timeout = 30
 	}
@@ -127,8 +245,41 @@ func Init() {
 	if cfg.Asynq.RedisDB == 0 {
 		cfg.Asynq.RedisDB = cfg.Redis.DB
 	}
-	if cfg.TTS.CacheDir == "" {
-		cfg.TTS.CacheDir = "/tmp/tts"
+	if cfg.TTS.Provider == "" {
+		cfg.TTS.Provider = "google"
+	}
+	if cfg.TTS.Model == "" {
+		cfg.TTS.Model = "tts-1"
+	}
+	if cfg.TTS.Voice == "" {
+		cfg.TTS.Voice = "alloy"
+	}
+	if cfg.TTS.Language == "" {
+		cfg.TTS.Language = "en"
+	}
+	if cfg.TTS.Timeout == 0 {
+		cfg.TTS.Timeout = 15
+	}
+	if cfg.TTS.FFmpeg == "" {
+		cfg.TTS.FFmpeg = "ffmpeg"
+	}
+	if cfg.TTS.Storage == "" {
+		cfg.TTS.Storage = "redis"
+	}
+	if cfg.TTS.CacheTTL == 0 {
+		cfg.TTS.CacheTTL = 30
+	}
+	if cfg.S3.Region == "" {
+		cfg.S3.Region = "us-east-1"
+	}
+	if cfg.STT.Model == "" {
+		cfg.STT.Model = "whisper-1"
+	}
+	if cfg.STT.Language == "" {
+		cfg.STT.Language = "en"
+	}
+	if cfg.STT.Timeout == 0 {
+		cfg.STT.Timeout = 30
 	}
 }
 
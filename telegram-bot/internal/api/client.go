@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"telegram-bot/internal/domain"
@@ -15,17 +17,40 @@ import (
 	"go.uber.org/zap"
 )
 
+// progressSyncAttempts is how many times lesson progress is sent before giving up
+const progressSyncAttempts = 3
+
+// requestIDHeader carries the correlation ID of the update that caused a request
+const requestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the correlation ID sent with backend requests
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
 // Client represents the API client for backend communication
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	logger     *zap.Logger
+	baseURL      string
+	serviceKeyID string
+	serviceKey   string
+	httpClient   *http.Client
+	logger       *zap.Logger
 }
 
-// NewClient creates a new API client
-func NewClient(baseURL string, logger *zap.Logger) *Client {
+// NewClient creates a new API client, the service key signs calls to the /telegram machine endpoints
+func NewClient(baseURL, serviceKeyID, serviceKey string, logger *zap.Logger) *Client {
 	return &Client{
-		baseURL: baseURL,
+		baseURL:      baseURL,
+		serviceKeyID: serviceKeyID,
+		serviceKey:   serviceKey,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -88,16 +113,17 @@ type JWTResponse struct {
 
 // PreferenceResponse represents user preferences from backend
 type PreferenceResponse struct {
-	ID             string `json:"id"`
-	UserID         string `json:"user_id"`
-	CEFRLevel      string `json:"cefr_level"`
-	WordsPerDay    int    `json:"words_per_day"`
-	NotificationAt string `json:"notification_at"`
-	Notifications  bool   `json:"notifications"`
-	Goal           string `json:"goal"`
-	FactEveryday   bool   `json:"fact_everyday"`
-	Subscribed     bool   `json:"subscribed"`
-	AvatarImageURL string `json:"avatar_image_url"`
+	ID                string `json:"id"`
+	UserID            string `json:"user_id"`
+	CEFRLevel         string `json:"cefr_level"`
+	WordsPerDay       int    `json:"words_per_day"`
+	NotificationAt    string `json:"notification_at"`
+	Notifications     bool   `json:"notifications"`
+	Goal              string `json:"goal"`
+	FactEveryday      bool   `json:"fact_everyday"`
+	Subscribed        bool   `json:"subscribed"`
+	AvatarImageURL    string `json:"avatar_image_url"`
+	LeaderboardOptOut bool   `json:"leaderboard_opt_out"`
 }
 
 // CreatePreferenceRequest represents user preferences creation request
@@ -114,19 +140,28 @@ type CreatePreferenceRequest struct {
 
 // UpdatePreferenceRequest represents user preferences update request
 type UpdatePreferenceRequest struct {
-	CEFRLevel      *string    `json:"cefr_level,omitempty"`
-	FactEveryday   *bool      `json:"fact_everyday,omitempty"`
-	Notifications  *bool      `json:"notifications,omitempty"`
-	NotificationAt *time.Time `json:"notification_at,omitempty"`
-	WordsPerDay    *int       `json:"words_per_day,omitempty"`
-	Goal           *string    `json:"goal,omitempty"`
-	Subscribed     *bool      `json:"subscribed,omitempty"`
-	AvatarImageURL *string    `json:"avatar_image_url,omitempty"`
+	CEFRLevel         *string    `json:"cefr_level,omitempty"`
+	FactEveryday      *bool      `json:"fact_everyday,omitempty"`
+	Notifications     *bool      `json:"notifications,omitempty"`
+	NotificationAt    *time.Time `json:"notification_at,omitempty"`
+	WordsPerDay       *int       `json:"words_per_day,omitempty"`
+	Goal              *string    `json:"goal,omitempty"`
+	Subscribed        *bool      `json:"subscribed,omitempty"`
+	AvatarImageURL    *string    `json:"avatar_image_url,omitempty"`
+	LeaderboardOptOut *bool      `json:"leaderboard_opt_out,omitempty"`
 }
 
 // TopicResponse represents a topic from the backend
 type TopicResponse struct {
-	Title string `json:"title"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	ParentID string `json:"parent_id"`
+}
+
+// TopicListResponse represents a page of topics
+type TopicListResponse struct {
+	Topics     []TopicResponse `json:"topics"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 // WordProgressRequest represents word progress update request
@@ -158,12 +193,157 @@ type ExerciseResultRequest struct {
 	TimeSpent    int       `json:"time_spent"`
 }
 
-// ProgressRequest represents progress request for backend API
-type ProgressRequest struct {
-	WordID          string     `json:"word_id"`
-	LearnedAt       *time.Time `json:"learned_at,omitempty"`
-	ConfidenceScore *int       `json:"confidence_score,omitempty"`
-	CntReviewed     *int       `json:"cnt_reviewed,omitempty"`
+// SyncEvent is a progress event for the backend sync endpoint
+type SyncEvent struct {
+	IdempotencyKey  string    `json:"idempotency_key"`
+	Type            string    `json:"type"` // "learned", "not_learned" or "reviewed"
+	WordID          string    `json:"word_id"`
+	ClientTime      time.Time `json:"client_time"`
+	ConfidenceScore *int      `json:"confidence_score,omitempty"`
+	CntReviewed     *int      `json:"cnt_reviewed,omitempty"`
+}
+
+// SyncRequest is the request body for the backend sync endpoint
+type SyncRequest struct {
+	DeviceID string      `json:"device_id"`
+	Events   []SyncEvent `json:"events"`
+}
+
+// SyncEventResult is the outcome of a single sync event
+type SyncEventResult struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// SyncResponse is the response of the backend sync endpoint
+type SyncResponse struct {
+	Results []SyncEventResult   `json:"results"`
+	Events  []GamificationEvent `json:"events,omitempty"`
+}
+
+// ActivityRequest reports answered exercises or a finished lesson for XP and the streak
+type ActivityRequest struct {
+	IdempotencyKey string     `json:"idempotency_key,omitempty"`
+	Type           string     `json:"type"` // "exercise" or "lesson"
+	Correct        int        `json:"correct"`
+	Total          int        `json:"total"`
+	ClientTime     *time.Time `json:"client_time,omitempty"`
+}
+
+// Achievement is an achievement and the user's progress towards it
+type Achievement struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Threshold   int    `json:"threshold"`
+	Progress    int    `json:"progress"`
+	Unlocked    bool   `json:"unlocked"`
+}
+
+// GamificationStats are the user's XP, level and streak
+type GamificationStats struct {
+	XP            int `json:"xp"`
+	Level         int `json:"level"`
+	NextLevelXP   int `json:"next_level_xp"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+	StreakFreezes int `json:"streak_freezes"`
+}
+
+// GamificationEvent is a level up, streak change or unlocked achievement worth celebrating
+type GamificationEvent struct {
+	Type        string       `json:"type"`
+	XP          int          `json:"xp,omitempty"`
+	Level       int          `json:"level,omitempty"`
+	Streak      int          `json:"streak,omitempty"`
+	Freezes     int          `json:"freezes,omitempty"`
+	Achievement *Achievement `json:"achievement,omitempty"`
+}
+
+// ActivityResponse is the response of the backend activity endpoint
+type ActivityResponse struct {
+	XPGained int                 `json:"xp_gained"`
+	Stats    GamificationStats   `json:"stats"`
+	Events   []GamificationEvent `json:"events"`
+}
+
+// AchievementsResponse is the response of the backend achievements endpoint
+type AchievementsResponse struct {
+	Stats        GamificationStats `json:"stats"`
+	Achievements []Achievement     `json:"achievements"`
+}
+
+// FriendInvite is an invite token, shared as a bot start link
+type FriendInvite struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Friend is a friend of the user
+type Friend struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// LeaderboardEntry is a user's place on a leaderboard
+type LeaderboardEntry struct {
+	Rank int    `json:"rank"`
+	Name string `json:"name"`
+	XP   int    `json:"xp"`
+	IsMe bool   `json:"is_me"`
+}
+
+// LeaderboardResponse is the weekly XP leaderboard
+type LeaderboardResponse struct {
+	Scope   string             `json:"scope"`
+	Week    string             `json:"week"`
+	EndsAt  time.Time          `json:"ends_at"`
+	Entries []LeaderboardEntry `json:"entries"`
+	Me      *LeaderboardEntry  `json:"me,omitempty"`
+	Hidden  bool               `json:"hidden"`
+}
+
+// WordSentence is an example sentence of a looked up word
+type WordSentence struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+}
+
+// WordLookupResult is a word found by the backend lookup
+type WordLookupResult struct {
+	ID           string         `json:"id"`
+	Word         string         `json:"word"`
+	Translation  *string        `json:"translation,omitempty"`
+	PartOfSpeech string         `json:"part_of_speech"`
+	Phonetic     *string        `json:"phonetic,omitempty"`
+	CEFRLevel    string         `json:"cefr_level"`
+	Sentences    []WordSentence `json:"sentences"`
+}
+
+// CaptureRequest adds a word to the user's next lesson
+type CaptureRequest struct {
+	Word string `json:"word"`
+}
+
+// CaptureResponse is the saved capture
+type CaptureResponse struct {
+	ID        string `json:"id"`
+	WordID    string `json:"word_id"`
+	Word      string `json:"word"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+// ChatRequest is the whole dialog sent to the backend chat engine
+type ChatRequest struct {
+	Chat []domain.ChatMessage `json:"chat"`
+}
+
+// ChatResponse is the dialog with the AI reply appended
+type ChatResponse struct {
+	Chat     []domain.ChatMessage `json:"chat"`
+	Finished bool                 `json:"finished,omitempty"`
 }
 
 // ErrorResponse represents API error response
@@ -192,6 +372,43 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "fluently-telegram-bot/1.0")
+	if id := RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// doServiceRequest performs HTTP request signed with the bot's service key
+func (c *Client) doServiceRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fluently-telegram-bot/1.0")
+	if id := RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+	if err := signRequest(req, jsonData, c.serviceKeyID, c.serviceKey, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -220,11 +437,14 @@ func (c *Client) doAuthenticatedRequest(ctx context.Context, method, endpoint st
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "fluently-telegram-bot/1.0")
+	if id := RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
 	if token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	c.logger.Info("Sending request", zap.String("url", url), zap.String("method", method), zap.Any("body", body))
+	c.logger.Info("Sending request", zap.String("url", url), zap.String("method", method), zap.String("request_id", RequestIDFromContext(ctx)), zap.Any("body", body))
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -263,7 +483,7 @@ func (c *Client) parseResponse(resp *http.Response, dest interface{}) error {
 func (c *Client) CreateLinkToken(ctx context.Context, telegramID int64) (*CreateLinkTokenResponse, error) {
 	req := CreateLinkTokenRequest{TelegramID: telegramID}
 
-	resp, err := c.doRequest(ctx, "POST", "/telegram/create-link", req)
+	resp, err := c.doServiceRequest(ctx, "POST", "/telegram/create-link", req)
 	if err != nil {
 		c.logger.With(zap.Int64("telegram_id", telegramID), zap.Error(err)).Error("Failed to create link token")
 		return nil, err
@@ -283,7 +503,7 @@ func (c *Client) CreateLinkToken(ctx context.Context, telegramID int64) (*Create
 func (c *Client) CheckLinkStatus(ctx context.Context, telegramID int64) (*CheckLinkStatusResponse, error) {
 	req := CheckLinkStatusRequest{TelegramID: telegramID}
 
-	resp, err := c.doRequest(ctx, "POST", "/telegram/check-status", req)
+	resp, err := c.doServiceRequest(ctx, "POST", "/telegram/check-status", req)
 	if err != nil {
 		c.logger.With(zap.Int64("telegram_id", telegramID), zap.Error(err)).Error("Failed to check link status")
 		return nil, err
@@ -353,22 +573,37 @@ func (c *Client) UpdateUserPreferences(ctx context.Context, token string, prefer
 	return &result, nil
 }
 
-// GetTopics retrieves all available topics from backend
+// GetTopics retrieves all main topics from backend, following the pages of the topic list
 func (c *Client) GetTopics(ctx context.Context, token string) ([]TopicResponse, error) {
-	resp, err := c.doAuthenticatedRequest(ctx, "GET", "/api/v1/topics", nil, token)
-	if err != nil {
-		c.logger.With(zap.Error(err)).Error("Failed to get topics")
-		return nil, err
-	}
+	var topics []TopicResponse
+	cursor := ""
+	for {
+		endpoint := "/api/v1/topics?root=true&limit=200"
+		if cursor != "" {
+			endpoint += "&cursor=" + url.QueryEscape(cursor)
+		}
 
-	var result []TopicResponse
-	if err := c.parseResponse(resp, &result); err != nil {
-		c.logger.With(zap.Error(err)).Error("Failed to parse get topics response")
-		return nil, err
+		resp, err := c.doAuthenticatedRequest(ctx, "GET", endpoint, nil, token)
+		if err != nil {
+			c.logger.With(zap.Error(err)).Error("Failed to get topics")
+			return nil, err
+		}
+
+		var page TopicListResponse
+		if err := c.parseResponse(resp, &page); err != nil {
+			c.logger.With(zap.Error(err)).Error("Failed to parse get topics response")
+			return nil, err
+		}
+
+		topics = append(topics, page.Topics...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
 	}
 
-	c.logger.With(zap.Int("topic_count", len(result))).Debug("Successfully retrieved topics")
-	return result, nil
+	c.logger.With(zap.Int("topic_count", len(topics))).Debug("Successfully retrieved topics")
+	return topics, nil
 }
 
 // GenerateLesson generates a new lesson for the user with JWT authentication
@@ -464,7 +699,7 @@ func (c *Client) GetUserStats(ctx context.Context, userID string) (map[string]in
 func (c *Client) GetJWTTokens(ctx context.Context, telegramID int64) (*JWTResponse, error) {
 	req := AuthRequest{TelegramID: telegramID}
 
-	resp, err := c.doRequest(ctx, "POST", "/telegram/get-tokens", req)
+	resp, err := c.doServiceRequest(ctx, "POST", "/telegram/get-tokens", req)
 	if err != nil {
 		c.logger.With(zap.Int64("telegram_id", telegramID), zap.Error(err)).Error("Failed to get JWT tokens")
 		return nil, err
@@ -480,40 +715,281 @@ func (c *Client) GetJWTTokens(ctx context.Context, telegramID int64) (*JWTRespon
 	return &result, nil
 }
 
-// SendLessonProgress sends word progress data to backend after lesson completion
-func (c *Client) SendLessonProgress(ctx context.Context, token string, progressData []domain.WordProgress, badlyAnsweredWords []domain.BadlyAnsweredWord) error {
-	// Convert to backend format
-	var progressRequests []ProgressRequest
+// SendLessonProgress sends word progress data to backend after lesson completion and returns
+// the gamification events it earned. Events are keyed by the lesson start time, so resending
+// the same lesson is a no-op on the backend.
+func (c *Client) SendLessonProgress(ctx context.Context, token string, lessonStart time.Time, progressData []domain.WordProgress, badlyAnsweredWords []domain.BadlyAnsweredWord) ([]GamificationEvent, error) {
+	lessonKey := strconv.FormatInt(lessonStart.UnixNano(), 10)
+	req := SyncRequest{DeviceID: "telegram-bot"}
 
 	// Add well-answered words with full metadata
 	for _, progress := range progressData {
-		progressRequests = append(progressRequests, ProgressRequest{
+		confidence := progress.ConfidenceScore
+		cntReviewed := progress.CntReviewed
+		req.Events = append(req.Events, SyncEvent{
+			IdempotencyKey:  "tg:" + lessonKey + ":learned:" + progress.WordID,
+			Type:            "learned",
 			WordID:          progress.WordID,
-			LearnedAt:       &progress.LearnedAt,
-			ConfidenceScore: &progress.ConfidenceScore,
-			CntReviewed:     &progress.CntReviewed,
+			ClientTime:      progress.LearnedAt,
+			ConfidenceScore: &confidence,
+			CntReviewed:     &cntReviewed,
 		})
 	}
 
-	// Add badly-answered words with only word_id (no metadata)
+	// Badly answered words are dated by the lesson start, so a word learned later in the same lesson wins
 	for _, badWord := range badlyAnsweredWords {
-		progressRequests = append(progressRequests, ProgressRequest{
-			WordID: badWord.WordID,
-			// No metadata for badly answered words
+		req.Events = append(req.Events, SyncEvent{
+			IdempotencyKey: "tg:" + lessonKey + ":not_learned:" + badWord.WordID,
+			Type:           "not_learned",
+			WordID:         badWord.WordID,
+			ClientTime:     lessonStart,
 		})
 	}
 
-	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/progress", progressRequests, token)
+	if len(req.Events) == 0 {
+		return nil, nil
+	}
+
+	var result SyncResponse
+	var err error
+	for attempt := 1; attempt <= progressSyncAttempts; attempt++ {
+		var resp *http.Response
+		resp, err = c.doAuthenticatedRequest(ctx, "POST", "/api/v1/sync", req, token)
+		if err == nil {
+			err = c.parseResponse(resp, &result)
+			if err == nil || resp.StatusCode < 500 {
+				break
+			}
+		}
+
+		if attempt == progressSyncAttempts {
+			break
+		}
+		c.logger.With(zap.Error(err), zap.Int("attempt", attempt)).Warn("Failed to send lesson progress, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
 	if err != nil {
 		c.logger.With(zap.Error(err)).Error("Failed to send lesson progress")
+		return nil, err
+	}
+
+	for _, r := range result.Results {
+		if r.Status == "rejected" {
+			c.logger.With(zap.String("idempotency_key", r.IdempotencyKey), zap.String("error", r.Error)).Warn("Lesson progress event rejected")
+		}
+	}
+
+	c.logger.With(zap.Int("words_count", len(req.Events))).Info("Successfully sent lesson progress")
+	return result.Events, nil
+}
+
+// RecordActivity reports an activity and returns the XP, streak and achievements it earned
+func (c *Client) RecordActivity(ctx context.Context, token string, activity ActivityRequest) (*ActivityResponse, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/activity", activity, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to record activity")
+		return nil, err
+	}
+
+	var result ActivityResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse record activity response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateFriendInvite creates an invite anyone can accept to become the user's friend
+func (c *Client) CreateFriendInvite(ctx context.Context, token string) (*FriendInvite, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/friends/invites", nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to create friend invite")
+		return nil, err
+	}
+
+	var result FriendInvite
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse friend invite response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AcceptFriendInvite accepts an invite and returns the new friend
+func (c *Client) AcceptFriendInvite(ctx context.Context, token, inviteToken string) (*Friend, error) {
+	body := map[string]string{"token": inviteToken}
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/friends/accept", body, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to accept friend invite")
+		return nil, err
+	}
+
+	var result Friend
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetLeaderboard returns this week's leaderboard, scope is "global" or "friends"
+func (c *Client) GetLeaderboard(ctx context.Context, token, scope string, limit int) (*LeaderboardResponse, error) {
+	endpoint := "/api/v1/leaderboard?scope=" + url.QueryEscape(scope) + "&limit=" + strconv.Itoa(limit)
+
+	resp, err := c.doAuthenticatedRequest(ctx, "GET", endpoint, nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to get leaderboard")
+		return nil, err
+	}
+
+	var result LeaderboardResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse leaderboard response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetAchievements returns the user's XP, streak and achievements
+func (c *Client) GetAchievements(ctx context.Context, token string) (*AchievementsResponse, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "GET", "/api/v1/achievements", nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to get achievements")
+		return nil, err
+	}
+
+	var result AchievementsResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse achievements response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// LookupWords looks a word up in the dictionary and the user's personal words
+func (c *Client) LookupWords(ctx context.Context, token, query string, limit int) ([]WordLookupResult, error) {
+	endpoint := "/api/v1/words/lookup?q=" + url.QueryEscape(query) + "&limit=" + strconv.Itoa(limit)
+
+	resp, err := c.doAuthenticatedRequest(ctx, "GET", endpoint, nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to look up words")
+		return nil, err
+	}
+
+	var result []WordLookupResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse word lookup response")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetWord returns a word by ID
+func (c *Client) GetWord(ctx context.Context, token, wordID string) (*WordLookupResult, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "GET", "/api/v1/words/"+url.PathEscape(wordID), nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to get word")
+		return nil, err
+	}
+
+	var result WordLookupResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse get word response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CaptureWord adds a word to the user's words, it is queued into the next lesson
+func (c *Client) CaptureWord(ctx context.Context, token, word string) (*CaptureResponse, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/capture", CaptureRequest{Word: word}, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to capture word")
+		return nil, err
+	}
+
+	var result CaptureResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse capture response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Chat sends the dialog to the backend and returns it with the AI reply
+func (c *Client) Chat(ctx context.Context, token string, messages []domain.ChatMessage) (*ChatResponse, error) {
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/chat", ChatRequest{Chat: messages}, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to send chat message")
+		return nil, err
+	}
+
+	var result ChatResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse chat response")
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FinishChat ends the dialog, the backend moves its history to the database
+func (c *Client) FinishChat(ctx context.Context, token string) error {
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/chat/finish", nil, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to finish chat")
 		return err
 	}
 
 	if err := c.parseResponse(resp, nil); err != nil {
-		c.logger.With(zap.Error(err)).Error("Failed to parse send lesson progress response")
+		c.logger.With(zap.Error(err)).Error("Failed to parse finish chat response")
 		return err
 	}
 
-	c.logger.With(zap.Int("words_count", len(progressData)+len(badlyAnsweredWords))).Info("Successfully sent lesson progress")
+	return nil
+}
+
+// RecordReview records a single review of a word through the sync endpoint. The review
+// updates the confidence and revisions of a learned word and never changes whether the
+// word is learned, so a bad take doesn't wipe progress and a lookup doesn't learn a word.
+func (c *Client) RecordReview(ctx context.Context, token, wordID string, score int, reviewedAt time.Time) error {
+	event := SyncEvent{
+		IdempotencyKey:  "tg:review:" + wordID + ":" + strconv.FormatInt(reviewedAt.UnixNano(), 10),
+		Type:            "reviewed",
+		WordID:          wordID,
+		ClientTime:      reviewedAt,
+		ConfidenceScore: &score,
+	}
+
+	req := SyncRequest{DeviceID: "telegram-bot", Events: []SyncEvent{event}}
+	resp, err := c.doAuthenticatedRequest(ctx, "POST", "/api/v1/sync", req, token)
+	if err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to record review")
+		return err
+	}
+
+	var result SyncResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		c.logger.With(zap.Error(err)).Error("Failed to parse record review response")
+		return err
+	}
+
+	for _, r := range result.Results {
+		if r.Status == "rejected" {
+			return fmt.Errorf("review rejected: %s", r.Error)
+		}
+	}
+
 	return nil
 }
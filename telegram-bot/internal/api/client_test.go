@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestGetTopicsFollowsPages(t *testing.T) {
+	pages := map[string]TopicListResponse{
+		"":     {Topics: []TopicResponse{{ID: "1", Title: "Animals"}}, NextCursor: "next"},
+		"next": {Topics: []TopicResponse{{ID: "2", Title: "Travel"}}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/topics" || r.URL.Query().Get("root") != "true" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Missing access token")
+		}
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", zap.NewNop())
+	topics, err := client.GetTopics(t.Context(), "token")
+	if err != nil {
+		t.Fatalf("Failed to get topics: %v", err)
+	}
+	if len(topics) != 2 || topics[0].Title != "Animals" || topics[1].Title != "Travel" {
+		t.Errorf("Unexpected topics: %+v", topics)
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of requests signed with the bot's service key, the backend verifies them in serviceauth
+const (
+	serviceKeyHeader       = "X-Service-Key"
+	serviceTimestampHeader = "X-Service-Timestamp"
+	serviceNonceHeader     = "X-Service-Nonce"
+	serviceSignatureHeader = "X-Service-Signature"
+)
+
+// signRequest signs a request with HMAC-SHA256 over its method, path, timestamp, a random nonce and body hash
+func signRequest(req *http.Request, body []byte, keyID, secret string, now time.Time) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set(serviceKeyHeader, keyID)
+	req.Header.Set(serviceTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(serviceNonceHeader, hex.EncodeToString(nonce))
+	req.Header.Set(serviceSignatureHeader, signature(secret, req.Method, req.URL.RequestURI(), timestamp, hex.EncodeToString(nonce), body))
+	return nil
+}
+
+// signature returns the hex HMAC-SHA256 of a request
+func signature(secret, method, path string, timestamp int64, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		method,
+		path,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testServiceSecret = "0123456789abcdef0123456789abcdef"
+
+// TestSignature checks the signature against the vector the backend tests too
+func TestSignature(t *testing.T) {
+	got := signature(testServiceSecret, "POST", "/telegram/get-tokens", 1735689600, "n0nce", []byte(`{"telegram_id":42}`))
+	want := "137cf4d5e67bb46d0685a925ff2b549d9cfc0556554607c052716005b82b9f9e"
+	if got != want {
+		t.Fatalf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	now := time.Unix(1735689600, 0)
+	body := []byte(`{"telegram_id":42}`)
+
+	first := httptest.NewRequest("POST", "http://backend/telegram/get-tokens", nil)
+	second := httptest.NewRequest("POST", "http://backend/telegram/get-tokens", nil)
+	if err := signRequest(first, body, "telegram-bot-1", testServiceSecret, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := signRequest(second, body, "telegram-bot-1", testServiceSecret, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := first.Header.Get(serviceKeyHeader); got != "telegram-bot-1" {
+		t.Errorf("key header = %q", got)
+	}
+	if got := first.Header.Get(serviceTimestampHeader); got != "1735689600" {
+		t.Errorf("timestamp header = %q", got)
+	}
+	if first.Header.Get(serviceNonceHeader) == second.Header.Get(serviceNonceHeader) {
+		t.Error("requests share a nonce")
+	}
+
+	want := signature(testServiceSecret, "POST", "/telegram/get-tokens", now.Unix(), first.Header.Get(serviceNonceHeader), body)
+	if got := first.Header.Get(serviceSignatureHeader); got != want {
+		t.Errorf("signature header = %s, want %s", got, want)
+	}
+}
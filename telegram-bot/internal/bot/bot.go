@@ -13,6 +13,7 @@ import (
 	"telegram-bot/internal/api"
 	"telegram-bot/internal/bot/fsm"
 	"telegram-bot/internal/bot/handlers"
+	"telegram-bot/internal/bot/middleware"
 	"telegram-bot/internal/tasks"
 )
 
@@ -29,13 +30,27 @@ type TelegramBot struct {
 
 // NewTelegramBot creates a new Telegram bot instance
 func NewTelegramBot(cfg *config.Config, redisClient *redis.Client, apiClient *api.Client, scheduler *tasks.Scheduler, logger *zap.Logger) (*TelegramBot, error) {
-	// Create bot settings for long polling
 	settings := tele.Settings{
-		Token:  cfg.Bot.Token,
-		Poller: &tele.LongPoller{Timeout: 10 * time.Second},
+		Token: cfg.Bot.Token,
 	}
 
-	logger.Info("Using long polling")
+	switch cfg.Bot.Mode {
+	case "webhook":
+		if cfg.Webhook.URL == "" {
+			return nil, fmt.Errorf("WEBHOOK_URL is required in webhook mode")
+		}
+		// Without the secret anyone who finds the URL can post updates as any user
+		if cfg.Webhook.Secret == "" {
+			return nil, fmt.Errorf("WEBHOOK_SECRET is required in webhook mode")
+		}
+		settings.Poller = NewWebhookPoller(cfg.Webhook, redisClient, logger)
+		logger.Info("Using webhook", zap.String("url", cfg.Webhook.URL))
+	case "polling":
+		settings.Poller = &tele.LongPoller{Timeout: 10 * time.Second}
+		logger.Info("Using long polling")
+	default:
+		return nil, fmt.Errorf("unknown bot mode %q", cfg.Bot.Mode)
+	}
 
 	// Create bot instance
 	bot, err := tele.NewBot(settings)
@@ -67,36 +82,61 @@ func NewTelegramBot(cfg *config.Config, redisClient *redis.Client, apiClient *ap
 
 // setupHandlers configures all bot handlers
 func (tb *TelegramBot) setupHandlers() {
+	handlerTimeout := time.Duration(tb.config.Bot.HandlerTimeout) * time.Second
+
+	// Middleware has to be registered before the handlers it wraps
+	tb.bot.Use(
+		middleware.WithContext(handlerTimeout),
+		middleware.Logging(tb.logger),
+		middleware.Recover(tb.logger, errorReply),
+		middleware.Throttle(tb.redisClient, tb.logger, tb.config.Bot.RateLimit,
+			time.Duration(tb.config.Bot.RateWindow)*time.Second, "🐢 Слишком много запросов, подождите немного."),
+		middleware.UserLock(tb.redisClient, tb.logger, handlerTimeout+5*time.Second, 5*time.Second,
+			"⏳ Предыдущее действие ещё выполняется.", "⚠️ Не удалось обработать запрос, попробуйте ещё раз."),
+		tb.errorMiddleware,
+	)
+
 	// Command handlers
-	tb.bot.Handle("/start", tb.handleStart)
-	tb.bot.Handle("/help", tb.handleHelp)
-	tb.bot.Handle("/settings", tb.handleSettings)
-	tb.bot.Handle("/learn", tb.handleLearn)
-	tb.bot.Handle("/lesson", tb.handleLesson)
-	tb.bot.Handle("/test", tb.handleTest)
-	tb.bot.Handle("/stats", tb.handleStats)
-	tb.bot.Handle("/cancel", tb.handleCancel)
-	tb.bot.Handle("/menu", tb.handleMenu)
+	tb.bot.Handle("/start", tb.withState(tb.handlerService.HandleStartCommand))
+	tb.bot.Handle("/help", tb.withState(tb.handlerService.HandleHelpCommand))
+	tb.bot.Handle("/settings", tb.withState(tb.handlerService.HandleSettingsCommand))
+	tb.bot.Handle("/learn", tb.withState(tb.handlerService.HandleLearnCommand))
+	tb.bot.Handle("/lesson", tb.withState(tb.handlerService.HandleLessonCommand))
+	tb.bot.Handle("/test", tb.withState(tb.handlerService.HandleTestCommand))
+	tb.bot.Handle("/stats", tb.withState(tb.handlerService.HandleStatsCommand))
+	tb.bot.Handle("/cancel", tb.withState(tb.handlerService.HandleCancelCommand))
+	tb.bot.Handle("/menu", tb.withState(tb.handlerService.HandleMenuCommand))
+	tb.bot.Handle("/word", tb.withState(tb.handlerService.HandleWordCommand))
+	tb.bot.Handle("/chat", tb.withState(tb.handlerService.HandleChatCommand))
+	tb.bot.Handle("/pronounce", tb.withState(tb.handlerService.HandlePronounceCommand))
+	tb.bot.Handle("/top", tb.withState(tb.handlerService.HandleTopCommand))
+
+	// Inline mode: @bot word
+	tb.bot.Handle(tele.OnQuery, tb.withState(tb.handlerService.HandleInlineQuery))
 
 	// Message handler for all text messages
-	tb.bot.Handle(tele.OnText, tb.handleMessage)
+	tb.bot.Handle(tele.OnText, tb.withState(tb.handlerService.HandleTextMessage))
 
-	// Callback handler for all callback queries
-	tb.bot.Handle(tele.OnCallback, tb.handleCallback)
+	// Voice messages are used in audio exercises and AI conversations
+	tb.bot.Handle(tele.OnVoice, tb.withState(tb.handlerService.HandleVoiceMessage))
 
-	// Set error handler
-	tb.bot.Use(tb.errorMiddleware)
+	// Shared contacts are invited to become friends
+	tb.bot.Handle(tele.OnContact, tb.withState(tb.handlerService.HandleContactMessage))
+
+	// Callback handler for all callback queries
+	tb.bot.Handle(tele.OnCallback, tb.withState(tb.handlerService.HandleCallback))
 }
 
+// errorReply is sent to the user when a handler fails
+const errorReply = "⚠️ Что-то пошло не так. Пожалуйста, попробуйте еще раз или используйте /cancel для сброса."
+
 // errorMiddleware handles errors in middleware
 func (tb *TelegramBot) errorMiddleware(next tele.HandlerFunc) tele.HandlerFunc {
 	return func(c tele.Context) error {
 		err := next(c)
 		if err != nil {
-			tb.logger.Error("Handler error occurred", zap.Error(err))
-
 			// Try to send error message to user
-			if sendErr := c.Send("⚠️ Что-то пошло не так. Пожалуйста, попробуйте еще раз или используйте /cancel для сброса."); sendErr != nil {
+			if sendErr := c.Send(errorReply); sendErr != nil {
 				tb.logger.Error("Failed to send error message to user", zap.Error(sendErr))
 			}
 		}
@@ -104,147 +144,23 @@ func (tb *TelegramBot) errorMiddleware(next tele.HandlerFunc) tele.HandlerFunc {
 	}
 }
 
-// handleStart handles the /start command
-func (tb *TelegramBot) handleStart(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleStartCommand(ctx, c, userID, currentState)
-}
-
-// handleHelp handles the /help command
-func (tb *TelegramBot) handleHelp(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleHelpCommand(ctx, c, userID, currentState)
-}
-
-// handleSettings handles the /settings command
-func (tb *TelegramBot) handleSettings(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleSettingsCommand(ctx, c, userID, currentState)
-}
-
-// handleLearn handles the /learn command
-func (tb *TelegramBot) handleLearn(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleLearnCommand(ctx, c, userID, currentState)
-}
-
-// handleLesson handles the /lesson command
-func (tb *TelegramBot) handleLesson(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleLessonCommand(ctx, c, userID, currentState)
-}
+// stateHandler is a handler that works with the user's current FSM state
+type stateHandler func(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error
 
-// handleTest handles the /test command
-func (tb *TelegramBot) handleTest(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleTestCommand(ctx, c, userID, currentState)
-}
-
-// handleStats handles the /stats command
-func (tb *TelegramBot) handleStats(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleStatsCommand(ctx, c, userID, currentState)
-}
-
-// handleCancel handles the /cancel command
-func (tb *TelegramBot) handleCancel(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleCancelCommand(ctx, c, userID, currentState)
-}
-
-// handleMenu handles the /menu command
-func (tb *TelegramBot) handleMenu(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
-
-	return tb.handlerService.HandleMenuCommand(ctx, c, userID, currentState)
-}
-
-// handleMessage handles all text messages
-func (tb *TelegramBot) handleMessage(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
-	}
+// withState adapts a stateHandler, loading the sender's state with the request context
+func (tb *TelegramBot) withState(h stateHandler) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		ctx := middleware.Context(c)
+		userID := c.Sender().ID
 
-	return tb.handlerService.HandleTextMessage(ctx, c, userID, currentState)
-}
+		currentState, err := tb.stateManager.GetState(ctx, userID)
+		if err != nil {
+			tb.logger.Error("Failed to get user state", zap.Error(err), zap.Int64("user_id", userID))
+			currentState = fsm.StateStart
+		}
 
-// handleCallback handles all callback queries
-func (tb *TelegramBot) handleCallback(c tele.Context) error {
-	ctx := context.Background()
-	userID := c.Sender().ID
-	currentState, err := tb.stateManager.GetState(ctx, userID)
-	if err != nil {
-		tb.logger.Error("Failed to get user state")
-		currentState = fsm.StateStart
+		return h(ctx, c, userID, currentState)
 	}
-
-	return tb.handlerService.HandleCallback(ctx, c, userID, currentState)
 }
 
 // Start starts the bot
@@ -258,6 +174,12 @@ func (tb *TelegramBot) Start() error {
 	}
 	tb.logger.Info("Redis connection established")
 
+	if webhook, ok := tb.bot.Poller.(*WebhookPoller); ok {
+		if err := webhook.Register(tb.bot); err != nil {
+			return fmt.Errorf("failed to set webhook: %w", err)
+		}
+	}
+
 	// Start the bot
 	tb.bot.Start()
 	return nil
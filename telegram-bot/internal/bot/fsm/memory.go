@@ -175,8 +175,28 @@ const (
 	TempDataNotifications     TempDataType = "notifications"
 	TempDataNotificationTime  TempDataType = "notification_time"
 	TempDataTopicSelection    TempDataType = "topic_selection"
+	TempDataChat              TempDataType = "chat"
+	TempDataPronunciation     TempDataType = "pronunciation"
 )
 
+// ChatData holds the dialog with the AI. The backend chat API is stateless
+// towards the client, so the whole history is sent with every message.
+type ChatData struct {
+	Messages  []domain.ChatMessage `json:"messages"`
+	StartedAt time.Time            `json:"started_at"`
+}
+
+// PronunciationData holds the current pronunciation exercise
+type PronunciationData struct {
+	WordID    string    `json:"word_id"`
+	Word      string    `json:"word"`
+	Sentence  string    `json:"sentence"` // example sentence offered after the word
+	Target    string    `json:"target"`   // text the user has to say: the word or the sentence
+	Attempts  int       `json:"attempts"`
+	BestScore int       `json:"best_score"`
+	StartedAt time.Time `json:"started_at"`
+}
+
 // CEFRTestData holds temporary data for CEFR test flow
 type CEFRTestData struct {
 	Questions      []map[string]interface{} `json:"questions"`
@@ -527,6 +547,8 @@ func (m *UserStateManager) ResetUserToInitial(ctx context.Context, userID int64)
 		TempDataSettings,
 		TempDataExercise,
 		TempDataOnboarding,
+		TempDataChat,
+		TempDataPronunciation,
 	}
 
 	for _, dt := range dataTypes {
@@ -728,6 +750,11 @@ func (m *UserStateManager) IsInSettingsState(ctx context.Context, userID int64)
 	return m.IsInStateGroup(ctx, userID, IsSettingsState)
 }
 
+// IsInChatState checks if user is talking to the AI
+func (m *UserStateManager) IsInChatState(ctx context.Context, userID int64) (bool, error) {
+	return m.IsInStateGroup(ctx, userID, IsChatState)
+}
+
 // IsInCEFRTestState checks if the user is in a CEFR test state
 func (m *UserStateManager) IsInCEFRTestState(ctx context.Context, userID int64) (bool, error) {
 	return m.IsInStateGroup(ctx, userID, IsCEFRTestState)
@@ -774,6 +801,60 @@ func IsWrongStateError(err error) bool {
 	return errors.As(err, &wse)
 }
 
+// GetChatData retrieves the current AI dialog, an empty one if there is none
+func (m *UserStateManager) GetChatData(ctx context.Context, userID int64) (*ChatData, error) {
+	data := &ChatData{}
+	jsonData, err := m.redisClient.Get(ctx, userTempDataKey(userID, TempDataChat)).Result()
+	if err == redis.Nil {
+		return data, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get chat data: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(jsonData), data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat data: %w", err)
+	}
+
+	return data, nil
+}
+
+// StoreChatData stores the current AI dialog
+func (m *UserStateManager) StoreChatData(ctx context.Context, userID int64, data *ChatData) error {
+	return m.StoreTempData(ctx, userID, TempDataChat, data)
+}
+
+// ClearChatData removes the current AI dialog
+func (m *UserStateManager) ClearChatData(ctx context.Context, userID int64) error {
+	return m.ClearTempData(ctx, userID, TempDataChat)
+}
+
+// GetPronunciationData retrieves the current pronunciation exercise, nil if there is none
+func (m *UserStateManager) GetPronunciationData(ctx context.Context, userID int64) (*PronunciationData, error) {
+	jsonData, err := m.redisClient.Get(ctx, userTempDataKey(userID, TempDataPronunciation)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get pronunciation data: %w", err)
+	}
+
+	data := &PronunciationData{}
+	if err := json.Unmarshal([]byte(jsonData), data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pronunciation data: %w", err)
+	}
+
+	return data, nil
+}
+
+// StorePronunciationData stores the current pronunciation exercise
+func (m *UserStateManager) StorePronunciationData(ctx context.Context, userID int64, data *PronunciationData) error {
+	return m.StoreTempData(ctx, userID, TempDataPronunciation, data)
+}
+
+// ClearPronunciationData removes the current pronunciation exercise
+func (m *UserStateManager) ClearPronunciationData(ctx context.Context, userID int64) error {
+	return m.ClearTempData(ctx, userID, TempDataPronunciation)
+}
+
 // GetLessonProgress retrieves the new lesson progress structure
 func (m *UserStateManager) GetLessonProgress(ctx context.Context, userID int64) (*domain.LessonProgress, error) {
 	jsonData, err := m.redisClient.Get(ctx, userTempDataKey(userID, TempDataLesson)).Result()
@@ -89,6 +89,14 @@ const (
 	StateSettingsLanguage         UserState = "settings_language"
 	StateSettingsTopicSelection   UserState = "settings_topic_selection"
 
+	// AI Conversation Practice
+	StateChatStart    UserState = "chat_start"    // Waiting for the first message of a dialog
+	StateChatActive   UserState = "chat_active"   // Dialog with the AI in progress
+	StateChatFinished UserState = "chat_finished" // Dialog ended, history saved on the backend
+
+	// Pronunciation Practice
+	StatePronunciation UserState = "pronunciation" // Waiting for a voice message with the target text
+
 	// Account Management
 	StateAccountLinking UserState = "account_linking"
 	StateWaitingForLink UserState = "waiting_for_link"
@@ -238,6 +246,12 @@ var ValidTransitions = map[StateTransition]bool{
 	{StateSetComplete, StateShowingWordSet}:             true,
 	{StateSetComplete, StateSetComplete}:                true,
 
+	// AI conversation practice
+	{StateChatStart, StateChatActive}:     true,
+	{StateChatStart, StateChatFinished}:   true,
+	{StateChatActive, StateChatFinished}:  true,
+	{StateChatFinished, StateLessonStart}: true,
+
 	// Account management
 	{StateStart, StateAccountLinking}:          true,
 	{StateAccountLinking, StateWaitingForLink}: true,
@@ -321,6 +335,11 @@ func IsValidTransition(from, to UserState) bool {
 		return true
 	}
 
+	// Special case: a dialog with the AI or a pronunciation exercise can be started from any state
+	if to == StateChatStart || to == StatePronunciation {
+		return true
+	}
+
 	// Special case: allow transitions to start state from any state
 	if to == StateStart {
 		return true
@@ -452,3 +471,13 @@ func IsCEFRTestState(state UserState) bool {
 
 	return slices.Contains(testStates, state)
 }
+
+// IsChatState checks if the state is part of the AI conversation flow
+func IsChatState(state UserState) bool {
+	chatStates := []UserState{
+		StateChatStart,
+		StateChatActive,
+	}
+
+	return slices.Contains(chatStates, state)
+}
@@ -88,3 +88,39 @@ func TestMainStatesIncludeLessonStates(t *testing.T) {
 		t.Error("Expected transition from StateErrorRecovery to StateLessonInProgress to be valid")
 	}
 }
+
+func TestChatStateTransitions(t *testing.T) {
+	// A dialog can be started from anywhere
+	for _, fromState := range []UserState{StateStart, StateLessonInProgress, StateSettings, StateChatFinished} {
+		if !IsValidTransition(fromState, StateChatStart) {
+			t.Errorf("Expected transition from %s to StateChatStart to be valid", fromState)
+		}
+	}
+
+	if !IsValidTransition(StateChatStart, StateChatActive) {
+		t.Error("Expected transition from StateChatStart to StateChatActive to be valid")
+	}
+	if !IsValidTransition(StateChatActive, StateChatFinished) {
+		t.Error("Expected transition from StateChatActive to StateChatFinished to be valid")
+	}
+	if IsValidTransition(StateSettings, StateChatActive) {
+		t.Error("Expected transition from StateSettings to StateChatActive to be invalid")
+	}
+
+	if !IsChatState(StateChatActive) || IsChatState(StateChatFinished) {
+		t.Error("Only started and active dialogs should be chat states")
+	}
+}
+
+func TestPronunciationStateTransitions(t *testing.T) {
+	// Pronunciation practice can be started in the middle of a lesson and left with /start
+	if !IsValidTransition(StateLessonInProgress, StatePronunciation) {
+		t.Error("Expected transition from StateLessonInProgress to StatePronunciation to be valid")
+	}
+	if !IsValidTransition(StatePronunciation, StatePronunciation) {
+		t.Error("Expected starting a new pronunciation exercise to be valid")
+	}
+	if !IsValidTransition(StatePronunciation, StateStart) {
+		t.Error("Expected transition from StatePronunciation to StateStart to be valid")
+	}
+}
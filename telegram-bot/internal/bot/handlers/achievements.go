@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/api"
+	"telegram-bot/internal/domain"
+)
+
+// maxActivityAnswers is the most answers the backend accepts for one activity
+const maxActivityAnswers = 200
+
+// achievementTitles are Russian titles of backend achievements, unknown ones use the backend title
+var achievementTitles = map[string]string{
+	"first_word":     "Первые шаги",
+	"first_lesson":   "Хорошее начало",
+	"words_100":      "Коллекционер слов",
+	"words_500":      "Книжный червь",
+	"streak_7":       "В ударе",
+	"streak_30":      "Неудержимый",
+	"xp_1000":        "Опытный",
+	"topic_finished": "Мастер темы",
+}
+
+// reportLesson sends the finished lesson to the backend and returns the gamification events it earned.
+// Failures are logged only, the lesson is over for the user either way.
+func (s *HandlerService) reportLesson(ctx context.Context, userID int64, progress *domain.LessonProgress) []api.GamificationEvent {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return nil
+	}
+
+	events, err := s.apiClient.SendLessonProgress(ctx, token, progress.StartTime, progress.WordsLearned, progress.BadlyAnsweredWords)
+	if err != nil {
+		s.logger.Error("Failed to send lesson progress to backend", zap.Error(err))
+	}
+
+	correct := 0
+	for _, wordProgress := range progress.WordsLearned {
+		if wordProgress.ConfidenceScore > 0 {
+			correct++
+		}
+	}
+	total := min(len(progress.WordsLearned)+len(progress.BadlyAnsweredWords), maxActivityAnswers)
+
+	startTime := progress.StartTime
+	activity, err := s.apiClient.RecordActivity(ctx, token, api.ActivityRequest{
+		IdempotencyKey: "tg:lesson:" + strconv.FormatInt(startTime.UnixNano(), 10),
+		Type:           "lesson",
+		Correct:        min(correct, total),
+		Total:          total,
+		ClientTime:     &startTime,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record lesson activity", zap.Int64("user_id", userID), zap.Error(err))
+		return events
+	}
+
+	return append(events, activity.Events...)
+}
+
+// sendCelebrations congratulates the user on level ups, streaks and unlocked achievements
+func (s *HandlerService) sendCelebrations(c tele.Context, events []api.GamificationEvent) error {
+	var lines []string
+	xp := 0
+	for _, e := range events {
+		switch e.Type {
+		case "xp_gained":
+			xp += e.XP
+		case "level_up":
+			lines = append(lines, fmt.Sprintf("🆙 Новый уровень: *%d*!", e.Level))
+		case "streak_extended":
+			if e.Streak > 1 {
+				lines = append(lines, fmt.Sprintf("🔥 Серия занятий: *%d* %s подряд!", e.Streak, pluralDays(e.Streak)))
+			}
+		case "streak_freeze_used":
+			lines = append(lines, fmt.Sprintf("🧊 Заморозка сохранила вашу серию за пропущенные дни: %d", e.Freezes))
+		case "streak_freeze_earned":
+			lines = append(lines, "🧊 Вы получили заморозку серии: она спасёт серию, если пропустите день")
+		case "achievement_unlocked":
+			if e.Achievement == nil {
+				continue
+			}
+			title := achievementTitles[e.Achievement.ID]
+			if title == "" {
+				title = e.Achievement.Title
+			}
+			lines = append(lines, fmt.Sprintf("%s Достижение получено: *%s*", e.Achievement.Icon, title))
+		}
+	}
+
+	if len(lines) == 0 && xp == 0 {
+		return nil
+	}
+	if xp > 0 {
+		lines = append([]string{fmt.Sprintf("✨ +%d XP", xp)}, lines...)
+	}
+
+	return c.Send(strings.Join(lines, "\n"), &tele.SendOptions{ParseMode: tele.ModeMarkdown})
+}
+
+// pluralDays returns the Russian word for days agreeing with the number
+func pluralDays(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "день"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+		return "дня"
+	default:
+		return "дней"
+	}
+}
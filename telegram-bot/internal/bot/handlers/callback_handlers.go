@@ -395,13 +395,7 @@ func (s *HandlerService) handleFinalStats(ctx context.Context, c tele.Context, u
 	)
 
 	// Send progress to backend
-	token, err := s.stateManager.GetJWTToken(ctx, userID)
-	if err == nil {
-		err = s.apiClient.SendLessonProgress(ctx, token, progress.WordsLearned, progress.BadlyAnsweredWords)
-		if err != nil {
-			s.logger.Error("Failed to send lesson progress to backend", zap.Error(err))
-		}
-	}
+	events := s.reportLesson(ctx, userID, progress)
 
 	// Clear lesson progress
 	err = s.stateManager.ClearLessonProgress(ctx, userID)
@@ -423,7 +417,11 @@ func (s *HandlerService) handleFinalStats(ctx context.Context, c tele.Context, u
 		},
 	}
 
-	return c.Send(finalStatsText, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, keyboard)
+	if err := c.Send(finalStatsText, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, keyboard); err != nil {
+		return err
+	}
+
+	return s.sendCelebrations(c, events)
 }
 
 // handleCheckLinkStatus checks if user's Google account is linked
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/bot/fsm"
+	"telegram-bot/internal/domain"
+)
+
+// maxChatMessageLength is the longest message relayed to the AI
+const maxChatMessageLength = 1000
+
+// chatStopWords end the dialog when sent as a message, the backend knows some of them too
+var chatStopWords = []string{"стоп", "stop", "finish", "всё", "все", "хочу закончить", "закончить"}
+
+// HandleChatCommand handles the /chat command: starts a conversation practice with the AI
+func (s *HandlerService) HandleChatCommand(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	if _, err := s.stateManager.GetJWTToken(ctx, userID); err != nil {
+		return c.Send("🔐 Чтобы поговорить с ИИ, войдите в аккаунт: /start")
+	}
+
+	if fsm.IsChatState(currentState) {
+		return c.Send("💬 Диалог уже идёт, просто напишите сообщение.", chatKeyboard())
+	}
+
+	if err := s.stateManager.ClearChatData(ctx, userID); err != nil {
+		s.logger.Warn("Failed to clear previous chat", zap.Error(err))
+	}
+
+	if err := s.TransitionState(ctx, userID, fsm.StateChatStart); err != nil {
+		return err
+	}
+
+	text := "💬 *Разговорная практика*\n\n" +
+		"Давайте поговорим по-английски! Я буду использовать слова, которые вы сейчас изучаете.\n\n" +
+		"Напишите первое сообщение, например: _Hi! How are you?_"
+	if s.transcriber != nil {
+		text += "\nМожно отвечать и голосовыми сообщениями 🎤"
+	}
+	text += "\n\nЧтобы закончить, нажмите кнопку ниже или напишите «стоп»."
+
+	return c.Send(text, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, chatKeyboard())
+}
+
+// HandleChatMessage relays a user message to the AI and sends its reply
+func (s *HandlerService) HandleChatMessage(ctx context.Context, c tele.Context, userID int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	if isChatStopWord(text) {
+		return s.finishChat(ctx, c, userID)
+	}
+
+	if len([]rune(text)) > maxChatMessageLength {
+		return c.Send(fmt.Sprintf("✂️ Сообщение слишком длинное, уложитесь в %d символов.", maxChatMessageLength))
+	}
+
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Сессия истекла, войдите в аккаунт заново: /start")
+	}
+
+	chat, err := s.stateManager.GetChatData(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if chat.StartedAt.IsZero() {
+		chat.StartedAt = time.Now()
+	}
+
+	messages := append(chat.Messages, domain.ChatMessage{Author: "user", Message: text})
+
+	var reply []domain.ChatMessage
+	var finished bool
+	err = s.withTypingIndicator(ctx, c, func() error {
+		resp, err := s.apiClient.Chat(ctx, token, messages)
+		if err != nil {
+			return err
+		}
+		reply, finished = resp.Chat, resp.Finished
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to get AI reply", zap.Error(err), zap.Int64("user_id", userID))
+		return c.Send("😔 Собеседник задумался и не ответил. Попробуйте отправить сообщение ещё раз.")
+	}
+
+	answer := lastLLMMessage(reply)
+	if answer == "" {
+		return c.Send("😔 Собеседник не ответил. Попробуйте отправить сообщение ещё раз.")
+	}
+
+	// The AI ends the dialog itself once all target words were practised
+	if finished {
+		if err := c.Send(answer); err != nil {
+			return err
+		}
+		return s.completeChat(ctx, c, userID, countUserMessages(reply))
+	}
+
+	chat.Messages = reply
+	if err := s.stateManager.StoreChatData(ctx, userID, chat); err != nil {
+		return err
+	}
+
+	if err := s.SetStateIfDifferent(ctx, userID, fsm.StateChatActive); err != nil {
+		return err
+	}
+
+	return c.Send(answer, chatKeyboard())
+}
+
+// HandleChatVoice transcribes a voice message and relays it as text
+func (s *HandlerService) HandleChatVoice(ctx context.Context, c tele.Context, userID int64, voice *tele.Voice) error {
+	if s.transcriber == nil {
+		return c.Send("🎤 Голосовые сообщения в диалоге пока не поддерживаются, напишите текстом.")
+	}
+
+	file, err := s.bot.File(&voice.File)
+	if err != nil {
+		s.logger.Error("Failed to download voice message", zap.Error(err))
+		return c.Send("❌ Не удалось получить голосовое сообщение, попробуйте ещё раз.")
+	}
+	defer file.Close()
+
+	var text string
+	err = s.withTypingIndicator(ctx, c, func() error {
+		var err error
+		text, err = s.transcriber.Transcribe(ctx, file, "voice.ogg")
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to transcribe voice message", zap.Error(err), zap.Int64("user_id", userID))
+		return c.Send("❌ Не удалось распознать речь, попробуйте ещё раз или напишите текстом.")
+	}
+
+	if text == "" {
+		return c.Send("🤔 Не расслышал, попробуйте ещё раз.")
+	}
+
+	if err := c.Send("🎤 " + text); err != nil {
+		return err
+	}
+
+	return s.HandleChatMessage(ctx, c, userID, text)
+}
+
+// HandleChatCallback handles chat:* callbacks
+func (s *HandlerService) HandleChatCallback(ctx context.Context, c tele.Context, userID int64, action string) error {
+	switch action {
+	case "finish":
+		// The button stays under old replies after the dialog is over
+		inChat, err := s.stateManager.IsInChatState(ctx, userID)
+		if err != nil || !inChat {
+			return nil
+		}
+		return s.finishChat(ctx, c, userID)
+	default:
+		s.logger.Warn("Unknown chat callback", zap.String("action", action))
+		return nil
+	}
+}
+
+// finishChat ends the dialog on the backend so its history is saved
+func (s *HandlerService) finishChat(ctx context.Context, c tele.Context, userID int64) error {
+	chat, err := s.stateManager.GetChatData(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if token, err := s.stateManager.GetJWTToken(ctx, userID); err == nil && len(chat.Messages) > 0 {
+		if err := s.apiClient.FinishChat(ctx, token); err != nil {
+			// The backend drops unfinished dialogs after a day, nothing else is lost
+			s.logger.Warn("Failed to finish chat on backend", zap.Error(err), zap.Int64("user_id", userID))
+		}
+	}
+
+	return s.completeChat(ctx, c, userID, countUserMessages(chat.Messages))
+}
+
+// completeChat clears the local dialog and leaves the chat state
+func (s *HandlerService) completeChat(ctx context.Context, c tele.Context, userID int64, userMessages int) error {
+	if err := s.stateManager.ClearChatData(ctx, userID); err != nil {
+		s.logger.Warn("Failed to clear chat data", zap.Error(err))
+	}
+
+	if err := s.TransitionState(ctx, userID, fsm.StateChatFinished); err != nil {
+		return err
+	}
+
+	text := "👋 Диалог завершён."
+	if userMessages > 0 {
+		text = fmt.Sprintf("👋 Диалог завершён, вы написали сообщений: %d. Отличная практика!", userMessages)
+	}
+	text += "\n\nНачать новый разговор: /chat\nПродолжить обучение: /learn"
+
+	return c.Send(text)
+}
+
+// chatKeyboard is the keyboard shown under AI replies
+func chatKeyboard() *tele.ReplyMarkup {
+	return &tele.ReplyMarkup{
+		InlineKeyboard: [][]tele.InlineButton{
+			{{Text: "🏁 Закончить диалог", Data: "chat:finish"}},
+		},
+	}
+}
+
+// isChatStopWord checks if the whole message asks to end the dialog
+func isChatStopWord(text string) bool {
+	text = strings.ToLower(strings.Trim(strings.TrimSpace(text), ".!"))
+	for _, w := range chatStopWords {
+		if text == w {
+			return true
+		}
+	}
+	return false
+}
+
+// lastLLMMessage returns the last message written by the AI
+func lastLLMMessage(messages []domain.ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Author == "llm" {
+			return messages[i].Message
+		}
+	}
+	return ""
+}
+
+// countUserMessages counts messages written by the user
+func countUserMessages(messages []domain.ChatMessage) int {
+	count := 0
+	for _, m := range messages {
+		if m.Author == "user" {
+			count++
+		}
+	}
+	return count
+}
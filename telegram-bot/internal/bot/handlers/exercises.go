@@ -578,16 +578,10 @@ func (s *HandlerService) completeLessonFlow(ctx context.Context, c tele.Context,
 	)
 
 	// Send progress to backend
-	token, err := s.stateManager.GetJWTToken(ctx, userID)
-	if err == nil {
-		err = s.apiClient.SendLessonProgress(ctx, token, progress.WordsLearned, progress.BadlyAnsweredWords)
-		if err != nil {
-			s.logger.Error("Failed to send lesson progress to backend", zap.Error(err))
-		}
-	}
+	events := s.reportLesson(ctx, userID, progress)
 
 	// Clear lesson progress
-	err = s.stateManager.ClearLessonProgress(ctx, userID)
+	err := s.stateManager.ClearLessonProgress(ctx, userID)
 	if err != nil {
 		s.logger.Error("Failed to clear lesson progress", zap.Error(err))
 	}
@@ -606,7 +600,11 @@ func (s *HandlerService) completeLessonFlow(ctx context.Context, c tele.Context,
 		},
 	}
 
-	return c.Send(finalText, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, keyboard)
+	if err := c.Send(finalText, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, keyboard); err != nil {
+		return err
+	}
+
+	return s.sendCelebrations(c, events)
 }
 
 // HandleSkipExercise handles skipping an exercise
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/api"
+	"telegram-bot/internal/bot/fsm"
+)
+
+const (
+	// friendInvitePayload prefixes invite tokens in start links: t.me/<bot>?start=friend_<token>
+	friendInvitePayload = "friend_"
+	leaderboardLimit    = 10
+)
+
+// leaderboardMedals mark the first three places
+var leaderboardMedals = map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
+
+// HandleTopCommand handles /top: shows this week's leaderboard among friends
+func (s *HandlerService) HandleTopCommand(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	return s.showLeaderboard(ctx, c, userID, "friends", false)
+}
+
+// HandleTopCallback handles leaderboard buttons
+func (s *HandlerService) HandleTopCallback(ctx context.Context, c tele.Context, userID int64, action string) error {
+	switch action {
+	case "friends", "global":
+		return s.showLeaderboard(ctx, c, userID, action, true)
+	case "invite":
+		return s.sendFriendInvite(ctx, c, userID)
+	case "hide", "show":
+		return s.setLeaderboardVisibility(ctx, c, userID, action == "hide")
+	}
+	return nil
+}
+
+// HandleFriendCallback handles friend invites sent to a shared contact
+func (s *HandlerService) HandleFriendCallback(ctx context.Context, c tele.Context, userID int64, action string) error {
+	if token, ok := strings.CutPrefix(action, "accept:"); ok {
+		return s.acceptFriendInvite(ctx, c, userID, token)
+	}
+	return nil
+}
+
+// HandleContactMessage invites the owner of a shared contact to become a friend.
+// They get a message with a button, nobody becomes a friend without accepting.
+func (s *HandlerService) HandleContactMessage(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	contact := c.Message().Contact
+	if contact == nil || contact.UserID == 0 {
+		return c.Send("👤 У этого контакта нет аккаунта в Telegram, пригласите друга ссылкой: /top")
+	}
+	if contact.UserID == userID {
+		return c.Send("🙂 Это ваш собственный контакт.")
+	}
+
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы приглашать друзей, войдите в аккаунт: /start")
+	}
+
+	invite, err := s.apiClient.CreateFriendInvite(ctx, token)
+	if err != nil {
+		return c.Send("❌ Не удалось создать приглашение, попробуйте позже.")
+	}
+
+	text := fmt.Sprintf("👋 %s приглашает вас в друзья в Fluently: будете соревноваться в недельном рейтинге XP.", c.Sender().FirstName)
+	keyboard := &tele.ReplyMarkup{
+		InlineKeyboard: [][]tele.InlineButton{
+			{{Text: "🤝 Принять", Data: "friend:accept:" + invite.Token}},
+		},
+	}
+
+	// The bot can only write to users who started it, otherwise the link is forwarded by hand
+	if _, err := s.bot.Send(&tele.User{ID: contact.UserID}, text, keyboard); err != nil {
+		s.logger.Debug("Failed to send friend invite to contact", zap.Int64("contact_id", contact.UserID), zap.Error(err))
+		return c.Send(fmt.Sprintf("📨 %s ещё не пользуется ботом. Перешлите приглашение:\n\n%s",
+			contact.FirstName, s.friendInviteLink(invite.Token)))
+	}
+
+	return c.Send(fmt.Sprintf("📨 Приглашение отправлено: %s", contact.FirstName))
+}
+
+// acceptFriendInvite accepts an invite from a start link or a button
+func (s *HandlerService) acceptFriendInvite(ctx context.Context, c tele.Context, userID int64, inviteToken string) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы добавить друга, войдите в аккаунт через /start и снова откройте приглашение.")
+	}
+
+	friend, err := s.apiClient.AcceptFriendInvite(ctx, token, inviteToken)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "404"):
+			return c.Send("⌛ Приглашение устарело, попросите друга прислать новое.")
+		case strings.Contains(err.Error(), "400"):
+			return c.Send("🙂 Это ваше собственное приглашение, отправьте его другу.")
+		}
+		s.logger.Error("Failed to accept friend invite", zap.Int64("user_id", userID), zap.Error(err))
+		return c.Send("❌ Не удалось принять приглашение, попробуйте позже.")
+	}
+
+	return c.Send(fmt.Sprintf("🤝 Теперь вы друзья с %s! Сравнить успехи: /top", friend.Name))
+}
+
+// sendFriendInvite sends a start link to forward to friends
+func (s *HandlerService) sendFriendInvite(ctx context.Context, c tele.Context, userID int64) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы приглашать друзей, войдите в аккаунт: /start")
+	}
+
+	invite, err := s.apiClient.CreateFriendInvite(ctx, token)
+	if err != nil {
+		return c.Send("❌ Не удалось создать приглашение, попробуйте позже.")
+	}
+
+	return c.Send("🤝 Перешлите другу эту ссылку, она действует 7 дней:\n\n" + s.friendInviteLink(invite.Token) +
+		"\n\nИли отправьте мне контакт друга из Telegram — я передам приглашение сам.")
+}
+
+// friendInviteLink returns the start link of an invite
+func (s *HandlerService) friendInviteLink(inviteToken string) string {
+	return "https://t.me/" + s.bot.Me.Username + "?start=" + friendInvitePayload + inviteToken
+}
+
+// setLeaderboardVisibility hides or shows the user on the global leaderboard
+func (s *HandlerService) setLeaderboardVisibility(ctx context.Context, c tele.Context, userID int64, hidden bool) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Войдите в аккаунт: /start")
+	}
+
+	if _, err := s.apiClient.UpdateUserPreferences(ctx, token, &api.UpdatePreferenceRequest{LeaderboardOptOut: &hidden}); err != nil {
+		return c.Send("❌ Не удалось изменить настройку, попробуйте позже.")
+	}
+
+	return s.showLeaderboard(ctx, c, userID, "global", true)
+}
+
+// showLeaderboard shows a weekly leaderboard, editing the message when switching scopes
+func (s *HandlerService) showLeaderboard(ctx context.Context, c tele.Context, userID int64, scope string, edit bool) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы смотреть рейтинг, войдите в аккаунт: /start")
+	}
+
+	board, err := s.apiClient.GetLeaderboard(ctx, token, scope, leaderboardLimit)
+	if err != nil {
+		return c.Send("❌ Не удалось загрузить рейтинг, попробуйте позже.")
+	}
+
+	text := formatLeaderboard(board)
+
+	switchBtn := tele.InlineButton{Text: "🌍 Все участники", Data: "top:global"}
+	if scope == "global" {
+		switchBtn = tele.InlineButton{Text: "👥 Друзья", Data: "top:friends"}
+	}
+	rows := [][]tele.InlineButton{
+		{switchBtn, {Text: "➕ Пригласить друга", Data: "top:invite"}},
+	}
+	if scope == "global" {
+		if board.Hidden {
+			rows = append(rows, []tele.InlineButton{{Text: "👀 Показывать меня в рейтинге", Data: "top:show"}})
+		} else {
+			rows = append(rows, []tele.InlineButton{{Text: "🙈 Скрыть меня из рейтинга", Data: "top:hide"}})
+		}
+	}
+	keyboard := &tele.ReplyMarkup{InlineKeyboard: rows}
+
+	if edit && c.Callback() != nil {
+		return c.Edit(text, keyboard)
+	}
+	return c.Send(text, keyboard)
+}
+
+// formatLeaderboard renders a leaderboard as plain text, names are user input and aren't escaped for Markdown
+func formatLeaderboard(board *api.LeaderboardResponse) string {
+	var b strings.Builder
+	if board.Scope == "global" {
+		b.WriteString("🌍 Рейтинг недели\n")
+	} else {
+		b.WriteString("👥 Рейтинг недели среди друзей\n")
+	}
+	b.WriteString(fmt.Sprintf("Итоги подводятся %s\n\n", board.EndsAt.Format("02.01 15:04 UTC")))
+
+	if len(board.Entries) == 0 {
+		b.WriteString("Пока никто не заработал XP на этой неделе. Будьте первым: /learn\n")
+	}
+
+	meShown := false
+	for _, e := range board.Entries {
+		b.WriteString(formatLeaderboardEntry(e))
+		meShown = meShown || e.IsMe
+	}
+	if board.Me != nil && !meShown {
+		b.WriteString("…\n" + formatLeaderboardEntry(*board.Me))
+	}
+
+	switch {
+	case board.Hidden:
+		b.WriteString("\n🙈 Вы скрыты из общего рейтинга, друзья по-прежнему видят ваши очки.")
+	case board.Scope == "friends" && len(board.Entries) <= 1:
+		b.WriteString("\nПригласите друзей, чтобы соревноваться вместе!")
+	}
+
+	return b.String()
+}
+
+// formatLeaderboardEntry renders a leaderboard line
+func formatLeaderboardEntry(e api.LeaderboardEntry) string {
+	place, ok := leaderboardMedals[e.Rank]
+	if !ok {
+		place = fmt.Sprintf("%d.", e.Rank)
+	}
+	name := e.Name
+	if e.IsMe {
+		name += " (вы)"
+	}
+	return fmt.Sprintf("%s %s — %d XP\n", place, name, e.XP)
+}
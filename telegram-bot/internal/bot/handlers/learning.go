@@ -1,9 +1,9 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -895,33 +895,36 @@ func (s *HandlerService) redirectToOnboarding(ctx context.Context, c tele.Contex
 	return c.Send(onboardingText, &tele.SendOptions{ParseMode: tele.ModeMarkdown}, keyboard)
 }
 
-// sendWordVoiceMessage generates and sends a voice message for a word
+// sendWordVoiceMessage generates and sends a voice message for a word.
+// The uploaded file is remembered, so the next time the word is sent by file ID.
 func (s *HandlerService) sendWordVoiceMessage(ctx context.Context, c tele.Context, word string) error {
-	// Generate voice message
-	audioData, err := s.ttsService.GenerateWordVoiceMessage(word)
-	if err != nil {
-		return fmt.Errorf("failed to generate voice message: %w", err)
+	if fileID := s.cachedVoiceFileID(ctx, word); fileID != "" {
+		if err := c.Send(&tele.Voice{File: tele.File{FileID: fileID}, Caption: word}); err == nil {
+			return nil
+		}
+		// The file may be gone from Telegram servers, upload it again
+		s.redisClient.Del(ctx, voiceFileKey(word))
 	}
 
-	// Validate audio data
-	if err := s.ttsService.ValidateAudioData(audioData); err != nil {
-		return fmt.Errorf("invalid audio data: %w", err)
+	if s.ttsService == nil {
+		return fmt.Errorf("TTS is not configured")
 	}
 
-	// Create temporary file for the voice message
-	tempFile, err := s.ttsService.CreateVoiceMessageFromBytes(audioData, word)
+	audio, err := s.ttsService.Voice(ctx, word)
 	if err != nil {
-		return fmt.Errorf("failed to create voice file: %w", err)
+		return fmt.Errorf("failed to generate voice message: %w", err)
 	}
 
-	// Clean up temporary file after sending
-	defer func() {
-		if err := os.Remove(tempFile); err != nil {
-			s.logger.Warn("Failed to clean up temp voice file", zap.Error(err))
+	voice := &tele.Voice{File: tele.FromReader(bytes.NewReader(audio)), Caption: word}
+	if err := c.Send(voice); err != nil {
+		return err
+	}
+
+	if voice.FileID != "" {
+		if err := s.redisClient.Set(ctx, voiceFileKey(word), voice.FileID, voiceFileCacheTTL).Err(); err != nil {
+			s.logger.Warn("Failed to cache voice file ID", zap.Error(err))
 		}
-	}()
+	}
 
-	// Send voice message
-	voice := &tele.Voice{File: tele.FromDisk(tempFile), Caption: word}
-	return c.Send(voice)
+	return nil
 }
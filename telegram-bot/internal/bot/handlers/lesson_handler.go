@@ -287,11 +287,6 @@ func (s *HandlerService) HandleWaitingForAudioMessage(ctx context.Context, c tel
 	return c.Send("Пожалуйста, предоставьте аудио ответ.")
 }
 
-// HandleAudioExerciseResponse handles audio exercise responses
-func (s *HandlerService) HandleAudioExerciseResponse(ctx context.Context, c tele.Context, userID int64, voice interface{}) error {
-	return c.Send("Получен ответ на аудио упражнение.")
-}
-
 // HandleLearnMenuCallback handles learn menu callback
 func (s *HandlerService) HandleLearnMenuCallback(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
 	return c.Send("Меню обучения...")
@@ -306,7 +301,6 @@ func (s *HandlerService) buildCompletePreferencesFromQuestionnaire(ctx context.C
 	goal := "Изучение английского" // Default goal
 	factEveryday := false
 	subscribed := false
-	avatarImageURL := ""
 	wordsPerDay := 10 // Default words per day
 	notifications := false
 	var notificationTime *time.Time
@@ -366,7 +360,6 @@ func (s *HandlerService) buildCompletePreferencesFromQuestionnaire(ctx context.C
 	preferences.Goal = &goal
 	preferences.FactEveryday = &factEveryday
 	preferences.Subscribed = &subscribed
-	preferences.AvatarImageURL = &avatarImageURL
 	preferences.WordsPerDay = &wordsPerDay
 	preferences.Notifications = &notifications
 	if notificationTime != nil {
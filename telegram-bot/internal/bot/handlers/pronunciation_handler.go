@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/bot/fsm"
+	"telegram-bot/internal/stt"
+)
+
+// maxPronunciationAttempts is how many tries a user gets before the result is recorded as failed
+const maxPronunciationAttempts = 3
+
+// HandlePronounceCommand handles /pronounce [word]: asks the user to say a word and then its example sentence.
+// Without a word, one of the words of the current lesson is picked.
+func (s *HandlerService) HandlePronounceCommand(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы тренировать произношение, войдите в аккаунт: /start")
+	}
+
+	if s.transcriber == nil {
+		return c.Send("🎤 Распознавание речи пока не настроено, тренировка произношения недоступна.")
+	}
+
+	var data *fsm.PronunciationData
+	if query := strings.TrimSpace(c.Message().Payload); query != "" {
+		words, err := s.apiClient.LookupWords(ctx, token, query, 1)
+		if err != nil {
+			s.logger.Error("Failed to look up word to pronounce", zap.Error(err), zap.String("query", query))
+			return c.Send("❌ Не удалось найти слово, попробуйте позже.")
+		}
+		if len(words) == 0 {
+			return c.Send(fmt.Sprintf("🤷 Слово «%s» не найдено в словаре.", query))
+		}
+
+		word := words[0]
+		data = &fsm.PronunciationData{WordID: word.ID, Word: word.Word}
+		if len(word.Sentences) > 0 {
+			data.Sentence = word.Sentences[0].Sentence
+		}
+	} else {
+		data = s.pronunciationFromLesson(ctx, userID)
+		if data == nil {
+			return c.Send("🗣 Напишите слово после команды, например: /pronounce thought\n\n" +
+				"Во время урока можно просто отправить /pronounce — я выберу слово из урока.")
+		}
+	}
+
+	data.Target = data.Word
+	data.StartedAt = time.Now()
+	if err := s.stateManager.StorePronunciationData(ctx, userID, data); err != nil {
+		return err
+	}
+
+	if err := s.TransitionState(ctx, userID, fsm.StatePronunciation); err != nil {
+		return err
+	}
+
+	return s.sendPronunciationTask(ctx, c, data)
+}
+
+// HandleAudioExerciseResponse transcribes the user's recording, scores it against the
+// target text and records the result of the exercise as a review of the word
+func (s *HandlerService) HandleAudioExerciseResponse(ctx context.Context, c tele.Context, userID int64, file *tele.File, filename string) error {
+	data, err := s.stateManager.GetPronunciationData(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return c.Send("🗣 Сначала выберите слово для тренировки: /pronounce")
+	}
+
+	if s.transcriber == nil {
+		return c.Send("🎤 Распознавание речи пока не настроено, попробуйте позже.")
+	}
+
+	audio, err := s.bot.File(file)
+	if err != nil {
+		s.logger.Error("Failed to download voice message", zap.Error(err))
+		return c.Send("❌ Не удалось получить голосовое сообщение, попробуйте ещё раз.")
+	}
+	defer audio.Close()
+
+	var transcript string
+	err = s.withTypingIndicator(ctx, c, func() error {
+		var err error
+		transcript, err = s.transcriber.Transcribe(ctx, audio, filename)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to transcribe voice message", zap.Error(err), zap.Int64("user_id", userID))
+		return c.Send("❌ Не удалось распознать речь, попробуйте ещё раз.")
+	}
+
+	if transcript == "" {
+		return c.Send("🤔 Не расслышал, попробуйте ещё раз чуть громче.")
+	}
+
+	// Further recordings after the result are just practice
+	wasDone := data.BestScore >= stt.PassScore || data.Attempts >= maxPronunciationAttempts
+
+	score := stt.Score(data.Target, transcript)
+	passed := score >= stt.PassScore
+	data.Attempts++
+	data.BestScore = max(data.BestScore, score)
+	done := passed || data.Attempts >= maxPronunciationAttempts
+
+	// Only the word itself is reviewed, the sentence is extra practice
+	if done && !wasDone && data.Target == data.Word {
+		token, err := s.stateManager.GetJWTToken(ctx, userID)
+		if err == nil {
+			err = s.apiClient.RecordReview(ctx, token, data.WordID, data.BestScore, time.Now())
+		}
+		if err != nil {
+			s.logger.Warn("Failed to record pronunciation review", zap.Error(err), zap.String("word_id", data.WordID))
+		}
+	}
+
+	if err := s.stateManager.StorePronunciationData(ctx, userID, data); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("🎧 Я услышал: «%s»\nТочность: %d%%\n\n", transcript, score)
+	switch {
+	case passed:
+		text += "✅ Отлично, очень похоже!"
+	case strings.Contains(data.Target, " "):
+		if missed := stt.MissedWords(data.Target, transcript); len(missed) > 0 {
+			text += "Не расслышал: " + strings.Join(missed, ", ") + "\n"
+		}
+		fallthrough
+	default:
+		if done {
+			text += "Не страшно. Послушайте, как оно звучит, и попробуйте позже."
+		} else {
+			text += fmt.Sprintf("Попробуйте ещё раз, осталось попыток: %d", maxPronunciationAttempts-data.Attempts)
+		}
+	}
+
+	return c.Send(text, pronunciationKeyboard(data, done))
+}
+
+// HandlePronunciationCallback handles pron:* callbacks
+func (s *HandlerService) HandlePronunciationCallback(ctx context.Context, c tele.Context, userID int64, action string) error {
+	data, err := s.stateManager.GetPronunciationData(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// The buttons stay under old messages after the exercise is over
+	if data == nil {
+		return nil
+	}
+
+	switch action {
+	case "listen":
+		return s.sendWordVoiceMessage(ctx, c, data.Target)
+	case "sentence":
+		if data.Sentence == "" || data.Target == data.Sentence {
+			return nil
+		}
+		data.Target = data.Sentence
+		data.Attempts = 0
+		data.BestScore = 0
+		if err := s.stateManager.StorePronunciationData(ctx, userID, data); err != nil {
+			return err
+		}
+		return s.sendPronunciationTask(ctx, c, data)
+	case "finish":
+		if err := s.stateManager.ClearPronunciationData(ctx, userID); err != nil {
+			s.logger.Warn("Failed to clear pronunciation data", zap.Error(err))
+		}
+		if err := s.TransitionState(ctx, userID, fsm.StateStart); err != nil {
+			return err
+		}
+		return c.Send("👋 Тренировка произношения завершена.\n\nЕщё слово: /pronounce\nПродолжить обучение: /learn")
+	default:
+		s.logger.Warn("Unknown pronunciation callback", zap.String("action", action))
+		return nil
+	}
+}
+
+// sendPronunciationTask sends the text to say and a reference pronunciation
+func (s *HandlerService) sendPronunciationTask(ctx context.Context, c tele.Context, data *fsm.PronunciationData) error {
+	text := fmt.Sprintf("🗣 Произнесите вслух:\n\n<b>%s</b>\n\nЗапишите голосовое сообщение 🎤", html.EscapeString(data.Target))
+	if err := c.Send(text, &tele.SendOptions{ParseMode: tele.ModeHTML}, pronunciationKeyboard(data, false)); err != nil {
+		return err
+	}
+
+	// The task is still doable without the reference
+	if err := s.sendWordVoiceMessage(ctx, c, data.Target); err != nil {
+		s.logger.Warn("Failed to send reference pronunciation", zap.Error(err), zap.String("target", data.Target))
+	}
+
+	return nil
+}
+
+// pronunciationFromLesson picks a random word of the current lesson, nil if there is no lesson
+func (s *HandlerService) pronunciationFromLesson(ctx context.Context, userID int64) *fsm.PronunciationData {
+	progress, err := s.stateManager.GetLessonProgress(ctx, userID)
+	if err != nil || progress == nil || progress.LessonData == nil || len(progress.LessonData.Cards) == 0 {
+		return nil
+	}
+
+	cards := progress.LessonData.Cards
+	card := cards[rand.IntN(len(cards))]
+
+	data := &fsm.PronunciationData{WordID: card.WordID, Word: card.Word}
+	if len(card.Sentences) > 0 {
+		data.Sentence = card.Sentences[0].Text
+	}
+	return data
+}
+
+// pronunciationKeyboard is the keyboard under pronunciation tasks and results
+func pronunciationKeyboard(data *fsm.PronunciationData, done bool) *tele.ReplyMarkup {
+	rows := [][]tele.InlineButton{
+		{{Text: "🔊 Послушать ещё раз", Data: "pron:listen"}},
+	}
+	if done && data.Sentence != "" && data.Target != data.Sentence {
+		rows = append(rows, []tele.InlineButton{{Text: "🗣 Теперь предложение", Data: "pron:sentence"}})
+	}
+	rows = append(rows, []tele.InlineButton{{Text: "🏁 Закончить", Data: "pron:finish"}})
+
+	return &tele.ReplyMarkup{InlineKeyboard: rows}
+}
@@ -18,8 +18,9 @@ import (
 	"telegram-bot/internal/api"
 	"telegram-bot/internal/bot/fsm"
 	"telegram-bot/internal/domain"
+	"telegram-bot/internal/stt"
 	"telegram-bot/internal/tasks"
-	"telegram-bot/internal/utils"
+	"telegram-bot/internal/tts"
 )
 
 // HandlerService provides common functionality for message handlers
@@ -30,7 +31,8 @@ type HandlerService struct {
 	scheduler    *tasks.Scheduler
 	bot          *tele.Bot
 	stateManager *fsm.UserStateManager
-	ttsService   *utils.TTSService
+	ttsService   *tts.Service    // nil when the TTS provider is misconfigured
+	transcriber  stt.Transcriber // nil when speech-to-text isn't configured
 	logger       *zap.Logger
 }
 
@@ -44,8 +46,16 @@ func NewHandlerService(
 	stateManager *fsm.UserStateManager,
 	logger *zap.Logger,
 ) *HandlerService {
-	// Initialize TTS service
-	ttsService := utils.NewTTSService(cfg.TTS.CacheDir, logger)
+	// Pronunciations are optional, the bot works without them
+	ttsService, err := tts.New(cfg, redisClient, logger)
+	if err != nil {
+		logger.Error("Failed to initialize TTS, voice messages are disabled", zap.Error(err))
+	}
+
+	var transcriber stt.Transcriber
+	if cfg.STT.URL != "" {
+		transcriber = stt.NewWhisperClient(cfg.STT)
+	}
 
 	return &HandlerService{
 		config:       cfg,
@@ -55,6 +65,7 @@ func NewHandlerService(
 		bot:          bot,
 		stateManager: stateManager,
 		ttsService:   ttsService,
+		transcriber:  transcriber,
 		logger:       logger,
 	}
 }
@@ -147,8 +158,8 @@ func (s *HandlerService) GetUserProgress(ctx context.Context, userID int64) (*do
 		WordsPerDay:      preferences.WordsPerDay,
 		NotificationTime: "", // Will be set below after parsing
 		LearnedWords:     0,  // TODO: Get from backend stats
-		CurrentStreak:    0,  // TODO: Get from backend stats
-		LongestStreak:    0,  // TODO: Get from backend stats
+		CurrentStreak:    0,  // Set below from achievements
+		LongestStreak:    0,  // Set below from achievements
 		LastActivity:     time.Now(),
 		StartDate:        time.Now().Format("2006-01-02"),
 		Preferences: map[string]interface{}{
@@ -160,6 +171,14 @@ func (s *HandlerService) GetUserProgress(ctx context.Context, userID int64) (*do
 		},
 	}
 
+	// Streaks are best effort, progress is still useful without them
+	if achievements, err := s.apiClient.GetAchievements(ctx, accessToken); err != nil {
+		s.logger.Warn("Failed to get user streak", zap.Int64("user_id", userID), zap.Error(err))
+	} else {
+		userProgress.CurrentStreak = achievements.Stats.CurrentStreak
+		userProgress.LongestStreak = achievements.Stats.LongestStreak
+	}
+
 	// Parse notification time from ISO format to HH:MM format
 	if preferences.NotificationAt != "" {
 		s.logger.Debug("Parsing notification time from backend",
@@ -258,9 +277,7 @@ func (s *HandlerService) UpdateUserProgress(ctx context.Context, userID int64, p
 		if subscribed, ok := progress.Preferences["subscribed"].(bool); ok {
 			updateRequest.Subscribed = &subscribed
 		}
-		if avatarURL, ok := progress.Preferences["avatar_image_url"].(string); ok {
-			updateRequest.AvatarImageURL = &avatarURL
-		}
+		// The avatar isn't sent back: the backend returns it as an expiring signed URL
 	}
 
 	// Update preferences in backend
@@ -449,6 +466,10 @@ func (s *HandlerService) HandleTextMessage(ctx context.Context, c tele.Context,
 	case fsm.StateWaitingForTextInput:
 		// New learning flow: handle exercise text input
 		return s.HandleTextInputAnswer(ctx, c, userID, text)
+	case fsm.StateChatStart, fsm.StateChatActive:
+		return s.HandleChatMessage(ctx, c, userID, text)
+	case fsm.StatePronunciation:
+		return c.Send("🎤 Отправьте голосовое сообщение с произношением или нажмите «Закончить».")
 	default:
 		return s.HandleUnknownStateMessage(ctx, c, userID, currentState)
 	}
@@ -465,6 +486,11 @@ func (s *HandlerService) HandleCallback(ctx context.Context, c tele.Context, use
 
 	s.logger.With(zap.Int64("user_id", userID), zap.String("state", string(currentState)), zap.String("data", data)).Debug("Processing callback")
 
+	// Word cards answer with their own callback text
+	if strings.HasPrefix(data, "word:add:") {
+		return s.handleAddWordCallback(ctx, c, userID, strings.TrimPrefix(data, "word:add:"))
+	}
+
 	// Always respond to callback to remove loading state
 	defer func() {
 		if err := c.Respond(); err != nil {
@@ -493,6 +519,26 @@ func (s *HandlerService) HandleCallback(ctx context.Context, c tele.Context, use
 		return s.HandleVoiceCallback(ctx, c, userID, action)
 	}
 
+	if strings.HasPrefix(data, "chat:") {
+		action := strings.TrimPrefix(data, "chat:")
+		return s.HandleChatCallback(ctx, c, userID, action)
+	}
+
+	if strings.HasPrefix(data, "pron:") {
+		action := strings.TrimPrefix(data, "pron:")
+		return s.HandlePronunciationCallback(ctx, c, userID, action)
+	}
+
+	if strings.HasPrefix(data, "top:") {
+		action := strings.TrimPrefix(data, "top:")
+		return s.HandleTopCallback(ctx, c, userID, action)
+	}
+
+	if strings.HasPrefix(data, "friend:") {
+		action := strings.TrimPrefix(data, "friend:")
+		return s.HandleFriendCallback(ctx, c, userID, action)
+	}
+
 	if strings.HasPrefix(data, "stats:") {
 		action := strings.TrimPrefix(data, "stats:")
 		return s.HandleStatsCallback(ctx, c, userID, action)
@@ -723,12 +769,16 @@ func (s *HandlerService) HandleVoiceMessage(ctx context.Context, c tele.Context,
 	s.logger.With(zap.Int64("user_id", userID), zap.String("state", string(currentState)), zap.Int("duration", voice.Duration)).Debug("Processing voice message")
 
 	// Handle voice based on state
-	if currentState == fsm.StateWaitingForAudio {
-		return s.HandleAudioExerciseResponse(ctx, c, userID, voice)
+	if currentState == fsm.StatePronunciation || currentState == fsm.StateWaitingForAudio {
+		return s.HandleAudioExerciseResponse(ctx, c, userID, &voice.File, "voice.ogg")
+	}
+
+	if fsm.IsChatState(currentState) {
+		return s.HandleChatVoice(ctx, c, userID, voice)
 	}
 
 	// For other states, provide guidance
-	return c.Send("Голосовые сообщения поддерживаются во время аудио упражнений. Используйте /learn чтобы начать урок.")
+	return c.Send("Голосовые сообщения поддерживаются в тренировке произношения. Используйте /pronounce чтобы начать.")
 }
 
 // HandleAudioMessage handles audio messages
@@ -741,8 +791,12 @@ func (s *HandlerService) HandleAudioMessage(ctx context.Context, c tele.Context,
 	s.logger.With(zap.Int64("user_id", userID), zap.String("state", string(currentState)), zap.Int("duration", audio.Duration)).Debug("Processing audio message")
 
 	// Similar to voice handling
-	if currentState == fsm.StateWaitingForAudio {
-		return s.HandleAudioExerciseResponse(ctx, c, userID, audio)
+	if currentState == fsm.StatePronunciation || currentState == fsm.StateWaitingForAudio {
+		filename := audio.FileName
+		if filename == "" {
+			filename = "audio.mp3"
+		}
+		return s.HandleAudioExerciseResponse(ctx, c, userID, &audio.File, filename)
 	}
 
 	return c.Send("Аудио сообщения поддерживаются во время аудио упражнений. Используйте /learn чтобы начать урок.")
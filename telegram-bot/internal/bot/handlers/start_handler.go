@@ -26,6 +26,13 @@ func (s *HandlerService) HandleStartCommand(ctx context.Context, c tele.Context,
 		return err
 	}
 
+	// Friend invite links open the bot with /start friend_<token>
+	if inviteToken, ok := strings.CutPrefix(c.Message().Payload, friendInvitePayload); ok && inviteToken != "" {
+		if err := s.acceptFriendInvite(ctx, c, userID, inviteToken); err != nil {
+			return err
+		}
+	}
+
 	// Handle different user states
 	if isAuthenticated && hasCompletedOnboarding {
 		// User is fully set up - fast-track to main menu
@@ -150,9 +157,14 @@ func (s *HandlerService) HandleHelpCommand(ctx context.Context, c tele.Context,
 		"*/settings* - Настроить предпочтения обучения\n" +
 		"*/test* - Пройти тест на определение уровня словарного запаса\n" +
 		"*/stats* - Посмотреть статистику обучения\n" +
+		"*/word* - Найти слово: перевод, примеры и произношение\n" +
+		"*/chat* - Поговорить по-английски с ИИ\n" +
+		"*/pronounce* - Тренировка произношения голосом\n" +
+		"*/top* - Недельный рейтинг друзей и всех участников\n" +
 		"*/menu* - Вернуться в главное меню\n" +
 		"*/help* - Показать это сообщение справки\n" +
 		"*/cancel* - Отменить текущее действие\n\n" +
+		"Слова можно искать в любом чате: наберите @" + strings.ReplaceAll(s.bot.Me.Username, "_", "\\_") + " и слово.\n\n" +
 		"Нужна дополнительная помощь? Напишите свой вопрос, и я постараюсь помочь."
 
 	return c.Send(helpText, &tele.SendOptions{ParseMode: tele.ModeMarkdown})
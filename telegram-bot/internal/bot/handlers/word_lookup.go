@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/api"
+	"telegram-bot/internal/bot/fsm"
+)
+
+const (
+	inlineLookupLimit  = 5
+	lookupSentences    = 2
+	voiceFileCacheTTL  = 30 * 24 * time.Hour
+	inlineQueryMaxSize = 64
+)
+
+// HandleWordCommand handles /word <text>: looks the word up and sends its card and pronunciation
+func (s *HandlerService) HandleWordCommand(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	query := strings.TrimSpace(c.Message().Payload)
+	if query == "" {
+		return c.Send("🔎 Напишите слово после команды, например: /word serendipity\n\n" +
+			"Искать слова можно и в любом чате: наберите @" + s.bot.Me.Username + " и слово.")
+	}
+
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Send("🔐 Чтобы искать слова, войдите в аккаунт: /start")
+	}
+
+	words, err := s.apiClient.LookupWords(ctx, token, query, 1)
+	if err != nil {
+		s.logger.Error("Failed to look up word", zap.Error(err), zap.String("query", query))
+		return c.Send("❌ Не удалось найти слово, попробуйте позже.")
+	}
+
+	if len(words) == 0 {
+		return c.Send(fmt.Sprintf("🤷 Слово «%s» не найдено в словаре.", query))
+	}
+
+	word := words[0]
+	if err := c.Send(formatWordCard(&word), &tele.SendOptions{ParseMode: tele.ModeHTML}, addWordKeyboard(word.ID)); err != nil {
+		return err
+	}
+
+	// The card is useful even without pronunciation
+	if err := s.sendWordVoiceMessage(ctx, c, word.Word); err != nil {
+		s.logger.Warn("Failed to send word pronunciation", zap.Error(err), zap.String("word", word.Word))
+	}
+
+	return nil
+}
+
+// HandleInlineQuery answers inline queries (@bot word) with word cards and cached pronunciations
+func (s *HandlerService) HandleInlineQuery(ctx context.Context, c tele.Context, userID int64, currentState fsm.UserState) error {
+	query := strings.TrimSpace(c.Query().Text)
+	if query == "" || len([]rune(query)) > inlineQueryMaxSize {
+		return c.Answer(&tele.QueryResponse{Results: tele.Results{}, CacheTime: 0, IsPersonal: true})
+	}
+
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Answer(&tele.QueryResponse{
+			Results:           tele.Results{},
+			IsPersonal:        true,
+			SwitchPMText:      "Войдите, чтобы искать слова",
+			SwitchPMParameter: "inline",
+		})
+	}
+
+	words, err := s.apiClient.LookupWords(ctx, token, query, inlineLookupLimit)
+	if err != nil {
+		s.logger.Error("Failed to look up inline query", zap.Error(err), zap.String("query", query))
+		return c.Answer(&tele.QueryResponse{Results: tele.Results{}, IsPersonal: true})
+	}
+
+	results := make(tele.Results, 0, len(words)*2)
+	for i := range words {
+		word := &words[i]
+
+		article := &tele.ArticleResult{
+			Title:       inlineTitle(word),
+			Description: inlineDescription(word),
+			Text:        formatWordCard(word),
+		}
+		article.SetResultID("word:" + word.ID)
+		article.SetParseMode(tele.ModeHTML)
+		article.SetReplyMarkup(addWordKeyboard(word.ID))
+		results = append(results, article)
+
+		// Inline voice results need a file already on Telegram servers,
+		// so pronunciations show up once the word was voiced in any chat
+		if fileID := s.cachedVoiceFileID(ctx, word.Word); fileID != "" {
+			voice := &tele.VoiceResult{Title: "🔊 " + word.Word, Cache: fileID}
+			voice.SetResultID("voice:" + word.ID)
+			voice.SetReplyMarkup(addWordKeyboard(word.ID))
+			results = append(results, voice)
+		}
+	}
+
+	return c.Answer(&tele.QueryResponse{
+		Results:    results,
+		CacheTime:  60,
+		IsPersonal: true, // personal words are part of the results
+	})
+}
+
+// handleAddWordCallback adds a looked up word to the user's words so it's queued into the next lesson.
+// It may come from an inline message in another chat, so the result is shown as a callback alert.
+func (s *HandlerService) handleAddWordCallback(ctx context.Context, c tele.Context, userID int64, wordID string) error {
+	token, err := s.stateManager.GetJWTToken(ctx, userID)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "🔐 Сначала войдите в аккаунт в чате с ботом: /start", ShowAlert: true})
+	}
+
+	word, err := s.apiClient.GetWord(ctx, token, wordID)
+	if err != nil {
+		s.logger.Error("Failed to get word to add", zap.Error(err), zap.String("word_id", wordID))
+		return c.Respond(&tele.CallbackResponse{Text: "❌ Слово не найдено"})
+	}
+
+	capture, err := s.apiClient.CaptureWord(ctx, token, word.Word)
+	if err != nil {
+		s.logger.Error("Failed to add word", zap.Error(err), zap.String("word_id", wordID))
+		return c.Respond(&tele.CallbackResponse{Text: "❌ Не удалось добавить слово, попробуйте позже"})
+	}
+
+	if capture.Duplicate {
+		return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("«%s» уже есть в ваших словах", word.Word)})
+	}
+
+	return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("✅ «%s» будет в следующем уроке", word.Word)})
+}
+
+// formatWordCard renders a looked up word as an HTML message
+func formatWordCard(word *api.WordLookupResult) string {
+	var b strings.Builder
+
+	b.WriteString("<b>" + html.EscapeString(word.Word) + "</b>")
+	if word.Phonetic != nil && *word.Phonetic != "" {
+		b.WriteString(" <i>" + html.EscapeString(*word.Phonetic) + "</i>")
+	}
+	b.WriteString("\n")
+
+	if word.PartOfSpeech != "" {
+		b.WriteString(html.EscapeString(word.PartOfSpeech))
+		if word.CEFRLevel != "" {
+			b.WriteString(" · " + html.EscapeString(word.CEFRLevel))
+		}
+		b.WriteString("\n")
+	}
+
+	if word.Translation != nil && *word.Translation != "" {
+		b.WriteString("\n🇷🇺 " + html.EscapeString(*word.Translation) + "\n")
+	}
+
+	for i, sentence := range word.Sentences {
+		if i == lookupSentences {
+			break
+		}
+		if i == 0 {
+			b.WriteString("\n📝 Примеры:\n")
+		}
+		b.WriteString("• " + html.EscapeString(sentence.Sentence))
+		if sentence.Translation != "" {
+			b.WriteString("\n  <i>" + html.EscapeString(sentence.Translation) + "</i>")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// inlineTitle is the title of an inline result: the word and its translation
+func inlineTitle(word *api.WordLookupResult) string {
+	if word.Translation != nil && *word.Translation != "" {
+		return word.Word + " — " + *word.Translation
+	}
+	return word.Word
+}
+
+// inlineDescription is the second line of an inline result
+func inlineDescription(word *api.WordLookupResult) string {
+	parts := make([]string, 0, 2)
+	if word.Phonetic != nil && *word.Phonetic != "" {
+		parts = append(parts, *word.Phonetic)
+	}
+	if word.PartOfSpeech != "" {
+		parts = append(parts, word.PartOfSpeech)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// addWordKeyboard is the "add to my words" button of a word card
+func addWordKeyboard(wordID string) *tele.ReplyMarkup {
+	return &tele.ReplyMarkup{
+		InlineKeyboard: [][]tele.InlineButton{
+			{{Text: "➕ Добавить в мои слова", Data: "word:add:" + wordID}},
+		},
+	}
+}
+
+// voiceFileKey is the Redis key of the Telegram file ID of a word's pronunciation
+func voiceFileKey(word string) string {
+	return "tts:voice_file:" + strings.ToLower(word)
+}
+
+// cachedVoiceFileID returns the Telegram file ID of an already uploaded pronunciation, if any
+func (s *HandlerService) cachedVoiceFileID(ctx context.Context, word string) string {
+	fileID, err := s.redisClient.Get(ctx, voiceFileKey(word)).Result()
+	if err != nil {
+		return ""
+	}
+	return fileID
+}
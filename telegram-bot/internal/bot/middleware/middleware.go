@@ -1,4 +1,255 @@
-// middleware.go
+// Package middleware contains the telebot middleware stack applied to every update:
+// request context, logging, panic recovery, flood throttling and per-user serialization.
 package middleware
 
-// TODO: Implement middleware logic
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/api"
+)
+
+const (
+	contextKey   = "ctx"
+	requestIDKey = "request_id"
+
+	// lockRetryInterval is how often a busy user lock is polled
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// unlockScript deletes the lock only if it's still held by the same owner
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Context returns the request context set by the WithContext middleware,
+// or a background context for updates that didn't pass through it
+func Context(c tele.Context) context.Context {
+	if ctx, ok := c.Get(contextKey).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// RequestID returns the correlation ID of the update
+func RequestID(c tele.Context) string {
+	id, _ := c.Get(requestIDKey).(string)
+	return id
+}
+
+// WithContext creates a request context with a deadline and a correlation ID.
+// The ID is sent to the backend with every API request made during the update.
+func WithContext(timeout time.Duration) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			id := uuid.NewString()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			c.Set(requestIDKey, id)
+			c.Set(contextKey, api.WithRequestID(ctx, id))
+
+			return next(c)
+		}
+	}
+}
+
+// Logging logs every handled update with its user, kind, duration and error
+func Logging(logger *zap.Logger) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			fields := []zap.Field{
+				zap.String("request_id", RequestID(c)),
+				zap.Int("update_id", c.Update().ID),
+				zap.String("kind", updateKind(c)),
+				zap.Duration("duration", time.Since(start)),
+			}
+			if sender := c.Sender(); sender != nil {
+				fields = append(fields, zap.Int64("user_id", sender.ID))
+			}
+
+			if err != nil {
+				logger.Error("Update failed", append(fields, zap.Error(err))...)
+			} else {
+				logger.Info("Update handled", fields...)
+			}
+
+			return err
+		}
+	}
+}
+
+// Recover turns a panic in a handler into a logged error and a polite reply,
+// so one broken update doesn't crash the bot
+func Recover(logger *zap.Logger, reply string) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+
+				logger.Error("Recovered from panic in handler",
+					zap.Any("panic", r),
+					zap.String("request_id", RequestID(c)),
+					zap.Stack("stack"))
+
+				if c.Callback() != nil {
+					_ = c.Respond()
+				}
+				if c.Chat() != nil || c.Sender() != nil {
+					if sendErr := c.Send(reply); sendErr != nil {
+						logger.Error("Failed to send panic reply", zap.Error(sendErr))
+					}
+				}
+
+				err = fmt.Errorf("panic: %v", r)
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// Throttle drops updates from users that send more than limit updates per window.
+// The user is warned once per window, further updates are ignored silently.
+func Throttle(redisClient *redis.Client, logger *zap.Logger, limit int, window time.Duration, reply string) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			sender := c.Sender()
+			if sender == nil || limit <= 0 {
+				return next(c)
+			}
+
+			ctx := Context(c)
+			bucket := time.Now().UnixNano() / int64(window)
+			key := "tg:throttle:" + strconv.FormatInt(sender.ID, 10) + ":" + strconv.FormatInt(bucket, 10)
+
+			pipe := redisClient.TxPipeline()
+			incr := pipe.Incr(ctx, key)
+			pipe.Expire(ctx, key, window)
+			if _, err := pipe.Exec(ctx); err != nil {
+				// Better to let a flood through than to block everyone when Redis is down
+				logger.Warn("Failed to check rate limit", zap.Error(err), zap.Int64("user_id", sender.ID))
+				return next(c)
+			}
+
+			count := int(incr.Val())
+			if count <= limit {
+				return next(c)
+			}
+
+			logger.Debug("Throttled update", zap.Int64("user_id", sender.ID), zap.Int("count", count))
+
+			if c.Callback() != nil {
+				return c.Respond(&tele.CallbackResponse{Text: reply})
+			}
+			// Inline queries are sent while typing, no need to warn about them
+			if c.Query() != nil {
+				return nil
+			}
+			if count == limit+1 {
+				return c.Send(reply)
+			}
+			return nil
+		}
+	}
+}
+
+// UserLock processes updates of one user one at a time, across all bot replicas,
+// so rapid double-taps on inline buttons can't interleave and corrupt lesson state.
+// An update that can't get the lock within wait is dropped with busyReply. When the lock
+// can't be taken at all, the update is dropped with failReply rather than run unlocked.
+func UserLock(redisClient *redis.Client, logger *zap.Logger, ttl, wait time.Duration, busyReply, failReply string) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			// Inline queries don't touch user state and must be answered quickly
+			sender := c.Sender()
+			if sender == nil || c.Query() != nil {
+				return next(c)
+			}
+
+			ctx := Context(c)
+			key := "tg:lock:" + strconv.FormatInt(sender.ID, 10)
+			owner := uuid.NewString()
+
+			acquired, err := acquireLock(ctx, redisClient, key, owner, ttl, wait)
+			if err != nil {
+				logger.Warn("Dropped update, failed to acquire user lock", zap.Error(err), zap.Int64("user_id", sender.ID))
+				if c.Callback() != nil {
+					return c.Respond(&tele.CallbackResponse{Text: failReply})
+				}
+				return c.Send(failReply)
+			}
+			if !acquired {
+				logger.Info("Dropped update while previous one is in progress", zap.Int64("user_id", sender.ID))
+				if c.Callback() != nil {
+					return c.Respond(&tele.CallbackResponse{Text: busyReply})
+				}
+				return nil
+			}
+
+			defer func() {
+				// The request context may be done by now, the lock must still be released
+				if err := unlockScript.Run(context.Background(), redisClient, []string{key}, owner).Err(); err != nil {
+					logger.Warn("Failed to release user lock", zap.Error(err), zap.Int64("user_id", sender.ID))
+				}
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// acquireLock polls the lock until it's free, wait passes or ctx is done
+func acquireLock(ctx context.Context, redisClient *redis.Client, key, owner string, ttl, wait time.Duration) (bool, error) {
+	deadline := time.Now().Add(wait)
+
+	for {
+		ok, err := redisClient.SetNX(ctx, key, owner, ttl).Result()
+		if err != nil || ok {
+			return ok, err
+		}
+
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// updateKind names the kind of update for logs
+func updateKind(c tele.Context) string {
+	switch {
+	case c.Callback() != nil:
+		return "callback"
+	case c.Query() != nil:
+		return "inline_query"
+	case c.Message() != nil && c.Message().Text != "" && c.Message().Text[0] == '/':
+		return "command"
+	case c.Message() != nil:
+		return "message"
+	default:
+		return "other"
+	}
+}
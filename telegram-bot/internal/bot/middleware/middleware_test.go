@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/internal/api"
+)
+
+func newTestContext(t *testing.T) tele.Context {
+	b, err := tele.NewBot(tele.Settings{Offline: true})
+	if err != nil {
+		t.Fatalf("failed to create offline bot: %v", err)
+	}
+	return b.NewContext(tele.Update{ID: 1})
+}
+
+func TestWithContextSetsDeadlineAndRequestID(t *testing.T) {
+	c := newTestContext(t)
+
+	handler := WithContext(time.Minute)(func(c tele.Context) error {
+		ctx := Context(c)
+
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected request context to have a deadline")
+		}
+		if RequestID(c) == "" {
+			t.Error("expected request ID to be set")
+		}
+		if api.RequestIDFromContext(ctx) != RequestID(c) {
+			t.Error("expected request ID to be propagated to the API context")
+		}
+		return nil
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRecoverReturnsPanicAsError(t *testing.T) {
+	c := newTestContext(t)
+
+	handler := Recover(zap.NewNop(), "oops")(func(c tele.Context) error {
+		panic("boom")
+	})
+
+	err := handler(c)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected panic to be returned as an error, got %v", err)
+	}
+}
+
+func TestUserLockSkipsUpdateWithoutLock(t *testing.T) {
+	b, err := tele.NewBot(tele.Settings{Offline: true})
+	if err != nil {
+		t.Fatalf("failed to create offline bot: %v", err)
+	}
+	c := b.NewContext(tele.Update{ID: 1, Message: &tele.Message{
+		Sender: &tele.User{ID: 42},
+		Chat:   &tele.Chat{ID: 42},
+		Text:   "/learn",
+	}})
+
+	// Nothing listens on the port, so the lock can't be taken
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer redisClient.Close()
+
+	handled := false
+	handler := UserLock(redisClient, zap.NewNop(), time.Minute, time.Second, "busy", "try again")(func(c tele.Context) error {
+		handled = true
+		return nil
+	})
+
+	handler(c)
+	if handled {
+		t.Error("expected the update to be skipped without the lock")
+	}
+}
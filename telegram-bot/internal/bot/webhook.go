@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+
+	"telegram-bot/config"
+)
+
+const (
+	// updateDedupTTL is how long processed update IDs are remembered. Telegram stops
+	// redelivering an update long before that.
+	updateDedupTTL = 24 * time.Hour
+
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	maxUpdateSize     = 1 << 20
+)
+
+// WebhookPoller receives updates over HTTP so several bot replicas can run behind
+// a load balancer. Updates are de-duplicated by ID in Redis, so an update that
+// Telegram redelivers to another replica is processed only once.
+type WebhookPoller struct {
+	cfg         config.WebhookConfig
+	redisClient *redis.Client
+	logger      *zap.Logger
+
+	server *http.Server
+	dest   chan<- tele.Update
+}
+
+// NewWebhookPoller creates a new webhook poller
+func NewWebhookPoller(cfg config.WebhookConfig, redisClient *redis.Client, logger *zap.Logger) *WebhookPoller {
+	return &WebhookPoller{
+		cfg:         cfg,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// Register calls setWebhook, uploading the self-signed certificate if configured.
+// It is idempotent, so every replica registers the same webhook on start.
+func (p *WebhookPoller) Register(b *tele.Bot) error {
+	if err := b.SetWebhook(p.webhook()); err != nil {
+		return err
+	}
+
+	p.logger.Info("Webhook registered", zap.String("url", p.cfg.URL), zap.Bool("custom_cert", p.cfg.Cert != ""))
+	return nil
+}
+
+// Poll serves webhook updates until stop is closed
+func (p *WebhookPoller) Poll(b *tele.Bot, dest chan tele.Update, stop chan struct{}) {
+	p.dest = dest
+
+	mux := http.NewServeMux()
+	mux.Handle(p.cfg.Path, p)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p.server = &http.Server{
+		Addr:              p.cfg.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		var err error
+		if p.cfg.TLSCert != "" && p.cfg.TLSKey != "" {
+			err = p.server.ListenAndServeTLS(p.cfg.TLSCert, p.cfg.TLSKey)
+		} else {
+			err = p.server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Error("Webhook server stopped with error", zap.Error(err))
+		}
+	}()
+
+	p.logger.Info("Webhook server started", zap.String("listen", p.cfg.Listen), zap.String("path", p.cfg.Path))
+
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := p.server.Shutdown(ctx); err != nil {
+		p.logger.Error("Failed to shut down webhook server", zap.Error(err))
+	}
+
+	// Other replicas may still be serving, so the webhook is only removed when asked to
+	if p.cfg.DeleteOnStop {
+		if err := b.RemoveWebhook(); err != nil {
+			p.logger.Error("Failed to delete webhook", zap.Error(err))
+		} else {
+			p.logger.Info("Webhook deleted")
+		}
+	}
+}
+
+// webhook builds the setWebhook parameters
+func (p *WebhookPoller) webhook() *tele.Webhook {
+	return &tele.Webhook{
+		MaxConnections: p.cfg.MaxConnections,
+		DropUpdates:    p.cfg.DropPending,
+		SecretToken:    p.cfg.Secret,
+		Endpoint: &tele.WebhookEndpoint{
+			PublicURL: p.cfg.URL,
+			Cert:      p.cfg.Cert,
+		},
+	}
+}
+
+// ServeHTTP verifies the secret token, de-duplicates the update and passes it to the bot
+func (p *WebhookPoller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get(secretTokenHeader)
+	if p.cfg.Secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(p.cfg.Secret)) != 1 {
+		p.logger.Warn("Rejected webhook request with invalid secret token", zap.String("remote_addr", r.RemoteAddr))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var update tele.Update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&update); err != nil {
+		p.logger.Warn("Failed to decode webhook update", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	first, err := p.markUpdate(r.Context(), update.ID)
+	if err != nil {
+		// Processing twice is better than dropping the update
+		p.logger.Error("Failed to de-duplicate update", zap.Error(err), zap.Int("update_id", update.ID))
+	} else if !first {
+		p.logger.Debug("Skipping duplicate update", zap.Int("update_id", update.ID))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	p.dest <- update
+	w.WriteHeader(http.StatusOK)
+}
+
+// markUpdate remembers an update ID and reports whether it was seen for the first time
+func (p *WebhookPoller) markUpdate(ctx context.Context, updateID int) (bool, error) {
+	key := "tg:update:" + strconv.Itoa(updateID)
+	return p.redisClient.SetNX(ctx, key, 1, updateDedupTTL).Result()
+}
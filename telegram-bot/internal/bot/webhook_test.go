@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"telegram-bot/config"
+)
+
+func TestWebhookRejectsInvalidRequests(t *testing.T) {
+	poller := NewWebhookPoller(config.WebhookConfig{Secret: "s3cret"}, nil, zap.NewNop())
+
+	testCases := []struct {
+		name     string
+		method   string
+		secret   string
+		body     string
+		expected int
+	}{
+		{"missing secret", http.MethodPost, "", `{"update_id":1}`, http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "wrong", `{"update_id":1}`, http.StatusUnauthorized},
+		{"wrong method", http.MethodGet, "s3cret", "", http.StatusMethodNotAllowed},
+		{"invalid body", http.MethodPost, "s3cret", "not json", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, "/webhook", strings.NewReader(tc.body))
+		if tc.secret != "" {
+			req.Header.Set(secretTokenHeader, tc.secret)
+		}
+		rec := httptest.NewRecorder()
+
+		poller.ServeHTTP(rec, req)
+
+		if rec.Code != tc.expected {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.expected, rec.Code)
+		}
+	}
+}
+
+func TestWebhookRejectsWithoutConfiguredSecret(t *testing.T) {
+	poller := NewWebhookPoller(config.WebhookConfig{}, nil, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	rec := httptest.NewRecorder()
+	poller.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
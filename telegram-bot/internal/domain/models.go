@@ -20,6 +20,12 @@ type UserProgress struct {
 	Preferences      map[string]interface{} `json:"preferences"`       // Additional preferences
 }
 
+// ChatMessage is a message of a dialog with the AI, author is "user" or "llm"
+type ChatMessage struct {
+	Author  string `json:"author"`
+	Message string `json:"message"`
+}
+
 // New lesson structure matching the backend JSON format
 
 // Lesson represents the lesson metadata
@@ -0,0 +1,131 @@
+// Package storage is a minimal client of S3-compatible object storage (AWS S3, MinIO, R2)
+// built on minio-go, the library the backend stores media with
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+
+	"telegram-bot/config"
+)
+
+// ErrNotFound is returned when the object doesn't exist
+var ErrNotFound = errors.New("object not found")
+
+const maxObjectSize = 50 << 20
+
+// S3Client stores and reads objects of a single bucket
+type S3Client struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Client creates a new S3 client
+func NewS3Client(cfg config.S3Config) (*S3Client, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket are required")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || endpoint.Path != "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	c := &S3Client{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+	}
+	if c.publicURL == "" {
+		if cfg.PathStyle {
+			c.publicURL = endpoint.Scheme + "://" + endpoint.Host + "/" + cfg.Bucket
+		} else {
+			c.publicURL = endpoint.Scheme + "://" + cfg.Bucket + "." + endpoint.Host
+		}
+	}
+
+	return c, nil
+}
+
+// Put uploads an object
+func (c *S3Client) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := c.client.PutObject(ctx, c.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+
+	return nil
+}
+
+// Get downloads an object
+func (c *S3Client) Get(ctx context.Context, key string) ([]byte, error) {
+	object, err := c.client.GetObject(ctx, c.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, maxObjectSize+1))
+	if isNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	if len(data) > maxObjectSize {
+		return nil, fmt.Errorf("object is larger than %d bytes", maxObjectSize)
+	}
+
+	return data, nil
+}
+
+// Exists checks if an object exists
+func (c *S3Client) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.client.StatObject(ctx, c.bucket, key, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	return true, nil
+}
+
+// URL returns the public URL of an object
+func (c *S3Client) URL(key string) string {
+	return c.publicURL + "/" + s3utils.EncodePath(key)
+}
+
+// isNotFound reports whether an S3 error means the object or bucket is missing
+func isNotFound(err error) bool {
+	return err != nil && minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
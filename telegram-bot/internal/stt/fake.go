@@ -0,0 +1,24 @@
+package stt
+
+import (
+	"context"
+	"io"
+)
+
+// Fake is a Transcriber that returns a fixed text without calling any service.
+// It's meant for tests and for running the bot locally without a Whisper server.
+type Fake struct {
+	Text string
+	Err  error
+}
+
+// Transcribe drains the audio and returns the configured text
+func (f *Fake) Transcribe(ctx context.Context, audio io.Reader, filename string) (string, error) {
+	if _, err := io.Copy(io.Discard, audio); err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return f.Text, f.Err
+}
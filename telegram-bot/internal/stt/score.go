@@ -0,0 +1,104 @@
+package stt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PassScore is the lowest score at which a pronunciation counts as correct
+const PassScore = 80
+
+// wordMatchSimilarity is how close a transcribed word must be to count as the expected one
+const wordMatchSimilarity = 0.8
+
+// Score compares what the recognizer heard with the expected text and returns 0..100.
+// Similarity is based on the edit distance of normalized texts. For a single word the
+// closest transcribed word is used, so "the word is serendipity" still scores 100.
+func Score(expected, transcript string) int {
+	want := normalize(expected)
+	got := normalize(transcript)
+	if want == "" || got == "" {
+		return 0
+	}
+
+	best := similarity(want, got)
+	if !strings.Contains(want, " ") {
+		for _, token := range strings.Fields(got) {
+			if s := similarity(want, token); s > best {
+				best = s
+			}
+		}
+	}
+
+	return int(best*100 + 0.5)
+}
+
+// MissedWords returns the expected words that weren't recognized in the transcript
+func MissedWords(expected, transcript string) []string {
+	heard := strings.Fields(normalize(transcript))
+
+	var missed []string
+	for _, word := range strings.Fields(normalize(expected)) {
+		found := false
+		for _, token := range heard {
+			if similarity(word, token) >= wordMatchSimilarity {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missed = append(missed, word)
+		}
+	}
+
+	return missed
+}
+
+// normalize lowercases the text and drops punctuation, keeping apostrophes of contractions
+func normalize(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'':
+			b.WriteRune(r)
+		case r == '’':
+			b.WriteRune('\'')
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// similarity is 1 minus the edit distance relative to the longer string
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein counts single-rune insertions, deletions and substitutions between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,56 @@
+package stt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   string
+		transcript string
+		pass       bool
+	}{
+		{"exact word", "serendipity", "Serendipity.", true},
+		{"word inside a phrase", "apple", "the word is apple", true},
+		{"small slip", "necessary", "nesessary", true},
+		{"wrong word", "thought", "taught", false},
+		{"sentence with punctuation", "I don't know, really!", "i don’t know really", true},
+		{"half a sentence", "The weather is lovely today", "the weather", false},
+		{"nothing heard", "hello", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Score(tt.expected, tt.transcript)
+			if score < 0 || score > 100 {
+				t.Fatalf("Score out of range: %d", score)
+			}
+			if pass := score >= PassScore; pass != tt.pass {
+				t.Errorf("Score(%q, %q) = %d, expected pass=%v", tt.expected, tt.transcript, score, tt.pass)
+			}
+		})
+	}
+}
+
+func TestMissedWords(t *testing.T) {
+	missed := MissedWords("She sells sea shells", "she sell see")
+	if !reflect.DeepEqual(missed, []string{"sea", "shells"}) {
+		t.Errorf("Expected sea and shells to be missed, got %v", missed)
+	}
+
+	if missed := MissedWords("hello", "Hello!"); len(missed) != 0 {
+		t.Errorf("Expected no missed words, got %v", missed)
+	}
+}
+
+func TestFakeTranscriber(t *testing.T) {
+	var transcriber Transcriber = &Fake{Text: "hello"}
+
+	text, err := transcriber.Transcribe(t.Context(), strings.NewReader("ogg"), "voice.ogg")
+	if err != nil || text != "hello" {
+		t.Errorf("Expected fake transcript, got %q, %v", text, err)
+	}
+}
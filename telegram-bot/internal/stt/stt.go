@@ -0,0 +1,111 @@
+// Package stt transcribes voice messages to text
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"telegram-bot/config"
+)
+
+// maxAudioSize is the largest voice message sent for transcription
+const maxAudioSize = 20 << 20
+
+// Transcriber turns recorded speech into text
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader, filename string) (string, error)
+}
+
+// WhisperClient is a client of an OpenAI-compatible /v1/audio/transcriptions endpoint,
+// such as faster-whisper-server, whisper.cpp server or the OpenAI API itself
+type WhisperClient struct {
+	url        string
+	apiKey     string
+	model      string
+	language   string
+	httpClient *http.Client
+}
+
+// NewWhisperClient creates a new Whisper-compatible STT client
+func NewWhisperClient(cfg config.STTConfig) *WhisperClient {
+	return &WhisperClient{
+		url:      strings.TrimRight(cfg.URL, "/") + "/v1/audio/transcriptions",
+		apiKey:   cfg.APIKey,
+		model:    cfg.Model,
+		language: cfg.Language,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// transcriptionResponse is the JSON response of the transcription endpoint
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// Transcribe uploads the audio and returns the recognized text
+func (w *WhisperClient) Transcribe(ctx context.Context, audio io.Reader, filename string) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	n, err := io.Copy(part, io.LimitReader(audio, maxAudioSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	if n > maxAudioSize {
+		return "", fmt.Errorf("audio is larger than %d bytes", maxAudioSize)
+	}
+
+	fields := map[string]string{
+		"model":           w.model,
+		"language":        w.language,
+		"response_format": "json",
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return "", fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to close form: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("STT error (%d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result transcriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode transcription: %w", err)
+	}
+
+	return strings.TrimSpace(result.Text), nil
+}
@@ -0,0 +1,66 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// FFmpegEncoder encodes audio to OGG/Opus with ffmpeg
+type FFmpegEncoder struct {
+	path string
+}
+
+// NewFFmpegEncoder creates an ffmpeg encoder. Without ffmpeg, audio is passed through as is:
+// Telegram still plays it, but shows it as a file instead of a voice note.
+func NewFFmpegEncoder(path string, logger *zap.Logger) Encoder {
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		logger.Warn("ffmpeg not found, voice notes won't be encoded to OGG/Opus", zap.String("path", path), zap.Error(err))
+		return passthroughEncoder{}
+	}
+	return &FFmpegEncoder{path: resolved}
+}
+
+// Encode converts audio in any format ffmpeg reads to mono 48kHz OGG/Opus
+func (e *FFmpegEncoder) Encode(ctx context.Context, audio []byte) ([]byte, error) {
+	if IsOggOpus(audio) {
+		return audio, nil
+	}
+
+	cmd := exec.CommandContext(ctx, e.path,
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-vn", "-ac", "1", "-ar", "48000",
+		"-c:a", "libopus", "-b:a", "32k", "-application", "voip",
+		"-f", "ogg", "pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(audio)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// passthroughEncoder returns audio unchanged
+type passthroughEncoder struct{}
+
+// Encode returns the audio as is
+func (passthroughEncoder) Encode(ctx context.Context, audio []byte) ([]byte, error) {
+	return audio, nil
+}
+
+// IsOggOpus checks if the audio is already an Opus stream in an OGG container
+func IsOggOpus(audio []byte) bool {
+	// The first OGG page holds the OpusHead identification header
+	return bytes.HasPrefix(audio, []byte("OggS")) && bytes.Contains(audio[:min(len(audio), 64)], []byte("OpusHead"))
+}
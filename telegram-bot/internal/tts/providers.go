@@ -0,0 +1,237 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxAudioSize is the largest audio read from a provider
+const maxAudioSize = 10 << 20
+
+// GoogleTranslate voices text with the public Google Translate endpoint. It needs no
+// setup but is unofficial and rate limited, so it's meant for development.
+type GoogleTranslate struct {
+	httpClient *http.Client
+}
+
+// NewGoogleTranslate creates a Google Translate provider
+func NewGoogleTranslate(timeout time.Duration) *GoogleTranslate {
+	return &GoogleTranslate{httpClient: &http.Client{Timeout: timeout}}
+}
+
+// Name identifies the provider
+func (g *GoogleTranslate) Name() string {
+	return "google"
+}
+
+// Synthesize returns MP3 audio
+func (g *GoogleTranslate) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("ie", "UTF-8")
+	params.Set("q", text)
+	params.Set("tl", language)
+	params.Set("client", "tw-ob")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://translate.google.com/translate_tts?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36")
+	req.Header.Set("Referer", "https://translate.google.com/")
+
+	return readAudio(g.httpClient.Do(req))
+}
+
+// HTTPProvider voices text with an OpenAI-compatible /v1/audio/speech server,
+// such as openedai-speech, Kokoro-FastAPI or the OpenAI API itself
+type HTTPProvider struct {
+	url        string
+	apiKey     string
+	model      string
+	voice      string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates an HTTP TTS provider
+func NewHTTPProvider(baseURL, apiKey, model, voice string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		url:        strings.TrimRight(baseURL, "/") + "/v1/audio/speech",
+		apiKey:     apiKey,
+		model:      model,
+		voice:      voice,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the provider, the model and the voice
+func (p *HTTPProvider) Name() string {
+	return "http:" + p.model + ":" + p.voice
+}
+
+// speechRequest is the request body of the speech endpoint
+type speechRequest struct {
+	Model          string `json:"model"`
+	Input          string `json:"input"`
+	Voice          string `json:"voice"`
+	ResponseFormat string `json:"response_format"`
+}
+
+// Synthesize returns OGG/Opus audio. The language is defined by the voice.
+func (p *HTTPProvider) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	body, err := json.Marshal(speechRequest{Model: p.model, Input: text, Voice: p.voice, ResponseFormat: "opus"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	return readAudio(p.httpClient.Do(req))
+}
+
+// ExecProvider voices text with a local engine such as espeak-ng or piper. The command
+// must write audio to stdout; {text} and {lang} in its arguments are replaced with the text
+// and the language, and without {text} the text is written to the command's stdin. Words
+// come from users, so a substitution that would turn into an option of the engine is rejected.
+type ExecProvider struct {
+	args []string
+}
+
+// NewExecProvider creates a provider running the command
+func NewExecProvider(command string) (*ExecProvider, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("TTS_COMMAND is required for the exec TTS provider")
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("TTS command not found: %w", err)
+	}
+	return &ExecProvider{args: args}, nil
+}
+
+// Name identifies the provider and its command line
+func (p *ExecProvider) Name() string {
+	return "exec:" + strings.Join(p.args, " ")
+}
+
+// Synthesize runs the command and returns what it wrote to stdout
+func (p *ExecProvider) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	args := make([]string, len(p.args))
+	textInArgs := false
+	replacer := strings.NewReplacer("{text}", text, "{lang}", language)
+	for i, arg := range p.args {
+		if strings.Contains(arg, "{text}") {
+			textInArgs = true
+		}
+		args[i] = replacer.Replace(arg)
+		if strings.HasPrefix(args[i], "-") && !strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("text %q can't be passed as an argument", args[i])
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if !textInArgs {
+		cmd.Stdin = strings.NewReader(text)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Silent returns a short silent WAV for any text. It's meant for tests and local
+// development without network access.
+type Silent struct{}
+
+// Name identifies the provider
+func (Silent) Name() string {
+	return "silent"
+}
+
+// Synthesize returns 300ms of 16kHz mono silence
+func (Silent) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	const (
+		sampleRate = 16000
+		samples    = sampleRate * 3 / 10
+		dataSize   = samples * 2
+	)
+
+	header := struct {
+		Riff          [4]byte
+		Size          uint32
+		Wave          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		Riff:          [4]byte{'R', 'I', 'F', 'F'},
+		Size:          36 + dataSize,
+		Wave:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1, // PCM
+		Channels:      1,
+		SampleRate:    sampleRate,
+		ByteRate:      sampleRate * 2,
+		BlockAlign:    2,
+		BitsPerSample: 16,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      dataSize,
+	}
+
+	var b bytes.Buffer
+	if err := binary.Write(&b, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	b.Write(make([]byte, dataSize))
+
+	return b.Bytes(), nil
+}
+
+// readAudio reads the audio from a provider response
+func readAudio(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("TTS request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+
+	return audio, nil
+}
@@ -0,0 +1,56 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"telegram-bot/internal/storage"
+)
+
+// RedisStore caches audio in Redis, shared by all bot replicas
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore creates a Redis audio cache
+func NewRedisStore(client *redis.Client, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, ttl: ttl}
+}
+
+// Get returns cached audio
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	audio, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return audio, err
+}
+
+// Put caches audio
+func (s *RedisStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+// S3Store keeps audio in object storage, where the backend can serve it to apps as well
+type S3Store struct {
+	client *storage.S3Client
+}
+
+// NewS3Store creates an object storage audio cache
+func NewS3Store(client *storage.S3Client) *S3Store {
+	return &S3Store{client: client}
+}
+
+// Get returns stored audio
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.client.Get(ctx, key)
+}
+
+// Put stores audio
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	return s.client.Put(ctx, key, data, "audio/ogg")
+}
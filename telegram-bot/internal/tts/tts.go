@@ -0,0 +1,164 @@
+// Package tts generates voice notes for words and sentences. Audio comes from a pluggable
+// provider, is encoded to OGG/Opus as Telegram voice notes require, and is cached by content
+// so every replica (and the backend, with the same storage) reuses it.
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"telegram-bot/config"
+	"telegram-bot/internal/storage"
+)
+
+// KeyPrefix is the prefix of cached audio keys. The backend uses the same scheme
+// to fill Word.AudioURL, both are tested against testdata/tts_keys.json in the repository root.
+const KeyPrefix = "tts/"
+
+// maxTextLength is the longest text voiced at once
+const maxTextLength = 500
+
+// ErrNotFound is returned by a Store when the audio isn't cached
+var ErrNotFound = storage.ErrNotFound
+
+// Provider synthesizes speech. The audio may be in any format ffmpeg can read.
+type Provider interface {
+	// Name identifies the provider and its voice, it's part of the cache key
+	Name() string
+	Synthesize(ctx context.Context, text, language string) ([]byte, error)
+}
+
+// Encoder converts audio to OGG/Opus
+type Encoder interface {
+	Encode(ctx context.Context, audio []byte) ([]byte, error)
+}
+
+// Store caches encoded audio by key
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Service generates OGG/Opus voice notes, reusing cached audio
+type Service struct {
+	provider Provider
+	encoder  Encoder
+	store    Store
+	language string
+	logger   *zap.Logger
+}
+
+// NewService creates a TTS service from its parts
+func NewService(provider Provider, encoder Encoder, store Store, language string, logger *zap.Logger) *Service {
+	return &Service{
+		provider: provider,
+		encoder:  encoder,
+		store:    store,
+		language: language,
+		logger:   logger,
+	}
+}
+
+// New creates a TTS service with the provider and storage selected in the config
+func New(cfg *config.Config, redisClient *redis.Client, logger *zap.Logger) (*Service, error) {
+	timeout := time.Duration(cfg.TTS.Timeout) * time.Second
+
+	var provider Provider
+	switch cfg.TTS.Provider {
+	case "google":
+		provider = NewGoogleTranslate(timeout)
+	case "http":
+		if cfg.TTS.URL == "" {
+			return nil, errors.New("TTS_URL is required for the http TTS provider")
+		}
+		provider = NewHTTPProvider(cfg.TTS.URL, cfg.TTS.APIKey, cfg.TTS.Model, cfg.TTS.Voice, timeout)
+	case "exec":
+		p, err := NewExecProvider(cfg.TTS.Command)
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	case "silent":
+		provider = Silent{}
+	default:
+		return nil, fmt.Errorf("unknown TTS provider %q", cfg.TTS.Provider)
+	}
+
+	var store Store
+	switch cfg.TTS.Storage {
+	case "redis":
+		store = NewRedisStore(redisClient, time.Duration(cfg.TTS.CacheTTL)*24*time.Hour)
+	case "s3":
+		client, err := storage.NewS3Client(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		store = NewS3Store(client)
+	default:
+		return nil, fmt.Errorf("unknown TTS storage %q", cfg.TTS.Storage)
+	}
+
+	return NewService(provider, NewFFmpegEncoder(cfg.TTS.FFmpeg, logger), store, cfg.TTS.Language, logger), nil
+}
+
+// Key returns the cache key of the audio of text voiced by a provider
+func Key(provider, language, text string) string {
+	sum := sha256.Sum256([]byte(provider + "\n" + language + "\n" + normalizeText(text)))
+	return KeyPrefix + hex.EncodeToString(sum[:]) + ".ogg"
+}
+
+// Voice returns an OGG/Opus voice note of the text in the default language
+func (s *Service) Voice(ctx context.Context, text string) ([]byte, error) {
+	return s.VoiceIn(ctx, text, s.language)
+}
+
+// VoiceIn returns an OGG/Opus voice note of the text in the given language
+func (s *Service) VoiceIn(ctx context.Context, text, language string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("empty text provided")
+	}
+	if len([]rune(text)) > maxTextLength {
+		return nil, fmt.Errorf("text is longer than %d characters", maxTextLength)
+	}
+
+	key := Key(s.provider.Name(), language, text)
+	if audio, err := s.store.Get(ctx, key); err == nil && len(audio) > 0 {
+		return audio, nil
+	} else if err != nil && !errors.Is(err, ErrNotFound) {
+		s.logger.Warn("Failed to read cached audio", zap.Error(err), zap.String("key", key))
+	}
+
+	raw, err := s.provider.Synthesize(ctx, text, language)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to synthesize speech: %w", s.provider.Name(), err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%s returned empty audio", s.provider.Name())
+	}
+
+	audio, err := s.encoder.Encode(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audio: %w", err)
+	}
+
+	// The audio is still good to send when it can't be cached
+	if err := s.store.Put(ctx, key, audio); err != nil {
+		s.logger.Warn("Failed to cache audio", zap.Error(err), zap.String("key", key))
+	}
+
+	return audio, nil
+}
+
+// normalizeText makes texts that sound the same share a cache key
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,145 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memoryStore is an in-memory Store
+type memoryStore map[string][]byte
+
+func (m memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if data, ok := m[key]; ok {
+		return data, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (m memoryStore) Put(ctx context.Context, key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+// countingProvider counts calls to a Silent provider
+type countingProvider struct {
+	Silent
+	calls int
+}
+
+func (p *countingProvider) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	p.calls++
+	return p.Silent.Synthesize(ctx, text, language)
+}
+
+func TestVoiceIsCachedByContent(t *testing.T) {
+	provider := &countingProvider{}
+	store := memoryStore{}
+	service := NewService(provider, passthroughEncoder{}, store, "en", zap.NewNop())
+
+	first, err := service.Voice(t.Context(), "Hello  World")
+	if err != nil {
+		t.Fatalf("Failed to voice text: %v", err)
+	}
+	if !strings.HasPrefix(string(first), "RIFF") || len(first) != 44+9600 {
+		t.Errorf("Expected 300ms of WAV audio from the silent provider, got %d bytes", len(first))
+	}
+
+	// Same text up to case and spacing is served from the cache
+	if _, err := service.Voice(t.Context(), "hello world"); err != nil {
+		t.Fatalf("Failed to voice text: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected one synthesis, got %d", provider.calls)
+	}
+	if _, ok := store[Key("silent", "en", "hello world")]; !ok {
+		t.Errorf("Expected audio to be stored under its content key")
+	}
+
+	if _, err := service.Voice(t.Context(), "  "); err == nil {
+		t.Errorf("Expected an error for empty text")
+	}
+}
+
+func TestKey(t *testing.T) {
+	// The backend computes the same key to find audio generated by the bot, its TTSAudioKey is tested with the same vectors
+	data, err := os.ReadFile("../../../testdata/tts_keys.json")
+	if err != nil {
+		t.Fatalf("Failed to read key vectors: %v", err)
+	}
+	var vectors []struct {
+		Provider string `json:"provider"`
+		Language string `json:"language"`
+		Text     string `json:"text"`
+		Key      string `json:"key"`
+	}
+	if err := json.Unmarshal(data, &vectors); err != nil || len(vectors) == 0 {
+		t.Fatalf("Failed to parse key vectors: %v", err)
+	}
+	for _, v := range vectors {
+		if key := Key(v.Provider, v.Language, v.Text); key != v.Key {
+			t.Errorf("Key scheme changed for %q: %s", v.Text, key)
+		}
+	}
+	if name := NewHTTPProvider("http://tts:8000", "", "tts-1", "alloy", time.Second).Name(); name != "http:tts-1:alloy" {
+		t.Errorf("HTTP provider name changed, the backend names it http:tts-1:alloy: %s", name)
+	}
+
+	key := Key("google", "en", "apple")
+	if !strings.HasPrefix(key, KeyPrefix) || !strings.HasSuffix(key, ".ogg") {
+		t.Errorf("Unexpected key format: %s", key)
+	}
+	if key == Key("http:tts-1:alloy", "en", "apple") || key == Key("google", "de", "apple") {
+		t.Errorf("Expected provider and language to be part of the key")
+	}
+}
+
+func TestIsOggOpus(t *testing.T) {
+	ogg := append([]byte("OggS\x00\x02"), make([]byte, 22)...)
+	ogg = append(ogg, []byte("OpusHead")...)
+
+	if !IsOggOpus(ogg) {
+		t.Errorf("Expected OGG/Opus to be detected")
+	}
+	if IsOggOpus([]byte("ID3\x04")) || IsOggOpus(nil) {
+		t.Errorf("Expected MP3 and empty audio not to be OGG/Opus")
+	}
+}
+
+func TestExecProvider(t *testing.T) {
+	provider, err := NewExecProvider("echo {lang}:{text}")
+	if err != nil {
+		t.Skipf("echo is not available: %v", err)
+	}
+
+	audio, err := provider.Synthesize(t.Context(), "hi", "en")
+	if err != nil || strings.TrimSpace(string(audio)) != "en:hi" {
+		t.Errorf("Expected placeholders to be replaced, got %q, %v", audio, err)
+	}
+
+	provider, err = NewExecProvider("echo {text}")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if audio, err := provider.Synthesize(t.Context(), "-w/tmp/x", "en"); err == nil {
+		t.Errorf("Expected text starting with - to be rejected, got %q", audio)
+	}
+
+	provider, err = NewExecProvider("cat")
+	if err != nil {
+		t.Skipf("cat is not available: %v", err)
+	}
+	audio, err = provider.Synthesize(t.Context(), "-w/tmp/x", "en")
+	if err != nil || string(audio) != "-w/tmp/x" {
+		t.Errorf("Expected the text on stdin, got %q, %v", audio, err)
+	}
+
+	if _, err := NewExecProvider(""); err == nil {
+		t.Errorf("Expected an error for an empty command")
+	}
+}